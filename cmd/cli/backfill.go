@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/backfill"
+	"github.com/ridopark/jonbu-ohlcv/internal/config"
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/enrichment"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-261: Batch historical enrichment backfill
+
+var (
+	backfillCmd = &cobra.Command{
+		Use:   "backfill",
+		Short: "Enrich stored candles into the enriched candle history",
+		Long: `Enrich stored OHLCV candles of a date range with the live stream's settings
+and store them in the enriched candle history, e.g.
+
+  backfill --symbols AAPL,MSFT --timeframe 1h --start 2024-01-01 --end 2024-06-30
+
+Each candle is enriched with the candles before it as history, including
+candles before the start date. Progress is checkpointed after every batch, so
+an interrupted backfill resumes where it stopped when run again with the same
+arguments. Checkpoints belong to the engine version and configuration hash:
+after an engine or configuration change the same command enriches the range
+again. Use --restart to ignore checkpoints.`,
+		Args: cobra.NoArgs,
+		RunE: runBackfill,
+	}
+
+	// Backfill command flags
+	backfillSymbols   string
+	backfillTimeframe string
+	backfillStart     string
+	backfillEnd       string
+	backfillBatchSize int
+	backfillWorkers   int
+	backfillRestart   bool
+)
+
+func init() {
+	backfillCmd.Flags().StringVar(&backfillSymbols, "symbols", "", "comma-separated symbols to backfill (required)")
+	backfillCmd.Flags().StringVar(&backfillTimeframe, "timeframe", "1d", "stored timeframe (1m, 5m, 15m, 1h, 4h, 1d)")
+	backfillCmd.Flags().StringVar(&backfillStart, "start", "", "start date (YYYY-MM-DD, required)")
+	backfillCmd.Flags().StringVar(&backfillEnd, "end", "", "end date, inclusive (YYYY-MM-DD, default today)")
+	backfillCmd.Flags().IntVar(&backfillBatchSize, "batch-size", 500, "candles enriched and written per batch")
+	backfillCmd.Flags().IntVar(&backfillWorkers, "workers", 0, "parallel enrichments (default engine concurrency)")
+	backfillCmd.Flags().BoolVar(&backfillRestart, "restart", false, "ignore checkpoints and enrich the whole range again")
+}
+
+func runBackfill(cmd *cobra.Command, args []string) error {
+	// REQ-025: Input validation with helpful error messages
+	symbols, err := parseSymbolList(backfillSymbols)
+	if err != nil {
+		return err
+	}
+
+	if err := validateStoredTimeframe(backfillTimeframe); err != nil {
+		return err
+	}
+
+	start, err := validateDateString(backfillStart)
+	if err != nil {
+		return fmt.Errorf("invalid start date '%s': %w", backfillStart, err)
+	}
+
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	if backfillEnd != "" {
+		end, err = validateDateString(backfillEnd)
+		if err != nil {
+			return fmt.Errorf("invalid end date '%s': %w", backfillEnd, err)
+		}
+	}
+	// The end date is inclusive
+	end = end.AddDate(0, 0, 1).Add(-time.Microsecond)
+
+	if start.After(end) {
+		return fmt.Errorf("start date must be before end date")
+	}
+
+	if backfillBatchSize < 1 || backfillBatchSize > 10000 {
+		return fmt.Errorf("invalid batch size %d: must be between 1 and 10000", backfillBatchSize)
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("format")
+	if err := validateOutputFormat(outputFormat); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	logger.InitLogger(cfg.LogLevel, cfg.Environment)
+
+	db, err := database.NewConnection(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	repo, err := database.NewOHLCVRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create repository: %w", err)
+	}
+	defer repo.Close()
+
+	// Same engine settings as the live stream so stored history is comparable
+	engine := enrichment.NewCandleEnrichmentEngine(nil)
+	if err := engine.SetCustomSignals(cfg.Enrichment.Signals); err != nil {
+		return fmt.Errorf("failed to load custom signals: %w", err)
+	}
+	if err := engine.SetSignalProfiles(cfg.Enrichment.Profiles); err != nil {
+		return fmt.Errorf("failed to load signal profiles: %w", err)
+	}
+	options := models.StreamEnrichmentOptions()
+	options.Indicators = cfg.Enrichment.IndicatorSpecs()
+	options.MultiTimeframe = cfg.Enrichment.MultiTimeframe
+	options.Benchmark = cfg.Enrichment.Benchmark
+	options.SignalProfile = cfg.Enrichment.SignalProfile
+	engine.SetHistorySource(repo)
+
+	// Interrupting keeps the progress of written batches
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	job := &backfill.Job{
+		Symbols:   symbols,
+		Timeframe: backfillTimeframe,
+		Start:     start,
+		End:       end,
+		Options:   options,
+		BatchSize: backfillBatchSize,
+		Workers:   backfillWorkers,
+		Restart:   backfillRestart,
+	}
+
+	fmt.Fprintf(os.Stderr, "Backfilling %d symbol(s) (%s) from %s to %s, config %s...\n",
+		len(symbols), backfillTimeframe, start.Format("2006-01-02"), end.Format("2006-01-02"),
+		engine.ConfigHash(options))
+
+	runner := backfill.NewRunner(repo, database.NewEnrichedRepository(db), engine)
+	results := runner.Run(ctx, job, func(progress backfill.Progress) {
+		if !progress.Done && progress.Err == nil {
+			fmt.Fprintf(os.Stderr, "  %s: %d candles, through %s\n",
+				progress.Symbol, progress.Processed, progress.Last.Format("2006-01-02 15:04"))
+		}
+	})
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	default:
+		displayBackfillTable(results)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d symbol(s) failed; run the same command again to resume", failed, len(results))
+	}
+	return nil
+}
+
+func displayBackfillTable(results []backfill.Progress) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SYMBOL\tSTATUS\tPROCESSED\tSKIPPED\tLAST")
+
+	for _, result := range results {
+		status := "done"
+		switch {
+		case result.Err != nil:
+			status = "failed: " + result.Err.Error()
+		case result.Resumed:
+			status = "done (resumed)"
+		}
+
+		last := "-"
+		if !result.Last.IsZero() {
+			last = result.Last.Format("2006-01-02 15:04")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", result.Symbol, status, result.Processed, result.Skipped, last)
+	}
+	w.Flush()
+}
@@ -16,7 +16,7 @@ import (
 	"github.com/ridopark/jonbu-ohlcv/internal/database"
 	"github.com/ridopark/jonbu-ohlcv/internal/fetcher/alpaca"
 	"github.com/ridopark/jonbu-ohlcv/internal/logger"
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-021: CLI for historical data fetching
@@ -33,6 +33,9 @@ func init() {
 	// Add subcommands
 	rootCmd.AddCommand(migrateCmd)
 	rootCmd.AddCommand(fetchCmd)
+	rootCmd.AddCommand(screenCmd)
+	rootCmd.AddCommand(backfillCmd)
+	rootCmd.AddCommand(patternStatsCmd)
 }
 
 func main() {
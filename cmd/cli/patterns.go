@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/analysis"
+	"github.com/ridopark/jonbu-ohlcv/internal/config"
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/enrichment"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/internal/outcomes"
+)
+
+// REQ-272: Pattern outcome statistics from stored history
+
+var (
+	patternStatsCmd = &cobra.Command{
+		Use:   "pattern-stats",
+		Short: "Measure the outcomes of patterns in stored candles",
+		Long: `Detect every candlestick and chart pattern in stored OHLCV candles of a date
+range and measure what followed each one over the next candles, e.g.
+
+  pattern-stats --symbols AAPL,MSFT --timeframe 1h --start 2023-01-01 --horizon 20
+
+A pattern succeeds when its target is reached before its stop within the
+horizon. Chart patterns bring their measured-move target and invalidation
+level; candlestick patterns get a target and a stop in average candle ranges.
+The hit rates, excursions and returns replace the stored statistics of each
+symbol and timeframe, and the server calibrates pattern signal confidence
+with them.`,
+		Args: cobra.NoArgs,
+		RunE: runPatternStats,
+	}
+
+	// Pattern stats command flags
+	patternSymbols      string
+	patternTimeframe    string
+	patternStart        string
+	patternEnd          string
+	patternHorizon      int
+	patternTargetRanges float64
+	patternStopRanges   float64
+)
+
+func init() {
+	patternStatsCmd.Flags().StringVar(&patternSymbols, "symbols", "", "comma-separated symbols to scan (required)")
+	patternStatsCmd.Flags().StringVar(&patternTimeframe, "timeframe", "1d", "stored timeframe (1m, 5m, 15m, 1h, 4h, 1d)")
+	patternStatsCmd.Flags().StringVar(&patternStart, "start", "", "start date (YYYY-MM-DD, required)")
+	patternStatsCmd.Flags().StringVar(&patternEnd, "end", "", "end date, inclusive (YYYY-MM-DD, default today)")
+	patternStatsCmd.Flags().IntVar(&patternHorizon, "horizon", outcomes.DefaultHorizon, "candles measured after each pattern")
+	patternStatsCmd.Flags().Float64Var(&patternTargetRanges, "target-ranges", outcomes.DefaultTargetRanges, "target of candlestick patterns in average candle ranges")
+	patternStatsCmd.Flags().Float64Var(&patternStopRanges, "stop-ranges", outcomes.DefaultStopRanges, "stop of candlestick patterns in average candle ranges")
+}
+
+func runPatternStats(cmd *cobra.Command, args []string) error {
+	// REQ-025: Input validation with helpful error messages
+	symbols, err := parseSymbolList(patternSymbols)
+	if err != nil {
+		return err
+	}
+
+	if err := validateStoredTimeframe(patternTimeframe); err != nil {
+		return err
+	}
+
+	start, err := validateDateString(patternStart)
+	if err != nil {
+		return fmt.Errorf("invalid start date '%s': %w", patternStart, err)
+	}
+
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	if patternEnd != "" {
+		end, err = validateDateString(patternEnd)
+		if err != nil {
+			return fmt.Errorf("invalid end date '%s': %w", patternEnd, err)
+		}
+	}
+	// The end date is inclusive
+	end = end.AddDate(0, 0, 1).Add(-time.Microsecond)
+
+	if start.After(end) {
+		return fmt.Errorf("start date must be before end date")
+	}
+
+	if patternHorizon < 1 || patternHorizon > 500 {
+		return fmt.Errorf("invalid horizon %d: must be between 1 and 500", patternHorizon)
+	}
+	if patternTargetRanges <= 0 || patternStopRanges <= 0 {
+		return fmt.Errorf("target and stop ranges must be positive")
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("format")
+	if err := validateOutputFormat(outputFormat); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	logger.InitLogger(cfg.LogLevel, cfg.Environment)
+
+	db, err := database.NewConnection(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	repo, err := database.NewOHLCVRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create repository: %w", err)
+	}
+	defer repo.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	job := &outcomes.Job{
+		Symbols:      symbols,
+		Timeframe:    patternTimeframe,
+		Start:        start,
+		End:          end,
+		Horizon:      patternHorizon,
+		TargetRanges: patternTargetRanges,
+		StopRanges:   patternStopRanges,
+	}
+
+	fmt.Fprintf(os.Stderr, "Measuring patterns of %d symbol(s) (%s) from %s to %s over %d candles...\n",
+		len(symbols), patternTimeframe, start.Format("2006-01-02"), end.Format("2006-01-02"), patternHorizon)
+
+	// Patterns are measured on the swings the enrichment engine detects
+	enrichmentConfig := enrichment.DefaultEnrichmentConfig()
+	swings := analysis.NewSwingDetector(enrichmentConfig.SwingMode, enrichmentConfig.SwingThreshold)
+	scanner := outcomes.NewScanner(repo, database.NewPatternStatsRepository(db), swings)
+	results := scanner.Run(ctx, job)
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return err
+		}
+	default:
+		displayPatternStatsTable(results)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d symbol(s) failed", failed, len(results))
+	}
+	return nil
+}
+
+func displayPatternStatsTable(results []outcomes.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SYMBOL\tPATTERN\tSIGNAL\tCOUNT\tHIT RATE\tTARGET\tSTOP\tEXPIRED\tAVG MFE\tAVG MAE\tAVG RETURN")
+
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(w, "%s\tfailed: %s\t\t\t\t\t\t\t\t\t\n", result.Symbol, result.Err.Error())
+			continue
+		}
+		for _, stat := range result.Stats {
+			fmt.Fprintf(w, "%s\t%s %s\t%s\t%d\t%.1f%%\t%d\t%d\t%d\t%.2f%%\t%.2f%%\t%+.2f%%\n",
+				result.Symbol, stat.PatternType, stat.PatternName, stat.Signal, stat.Occurrences,
+				stat.HitRate*100, stat.TargetHits, stat.StopHits, stat.Expired,
+				stat.AvgMaxFavorable, stat.AvgMaxAdverse, stat.AvgReturn)
+		}
+	}
+	w.Flush()
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/config"
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/enrichment"
+	"github.com/ridopark/jonbu-ohlcv/internal/expression"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/internal/screener"
+)
+
+// REQ-259: Screening stored candles with rule expressions
+
+var (
+	screenCmd = &cobra.Command{
+		Use:   "screen [expression]",
+		Short: "Screen symbols with a rule expression",
+		Long: `Evaluate a rule expression on the latest stored candle of each symbol, e.g.
+
+  screen "rsi(14) < 30 and close > sma(200) and rel_volume > 2" --symbols AAPL,MSFT
+
+Expressions combine comparisons with and, or and not. They can read registry
+indicators such as sma(200) or bbands(20,2).upper, the candle fields open, high,
+low, close, volume and rel_volume, text fields such as trend == "bullish", and
+detected patterns with pattern("hammer").`,
+		Args: cobra.ExactArgs(1),
+		RunE: runScreen,
+	}
+
+	// Screen command flags
+	screenSymbols   string
+	screenTimeframe string
+	screenAll       bool
+	screenProfile   string
+)
+
+func init() {
+	screenCmd.Flags().StringVar(&screenSymbols, "symbols", "", "comma-separated symbols to screen (required)")
+	screenCmd.Flags().StringVar(&screenTimeframe, "timeframe", "1d", "stored timeframe (1m, 5m, 15m, 1h, 4h, 1d)")
+	screenCmd.Flags().BoolVar(&screenAll, "all", false, "also list symbols that did not match")
+	screenCmd.Flags().StringVar(&screenProfile, "profile", "", "signal profile of the signal column (default profile if empty)")
+}
+
+func runScreen(cmd *cobra.Command, args []string) error {
+	// REQ-025: Input validation with helpful error messages
+	program, err := expression.Compile(args[0])
+	if err != nil {
+		var exprErr *expression.Error
+		if errors.As(err, &exprErr) {
+			return fmt.Errorf("invalid expression: %s\n%s", exprErr.Error(), exprErr.Context())
+		}
+		return fmt.Errorf("invalid expression: %w", err)
+	}
+
+	if err := validateStoredTimeframe(screenTimeframe); err != nil {
+		return err
+	}
+
+	symbols, err := parseSymbolList(screenSymbols)
+	if err != nil {
+		return err
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("format")
+	if err := validateOutputFormat(outputFormat); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	logger.InitLogger(cfg.LogLevel, cfg.Environment)
+
+	db, err := database.NewConnection(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	repo, err := database.NewOHLCVRepository(db)
+	if err != nil {
+		return fmt.Errorf("failed to create repository: %w", err)
+	}
+	defer repo.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// REQ-266: Configured signal profiles
+	engine := enrichment.NewCandleEnrichmentEngine(nil)
+	if err := engine.SetSignalProfiles(cfg.Enrichment.Profiles); err != nil {
+		return fmt.Errorf("failed to load signal profiles: %w", err)
+	}
+	if !engine.HasSignalProfile(screenProfile) {
+		return fmt.Errorf("unknown signal profile: %s (available: %s)", screenProfile,
+			strings.Join(engine.SignalProfileNames(), ", "))
+	}
+	results := screener.New(repo, engine).Screen(ctx, program, symbols, screenTimeframe, screenProfile)
+
+	shown := make([]screener.Result, 0, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipped %s: %v\n", result.Symbol, result.Err)
+			continue
+		}
+		if result.Matched || screenAll {
+			shown = append(shown, result)
+		}
+	}
+
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(shown)
+	case "csv":
+		return displayScreenCSV(shown)
+	default:
+		return displayScreenTable(program, shown, len(symbols))
+	}
+}
+
+// screenValueKeys returns the sorted indicator keys present in the results
+func screenValueKeys(results []screener.Result) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, result := range results {
+		for key := range result.Values {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// displayScreenCSV quotes fields since indicator keys like bbands(20,2).upper contain commas
+func displayScreenCSV(results []screener.Result) error {
+	keys := screenValueKeys(results)
+	writer := csv.NewWriter(os.Stdout)
+	writer.Write(append([]string{"Symbol", "Matched", "Timestamp", "Close", "Signal"}, keys...))
+
+	for _, result := range results {
+		row := []string{
+			result.Symbol,
+			fmt.Sprintf("%t", result.Matched),
+			result.Timestamp.Format("2006-01-02T15:04:05Z"),
+			fmt.Sprintf("%.4f", result.Close),
+			result.Signal,
+		}
+		for _, key := range keys {
+			row = append(row, formatScreenValue(result.Values, key))
+		}
+		writer.Write(row)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func displayScreenTable(program *expression.Program, results []screener.Result, scanned int) error {
+	fmt.Printf("Screen: %s\n", program.Source())
+
+	keys := screenValueKeys(results)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	header := append([]string{"SYMBOL", "MATCH", "TIMESTAMP", "CLOSE", "SIGNAL"}, keys...)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	matched := 0
+	for _, result := range results {
+		match := "no"
+		if result.Matched {
+			match = "yes"
+			matched++
+		}
+
+		row := []string{
+			result.Symbol,
+			match,
+			result.Timestamp.Format("2006-01-02 15:04"),
+			fmt.Sprintf("%.4f", result.Close),
+			result.Signal,
+		}
+		for _, key := range keys {
+			row = append(row, formatScreenValue(result.Values, key))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d of %d symbol(s) matched.\n", matched, scanned)
+
+	return nil
+}
+
+// formatScreenValue formats an indicator value, or "-" while it warms up
+func formatScreenValue(values map[string]float64, key string) string {
+	value, exists := values[key]
+	if !exists {
+		return "-"
+	}
+	return fmt.Sprintf("%.4f", value)
+}
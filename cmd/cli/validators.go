@@ -57,3 +57,32 @@ func validateOutputFormat(format string) error {
 
 	return nil
 }
+
+// validateStoredTimeframe validates a timeframe as stored in the database
+func validateStoredTimeframe(timeframe string) error {
+	switch timeframe {
+	case "1m", "5m", "15m", "1h", "4h", "1d":
+		return nil
+	default:
+		return fmt.Errorf("invalid timeframe: %s (valid: 1m, 5m, 15m, 1h, 4h, 1d)", timeframe)
+	}
+}
+
+// parseSymbolList parses and validates a comma-separated --symbols flag
+func parseSymbolList(list string) ([]string, error) {
+	var symbols []string
+	for _, symbol := range strings.Split(list, ",") {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" {
+			continue
+		}
+		if err := validateSymbol(symbol); err != nil {
+			return nil, fmt.Errorf("invalid symbol '%s': %w", symbol, err)
+		}
+		symbols = append(symbols, symbol)
+	}
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols given: use --symbols AAPL,MSFT")
+	}
+	return symbols, nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/alerts"
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/stream"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/handlers"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-253: Server-side alert rules evaluated against enriched candles
+
+// setupAlerts creates the alert engine and streams fired alerts to subscribers
+func (s *Server) setupAlerts() {
+	cfg := s.config.Alerts
+
+	s.alertRepo = database.NewAlertRepository(s.db)
+	notifier := alerts.NewWebhookNotifier(time.Duration(cfg.WebhookTimeout)*time.Second, cfg.WebhookRetries)
+	s.alertEngine = alerts.NewEngine(s.alertRepo, notifier)
+
+	hub := s.streamServer.GetHub()
+	s.alertEngine.OnAlert(func(alert *models.Alert) {
+		hub.BroadcastEvent(stream.EventAlert, alert.Symbol, alert.Timeframe, alert)
+	})
+
+	s.logger.Info().
+		Int("refresh_interval", cfg.RefreshInterval).
+		Msg("Alert engine enabled")
+}
+
+// registerAlertRoutes exposes alert rule management under the API router
+func (s *Server) registerAlertRoutes(apiRouter *mux.Router) {
+	if s.alertRepo == nil {
+		return
+	}
+
+	alertHandler := handlers.NewAlertHandler(s.alertRepo, s.alertEngine)
+	apiRouter.HandleFunc("/alerts", alertHandler.CreateRule).Methods("POST")
+	apiRouter.HandleFunc("/alerts", alertHandler.ListRules).Methods("GET")
+	apiRouter.HandleFunc("/alerts/{id:[0-9]+}", alertHandler.GetRule).Methods("GET")
+	apiRouter.HandleFunc("/alerts/{id:[0-9]+}", alertHandler.DeleteRule).Methods("DELETE")
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/stream"
+)
+
+// REQ-251: Multi-instance candle fan-out with leader-elected ingestion
+
+// Symbol control actions forwarded over the fan-out bus
+const (
+	symbolActionAdd    = "add_symbols"
+	symbolActionRemove = "remove_symbols"
+)
+
+// streamTimeframes are the timeframes aggregated for every tracked symbol
+var streamTimeframes = []string{"1min", "5min", "15min"}
+
+// symbolControl is the payload of a control message on the fan-out bus
+type symbolControl struct {
+	Action  string   `json:"action"`
+	Symbols []string `json:"symbols"`
+}
+
+// setupFanout wires the fan-out bus and leader election into the server
+func (s *Server) setupFanout() {
+	cfg := s.config.Fanout
+
+	s.instanceID = cfg.InstanceID
+	if s.instanceID == "" {
+		hostname, _ := os.Hostname()
+		s.instanceID = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	switch cfg.Backend {
+	case "local":
+		s.bus = stream.NewLocalBus()
+	default:
+		s.bus = stream.NewPostgresBus(
+			s.db.GetConnection(),
+			database.ConnectionString(s.config.Database),
+			cfg.Channel,
+			s.logger,
+		)
+	}
+
+	s.streamServer.GetHub().AttachBus(s.bus, s.instanceID)
+	s.bus.Subscribe(s.handleControlMessage)
+
+	s.elector = database.NewLeaderElector(s.db, cfg.LeaderLockKey, time.Duration(cfg.ElectionInterval)*time.Second)
+	s.elector.OnElected(s.startIngestion)
+	s.elector.OnDemoted(s.stopIngestion)
+
+	s.logger.Info().
+		Str("instance_id", s.instanceID).
+		Str("backend", cfg.Backend).
+		Str("channel", cfg.Channel).
+		Msg("Fan-out enabled")
+}
+
+// applySymbolChange adds or removes stream symbols. The change is stored so
+// that an instance elected later resumes it. With fan-out enabled the change
+// is published so every instance tracks it and the leader applies it.
+func (s *Server) applySymbolChange(action string, symbols []string) error {
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.storeSymbolChange(ctx, action, symbols); err != nil {
+		return err
+	}
+
+	if s.bus == nil {
+		return s.applySymbolChangeLocally(action, symbols)
+	}
+
+	payload, err := json.Marshal(symbolControl{Action: action, Symbols: symbols})
+	if err != nil {
+		return fmt.Errorf("failed to marshal symbol control: %w", err)
+	}
+
+	return s.bus.Publish(ctx, &stream.BusMessage{
+		Type:        stream.BusMessageControl,
+		Origin:      s.instanceID,
+		Payload:     payload,
+		PublishedAt: time.Now(),
+	})
+}
+
+// handleControlMessage applies symbol changes published by any instance
+func (s *Server) handleControlMessage(msg *stream.BusMessage) {
+	if msg.Type != stream.BusMessageControl {
+		return
+	}
+
+	var control symbolControl
+	if err := json.Unmarshal(msg.Payload, &control); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to decode symbol control message")
+		return
+	}
+
+	if !s.elector.IsLeader() {
+		// Followers only remember the symbols in case they are elected later
+		s.trackSymbols(control.Action, control.Symbols)
+		return
+	}
+
+	if err := s.applySymbolChangeLocally(control.Action, control.Symbols); err != nil {
+		s.logger.Error().Err(err).
+			Str("action", control.Action).
+			Strs("symbols", control.Symbols).
+			Str("origin", msg.Origin).
+			Msg("Failed to apply symbol control message")
+	}
+}
+
+// applySymbolChangeLocally updates the worker pool and Alpaca subscription
+func (s *Server) applySymbolChangeLocally(action string, symbols []string) error {
+	s.trackSymbols(action, symbols)
+
+	switch action {
+	case symbolActionAdd:
+		s.addSymbolWorkers(symbols)
+		// REQ-273: Added symbols get their stored-model regimes without
+		// waiting for the hourly refit
+		s.triggerRegimeSync()
+		return s.getAlpacaStream().Subscribe(symbols)
+
+	case symbolActionRemove:
+		for _, symbol := range symbols {
+			for _, timeframe := range streamTimeframes {
+				if err := s.workerPool.RemoveSymbol(symbol, timeframe); err != nil {
+					s.logger.Error().Err(err).
+						Str("symbol", symbol).
+						Str("timeframe", timeframe).
+						Msg("Failed to remove symbol worker")
+				}
+				// Drop indicator state so a later re-add warm-starts without a gap
+				s.streamStates.Remove(symbol, timeframe)
+				dbTimeframe := s.convertTimeframeForDB(timeframe)
+				s.enrichmentEngine.RemoveStream(symbol, dbTimeframe)
+				s.levels.Remove(symbol, dbTimeframe)
+				s.regimes.Remove(symbol, dbTimeframe)
+			}
+			// REQ-263: Untracked symbols leave the correlation matrix
+			s.correlations.Remove(symbol)
+		}
+		return s.getAlpacaStream().Unsubscribe(symbols)
+
+	default:
+		return fmt.Errorf("unknown symbol action: %s", action)
+	}
+}
+
+// addSymbolWorkers starts aggregation workers for each symbol and timeframe
+func (s *Server) addSymbolWorkers(symbols []string) {
+	for _, symbol := range symbols {
+		for _, timeframe := range streamTimeframes {
+			if err := s.workerPool.AddSymbol(symbol, timeframe); err != nil {
+				s.logger.Error().Err(err).
+					Str("symbol", symbol).
+					Str("timeframe", timeframe).
+					Msg("Failed to add symbol worker")
+			}
+		}
+	}
+}
+
+// storeSymbolChange persists a change of the tracked symbols
+func (s *Server) storeSymbolChange(ctx context.Context, action string, symbols []string) error {
+	switch action {
+	case symbolActionAdd:
+		return s.streamSymbols.Add(ctx, symbols)
+	case symbolActionRemove:
+		return s.streamSymbols.Remove(ctx, symbols)
+	default:
+		return fmt.Errorf("unknown symbol action: %s", action)
+	}
+}
+
+// reloadTrackedSymbols replaces the tracked symbols with the stored ones,
+// which include changes published before this instance was listening. The
+// symbols in memory are kept when the store is unavailable.
+func (s *Server) reloadTrackedSymbols() {
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	defer cancel()
+
+	symbols, err := s.streamSymbols.List(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to reload tracked symbols, keeping the known ones")
+		return
+	}
+
+	s.symbolsMu.Lock()
+	defer s.symbolsMu.Unlock()
+
+	s.trackedSymbols = make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		s.trackedSymbols[symbol] = true
+	}
+}
+
+// trackSymbols records the set of symbols requested for streaming
+func (s *Server) trackSymbols(action string, symbols []string) {
+	s.symbolsMu.Lock()
+	defer s.symbolsMu.Unlock()
+
+	for _, symbol := range symbols {
+		switch action {
+		case symbolActionAdd:
+			s.trackedSymbols[symbol] = true
+		case symbolActionRemove:
+			delete(s.trackedSymbols, symbol)
+		}
+	}
+}
+
+// getTrackedSymbols returns the tracked symbols in sorted order
+func (s *Server) getTrackedSymbols() []string {
+	s.symbolsMu.RLock()
+	defer s.symbolsMu.RUnlock()
+
+	symbols := make([]string, 0, len(s.trackedSymbols))
+	for symbol := range s.trackedSymbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
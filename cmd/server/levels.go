@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/stream"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/handlers"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-274: Support and resistance levels tracked on the live stream
+
+// levelWriteBuffer bounds the level snapshots waiting to be stored
+const levelWriteBuffer = 1000
+
+// levelWrite is a snapshot of the levels of a stream to store
+type levelWrite struct {
+	symbol    string
+	timeframe string
+	levels    []*models.TrackedLevel
+}
+
+// registerLevelRoutes exposes the tracked levels under the API router
+func (s *Server) registerLevelRoutes(apiRouter *mux.Router) {
+	levelHandler := handlers.NewLevelHandler(s.levelRepo)
+	apiRouter.HandleFunc("/levels/{symbol}", levelHandler.GetLevels).Methods("GET")
+}
+
+// restoreLevels resumes the stored levels of a warm-started stream
+func (s *Server) restoreLevels(symbol, dbTimeframe string) {
+	ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+	defer cancel()
+
+	levels, err := s.levelRepo.List(ctx, symbol, dbTimeframe)
+	if err != nil {
+		s.logger.Error().Err(err).Str("symbol", symbol).Str("timeframe", dbTimeframe).Msg("Failed to load stored levels")
+		return
+	}
+	s.levels.Restore(symbol, dbTimeframe, levels)
+}
+
+// trackLevels applies an enriched candle to the levels of its stream, streams
+// the resulting tests and breaks and queues changed levels for storage
+func (s *Server) trackLevels(hub *stream.Hub, candle *models.Candle, enriched *models.EnrichedCandle) {
+	update := s.levels.Observe(enriched)
+
+	for _, event := range update.Events {
+		eventType := stream.EventLevelTest
+		if event.Type == models.LevelBreak {
+			eventType = stream.EventLevelBreak
+		}
+		hub.BroadcastEvent(eventType, candle.Symbol, candle.Interval, event)
+	}
+
+	if !update.Changed {
+		return
+	}
+	select {
+	case s.levelWrites <- levelWrite{symbol: candle.Symbol, timeframe: enriched.OHLCV.Timeframe, levels: update.Levels}:
+	default:
+		s.logger.Warn().Str("symbol", candle.Symbol).Str("timeframe", candle.Interval).Msg("Level storage queue full, dropping snapshot")
+	}
+}
+
+// runLevelWriter stores level snapshots in the order they changed until the
+// server stops
+func (s *Server) runLevelWriter() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case write := <-s.levelWrites:
+			ctx, cancel := context.WithTimeout(s.ctx, 5*time.Second)
+			if err := s.levelRepo.ReplaceLevels(ctx, write.symbol, write.timeframe, write.levels); err != nil {
+				s.logger.Error().Err(err).
+					Str("symbol", write.symbol).
+					Str("timeframe", write.timeframe).
+					Msg("Failed to store levels")
+			}
+			cancel()
+		}
+	}
+}
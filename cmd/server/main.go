@@ -7,27 +7,42 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog"
 
+	"github.com/ridopark/jonbu-ohlcv/internal/alerts"
 	"github.com/ridopark/jonbu-ohlcv/internal/config"
+	"github.com/ridopark/jonbu-ohlcv/internal/correlation"
 	"github.com/ridopark/jonbu-ohlcv/internal/database"
 	"github.com/ridopark/jonbu-ohlcv/internal/enrichment"
 	"github.com/ridopark/jonbu-ohlcv/internal/fetcher/alpaca"
+	"github.com/ridopark/jonbu-ohlcv/internal/indicators"
 	"github.com/ridopark/jonbu-ohlcv/internal/logger"
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/internal/screener"
 	"github.com/ridopark/jonbu-ohlcv/internal/stream"
 	"github.com/ridopark/jonbu-ohlcv/internal/worker"
 	"github.com/ridopark/jonbu-ohlcv/pkg/api/handlers"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-016: HTTP server for API endpoints
 // REQ-017: WebSocket server for real-time streaming
 // REQ-026: Graceful shutdown handling
 // REQ-031: High-performance event processing pipeline
+// REQ-251: Multi-instance candle fan-out with leader-elected ingestion
+// REQ-253: Server-side alert rules
+// REQ-254: Incremental per-stream indicator state
+// REQ-260: Persisted enriched candle history
+// REQ-262: Multi-timeframe confluence
+// REQ-263: Benchmark comparison and live correlation matrix
+
+// correlationWindow is the number of candles kept per series for the live
+// correlation matrix
+const correlationWindow = 1000
 
 // Server represents the main application server
 type Server struct {
@@ -39,8 +54,41 @@ type Server struct {
 	// Streaming components
 	streamServer     *stream.Server
 	workerPool       *worker.Pool
+	streamFactory    *alpaca.StreamClientFactory
 	alpacaStream     alpaca.StreamInterface
 	enrichmentEngine *enrichment.CandleEnrichmentEngine
+	streamStates     *enrichment.StreamStateManager
+	correlations     *correlation.Tracker
+
+	// Fan-out components (nil when running as a single instance)
+	bus        stream.Bus
+	elector    *database.LeaderElector
+	instanceID string
+
+	// Alerting components (nil when alerts are disabled)
+	alertRepo   *database.AlertRepository
+	alertEngine *alerts.Engine
+
+	// REQ-265: VWAP anchors maintained on the stream states
+	vwapAnchors *database.VWAPAnchorRepository
+	anchorSync  chan struct{}
+
+	// REQ-274: Support and resistance levels tracked per stream
+	levelRepo   *database.LevelRepository
+	levels      *enrichment.LevelTracker
+	levelWrites chan levelWrite
+
+	// REQ-273: Volatility regimes of the streams and model refit requests
+	regimes    *enrichment.RegimeTracker
+	regimeSync chan struct{}
+
+	// Ingestion state
+	ingesting      bool
+	ingestMu       sync.RWMutex
+	pipelineOnce   sync.Once
+	trackedSymbols map[string]bool
+	symbolsMu      sync.RWMutex
+	streamSymbols  *database.StreamSymbolRepository
 
 	// HTTP server
 	httpServer *http.Server
@@ -124,19 +172,54 @@ func initializeServer() (*Server, error) {
 	enrichmentConfig := enrichment.DefaultEnrichmentConfig()
 	enrichmentEngine := enrichment.NewCandleEnrichmentEngine(enrichmentConfig)
 
+	// REQ-259: Custom expression signals
+	if err := enrichmentEngine.SetCustomSignals(cfg.Enrichment.Signals); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to load custom signals: %w", err)
+	}
+
+	// REQ-266: Signal profiles selectable per subscription and request
+	if err := enrichmentEngine.SetSignalProfiles(cfg.Enrichment.Profiles); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to load signal profiles: %w", err)
+	}
+	streamServer.GetHub().SetSignalProfiles(enrichmentEngine.SignalProfileNames())
+
 	server := &Server{
 		config:           cfg,
 		logger:           appLogger,
 		db:               db,
 		streamServer:     streamServer,
 		workerPool:       workerPool,
+		streamFactory:    streamFactory,
 		alpacaStream:     alpacaStream,
 		enrichmentEngine: enrichmentEngine,
+		streamStates:     enrichment.NewStreamStateManager(enrichmentConfig.MaxHistoryPeriods, enrichmentConfig.WarmStartPeriods),
+		correlations:     correlation.NewTracker(correlationWindow),
+		trackedSymbols:   make(map[string]bool),
+		streamSymbols:    database.NewStreamSymbolRepository(db),
+		vwapAnchors:      database.NewVWAPAnchorRepository(db),
+		anchorSync:       make(chan struct{}, 1),
+		levelRepo:        database.NewLevelRepository(db),
+		levels:           enrichment.NewLevelTracker(enrichmentEngine),
+		levelWrites:      make(chan levelWrite, levelWriteBuffer),
+		regimes:          enrichment.NewRegimeTracker(),
+		regimeSync:       make(chan struct{}, 1),
 		router:           router,
 		ctx:              ctx,
 		cancel:           cancel,
 	}
 
+	// REQ-251: Optional multi-instance fan-out
+	if cfg.Fanout.Enabled {
+		server.setupFanout()
+	}
+
+	// REQ-253: Server-side alert rules
+	if cfg.Alerts.Enabled {
+		server.setupAlerts()
+	}
+
 	// Setup routes
 	server.setupRoutes()
 
@@ -199,6 +282,40 @@ func (s *Server) setupRoutes() {
 	apiRouter.HandleFunc("/ohlcv/{symbol}", ohlcvHandler.GetOHLCV).Methods("GET")
 	apiRouter.HandleFunc("/ohlcv/{symbol}/history", ohlcvHandler.GetOHLCVHistory).Methods("GET")
 
+	// REQ-257: Indicator series endpoint
+	indicatorHandler := handlers.NewIndicatorHandler(repo)
+	apiRouter.HandleFunc("/indicators/{symbol}", indicatorHandler.GetSeries).Methods("GET")
+
+	// REQ-264: Volume profiles of stored bars
+	volumeProfileHandler := handlers.NewVolumeProfileHandler(repo)
+	apiRouter.HandleFunc("/volume-profile/{symbol}", volumeProfileHandler.GetVolumeProfile).Methods("GET")
+
+	// REQ-265: Anchored VWAP management
+	s.registerVWAPRoutes(apiRouter, repo)
+
+	// REQ-263: Correlation matrix across tracked symbols
+	correlationHandler := handlers.NewCorrelationHandler(s.correlations, repo, s.getTrackedSymbols)
+	apiRouter.HandleFunc("/correlation", correlationHandler.GetCorrelation).Methods("GET")
+
+	// REQ-259: Screening tracked symbols with rule expressions
+	screenHandler := handlers.NewScreenHandler(screener.New(repo, s.enrichmentEngine), s.getTrackedSymbols)
+	apiRouter.HandleFunc("/screen", screenHandler.Screen).Methods("POST")
+
+	// REQ-266: Available signal profiles
+	signalProfileHandler := handlers.NewSignalProfileHandler(s.enrichmentEngine.SignalProfiles)
+	apiRouter.HandleFunc("/signal-profiles", signalProfileHandler.ListProfiles).Methods("GET")
+
+	// REQ-260: Stored enriched candle history
+	enrichedHandler := handlers.NewEnrichedHandler(database.NewEnrichedRepository(s.db))
+	apiRouter.HandleFunc("/enriched/{symbol}", enrichedHandler.GetEnriched).Methods("GET")
+
+	// REQ-272: Pattern outcome statistics
+	patternStatsHandler := handlers.NewPatternStatsHandler(database.NewPatternStatsRepository(s.db))
+	apiRouter.HandleFunc("/patterns/stats", patternStatsHandler.GetStats).Methods("GET")
+
+	// REQ-274: Tracked support and resistance levels
+	s.registerLevelRoutes(apiRouter)
+
 	// Stream management endpoints
 	apiRouter.HandleFunc("/stream/symbols", s.handleAddSymbols).Methods("POST")
 	apiRouter.HandleFunc("/stream/symbols/{symbol}", s.handleRemoveSymbol).Methods("DELETE")
@@ -207,6 +324,14 @@ func (s *Server) setupRoutes() {
 	// Worker pool metrics
 	apiRouter.HandleFunc("/workers/metrics", s.handleWorkerMetrics).Methods("GET")
 
+	// REQ-269: Enrichment latency metrics, also scraped by Prometheus
+	enrichmentMetricsHandler := handlers.NewEnrichmentMetricsHandler(s.enrichmentEngine)
+	apiRouter.HandleFunc("/enrichment/metrics", enrichmentMetricsHandler.GetMetrics).Methods("GET")
+	s.router.HandleFunc("/metrics", enrichmentMetricsHandler.WritePrometheus).Methods("GET")
+
+	// Alert rule endpoints
+	s.registerAlertRoutes(apiRouter)
+
 	// Register WebSocket routes
 	s.streamServer.RegisterRoutes(s.router)
 
@@ -223,15 +348,20 @@ func (s *Server) Start() error {
 	s.streamServer.Start()
 	s.workerPool.Start()
 
-	// Start Alpaca stream
-	if err := s.alpacaStream.Start(); err != nil {
-		s.logger.Error().Err(err).Msg("Failed to start Alpaca stream - continuing without streaming")
-		// Don't fail server startup, just log the error
-	} else {
-		s.logger.Info().Msg("Alpaca stream started successfully")
+	if s.alertEngine != nil {
+		// Periodic reloads pick up rules created through other instances
+		go s.alertEngine.Run(s.ctx, time.Duration(s.config.Alerts.RefreshInterval)*time.Second)
+	}
 
-		// Connect data pipeline: Alpaca → Worker Pool → WebSocket Hub
-		go s.runDataPipeline()
+	if s.bus != nil {
+		// REQ-251: Only the elected leader ingests; every instance delivers
+		if err := s.bus.Start(s.ctx); err != nil {
+			return fmt.Errorf("failed to start fan-out bus: %w", err)
+		}
+		go s.elector.Run(s.ctx)
+	} else if err := s.startIngestion(); err != nil {
+		// Don't fail server startup, just log the error
+		s.logger.Error().Err(err).Msg("Failed to start Alpaca stream - continuing without streaming")
 	}
 
 	// Start HTTP server
@@ -245,7 +375,75 @@ func (s *Server) Start() error {
 	return nil
 }
 
-// runDataPipeline connects the data flow from Alpaca to WebSocket clients
+// startIngestion starts the Alpaca stream and connects it to the data pipeline
+func (s *Server) startIngestion() error {
+	s.ingestMu.Lock()
+	defer s.ingestMu.Unlock()
+
+	if s.ingesting {
+		return nil
+	}
+
+	// Start Alpaca stream
+	if err := s.alpacaStream.Start(); err != nil {
+		return fmt.Errorf("failed to start Alpaca stream: %w", err)
+	}
+	s.ingesting = true
+	s.logger.Info().Msg("Alpaca stream started successfully")
+
+	// Stream events from Alpaca to worker pool
+	go func(output <-chan models.MarketEvent) {
+		for event := range output {
+			s.workerPool.ProcessEvent(event)
+		}
+	}(s.alpacaStream.GetOutput())
+
+	// Resubscribe symbols requested before this instance became the ingester
+	s.reloadTrackedSymbols()
+	if symbols := s.getTrackedSymbols(); len(symbols) > 0 {
+		s.addSymbolWorkers(symbols)
+		if err := s.alpacaStream.Subscribe(symbols); err != nil {
+			s.logger.Error().Err(err).Strs("symbols", symbols).Msg("Failed to resubscribe tracked symbols")
+		}
+	}
+
+	// Connect data pipeline: Alpaca → Worker Pool → WebSocket Hub
+	s.pipelineOnce.Do(s.runDataPipeline)
+	return nil
+}
+
+// stopIngestion stops the Alpaca stream after this instance lost leadership
+func (s *Server) stopIngestion() {
+	s.ingestMu.Lock()
+	defer s.ingestMu.Unlock()
+
+	if !s.ingesting {
+		return
+	}
+
+	s.alpacaStream.Stop()
+	s.ingesting = false
+
+	// Stream clients cannot be restarted, prepare a fresh one for re-election
+	s.alpacaStream = s.streamFactory.Create(
+		s.config.Alpaca.APIKey,
+		s.config.Alpaca.SecretKey,
+		s.config.Alpaca.BaseURL,
+		s.logger,
+	)
+
+	s.logger.Warn().Msg("Alpaca ingestion stopped")
+}
+
+// getAlpacaStream returns the current Alpaca stream client
+func (s *Server) getAlpacaStream() alpaca.StreamInterface {
+	s.ingestMu.RLock()
+	defer s.ingestMu.RUnlock()
+
+	return s.alpacaStream
+}
+
+// runDataPipeline connects the data flow from the worker pool to WebSocket clients
 func (s *Server) runDataPipeline() {
 	s.logger.Info().Msg("Starting data pipeline")
 
@@ -256,19 +454,35 @@ func (s *Server) runDataPipeline() {
 		return
 	}
 
-	// Stream events from Alpaca to worker pool
-	go func() {
-		for event := range s.alpacaStream.GetOutput() {
-			s.workerPool.ProcessEvent(event)
-		}
-	}()
+	// REQ-262, REQ-263: Higher timeframes and benchmarks come from stored candles
+	s.enrichmentEngine.SetHistorySource(repo)
+
+	// REQ-265: Anchored VWAPs follow the stored anchors
+	go s.runAnchorSync(repo)
+
+	// REQ-272: Pattern signals are calibrated with the stored outcomes
+	go s.runPatternStatsSync()
+
+	// REQ-273: Volatility regimes come from models fit on stored returns
+	go s.runRegimeModelSync(repo)
+
+	// REQ-274: Level changes are stored in order by a single writer
+	go s.runLevelWriter()
+
+	// REQ-260: Enriched candles are stored with the hash of the stream options
+	var enrichedRepo *database.EnrichedRepository
+	var configHash string
+	if s.config.Enrichment.Persist {
+		enrichedRepo = database.NewEnrichedRepository(s.db)
+		configHash = s.enrichmentEngine.ConfigHash(s.streamEnrichmentOptions())
+	}
 
 	// Stream candles from worker pool to WebSocket clients AND database
 	go func() {
 		hub := s.streamServer.GetHub()
 		for candle := range s.workerPool.GetCandleOutput() {
 			// Enrich the candle with technical indicators
-			enrichedCandle, err := s.enrichCandle(&candle)
+			enrichedCandle, err := s.enrichCandle(repo, &candle)
 			if err != nil {
 				s.logger.Error().Err(err).
 					Str("symbol", candle.Symbol).
@@ -280,8 +494,33 @@ func (s *Server) runDataPipeline() {
 			} else {
 				// Broadcast enriched candle to WebSocket clients
 				hub.BroadcastEnrichedCandle(candle.Symbol, candle.Interval, enrichedCandle)
+
+				// REQ-273: Volatility regime shifts are streamed as events
+				if change := s.regimes.Observe(enrichedCandle); change != nil {
+					hub.BroadcastEvent(stream.EventRegimeChange, candle.Symbol, candle.Interval, change)
+				}
+
+				// REQ-274: Level tests and breaks are streamed as events
+				s.trackLevels(hub, &candle, enrichedCandle)
+
+				// REQ-253: Evaluate alert rules against the enriched candle
+				if s.alertEngine != nil {
+					s.alertEngine.Evaluate(candle.Interval, enrichedCandle)
+				}
+
+				if enrichedRepo != nil {
+					go s.storeEnrichedCandle(enrichedRepo, &candle, enrichedCandle, configHash)
+				}
 			}
 
+			// REQ-263: Live correlation across the tracked universe
+			s.correlations.Update(&models.OHLCV{
+				Symbol:    candle.Symbol,
+				Timeframe: s.convertTimeframeForDB(candle.Interval),
+				Timestamp: candle.Timestamp,
+				Close:     candle.Close,
+			})
+
 			// Store basic candle in database for historical data
 			go s.storeCandleToDatabase(repo, &candle)
 		}
@@ -306,8 +545,16 @@ func (s *Server) WaitForShutdown() {
 		s.logger.Error().Err(err).Msg("HTTP server shutdown error")
 	}
 
+	// Stop fan-out components and release leadership
+	s.cancel()
+	if s.bus != nil {
+		if err := s.bus.Close(); err != nil {
+			s.logger.Error().Err(err).Msg("Fan-out bus close error")
+		}
+	}
+
 	// Stop streaming components
-	s.alpacaStream.Stop()
+	s.getAlpacaStream().Stop()
 	s.workerPool.Stop()
 	s.streamServer.Stop()
 
@@ -359,6 +606,29 @@ func (s *Server) storeCandleToDatabase(repo *database.OHLCVRepository, candle *m
 	}
 }
 
+// storeEnrichedCandle persists an enriched candle for historical queries
+func (s *Server) storeEnrichedCandle(repo *database.EnrichedRepository, candle *models.Candle, enriched *models.EnrichedCandle, configHash string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	record := &models.EnrichedCandleRecord{
+		Symbol:        candle.Symbol,
+		Timeframe:     s.convertTimeframeForDB(candle.Interval),
+		Timestamp:     candle.Timestamp,
+		EngineVersion: enrichment.EngineVersion,
+		ConfigHash:    configHash,
+		Candle:        enriched,
+	}
+
+	if err := repo.Upsert(ctx, record); err != nil {
+		s.logger.Error().Err(err).
+			Str("symbol", candle.Symbol).
+			Str("timeframe", record.Timeframe).
+			Time("timestamp", candle.Timestamp).
+			Msg("Failed to store enriched candle to database")
+	}
+}
+
 // convertTimeframeForDB converts internal timeframe format to database format
 func (s *Server) convertTimeframeForDB(timeframe string) string {
 	switch timeframe {
@@ -384,28 +654,22 @@ func (s *Server) convertTimeframeForDB(timeframe string) string {
 	}
 }
 
-// enrichCandle adds technical indicators to a basic candle
-func (s *Server) enrichCandle(candle *models.Candle) (*models.EnrichedCandle, error) {
-	// Create context with timeout for enrichment
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
-	defer cancel()
-
-	// Get historical data needed for indicators (up to 50 candles for proper calculation)
-	repo, err := database.NewOHLCVRepository(s.db)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create repository: %w", err)
-	}
-
+// enrichCandle adds technical indicators to a basic candle using the
+// incremental state of its stream, warm-started from stored history once
+func (s *Server) enrichCandle(repo *database.OHLCVRepository, candle *models.Candle) (*models.EnrichedCandle, error) {
 	// Convert timeframe for database query
 	dbTimeframe := s.convertTimeframeForDB(candle.Interval)
 
-	// Get recent historical candles
-	ohlcvList, err := repo.GetBySymbol(ctx, candle.Symbol, dbTimeframe, 50)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get historical data: %w", err)
+	streamState := s.streamStates.Get(candle.Symbol, candle.Interval)
+	if streamState == nil {
+		var err error
+		streamState, err = s.warmStartStream(repo, candle.Symbol, candle.Interval, dbTimeframe)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Convert current candle to OHLCV format and add to list
+	// Convert current candle to OHLCV format and apply it to the stream state
 	currentOHLCV := &models.OHLCV{
 		Symbol:    candle.Symbol,
 		Timestamp: candle.Timestamp,
@@ -417,26 +681,94 @@ func (s *Server) enrichCandle(candle *models.Candle) (*models.EnrichedCandle, er
 		Timeframe: dbTimeframe,
 	}
 
-	// Append current candle to historical data
-	allCandles := append(ohlcvList, currentOHLCV)
+	if !streamState.Update(currentOHLCV) {
+		// Already part of the warm-start history or replayed out of order
+		if last := streamState.Last(); last == nil || !last.Timestamp.Equal(currentOHLCV.Timestamp) {
+			return nil, fmt.Errorf("out-of-order candle at %s", candle.Timestamp.Format(time.RFC3339))
+		}
+	}
 
-	// Use fast enrichment for real-time streaming to maintain performance
-	enrichmentOptions := models.FastEnrichmentOptions()
-	// Enable trend indicators for SMA and EMA display
-	enrichmentOptions.TrendIndicators = true
-	// Enable support/resistance for enhanced chart analysis
-	enrichmentOptions.SupportResistance = true
-	// Enable volatility indicators for ATR, Bollinger Bands, etc.
-	enrichmentOptions.VolatilityIndicators = true
+	// Create context with timeout for enrichment
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
 
-	enrichedCandle, err := s.enrichmentEngine.EnrichCandle(ctx, currentOHLCV, allCandles, enrichmentOptions)
+	enrichedCandle, err := s.enrichmentEngine.EnrichStream(ctx, streamState, s.streamEnrichmentOptions())
 	if err != nil {
 		return nil, fmt.Errorf("enrichment failed: %w", err)
 	}
 
+	// REQ-268: Components cut off by the budget are left out, not the candle
+	if enrichedCandle.Metadata != nil && enrichedCandle.Metadata.Partial {
+		s.logger.Debug().
+			Str("symbol", candle.Symbol).
+			Str("timeframe", candle.Interval).
+			Strs("skipped_components", enrichedCandle.Metadata.SkippedComponents).
+			Strs("failed_components", enrichedCandle.Metadata.FailedComponents).
+			Msg("Partial candle enrichment")
+	}
+
 	return enrichedCandle, nil
 }
 
+// streamEnrichmentOptions returns the enrichment options of streamed candles
+func (s *Server) streamEnrichmentOptions() *models.EnrichmentOptions {
+	// Fast enrichment plus trend, volatility and support/resistance for charting
+	enrichmentOptions := models.StreamEnrichmentOptions()
+	// REQ-255: Configured registry indicators, keyed by spec
+	enrichmentOptions.Indicators = s.config.Enrichment.IndicatorSpecs()
+	// REQ-262: Optional higher timeframe confluence
+	enrichmentOptions.MultiTimeframe = s.config.Enrichment.MultiTimeframe
+	// REQ-263: Relative strength, beta and correlation vs the benchmark
+	enrichmentOptions.Benchmark = s.config.Enrichment.Benchmark
+	// REQ-266: Configured profile, plus every profile clients may subscribe with
+	enrichmentOptions.SignalProfile = s.config.Enrichment.SignalProfile
+	enrichmentOptions.SignalProfiles = s.enrichmentEngine.SignalProfileNames()
+
+	return enrichmentOptions
+}
+
+// warmStartStream loads stored history once and builds the stream's indicator state
+func (s *Server) warmStartStream(repo *database.OHLCVRepository, symbol, timeframe, dbTimeframe string) (*enrichment.StreamState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	periods := s.enrichmentEngine.Config().WarmStartPeriods
+	ohlcvList, err := repo.GetBySymbol(ctx, symbol, dbTimeframe, periods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get historical data: %w", err)
+	}
+
+	// Stored candles come newest first; indicator state needs chronological order
+	for i, j := 0, len(ohlcvList)-1; i < j; i, j = i+1, j-1 {
+		ohlcvList[i], ohlcvList[j] = ohlcvList[j], ohlcvList[i]
+	}
+
+	// REQ-265: The session VWAP needs every candle since the session start
+	if len(ohlcvList) > 0 {
+		first := ohlcvList[0].Timestamp
+		sessionStart := indicators.SessionVWAPStart(ohlcvList[len(ohlcvList)-1].Timestamp)
+		if first.After(sessionStart) {
+			earlier, err := repo.GetHistory(ctx, symbol, dbTimeframe, sessionStart, first.Add(-time.Microsecond), maxAnchorCandles)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get session history: %w", err)
+			}
+			ohlcvList = append(earlier, ohlcvList...)
+		}
+	}
+
+	streamState := s.streamStates.WarmStart(symbol, timeframe, ohlcvList)
+	s.triggerAnchorSync()
+	s.restoreLevels(symbol, dbTimeframe)
+
+	s.logger.Info().
+		Str("symbol", symbol).
+		Str("timeframe", timeframe).
+		Int("candles", len(ohlcvList)).
+		Msg("Warm-started stream indicator state")
+
+	return streamState, nil
+}
+
 // HTTP Handlers
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
@@ -460,7 +792,13 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	// Add component status
 	status["stream_server"] = "running"
 	status["worker_pool"] = s.workerPool.GetStatus()
-	status["alpaca_stream"] = s.alpacaStream.GetConnectionStatus()
+	status["alpaca_stream"] = s.getAlpacaStream().GetConnectionStatus()
+	if s.elector != nil {
+		status["fanout"] = map[string]interface{}{
+			"instance_id": s.instanceID,
+			"leader":      s.elector.IsLeader(),
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(status)
@@ -483,21 +821,8 @@ func (s *Server) handleAddSymbols(w http.ResponseWriter, r *http.Request) {
 		request.Symbols = []string{"AAPL", "GOOGL", "MSFT"}
 	}
 
-	// Add symbols to worker pool
-	for _, symbol := range request.Symbols {
-		timeframes := []string{"1min", "5min", "15min"}
-		for _, timeframe := range timeframes {
-			if err := s.workerPool.AddSymbol(symbol, timeframe); err != nil {
-				s.logger.Error().Err(err).
-					Str("symbol", symbol).
-					Str("timeframe", timeframe).
-					Msg("Failed to add symbol worker")
-			}
-		}
-	}
-
-	// Subscribe to Alpaca stream
-	if err := s.alpacaStream.Subscribe(request.Symbols); err != nil {
+	// Subscribe to Alpaca stream (forwarded to the ingesting instance when fanned out)
+	if err := s.applySymbolChange(symbolActionAdd, request.Symbols); err != nil {
 		s.logger.Error().Err(err).Msg("Failed to subscribe to Alpaca stream")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -525,19 +850,8 @@ func (s *Server) handleRemoveSymbol(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	symbol := vars["symbol"]
 
-	// Remove from worker pool
-	timeframes := []string{"1min", "5min", "15min"}
-	for _, timeframe := range timeframes {
-		if err := s.workerPool.RemoveSymbol(symbol, timeframe); err != nil {
-			s.logger.Error().Err(err).
-				Str("symbol", symbol).
-				Str("timeframe", timeframe).
-				Msg("Failed to remove symbol worker")
-		}
-	}
-
-	// Unsubscribe from Alpaca stream
-	if err := s.alpacaStream.Unsubscribe([]string{symbol}); err != nil {
+	// Unsubscribe from Alpaca stream (forwarded to the ingesting instance when fanned out)
+	if err := s.applySymbolChange(symbolActionRemove, []string{symbol}); err != nil {
 		s.logger.Error().Err(err).Msg("Failed to unsubscribe from Alpaca stream")
 	}
 
@@ -554,7 +868,7 @@ func (s *Server) handleStreamStatus(w http.ResponseWriter, r *http.Request) {
 		"messages_sent":        messageCount,
 		"active_subscriptions": subscriptionCount,
 		"worker_pool":          s.workerPool.GetMetrics(),
-		"alpaca_connection":    s.alpacaStream.GetConnectionStatus(),
+		"alpaca_connection":    s.getAlpacaStream().GetConnectionStatus(),
 		"status":               "active",
 	}
 
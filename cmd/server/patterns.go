@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+)
+
+// REQ-272: Pattern signals calibrated with stored pattern outcomes
+
+// patternStatsSyncInterval picks up statistics of new pattern outcome scans
+const patternStatsSyncInterval = 15 * time.Minute
+
+// runPatternStatsSync loads the stored pattern statistics into the enrichment
+// engine until the server stops
+func (s *Server) runPatternStatsSync() {
+	repo := database.NewPatternStatsRepository(s.db)
+	ticker := time.NewTicker(patternStatsSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+		stats, err := repo.List(ctx, "", "", "")
+		cancel()
+
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to load pattern stats")
+		} else {
+			s.enrichmentEngine.SetPatternStats(stats)
+			s.logger.Debug().Int("patterns", len(stats)).Msg("Pattern stats loaded")
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/analysis"
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/enrichment"
+)
+
+// REQ-273: Volatility regime models fit on stored returns per symbol
+
+const (
+	// regimeModelInterval refits the models with the candles stored since
+	regimeModelInterval = time.Hour
+
+	// regimeModelCandles bounds the stored candles a model is fit on
+	regimeModelCandles = 2000
+)
+
+// triggerRegimeSync requests a refit of the regime models without waiting for it
+func (s *Server) triggerRegimeSync() {
+	select {
+	case s.regimeSync <- struct{}{}:
+	default:
+	}
+}
+
+// runRegimeModelSync fits the volatility regime model of every tracked
+// stream on its stored candles hourly and on request until the server stops
+func (s *Server) runRegimeModelSync(repo *database.OHLCVRepository) {
+	if !s.enrichmentEngine.Config().EnableMarketRegime {
+		return
+	}
+
+	ticker := time.NewTicker(regimeModelInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, symbol := range s.getTrackedSymbols() {
+			for _, timeframe := range streamTimeframes {
+				s.fitRegimeModel(repo, symbol, s.convertTimeframeForDB(timeframe))
+			}
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.regimeSync:
+		}
+	}
+}
+
+// fitRegimeModel fits the model of a symbol and stored timeframe; streams
+// without enough stored candles keep fitting on their window
+func (s *Server) fitRegimeModel(repo *database.OHLCVRepository, symbol, dbTimeframe string) {
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	candles, err := repo.GetBySymbol(ctx, symbol, dbTimeframe, regimeModelCandles)
+	cancel()
+	if err != nil {
+		s.logger.Error().Err(err).Str("symbol", symbol).Str("timeframe", dbTimeframe).Msg("Failed to load candles for regime model")
+		return
+	}
+
+	// Stored candles come newest first
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[len(candles)-1-i] = candle.Close
+	}
+
+	returns := analysis.LogReturns(closes)
+	if len(returns) < analysis.MinRegimeReturns {
+		return
+	}
+	model, err := analysis.FitVolatilityHMM(returns, enrichment.RegimeStates)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("symbol", symbol).Str("timeframe", dbTimeframe).Msg("Failed to fit regime model")
+		return
+	}
+
+	s.enrichmentEngine.SetRegimeModel(symbol, dbTimeframe, model)
+	s.logger.Debug().
+		Str("symbol", symbol).
+		Str("timeframe", dbTimeframe).
+		Int("returns", model.Samples).
+		Int("iterations", model.Iterations).
+		Msg("Regime model fit")
+}
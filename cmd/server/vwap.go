@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/enrichment"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/handlers"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-265: Anchored VWAPs maintained on the live stream
+
+const (
+	// anchorSyncInterval picks up anchors created through other instances
+	anchorSyncInterval = time.Minute
+
+	// maxAnchorCandles bounds the stored candles replayed into an anchored VWAP
+	maxAnchorCandles = 100000
+)
+
+// registerVWAPRoutes exposes VWAP anchor management under the API router
+func (s *Server) registerVWAPRoutes(apiRouter *mux.Router, repo *database.OHLCVRepository) {
+	anchorHandler := handlers.NewVWAPAnchorHandler(s.vwapAnchors, repo, s.triggerAnchorSync)
+	apiRouter.HandleFunc("/vwap/anchors", anchorHandler.CreateAnchor).Methods("POST")
+	apiRouter.HandleFunc("/vwap/anchors", anchorHandler.ListAnchors).Methods("GET")
+	apiRouter.HandleFunc("/vwap/anchors/{id:[0-9]+}", anchorHandler.DeleteAnchor).Methods("DELETE")
+}
+
+// triggerAnchorSync requests an anchor sync without waiting for it
+func (s *Server) triggerAnchorSync() {
+	select {
+	case s.anchorSync <- struct{}{}:
+	default:
+	}
+}
+
+// runAnchorSync keeps the anchored VWAPs of the stream states in line with
+// the stored anchors until the server stops
+func (s *Server) runAnchorSync(repo *database.OHLCVRepository) {
+	ticker := time.NewTicker(anchorSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		s.syncVWAPAnchors(repo)
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.anchorSync:
+		}
+	}
+}
+
+// syncVWAPAnchors starts the VWAPs of new anchors on every stream of their
+// symbol and drops those of deleted anchors
+func (s *Server) syncVWAPAnchors(repo *database.OHLCVRepository) {
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+
+	anchors, err := s.vwapAnchors.List(ctx, "")
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to load VWAP anchors")
+		return
+	}
+
+	bySymbol := make(map[string][]*models.VWAPAnchor)
+	for _, anchor := range anchors {
+		bySymbol[anchor.Symbol] = append(bySymbol[anchor.Symbol], anchor)
+	}
+
+	for _, symbol := range s.getTrackedSymbols() {
+		for _, timeframe := range streamTimeframes {
+			if state := s.streamStates.Get(symbol, timeframe); state != nil {
+				s.applyVWAPAnchors(ctx, repo, state, symbol, timeframe, bySymbol[symbol])
+			}
+		}
+	}
+}
+
+// applyVWAPAnchors makes a stream maintain exactly the given anchors of its symbol
+func (s *Server) applyVWAPAnchors(
+	ctx context.Context,
+	repo *database.OHLCVRepository,
+	state *enrichment.StreamState,
+	symbol, timeframe string,
+	anchors []*models.VWAPAnchor,
+) {
+	ids := make(map[int64]bool, len(anchors))
+	for _, anchor := range anchors {
+		ids[anchor.ID] = true
+	}
+	state.RetainAnchors(ids)
+
+	last := state.Last()
+	if last == nil {
+		return
+	}
+	dbTimeframe := s.convertTimeframeForDB(timeframe)
+
+	for _, anchor := range anchors {
+		if state.HasAnchor(anchor.ID) {
+			continue
+		}
+
+		history, err := repo.GetHistory(ctx, symbol, dbTimeframe, anchor.AnchorAt, last.Timestamp, maxAnchorCandles)
+		if err != nil {
+			s.logger.Error().Err(err).
+				Int64("anchor_id", anchor.ID).
+				Str("symbol", symbol).
+				Str("timeframe", timeframe).
+				Msg("Failed to load anchored VWAP history")
+			continue
+		}
+		if len(history) == maxAnchorCandles {
+			s.logger.Warn().
+				Int64("anchor_id", anchor.ID).
+				Str("symbol", symbol).
+				Str("timeframe", timeframe).
+				Msg("Anchor too far back for the timeframe, VWAP not maintained")
+			continue
+		}
+
+		state.SetAnchor(anchor, history)
+
+		s.logger.Info().
+			Int64("anchor_id", anchor.ID).
+			Str("symbol", symbol).
+			Str("timeframe", timeframe).
+			Int("candles", len(history)).
+			Msg("Started anchored VWAP")
+	}
+}
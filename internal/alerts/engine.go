@@ -0,0 +1,387 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-253: Server-side alert rules evaluated against enriched candles
+
+// RuleStore loads alert rules and records when they fire
+type RuleStore interface {
+	ListEnabled(ctx context.Context) ([]*models.AlertRule, error)
+	MarkTriggered(ctx context.Context, id int64, triggeredAt time.Time) error
+}
+
+// AlertHandler receives every alert that fires
+type AlertHandler func(alert *models.Alert)
+
+// Engine evaluates alert rules against the enriched candle stream.
+//
+// Crossing conditions compare the current candle with the previous one of the
+// same symbol:timeframe. Level conditions (RSI above/below) are edge-triggered:
+// they fire when the condition becomes true, or when the cooldown that held
+// back a match ends while it still holds, and re-arm once it is false again.
+// A rule never fires twice for the same candle and not again within its cooldown.
+type Engine struct {
+	store    RuleStore
+	notifier *WebhookNotifier
+
+	rules   map[string][]*models.AlertRule // symbol:timeframe -> rules
+	states  map[int64]*ruleState
+	streams map[string]*streamSnapshot
+	mu      sync.Mutex
+
+	handlers []AlertHandler
+
+	logger zerolog.Logger
+}
+
+// ruleState tracks cooldown and dedup state of a single rule
+type ruleState struct {
+	lastFired  time.Time
+	lastCandle time.Time
+	active     bool // level condition true since the rule last fired
+}
+
+// streamSnapshot is the last evaluated candle of a symbol:timeframe
+type streamSnapshot struct {
+	timestamp         time.Time
+	close             float64
+	rsi               float64
+	nearestResistance float64
+	nearestSupport    float64
+}
+
+// NewEngine creates a new alert engine
+func NewEngine(store RuleStore, notifier *WebhookNotifier) *Engine {
+	return &Engine{
+		store:    store,
+		notifier: notifier,
+		rules:    make(map[string][]*models.AlertRule),
+		states:   make(map[int64]*ruleState),
+		streams:  make(map[string]*streamSnapshot),
+		logger:   logger.NewContextLogger("alert_engine"),
+	}
+}
+
+// OnAlert registers a handler invoked for every fired alert
+func (e *Engine) OnAlert(handler AlertHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.handlers = append(e.handlers, handler)
+}
+
+// Load replaces the active rule set with the enabled rules from the store.
+// Cooldown and dedup state of rules that are still present is preserved.
+func (e *Engine) Load(ctx context.Context) error {
+	rules, err := e.store.ListEnabled(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load alert rules: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	byStream := make(map[string][]*models.AlertRule)
+	states := make(map[int64]*ruleState, len(rules))
+	for _, rule := range rules {
+		key := streamKey(rule.Symbol, rule.Timeframe)
+		byStream[key] = append(byStream[key], rule)
+
+		state, exists := e.states[rule.ID]
+		if !exists {
+			state = &ruleState{}
+			if rule.LastTriggeredAt != nil {
+				state.lastFired = *rule.LastTriggeredAt
+			}
+		}
+		states[rule.ID] = state
+	}
+
+	e.rules = byStream
+	e.states = states
+
+	e.logger.Debug().Int("rules", len(rules)).Msg("Alert rules loaded")
+	return nil
+}
+
+// Run periodically reloads rules so changes made on any instance take effect
+func (e *Engine) Run(ctx context.Context, interval time.Duration) {
+	if err := e.Load(ctx); err != nil {
+		e.logger.Error().Err(err).Msg("Initial alert rule load failed")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.Load(ctx); err != nil {
+				e.logger.Error().Err(err).Msg("Failed to reload alert rules")
+			}
+		}
+	}
+}
+
+// AddRule activates a rule immediately without waiting for the next reload
+func (e *Engine) AddRule(rule *models.AlertRule) {
+	if !rule.Enabled {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := streamKey(rule.Symbol, rule.Timeframe)
+	e.rules[key] = append(e.rules[key], rule)
+	if _, exists := e.states[rule.ID]; !exists {
+		e.states[rule.ID] = &ruleState{}
+	}
+}
+
+// RemoveRule deactivates a rule immediately
+func (e *Engine) RemoveRule(id int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for key, rules := range e.rules {
+		for i, rule := range rules {
+			if rule.ID == id {
+				e.rules[key] = append(rules[:i:i], rules[i+1:]...)
+				break
+			}
+		}
+	}
+	delete(e.states, id)
+}
+
+// Evaluate checks all rules of the candle's symbol and timeframe, dispatches
+// fired alerts to handlers and webhooks, and returns them
+func (e *Engine) Evaluate(timeframe string, candle *models.EnrichedCandle) []*models.Alert {
+	if candle == nil || candle.OHLCV == nil {
+		return nil
+	}
+
+	current := snapshotOf(candle)
+	key := streamKey(candle.OHLCV.Symbol, timeframe)
+
+	e.mu.Lock()
+	previous := e.streams[key]
+	if previous != nil && !current.timestamp.After(previous.timestamp) {
+		// Replayed or out-of-order candle; state already reflects it
+		e.mu.Unlock()
+		return nil
+	}
+	e.streams[key] = current
+
+	now := time.Now()
+	var fired []*models.Alert
+	for _, rule := range e.rules[key] {
+		state := e.states[rule.ID]
+		if state == nil {
+			state = &ruleState{}
+			e.states[rule.ID] = state
+		}
+
+		alert := evaluateRule(rule, previous, current, candle)
+		if alert == nil {
+			state.active = false
+			continue
+		}
+
+		if isLevelCondition(rule.Condition) && state.active {
+			// Still above/below the level since it fired; wait until it re-arms
+			continue
+		}
+
+		if state.lastCandle.Equal(current.timestamp) {
+			continue
+		}
+
+		// A match dropped by the cooldown leaves the rule armed, so a level
+		// still holding fires once the cooldown ends
+		cooldown := time.Duration(rule.CooldownSeconds) * time.Second
+		if !state.lastFired.IsZero() && now.Sub(state.lastFired) < cooldown {
+			continue
+		}
+
+		state.active = isLevelCondition(rule.Condition)
+		state.lastFired = now
+		state.lastCandle = current.timestamp
+
+		alert.RuleID = rule.ID
+		alert.RuleName = rule.Name
+		alert.Symbol = rule.Symbol
+		alert.Timeframe = rule.Timeframe
+		alert.Condition = rule.Condition
+		alert.Price = current.close
+		alert.CandleTime = current.timestamp
+		alert.TriggeredAt = now
+		fired = append(fired, alert)
+
+		if rule.WebhookURL != "" && e.notifier != nil {
+			go e.notifier.Send(context.Background(), rule.WebhookURL, alert)
+		}
+		go e.markTriggered(rule.ID, now)
+	}
+
+	handlers := make([]AlertHandler, len(e.handlers))
+	copy(handlers, e.handlers)
+	e.mu.Unlock()
+
+	for _, alert := range fired {
+		e.logger.Info().
+			Int64("rule_id", alert.RuleID).
+			Str("symbol", alert.Symbol).
+			Str("timeframe", alert.Timeframe).
+			Str("condition", alert.Condition).
+			Float64("value", alert.Value).
+			Msg("Alert fired")
+
+		for _, handler := range handlers {
+			handler(alert)
+		}
+	}
+
+	return fired
+}
+
+// markTriggered persists the firing time so cooldowns survive restarts
+func (e *Engine) markTriggered(id int64, triggeredAt time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := e.store.MarkTriggered(ctx, id, triggeredAt); err != nil {
+		e.logger.Warn().Err(err).Int64("rule_id", id).Msg("Failed to record alert trigger")
+	}
+}
+
+// evaluateRule returns a partially filled alert if the rule condition holds
+func evaluateRule(rule *models.AlertRule, previous, current *streamSnapshot, candle *models.EnrichedCandle) *models.Alert {
+	// Without a previous candle, crossings are judged within the candle itself
+	priorClose := candle.OHLCV.Open
+	if previous != nil {
+		priorClose = previous.close
+	}
+
+	switch rule.Condition {
+	case models.AlertPriceCrossAbove:
+		if priorClose < rule.Threshold && current.close >= rule.Threshold {
+			return &models.Alert{
+				Value:   current.close,
+				Level:   rule.Threshold,
+				Message: fmt.Sprintf("%s crossed above %.2f (close %.2f)", rule.Symbol, rule.Threshold, current.close),
+			}
+		}
+
+	case models.AlertPriceCrossBelow:
+		if priorClose > rule.Threshold && current.close <= rule.Threshold {
+			return &models.Alert{
+				Value:   current.close,
+				Level:   rule.Threshold,
+				Message: fmt.Sprintf("%s crossed below %.2f (close %.2f)", rule.Symbol, rule.Threshold, current.close),
+			}
+		}
+
+	case models.AlertRSIAbove:
+		if current.rsi > 0 && current.rsi > rule.Threshold {
+			return &models.Alert{
+				Value:   current.rsi,
+				Level:   rule.Threshold,
+				Message: fmt.Sprintf("%s RSI %.1f above %.1f", rule.Symbol, current.rsi, rule.Threshold),
+			}
+		}
+
+	case models.AlertRSIBelow:
+		if current.rsi > 0 && current.rsi < rule.Threshold {
+			return &models.Alert{
+				Value:   current.rsi,
+				Level:   rule.Threshold,
+				Message: fmt.Sprintf("%s RSI %.1f below %.1f", rule.Symbol, current.rsi, rule.Threshold),
+			}
+		}
+
+	case models.AlertPattern:
+		if candle.Analysis == nil {
+			return nil
+		}
+		for _, pattern := range candle.Analysis.CandlestickPatterns {
+			if pattern == rule.Pattern {
+				return &models.Alert{
+					Value:   current.close,
+					Message: fmt.Sprintf("%s formed %s", rule.Symbol, pattern),
+				}
+			}
+		}
+
+	case models.AlertResistanceBreakout:
+		// The level is taken from the previous candle, before the breakout moved it
+		if previous != nil && previous.nearestResistance > 0 &&
+			previous.close <= previous.nearestResistance && current.close > previous.nearestResistance {
+			return &models.Alert{
+				Value:   current.close,
+				Level:   previous.nearestResistance,
+				Message: fmt.Sprintf("%s broke resistance at %.2f (close %.2f)", rule.Symbol, previous.nearestResistance, current.close),
+			}
+		}
+
+	case models.AlertSupportBreakdown:
+		if previous != nil && previous.nearestSupport > 0 &&
+			previous.close >= previous.nearestSupport && current.close < previous.nearestSupport {
+			return &models.Alert{
+				Value:   current.close,
+				Level:   previous.nearestSupport,
+				Message: fmt.Sprintf("%s broke support at %.2f (close %.2f)", rule.Symbol, previous.nearestSupport, current.close),
+			}
+		}
+	}
+
+	return nil
+}
+
+// snapshotOf extracts the values rules compare between candles
+func snapshotOf(candle *models.EnrichedCandle) *streamSnapshot {
+	snapshot := &streamSnapshot{
+		timestamp: candle.OHLCV.Timestamp,
+		close:     candle.OHLCV.Close,
+	}
+
+	if candle.Indicators != nil {
+		snapshot.rsi = candle.Indicators.RSI
+	}
+
+	if candle.Analysis != nil && candle.Analysis.SupportResistance != nil {
+		if current := candle.Analysis.SupportResistance.Current; current != nil {
+			if current.NearestResistance != nil {
+				snapshot.nearestResistance = current.NearestResistance.Price
+			}
+			if current.NearestSupport != nil {
+				snapshot.nearestSupport = current.NearestSupport.Price
+			}
+		}
+	}
+
+	return snapshot
+}
+
+// isLevelCondition reports whether a condition holds over a span of candles
+func isLevelCondition(condition string) bool {
+	return condition == models.AlertRSIAbove || condition == models.AlertRSIBelow
+}
+
+// streamKey matches the hub's symbol:timeframe subscription key
+func streamKey(symbol, timeframe string) string {
+	return symbol + ":" + timeframe
+}
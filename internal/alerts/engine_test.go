@@ -0,0 +1,240 @@
+package alerts
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// memoryRuleStore serves rules from memory and records triggers
+type memoryRuleStore struct {
+	mu        sync.Mutex
+	rules     []*models.AlertRule
+	triggered map[int64]time.Time
+}
+
+func (s *memoryRuleStore) ListEnabled(ctx context.Context) ([]*models.AlertRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*models.AlertRule(nil), s.rules...), nil
+}
+
+func (s *memoryRuleStore) MarkTriggered(ctx context.Context, id int64, triggeredAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.triggered == nil {
+		s.triggered = make(map[int64]time.Time)
+	}
+	s.triggered[id] = triggeredAt
+	return nil
+}
+
+var testBase = time.Date(2024, 6, 3, 14, 30, 0, 0, time.UTC)
+
+// testCandle is the minute candle at index closing at price with an RSI
+func testCandle(index int, price, rsi float64) *models.EnrichedCandle {
+	return &models.EnrichedCandle{
+		OHLCV: &models.OHLCV{
+			Symbol:    "TEST",
+			Timestamp: testBase.Add(time.Duration(index) * time.Minute),
+			Open:      price,
+			High:      price,
+			Low:       price,
+			Close:     price,
+		},
+		Indicators: &models.TechnicalIndicators{RSI: rsi},
+	}
+}
+
+func testRule(id int64, condition string, threshold float64, cooldown int) *models.AlertRule {
+	return &models.AlertRule{
+		ID:              id,
+		Symbol:          "TEST",
+		Timeframe:       "1min",
+		Condition:       condition,
+		Threshold:       threshold,
+		CooldownSeconds: cooldown,
+		Enabled:         true,
+	}
+}
+
+func loadedEngine(t *testing.T, rules ...*models.AlertRule) (*Engine, *memoryRuleStore) {
+	t.Helper()
+	store := &memoryRuleStore{rules: rules}
+	engine := NewEngine(store, nil)
+	if err := engine.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	return engine, store
+}
+
+func TestEvaluateEdgeTriggers(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		threshold float64
+		closes    []float64
+		rsi       []float64
+		want      []bool
+	}{
+		{
+			name: "rsi above fires once per excursion", condition: models.AlertRSIAbove, threshold: 70,
+			rsi:  []float64{65, 75, 80, 60, 72},
+			want: []bool{false, true, false, false, true},
+		},
+		{
+			name: "rsi below fires once per excursion", condition: models.AlertRSIBelow, threshold: 30,
+			rsi:  []float64{25, 20, 35, 28},
+			want: []bool{true, false, false, true},
+		},
+		{
+			name: "price crosses above", condition: models.AlertPriceCrossAbove, threshold: 100,
+			closes: []float64{99, 101, 102, 98, 101},
+			want:   []bool{false, true, false, false, true},
+		},
+		{
+			name: "price crosses below", condition: models.AlertPriceCrossBelow, threshold: 100,
+			closes: []float64{101, 99, 98, 101, 100},
+			want:   []bool{false, true, false, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, _ := loadedEngine(t, testRule(1, tt.condition, tt.threshold, 0))
+
+			for i, want := range tt.want {
+				price, rsi := 100.0, 50.0
+				if tt.closes != nil {
+					price = tt.closes[i]
+				}
+				if tt.rsi != nil {
+					rsi = tt.rsi[i]
+				}
+
+				fired := engine.Evaluate("1min", testCandle(i, price, rsi))
+				if (len(fired) == 1) != want {
+					t.Errorf("candle %d: fired %d alerts, want fired=%v", i, len(fired), want)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateCooldown(t *testing.T) {
+	engine, store := loadedEngine(t, testRule(1, models.AlertRSIAbove, 70, 300))
+
+	steps := []struct {
+		rsi    float64
+		expire bool // end the cooldown before the candle
+		want   bool
+	}{
+		{75, false, true},
+		{65, false, false}, // re-arms
+		{75, false, false}, // matches within the cooldown
+		{76, true, true},   // still above once the cooldown ended
+		{77, true, false},  // fired for this excursion already
+	}
+
+	for i, step := range steps {
+		if step.expire {
+			engine.mu.Lock()
+			engine.states[1].lastFired = time.Now().Add(-301 * time.Second)
+			engine.mu.Unlock()
+		}
+
+		fired := engine.Evaluate("1min", testCandle(i, 100, step.rsi))
+		if (len(fired) == 1) != step.want {
+			t.Errorf("step %d: fired %d alerts, want fired=%v", i, len(fired), step.want)
+		}
+	}
+
+	// Triggers are recorded asynchronously
+	deadline := time.Now().Add(time.Second)
+	for {
+		store.mu.Lock()
+		_, recorded := store.triggered[1]
+		store.mu.Unlock()
+		if recorded || time.Now().After(deadline) {
+			if !recorded {
+				t.Error("Expected the trigger to be recorded")
+			}
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestEvaluateSameCandle(t *testing.T) {
+	engine, _ := loadedEngine(t, testRule(1, models.AlertRSIBelow, 30, 0))
+
+	candle := testCandle(1, 100, 25)
+	if fired := engine.Evaluate("1min", candle); len(fired) != 1 {
+		t.Fatalf("Expected the first evaluation to fire, got %d alerts", len(fired))
+	}
+
+	tests := []struct {
+		name   string
+		candle *models.EnrichedCandle
+	}{
+		{"replayed candle", candle},
+		{"same timestamp", testCandle(1, 99, 20)},
+		{"older candle", testCandle(0, 98, 10)},
+	}
+	for _, tt := range tests {
+		engine.mu.Lock()
+		engine.states[1].active = false
+		engine.mu.Unlock()
+
+		if fired := engine.Evaluate("1min", tt.candle); len(fired) != 0 {
+			t.Errorf("%s: fired %d alerts, want none", tt.name, len(fired))
+		}
+	}
+}
+
+func TestLoadPreservesState(t *testing.T) {
+	fired := testRule(1, models.AlertRSIAbove, 70, 0)
+	engine, store := loadedEngine(t, fired)
+
+	if alerts := engine.Evaluate("1min", testCandle(0, 100, 75)); len(alerts) != 1 {
+		t.Fatalf("Expected the rule to fire, got %d alerts", len(alerts))
+	}
+
+	// A reload keeps the rule disarmed while its level holds, and seeds the
+	// cooldown of new rules from their last trigger
+	lastTriggered := time.Now()
+	cooling := testRule(2, models.AlertRSIAbove, 70, 300)
+	cooling.LastTriggeredAt = &lastTriggered
+	fresh := testRule(3, models.AlertRSIAbove, 70, 300)
+
+	store.mu.Lock()
+	store.rules = []*models.AlertRule{fired, cooling, fresh}
+	store.mu.Unlock()
+	if err := engine.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	alerts := engine.Evaluate("1min", testCandle(1, 100, 80))
+	if len(alerts) != 1 || alerts[0].RuleID != 3 {
+		t.Fatalf("Expected only the new rule to fire, got %+v", alerts)
+	}
+
+	// Rules no longer enabled lose their state
+	store.mu.Lock()
+	store.rules = []*models.AlertRule{fresh}
+	store.mu.Unlock()
+	if err := engine.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	if _, exists := engine.states[1]; exists || len(engine.states) != 1 {
+		t.Errorf("Expected only the enabled rule's state, got %d states", len(engine.states))
+	}
+}
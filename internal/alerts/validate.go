@@ -0,0 +1,28 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/analysis"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// ValidateRule checks a rule before it is stored: its fields, that a pattern
+// rule names a pattern the detector reports, so a typo is rejected instead of
+// never firing, and that its webhook only reaches public hosts
+func ValidateRule(ctx context.Context, rule *models.AlertRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	if rule.Condition == models.AlertPattern && !analysis.IsCandlestickPattern(rule.Pattern) {
+		return fmt.Errorf("unknown candlestick pattern %q", rule.Pattern)
+	}
+
+	if rule.WebhookURL != "" {
+		return ValidateWebhookURL(ctx, rule.WebhookURL)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-253: Optional webhook delivery of fired alerts
+
+// WebhookNotifier posts alerts as JSON to rule webhooks
+type WebhookNotifier struct {
+	client  *http.Client
+	retries int
+	logger  zerolog.Logger
+}
+
+// NewWebhookNotifier creates a notifier with a per-attempt timeout. It only
+// connects to public addresses, so neither DNS changes after a rule was
+// created nor redirects reach internal services.
+func NewWebhookNotifier(timeout time.Duration, retries int) *WebhookNotifier {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("webhook address %s is not public", host)
+			}
+			return nil
+		},
+	}
+
+	return &WebhookNotifier{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+		},
+		retries: retries,
+		logger:  logger.NewContextLogger("alert_webhook"),
+	}
+}
+
+// Send delivers an alert, retrying with exponential backoff on failure
+func (n *WebhookNotifier) Send(ctx context.Context, url string, alert *models.Alert) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		err = n.post(ctx, url, payload)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= n.retries {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	n.logger.Warn().Err(err).
+		Int64("rule_id", alert.RuleID).
+		Str("url", url).
+		Msg("Alert webhook delivery failed")
+
+	return err
+}
+
+// post performs a single delivery attempt
+func (n *WebhookNotifier) post(ctx context.Context, url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "jonbu-ohlcv-alerts")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ValidateWebhookURL checks that a webhook is an absolute http(s) URL whose
+// host resolves to public addresses only, as alerts are posted server-side
+func ValidateWebhookURL(ctx context.Context, raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return fmt.Errorf("webhook_url must be an absolute http(s) URL")
+	}
+	if parsed.User != nil {
+		return fmt.Errorf("webhook_url cannot carry credentials")
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if ip := net.ParseIP(host); ip != nil {
+		if !isPublicIP(ip) {
+			return fmt.Errorf("webhook_url host %s is not a public address", host)
+		}
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".local") ||
+		strings.HasSuffix(host, ".internal") || !strings.Contains(host, ".") {
+		return fmt.Errorf("webhook_url host %s is not a public host name", host)
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addresses) == 0 {
+		return fmt.Errorf("webhook_url host %s does not resolve", host)
+	}
+	for _, address := range addresses {
+		if !isPublicIP(address.IP) {
+			return fmt.Errorf("webhook_url host %s resolves to non-public address %s", host, address.IP)
+		}
+	}
+
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, private in practice
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicIP reports whether an address is routable on the internet: not
+// loopback, private, link-local (cloud metadata), shared, multicast or unspecified
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || sharedAddressSpace.Contains(ip) ||
+		(ip.To4() != nil && ip.To4()[0] == 0))
+}
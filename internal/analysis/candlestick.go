@@ -3,445 +3,408 @@ package analysis
 import (
 	"math"
 
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-211: Candlestick patterns (doji, hammer, shooting star, etc.)
+// REQ-270: One scored detector for the whole catalog, in the context of the
+// trend before the pattern
+
+// CandlestickLookback is the number of recent candles the detector reads:
+// the longest pattern and the candles its prior trend is measured on
+const CandlestickLookback = 3 + trendLookback
+
+const (
+	// trendLookback is the number of candles before a pattern that set its context
+	trendLookback = 10
+
+	// minTrendCandles is the fewest candles a prior trend is measured on
+	minTrendCandles = 3
+
+	// contextBonus is added to the strength of a reversal after the trend it reverses
+	contextBonus = 20
+)
+
+// Prior trend directions
+const (
+	trendUp   = "up"
+	trendDown = "down"
+	trendFlat = ""
+)
+
+// CandlestickPatternNames is the catalog of patterns the detector reports
+var CandlestickPatternNames = []string{
+	"doji", "spinning_top", "hammer", "hanging_man", "shooting_star",
+	"bullish_marubozu", "bearish_marubozu",
+	"bullish_engulfing", "bearish_engulfing", "bullish_harami", "bearish_harami",
+	"piercing_line", "dark_cloud_cover", "tweezer_top", "tweezer_bottom",
+	"inside_bar", "outside_bar",
+	"morning_star", "evening_star", "three_white_soldiers", "three_black_crows",
+}
+
+// IsCandlestickPattern reports whether name is in the catalog
+func IsCandlestickPattern(name string) bool {
+	return containsString(CandlestickPatternNames, name)
+}
+
+// CandlestickPattern is a detected candlestick pattern
+type CandlestickPattern struct {
+	Name        string  `json:"name"`     // e.g. bullish_engulfing
+	Type        string  `json:"type"`     // bullish, bearish, neutral
+	Strength    float64 `json:"strength"` // 0-100
+	Candles     int     `json:"candles"`  // candles forming the pattern
+	Description string  `json:"description"`
+}
 
 // CandlestickAnalyzer detects candlestick patterns
 type CandlestickAnalyzer struct {
 	// Configuration for pattern detection
-	minBodyPercent float64 // Minimum body size as percentage of range
-	wickRatio      float64 // Wick to body ratio for specific patterns
+	minBodyPercent   float64 // body below this share of the range is a doji
+	smallBodyPercent float64 // body below this share of the range is small
+	longBodyPercent  float64 // body above this share of the range is long
+	marubozuPercent  float64 // body above this share of the range has no shadows
+	wickRatio        float64 // Wick to body ratio for hammers and shooting stars
+	tweezerTolerance float64 // matching highs or lows, as a share of the average range
+	trendRanges      float64 // prior move, in average ranges, that makes a trend
 }
 
 // NewCandlestickAnalyzer creates a new candlestick analyzer
 func NewCandlestickAnalyzer() *CandlestickAnalyzer {
 	return &CandlestickAnalyzer{
-		minBodyPercent: 0.1, // 10% minimum body size
-		wickRatio:      2.0, // 2:1 wick to body ratio
+		minBodyPercent:   0.1, // 10% minimum body size
+		smallBodyPercent: 0.3,
+		longBodyPercent:  0.5,
+		marubozuPercent:  0.9,
+		wickRatio:        2.0, // 2:1 wick to body ratio
+		tweezerTolerance: 0.05,
+		trendRanges:      1.0,
 	}
 }
 
-// DetectPatterns detects candlestick patterns in a candle sequence
-func (ca *CandlestickAnalyzer) DetectPatterns(candles []*models.OHLCV) []string {
-	if len(candles) < 3 {
+// DetectPatterns detects the patterns completed by the last candle of a
+// sequence in chronological order. Reversal patterns are judged against the
+// trend of the candles before them: a hammer only counts after a downtrend,
+// the same candle after an uptrend is a hanging man.
+func (ca *CandlestickAnalyzer) DetectPatterns(candles []*models.OHLCV) []*CandlestickPattern {
+	if len(candles) == 0 {
 		return nil
 	}
 
-	patterns := make([]string, 0)
+	var patterns []*CandlestickPattern
 
 	// Single candle patterns
-	current := candles[len(candles)-1]
-
-	if ca.isDoji(current) {
-		patterns = append(patterns, "doji")
-	}
-
-	if ca.isHammer(current) {
-		patterns = append(patterns, "hammer")
-	}
+	current := shapeOf(candles[len(candles)-1])
+	patterns = ca.appendSingle(patterns, current, ca.priorTrend(candles[:len(candles)-1]))
 
-	if ca.isShootingStar(current) {
-		patterns = append(patterns, "shooting_star")
-	}
-
-	// Multi-candle patterns (need at least 2 candles)
+	// Two candle patterns
 	if len(candles) >= 2 {
-		prev := candles[len(candles)-2]
-
-		if ca.isBullishEngulfing(prev, current) {
-			patterns = append(patterns, "engulfing_bullish")
-		}
-
-		if ca.isBearishEngulfing(prev, current) {
-			patterns = append(patterns, "engulfing_bearish")
-		}
+		previous := shapeOf(candles[len(candles)-2])
+		patterns = ca.appendDouble(patterns, previous, current, ca.priorTrend(candles[:len(candles)-2]))
 	}
 
-	// Three-candle patterns
+	// Three candle patterns
 	if len(candles) >= 3 {
-		first := candles[len(candles)-3]
-		middle := candles[len(candles)-2]
-		last := candles[len(candles)-1]
-
-		if ca.isMorningStar(first, middle, last) {
-			patterns = append(patterns, "morning_star")
-		}
-
-		if ca.isEveningStar(first, middle, last) {
-			patterns = append(patterns, "evening_star")
-		}
+		first := shapeOf(candles[len(candles)-3])
+		middle := shapeOf(candles[len(candles)-2])
+		patterns = ca.appendTriple(patterns, first, middle, current, ca.priorTrend(candles[:len(candles)-3]))
 	}
 
 	return patterns
 }
 
-// Single candle pattern detection methods
-
-func (ca *CandlestickAnalyzer) isDoji(candle *models.OHLCV) bool {
-	bodySize := math.Abs(candle.Close - candle.Open)
-	totalRange := candle.High - candle.Low
-
-	if totalRange == 0 {
-		return false
-	}
-
-	bodyPercent := bodySize / totalRange
-	return bodyPercent < ca.minBodyPercent
-}
-
-func (ca *CandlestickAnalyzer) isHammer(candle *models.OHLCV) bool {
-	bodySize := math.Abs(candle.Close - candle.Open)
-	totalRange := candle.High - candle.Low
-
-	if totalRange == 0 || bodySize == 0 {
-		return false
-	}
-
-	// Hammer has small body in upper part and long lower shadow
-	lowerShadow := math.Min(candle.Open, candle.Close) - candle.Low
-	upperShadow := candle.High - math.Max(candle.Open, candle.Close)
-
-	return lowerShadow > bodySize*ca.wickRatio && upperShadow < bodySize*0.5
-}
-
-func (ca *CandlestickAnalyzer) isShootingStar(candle *models.OHLCV) bool {
-	bodySize := math.Abs(candle.Close - candle.Open)
-	totalRange := candle.High - candle.Low
-
-	if totalRange == 0 || bodySize == 0 {
-		return false
-	}
-
-	// Shooting star has small body in lower part and long upper shadow
-	lowerShadow := math.Min(candle.Open, candle.Close) - candle.Low
-	upperShadow := candle.High - math.Max(candle.Open, candle.Close)
-
-	return upperShadow > bodySize*ca.wickRatio && lowerShadow < bodySize*0.5
-}
-
-// Multi-candle pattern detection methods
-
-func (ca *CandlestickAnalyzer) isBullishEngulfing(prev, current *models.OHLCV) bool {
-	// Previous candle is bearish, current is bullish and engulfs previous
-	if prev.Close >= prev.Open || current.Close <= current.Open {
-		return false
-	}
-
-	return current.Open < prev.Close && current.Close > prev.Open
-}
-
-func (ca *CandlestickAnalyzer) isBearishEngulfing(prev, current *models.OHLCV) bool {
-	// Previous candle is bearish, current is bearish and engulfs previous
-	if prev.Close <= prev.Open || current.Close >= current.Open {
-		return false
+// appendSingle adds the patterns of the last candle
+func (ca *CandlestickAnalyzer) appendSingle(patterns []*CandlestickPattern, c shape, trend string) []*CandlestickPattern {
+	if c.rng == 0 {
+		return patterns
 	}
+	bodyRatio := c.body / c.rng
 
-	return current.Open > prev.Close && current.Close < prev.Open
-}
-
-func (ca *CandlestickAnalyzer) isMorningStar(first, middle, last *models.OHLCV) bool {
-	// First candle bearish, middle is doji/small, last is bullish
-	if first.Close >= first.Open || last.Close <= last.Open {
-		return false
-	}
-
-	// Middle candle should be small
-	middleBody := math.Abs(middle.Close - middle.Open)
-	firstBody := math.Abs(first.Close - first.Open)
-	lastBody := math.Abs(last.Close - last.Open)
-
-	if middleBody > firstBody*0.3 || middleBody > lastBody*0.3 {
-		return false
+	// Doji: very small body relative to total range
+	if bodyRatio < ca.minBodyPercent {
+		patterns = append(patterns, newPattern("doji", "neutral", (1-bodyRatio)*100, 1,
+			"Indecision pattern with small body, potential reversal signal"))
+	}
+
+	// Spinning top: small body between shadows longer than it
+	if bodyRatio >= ca.minBodyPercent && bodyRatio < ca.smallBodyPercent && c.upper > c.body && c.lower > c.body {
+		patterns = append(patterns, newPattern("spinning_top", "neutral", 100-bodyRatio*100, 1,
+			"Small body between long shadows, indecision"))
+	}
+
+	// Hammer and hanging man: long lower shadow, body at the top of the range
+	if c.body > 0 && c.lower >= ca.wickRatio*c.body && c.lower >= 0.6*c.rng && c.upper <= 0.1*c.rng {
+		strength := c.lower / c.rng * 100
+		switch trend {
+		case trendDown:
+			patterns = append(patterns, newPattern("hammer", "bullish", strength, 1,
+				"Long lower shadow after a downtrend, buyers rejected lower prices"))
+		case trendUp:
+			patterns = append(patterns, newPattern("hanging_man", "bearish", strength, 1,
+				"Long lower shadow after an uptrend, selling pressure emerging"))
+		}
 	}
 
-	// Last candle should close above middle of first candle
-	firstMiddle := (first.Open + first.Close) / 2
-	return last.Close > firstMiddle
-}
-
-func (ca *CandlestickAnalyzer) isEveningStar(first, middle, last *models.OHLCV) bool {
-	// First candle bullish, middle is doji/small, last is bearish
-	if first.Close <= first.Open || last.Close >= last.Open {
-		return false
+	// Shooting star: long upper shadow, body at the bottom of the range
+	if c.body > 0 && c.upper >= ca.wickRatio*c.body && c.upper >= 0.6*c.rng && c.lower <= 0.1*c.rng && trend == trendUp {
+		patterns = append(patterns, newPattern("shooting_star", "bearish", c.upper/c.rng*100, 1,
+			"Long upper shadow after an uptrend, sellers rejected higher prices"))
 	}
 
-	// Middle candle should be small
-	middleBody := math.Abs(middle.Close - middle.Open)
-	firstBody := math.Abs(first.Close - first.Open)
-	lastBody := math.Abs(last.Close - last.Open)
-
-	if middleBody > firstBody*0.3 || middleBody > lastBody*0.3 {
-		return false
+	// Marubozu: a body without shadows to speak of
+	if bodyRatio >= ca.marubozuPercent {
+		if c.bullish() {
+			patterns = append(patterns, newPattern("bullish_marubozu", "bullish", bodyRatio*100, 1,
+				"Buyers in control from open to close"))
+		} else {
+			patterns = append(patterns, newPattern("bearish_marubozu", "bearish", bodyRatio*100, 1,
+				"Sellers in control from open to close"))
+		}
 	}
 
-	// Last candle should close below middle of first candle
-	firstMiddle := (first.Open + first.Close) / 2
-	return last.Close < firstMiddle
-}
-
-// CandlestickPattern represents a detected candlestick pattern
-type CandlestickPattern struct {
-	Name        string  `json:"name"`
-	Type        string  `json:"type"`     // bullish, bearish, neutral
-	Strength    float64 `json:"strength"` // 0-100
-	Description string  `json:"description"`
-}
-
-// PatternDetector detects candlestick patterns (legacy interface)
-type PatternDetector struct{}
-
-// NewPatternDetector creates a new pattern detector
-func NewPatternDetector() *PatternDetector {
-	return &PatternDetector{}
+	return patterns
 }
 
-// DetectPatterns identifies candlestick patterns in recent candles
-func (pd *PatternDetector) DetectPatterns(candles []*models.OHLCV) []*CandlestickPattern {
-	if len(candles) == 0 {
-		return nil
+// appendDouble adds the patterns of the last two candles
+func (ca *CandlestickAnalyzer) appendDouble(patterns []*CandlestickPattern, prev, curr shape, trend string) []*CandlestickPattern {
+	if prev.rng == 0 || curr.rng == 0 {
+		return patterns
 	}
 
-	var patterns []*CandlestickPattern
-
-	// Single candle patterns
-	if len(candles) >= 1 {
-		current := candles[len(candles)-1]
-
-		if pattern := pd.detectDoji(current); pattern != nil {
-			patterns = append(patterns, pattern)
+	// Engulfing: the body engulfs the opposite body before it
+	if curr.body > prev.body {
+		if prev.bearish() && curr.bullish() && curr.open <= prev.close && curr.close >= prev.open && trend != trendUp {
+			patterns = append(patterns, newPattern("bullish_engulfing", "bullish",
+				50*curr.body/prev.body+reversalBonus(trend, trendDown), 2, "Strong bullish reversal pattern"))
 		}
-
-		if pattern := pd.detectHammer(current); pattern != nil {
-			patterns = append(patterns, pattern)
-		}
-
-		if pattern := pd.detectShootingStar(current); pattern != nil {
-			patterns = append(patterns, pattern)
+		if prev.bullish() && curr.bearish() && curr.open >= prev.close && curr.close <= prev.open && trend != trendDown {
+			patterns = append(patterns, newPattern("bearish_engulfing", "bearish",
+				50*curr.body/prev.body+reversalBonus(trend, trendUp), 2, "Strong bearish reversal pattern"))
 		}
 	}
 
-	// Two candle patterns
-	if len(candles) >= 2 {
-		current := candles[len(candles)-1]
-		previous := candles[len(candles)-2]
-
-		if pattern := pd.detectEngulfing(previous, current); pattern != nil {
-			patterns = append(patterns, pattern)
+	// Harami: a small opposite body within a long body
+	if prev.body >= ca.longBodyPercent*prev.rng && curr.body > 0 &&
+		curr.bodyTop() < prev.bodyTop() && curr.bodyBottom() > prev.bodyBottom() {
+		strength := (1-curr.body/prev.body)*60 + 10
+		if prev.bearish() && curr.bullish() && trend != trendUp {
+			patterns = append(patterns, newPattern("bullish_harami", "bullish",
+				strength+reversalBonus(trend, trendDown), 2, "Selling stalls within a long bearish candle"))
+		}
+		if prev.bullish() && curr.bearish() && trend != trendDown {
+			patterns = append(patterns, newPattern("bearish_harami", "bearish",
+				strength+reversalBonus(trend, trendUp), 2, "Buying stalls within a long bullish candle"))
 		}
 	}
 
-	// Three candle patterns
-	if len(candles) >= 3 {
-		third := candles[len(candles)-3]
-		second := candles[len(candles)-2]
-		first := candles[len(candles)-1]
-
-		if pattern := pd.detectMorningStar(third, second, first); pattern != nil {
-			patterns = append(patterns, pattern)
+	// Piercing line and dark cloud cover: opening beyond the previous close
+	// and closing past the middle of its long body
+	if prev.body >= ca.longBodyPercent*prev.rng {
+		if prev.bearish() && curr.bullish() && curr.open < prev.close &&
+			curr.close > prev.bodyMiddle() && curr.close < prev.open && trend != trendUp {
+			penetration := (curr.close - prev.close) / prev.body
+			patterns = append(patterns, newPattern("piercing_line", "bullish",
+				40+40*penetration+reversalBonus(trend, trendDown), 2, "Bullish close deep into a long bearish candle"))
 		}
-
-		if pattern := pd.detectEveningStar(third, second, first); pattern != nil {
-			patterns = append(patterns, pattern)
+		if prev.bullish() && curr.bearish() && curr.open > prev.close &&
+			curr.close < prev.bodyMiddle() && curr.close > prev.open && trend != trendDown {
+			penetration := (prev.close - curr.close) / prev.body
+			patterns = append(patterns, newPattern("dark_cloud_cover", "bearish",
+				40+40*penetration+reversalBonus(trend, trendUp), 2, "Bearish close deep into a long bullish candle"))
 		}
 	}
 
-	return patterns
-}
-
-// detectDoji detects doji patterns
-func (pd *PatternDetector) detectDoji(candle *models.OHLCV) *CandlestickPattern {
-	bodySize := math.Abs(candle.Close - candle.Open)
-	totalSize := candle.High - candle.Low
-
-	if totalSize == 0 {
-		return nil
+	// Tweezers: matching extremes of opposite candles at the end of a trend
+	tolerance := ca.tweezerTolerance * (prev.rng + curr.rng) / 2
+	if prev.bullish() && curr.bearish() && trend == trendUp && math.Abs(prev.high-curr.high) <= tolerance {
+		patterns = append(patterns, newPattern("tweezer_top", "bearish",
+			70-30*math.Abs(prev.high-curr.high)/tolerance, 2, "Two rejections at the same high after an uptrend"))
+	}
+	if prev.bearish() && curr.bullish() && trend == trendDown && math.Abs(prev.low-curr.low) <= tolerance {
+		patterns = append(patterns, newPattern("tweezer_bottom", "bullish",
+			70-30*math.Abs(prev.low-curr.low)/tolerance, 2, "Two rejections at the same low after a downtrend"))
 	}
 
-	bodyRatio := bodySize / totalSize
-
-	// Doji: very small body relative to total range
-	if bodyRatio < 0.1 {
-		return &CandlestickPattern{
-			Name:        "Doji",
-			Type:        "neutral",
-			Strength:    (1 - bodyRatio) * 100,
-			Description: "Indecision pattern with small body, potential reversal signal",
+	// Inside and outside bars
+	if curr.high < prev.high && curr.low > prev.low {
+		patterns = append(patterns, newPattern("inside_bar", "neutral", (1-curr.rng/prev.rng)*100, 2,
+			"Range contained in the previous range, consolidation"))
+	}
+	if curr.high > prev.high && curr.low < prev.low {
+		direction := "neutral"
+		if curr.body >= ca.minBodyPercent*curr.rng {
+			direction = curr.direction()
 		}
+		patterns = append(patterns, newPattern("outside_bar", direction, 50*curr.rng/prev.rng, 2,
+			"Range beyond both ends of the previous range, expansion"))
 	}
 
-	return nil
+	return patterns
 }
 
-// detectHammer detects hammer patterns
-func (pd *PatternDetector) detectHammer(candle *models.OHLCV) *CandlestickPattern {
-	bodySize := math.Abs(candle.Close - candle.Open)
-	lowerShadow := math.Min(candle.Open, candle.Close) - candle.Low
-	upperShadow := candle.High - math.Max(candle.Open, candle.Close)
-	totalSize := candle.High - candle.Low
-
-	if totalSize == 0 {
-		return nil
+// appendTriple adds the patterns of the last three candles
+func (ca *CandlestickAnalyzer) appendTriple(patterns []*CandlestickPattern, first, middle, last shape, trend string) []*CandlestickPattern {
+	if first.rng == 0 || middle.rng == 0 || last.rng == 0 {
+		return patterns
 	}
 
-	// Hammer: small body, long lower shadow, small upper shadow
-	if bodySize/totalSize < 0.3 && lowerShadow > 2*bodySize && upperShadow < bodySize {
-		patternType := "bullish"
-		if candle.Close < candle.Open {
-			patternType = "bearish"
+	// Morning and evening star: a long body, a small star and a strong
+	// candle closing past the middle of the first
+	if middle.body < first.body*0.5 && last.body > first.body*0.3 {
+		if first.bearish() && last.bullish() && last.close > first.bodyMiddle() && trend != trendUp {
+			patterns = append(patterns, newPattern("morning_star", "bullish", 70+reversalBonus(trend, trendDown), 3,
+				"Strong three-candle bullish reversal pattern"))
 		}
-
-		strength := (lowerShadow / totalSize) * 100
-
-		return &CandlestickPattern{
-			Name:        "Hammer",
-			Type:        patternType,
-			Strength:    strength,
-			Description: "Potential bullish reversal with long lower shadow",
+		if first.bullish() && last.bearish() && last.close < first.bodyMiddle() && trend != trendDown {
+			patterns = append(patterns, newPattern("evening_star", "bearish", 70+reversalBonus(trend, trendUp), 3,
+				"Strong three-candle bearish reversal pattern"))
 		}
 	}
 
-	return nil
-}
-
-// detectShootingStar detects shooting star patterns
-func (pd *PatternDetector) detectShootingStar(candle *models.OHLCV) *CandlestickPattern {
-	bodySize := math.Abs(candle.Close - candle.Open)
-	lowerShadow := math.Min(candle.Open, candle.Close) - candle.Low
-	upperShadow := candle.High - math.Max(candle.Open, candle.Close)
-	totalSize := candle.High - candle.Low
-
-	if totalSize == 0 {
-		return nil
+	// Three white soldiers and three black crows: three long bodies of one
+	// direction, each opening within the previous body
+	candles := []shape{first, middle, last}
+	if soldiers, strength := ca.threeAdvancing(candles, true); soldiers {
+		patterns = append(patterns, newPattern("three_white_soldiers", "bullish", strength, 3,
+			"Three long bullish candles closing progressively higher"))
 	}
-
-	// Shooting star: small body, long upper shadow, small lower shadow
-	if bodySize/totalSize < 0.3 && upperShadow > 2*bodySize && lowerShadow < bodySize {
-		strength := (upperShadow / totalSize) * 100
-
-		return &CandlestickPattern{
-			Name:        "Shooting Star",
-			Type:        "bearish",
-			Strength:    strength,
-			Description: "Potential bearish reversal with long upper shadow",
-		}
+	if crows, strength := ca.threeAdvancing(candles, false); crows {
+		patterns = append(patterns, newPattern("three_black_crows", "bearish", strength, 3,
+			"Three long bearish candles closing progressively lower"))
 	}
 
-	return nil
+	return patterns
 }
 
-// detectEngulfing detects bullish/bearish engulfing patterns
-func (pd *PatternDetector) detectEngulfing(prev, curr *models.OHLCV) *CandlestickPattern {
-	prevBody := math.Abs(prev.Close - prev.Open)
-	currBody := math.Abs(curr.Close - curr.Open)
-
-	// Current candle must have larger body than previous
-	if currBody <= prevBody {
-		return nil
-	}
-
-	// Bullish engulfing: prev bearish, curr bullish, curr engulfs prev
-	if prev.Close < prev.Open && curr.Close > curr.Open &&
-		curr.Open < prev.Close && curr.Close > prev.Open {
-
-		strength := (currBody / prevBody) * 50
-		if strength > 100 {
-			strength = 100
+// threeAdvancing reports whether three candles are long bodies of one
+// direction that each open within the previous body and close beyond it,
+// with the strength given by how much of their ranges the bodies cover
+func (ca *CandlestickAnalyzer) threeAdvancing(candles []shape, bullish bool) (bool, float64) {
+	coverage := 0.0
+	for i, c := range candles {
+		if c.bullish() != bullish || c.body == 0 || c.body < ca.longBodyPercent*c.rng {
+			return false, 0
 		}
 
-		return &CandlestickPattern{
-			Name:        "Bullish Engulfing",
-			Type:        "bullish",
-			Strength:    strength,
-			Description: "Strong bullish reversal pattern",
+		// Closing near the extreme: little shadow beyond the close
+		closingShadow := c.upper
+		if !bullish {
+			closingShadow = c.lower
 		}
-	}
-
-	// Bearish engulfing: prev bullish, curr bearish, curr engulfs prev
-	if prev.Close > prev.Open && curr.Close < curr.Open &&
-		curr.Open > prev.Close && curr.Close < prev.Open {
-
-		strength := (currBody / prevBody) * 50
-		if strength > 100 {
-			strength = 100
+		if closingShadow > 0.3*c.body {
+			return false, 0
 		}
 
-		return &CandlestickPattern{
-			Name:        "Bearish Engulfing",
-			Type:        "bearish",
-			Strength:    strength,
-			Description: "Strong bearish reversal pattern",
+		if i > 0 {
+			prev := candles[i-1]
+			if c.open < prev.bodyBottom() || c.open > prev.bodyTop() {
+				return false, 0
+			}
+			if bullish && c.close <= prev.close || !bullish && c.close >= prev.close {
+				return false, 0
+			}
 		}
+		coverage += c.body / c.rng
 	}
 
-	return nil
+	return true, 40 + 60*coverage/float64(len(candles))
 }
 
-// detectMorningStar detects morning star patterns
-func (pd *PatternDetector) detectMorningStar(first, second, third *models.OHLCV) *CandlestickPattern {
-	// First candle: bearish
-	// Second candle: small body (doji/spinning top)
-	// Third candle: bullish, closes above midpoint of first candle
-
-	if first.Close >= first.Open || third.Close <= third.Open {
-		return nil
+// priorTrend classifies the closes of up to trendLookback candles by their
+// regression move measured in average candle ranges
+func (ca *CandlestickAnalyzer) priorTrend(candles []*models.OHLCV) string {
+	if len(candles) > trendLookback {
+		candles = candles[len(candles)-trendLookback:]
+	}
+	n := len(candles)
+	if n < minTrendCandles {
+		return trendFlat
 	}
 
-	firstBody := first.Open - first.Close
-	secondBody := math.Abs(second.Close - second.Open)
-	thirdBody := third.Close - third.Open
+	var sumX, sumY, sumXY, sumXX, sumRange float64
+	for i, candle := range candles {
+		x := float64(i)
+		sumX += x
+		sumY += candle.Close
+		sumXY += x * candle.Close
+		sumXX += x * x
+		sumRange += candle.High - candle.Low
+	}
 
-	firstMidpoint := (first.Open + first.Close) / 2
+	count := float64(n)
+	averageRange := sumRange / count
+	if averageRange == 0 {
+		return trendFlat
+	}
 
-	// Check pattern conditions
-	if secondBody < firstBody*0.5 && // Small middle candle
-		third.Close > firstMidpoint && // Third closes above first's midpoint
-		thirdBody > firstBody*0.3 { // Third has decent size
+	slope := (count*sumXY - sumX*sumY) / (count*sumXX - sumX*sumX)
+	move := slope * (count - 1) / averageRange
 
-		return &CandlestickPattern{
-			Name:        "Morning Star",
-			Type:        "bullish",
-			Strength:    75.0,
-			Description: "Strong three-candle bullish reversal pattern",
-		}
+	switch {
+	case move >= ca.trendRanges:
+		return trendUp
+	case move <= -ca.trendRanges:
+		return trendDown
+	default:
+		return trendFlat
 	}
-
-	return nil
 }
 
-// detectEveningStar detects evening star patterns
-func (pd *PatternDetector) detectEveningStar(first, second, third *models.OHLCV) *CandlestickPattern {
-	// First candle: bullish
-	// Second candle: small body (doji/spinning top)
-	// Third candle: bearish, closes below midpoint of first candle
+// reversalBonus returns the context bonus when the prior trend is the one a
+// reversal pattern reverses
+func reversalBonus(trend, reverses string) float64 {
+	if trend == reverses {
+		return contextBonus
+	}
+	return 0
+}
 
-	if first.Close <= first.Open || third.Close >= third.Open {
-		return nil
+func newPattern(name, patternType string, strength float64, candles int, description string) *CandlestickPattern {
+	return &CandlestickPattern{
+		Name:        name,
+		Type:        patternType,
+		Strength:    math.Max(0, math.Min(100, strength)),
+		Candles:     candles,
+		Description: description,
 	}
+}
 
-	firstBody := first.Close - first.Open
-	secondBody := math.Abs(second.Close - second.Open)
-	thirdBody := third.Open - third.Close
+// shape is the geometry of a candle
+type shape struct {
+	open, high, low, close  float64
+	body, rng, upper, lower float64
+}
 
-	firstMidpoint := (first.Open + first.Close) / 2
+func shapeOf(candle *models.OHLCV) shape {
+	return shape{
+		open:  candle.Open,
+		high:  candle.High,
+		low:   candle.Low,
+		close: candle.Close,
+		body:  math.Abs(candle.Close - candle.Open),
+		rng:   candle.High - candle.Low,
+		upper: candle.High - math.Max(candle.Open, candle.Close),
+		lower: math.Min(candle.Open, candle.Close) - candle.Low,
+	}
+}
 
-	// Check pattern conditions
-	if secondBody < firstBody*0.5 && // Small middle candle
-		third.Close < firstMidpoint && // Third closes below first's midpoint
-		thirdBody > firstBody*0.3 { // Third has decent size
+func (s shape) bullish() bool { return s.close > s.open }
+func (s shape) bearish() bool { return s.close < s.open }
 
-		return &CandlestickPattern{
-			Name:        "Evening Star",
-			Type:        "bearish",
-			Strength:    75.0,
-			Description: "Strong three-candle bearish reversal pattern",
-		}
+func (s shape) direction() string {
+	switch {
+	case s.bullish():
+		return "bullish"
+	case s.bearish():
+		return "bearish"
+	default:
+		return "neutral"
 	}
-
-	return nil
 }
 
+func (s shape) bodyTop() float64    { return math.Max(s.open, s.close) }
+func (s shape) bodyBottom() float64 { return math.Min(s.open, s.close) }
+func (s shape) bodyMiddle() float64 { return (s.open + s.close) / 2 }
+
 // GetStrongestPattern returns the pattern with highest strength
 func GetStrongestPattern(patterns []*CandlestickPattern) *CandlestickPattern {
 	if len(patterns) == 0 {
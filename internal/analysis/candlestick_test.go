@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+func candle(open, high, low, close float64) *models.OHLCV {
+	return &models.OHLCV{Open: open, High: high, Low: low, Close: close, Volume: 1000}
+}
+
+// trending returns count candles whose closes move by step from start, each
+// opening half a step back
+func trending(count int, start, step float64) []*models.OHLCV {
+	candles := make([]*models.OHLCV, count)
+	for i := range candles {
+		close := start + float64(i)*step
+		open := close - step/2
+		candles[i] = candle(open, math.Max(open, close)+0.25, math.Min(open, close)-0.25, close)
+	}
+	return candles
+}
+
+func TestCandlestickPatterns(t *testing.T) {
+	hammer := candle(100.7, 101.05, 99, 101)
+
+	tests := []struct {
+		name    string
+		candles []*models.OHLCV
+		want    string
+		absent  []string
+	}{
+		{"hammer after downtrend", append(trending(10, 110, -1), hammer), "hammer", []string{"hanging_man"}},
+		{"hanging man after uptrend", append(trending(10, 92, 1), hammer), "hanging_man", []string{"hammer"}},
+		{"bullish engulfing", append(trending(10, 110, -1),
+			candle(101.5, 101.6, 100.4, 100.5), candle(100.3, 102.1, 100.2, 102)), "bullish_engulfing", nil},
+		{"tweezer bottom", append(trending(10, 111, -1),
+			candle(101.5, 101.6, 100, 100.5), candle(100.6, 101.8, 100.02, 101.6)), "tweezer_bottom", []string{"tweezer_top"}},
+		{"evening star", append(trending(10, 92, 1),
+			candle(101, 103.1, 100.9, 103), candle(103.2, 103.6, 103, 103.3), candle(103, 103.1, 101.4, 101.5)), "evening_star", nil},
+		{"three white soldiers", append(trending(10, 100, 0),
+			candle(100, 101.1, 99.95, 101), candle(100.5, 102.1, 100.45, 102), candle(101.5, 103.1, 101.45, 103)), "three_white_soldiers", nil},
+		{"inside bar", []*models.OHLCV{candle(100, 105, 95, 104), candle(101, 103, 98, 102)}, "inside_bar", nil},
+	}
+
+	analyzer := NewCandlestickAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patterns := analyzer.DetectPatterns(tt.candles)
+
+			found := make(map[string]*CandlestickPattern)
+			for _, pattern := range patterns {
+				found[pattern.Name] = pattern
+				if pattern.Strength < 0 || pattern.Strength > 100 {
+					t.Errorf("%s strength %v out of range", pattern.Name, pattern.Strength)
+				}
+				if !IsCandlestickPattern(pattern.Name) {
+					t.Errorf("%s missing from the catalog", pattern.Name)
+				}
+			}
+
+			if found[tt.want] == nil {
+				t.Fatalf("Expected %s, got %+v", tt.want, patterns)
+			}
+			for _, name := range tt.absent {
+				if found[name] != nil {
+					t.Errorf("Unexpected %s", name)
+				}
+			}
+		})
+	}
+}
+
+func TestHammerNeedsDowntrend(t *testing.T) {
+	analyzer := NewCandlestickAnalyzer()
+	hammer := candle(100.7, 101.05, 99, 101)
+
+	for _, pattern := range analyzer.DetectPatterns(append(trending(10, 101, 0), hammer)) {
+		if pattern.Name == "hammer" || pattern.Name == "hanging_man" {
+			t.Errorf("Expected no hammer without a prior trend, got %s", pattern.Name)
+		}
+	}
+
+	// A reversal after the trend it reverses is stronger
+	patterns := analyzer.DetectPatterns(append(trending(10, 92, 1),
+		candle(101, 103.1, 100.9, 103), candle(103.2, 103.6, 103, 103.3), candle(103, 103.1, 101.4, 101.5)))
+	star := GetStrongestPattern(patterns)
+	if star == nil || star.Name != "evening_star" || star.Strength != 90 || star.Candles != 3 {
+		t.Errorf("Expected a 90 strength evening star, got %+v", star)
+	}
+}
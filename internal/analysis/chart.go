@@ -3,7 +3,7 @@ package analysis
 import (
 	"math"
 
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-212: Chart patterns (breakouts, reversals, continuations)
@@ -11,14 +11,35 @@ import (
 // ChartPatternAnalyzer detects chart patterns
 type ChartPatternAnalyzer struct {
 	minPatternLength int     // Minimum candles for pattern
-	tolerance        float64 // Price tolerance for pattern recognition
+	pivotSpan        int     // Candles on each side of a swing high or low
+	maxPatternAge    int     // Candles since the last pivot of a current pattern
+	levelTolerance   float64 // Difference of equal levels, as a share of the pattern height
+	minHeightRanges  float64 // Minimum pattern height in average candle ranges
+	flatSlope        float64 // Maximum slope of a flat line, in average ranges per candle
+	poleRanges       float64 // Minimum flag pole height in average candle ranges
+	maxPoleLength    int     // Maximum candles of a flag pole
+	minFlagLength    int     // Minimum candles of a flag or pennant
+	maxFlagLength    int     // Maximum candles of a flag or pennant
+
+	// REQ-275: Swings confirmed within the last pivotSpan candles
+	swings *SwingDetector
 }
 
-// NewChartPatternAnalyzer creates a new chart pattern analyzer
-func NewChartPatternAnalyzer() *ChartPatternAnalyzer {
+// NewChartPatternAnalyzer creates a new chart pattern analyzer pivoting on
+// the swings of a detector
+func NewChartPatternAnalyzer(swings *SwingDetector) *ChartPatternAnalyzer {
 	return &ChartPatternAnalyzer{
-		minPatternLength: 10,   // Minimum 10 candles
-		tolerance:        0.02, // 2% tolerance
+		minPatternLength: 10, // Minimum 10 candles
+		pivotSpan:        3,
+		maxPatternAge:    20,
+		levelTolerance:   0.15, // Equal within 15% of the pattern height
+		minHeightRanges:  2,
+		flatSlope:        0.03,
+		poleRanges:       4,
+		maxPoleLength:    12,
+		minFlagLength:    4,
+		maxFlagLength:    15,
+		swings:           swings,
 	}
 }
 
@@ -29,19 +50,31 @@ func (cpa *ChartPatternAnalyzer) DetectPatterns(candles []*models.OHLCV) []Chart
 	}
 
 	patterns := make([]ChartPatternResult, 0)
+	add := func(pattern *ChartPatternResult) bool {
+		if pattern == nil {
+			return false
+		}
+		patterns = append(patterns, *pattern)
+		return true
+	}
 
 	// Detect various chart patterns
-	if breakout := cpa.detectBreakout(candles); breakout != nil {
-		patterns = append(patterns, *breakout)
+	add(cpa.detectBreakout(candles))
+
+	// REQ-271: Reversal and continuation patterns are detected on the chart
+	// and on its mirror image, so each detector finds both orientations
+	chart := cpa.newChartContext(candles, false)
+	for _, c := range []*chartContext{chart, cpa.newChartContext(candles, true)} {
+		// A head and shoulders also holds a double top; report the larger pattern
+		if !add(cpa.detectHeadAndShoulders(c)) && !add(cpa.detectTripleTop(c)) {
+			add(cpa.detectDoubleTop(c))
+		}
+		add(cpa.detectFlag(c))
 	}
 
-	if triangle := cpa.detectTriangle(candles); triangle != nil {
-		patterns = append(patterns, *triangle)
-	}
-
-	if headShoulders := cpa.detectHeadAndShoulders(candles); headShoulders != nil {
-		patterns = append(patterns, *headShoulders)
-	}
+	// Triangles, wedges and rectangles classify both orientations themselves
+	add(cpa.detectConsolidation(chart))
+	add(cpa.detectCupAndHandle(chart))
 
 	return patterns
 }
@@ -55,7 +88,13 @@ type ChartPatternResult struct {
 	Target     float64 `json:"target,omitempty"`
 	StopLoss   float64 `json:"stop_loss,omitempty"`
 	Timeframe  string  `json:"timeframe"`
-	Status     string  `json:"status"`
+	Status     string  `json:"status"` // forming, confirmed, failed
+
+	// REQ-271: Geometry for drawing the pattern
+	Neckline     *models.PatternLine   `json:"neckline,omitempty"`
+	Boundaries   []models.PatternLine  `json:"boundaries,omitempty"`
+	Points       []models.PatternPoint `json:"points,omitempty"`
+	Invalidation float64               `json:"invalidation,omitempty"`
 }
 
 func (cpa *ChartPatternAnalyzer) detectBreakout(candles []*models.OHLCV) *ChartPatternResult {
@@ -82,30 +121,42 @@ func (cpa *ChartPatternAnalyzer) detectBreakout(candles []*models.OHLCV) *ChartP
 
 	rangeSize := high - low
 
+	// REQ-271: The consolidation range is drawn as the pattern boundaries
+	boundaries := []models.PatternLine{
+		{Label: "upper", Start: models.PatternPoint{Time: consolidation[0].Timestamp, Price: high},
+			End: models.PatternPoint{Time: consolidation[len(consolidation)-1].Timestamp, Price: high}},
+		{Label: "lower", Start: models.PatternPoint{Time: consolidation[0].Timestamp, Price: low},
+			End: models.PatternPoint{Time: consolidation[len(consolidation)-1].Timestamp, Price: low}},
+	}
+
 	// Check if recent candles break out
 	for _, candle := range recent {
 		if candle.Close > high {
 			return &ChartPatternResult{
-				Type:       "breakout",
-				Name:       "Bullish Breakout",
-				Confidence: 75.0,
-				Signal:     "bullish",
-				Target:     candle.Close + rangeSize,
-				StopLoss:   high * 0.98,
-				Timeframe:  "medium",
-				Status:     "confirmed",
+				Type:         "breakout",
+				Name:         "Bullish Breakout",
+				Confidence:   75.0,
+				Signal:       "bullish",
+				Target:       candle.Close + rangeSize,
+				StopLoss:     high * 0.98,
+				Timeframe:    "medium",
+				Status:       PatternConfirmed,
+				Boundaries:   boundaries,
+				Invalidation: high * 0.98,
 			}
 		}
 		if candle.Close < low {
 			return &ChartPatternResult{
-				Type:       "breakout",
-				Name:       "Bearish Breakdown",
-				Confidence: 75.0,
-				Signal:     "bearish",
-				Target:     candle.Close - rangeSize,
-				StopLoss:   low * 1.02,
-				Timeframe:  "medium",
-				Status:     "confirmed",
+				Type:         "breakout",
+				Name:         "Bearish Breakdown",
+				Confidence:   75.0,
+				Signal:       "bearish",
+				Target:       candle.Close - rangeSize,
+				StopLoss:     low * 1.02,
+				Timeframe:    "medium",
+				Status:       PatternConfirmed,
+				Boundaries:   boundaries,
+				Invalidation: low * 1.02,
 			}
 		}
 	}
@@ -113,510 +164,218 @@ func (cpa *ChartPatternAnalyzer) detectBreakout(candles []*models.OHLCV) *ChartP
 	return nil
 }
 
-func (cpa *ChartPatternAnalyzer) detectTriangle(candles []*models.OHLCV) *ChartPatternResult {
-	if len(candles) < 30 {
-		return nil
-	}
-
-	// Find recent highs and lows
-	highs := make([]float64, 0)
-	lows := make([]float64, 0)
-
-	for i := 5; i < len(candles)-5; i++ {
-		isHigh := true
-		isLow := true
+// REQ-271: Reversal patterns are written for tops; on a mirrored chart they
+// find the matching bottoms.
 
-		// Check if it's a local high
-		for j := i - 5; j <= i+5; j++ {
-			if j != i && candles[j].High >= candles[i].High {
-				isHigh = false
-			}
-		}
-
-		// Check if it's a local low
-		for j := i - 5; j <= i+5; j++ {
-			if j != i && candles[j].Low <= candles[i].Low {
-				isLow = false
-			}
-		}
-
-		if isHigh {
-			highs = append(highs, candles[i].High)
-		}
-		if isLow {
-			lows = append(lows, candles[i].Low)
-		}
+// detectHeadAndShoulders finds a head above two similar shoulders, with the
+// neckline through the troughs between them
+func (cpa *ChartPatternAnalyzer) detectHeadAndShoulders(c *chartContext) *ChartPatternResult {
+	seq := c.peakSequence(5)
+	if seq == nil || !cpa.recent(c, seq[4].index) {
+		return nil
 	}
+	leftShoulder, head, rightShoulder := seq[0], seq[2], seq[4]
 
-	// Check for converging trend lines (triangle pattern)
-	if len(highs) >= 2 && len(lows) >= 2 {
-		// Simplified triangle detection
-		highSlope := (highs[len(highs)-1] - highs[0]) / float64(len(highs))
-		lowSlope := (lows[len(lows)-1] - lows[0]) / float64(len(lows))
-
-		// Converging lines indicate triangle
-		if math.Abs(highSlope-lowSlope) > 0.1 && math.Abs(highSlope) < 1.0 && math.Abs(lowSlope) < 1.0 {
-			currentPrice := candles[len(candles)-1].Close
-
-			return &ChartPatternResult{
-				Type:       "triangle",
-				Name:       "Symmetrical Triangle",
-				Confidence: 65.0,
-				Signal:     "neutral",
-				Target:     currentPrice * 1.05, // 5% move expected
-				StopLoss:   currentPrice * 0.95,
-				Timeframe:  "medium",
-				Status:     "forming",
-			}
-		}
+	neck := lineThrough(seq[1], seq[3])
+	height := head.price - neck.at(head.index)
+	if height < cpa.minHeightRanges*c.avgRange {
+		return nil
 	}
 
-	return nil
-}
-
-func (cpa *ChartPatternAnalyzer) detectHeadAndShoulders(candles []*models.OHLCV) *ChartPatternResult {
-	if len(candles) < 50 {
+	// The head stands out, the shoulders are alike and above the neckline
+	if head.price-math.Max(leftShoulder.price, rightShoulder.price) < cpa.levelTolerance*height ||
+		math.Abs(leftShoulder.price-rightShoulder.price) > 2*cpa.levelTolerance*height ||
+		leftShoulder.price <= neck.at(leftShoulder.index) || rightShoulder.price <= neck.at(rightShoulder.index) {
 		return nil
 	}
 
-	// Find three prominent peaks
-	peaks := make([]struct {
-		index int
-		price float64
-	}, 0)
+	neckline := c.line(neck, "neckline")
+	breakout := neck.at(c.last)
 
-	for i := 10; i < len(candles)-10; i++ {
-		isPeak := true
-
-		// Check if it's a significant peak
-		for j := i - 10; j <= i+10; j++ {
-			if j != i && candles[j].High >= candles[i].High {
-				isPeak = false
-				break
-			}
-		}
-
-		if isPeak {
-			peaks = append(peaks, struct {
-				index int
-				price float64
-			}{i, candles[i].High})
-		}
-	}
-
-	// Need at least 3 peaks for head and shoulders
-	if len(peaks) >= 3 {
-		// Find the highest peak (head) and two shoulders
-		for i := 1; i < len(peaks)-1; i++ {
-			leftShoulder := peaks[i-1]
-			head := peaks[i]
-			rightShoulder := peaks[i+1]
-
-			// Head should be higher than both shoulders
-			if head.price > leftShoulder.price && head.price > rightShoulder.price {
-				// Shoulders should be roughly equal
-				shoulderRatio := leftShoulder.price / rightShoulder.price
-
-				if shoulderRatio > 0.95 && shoulderRatio < 1.05 {
-					neckline := math.Min(leftShoulder.price, rightShoulder.price) * 0.98
-
-					return &ChartPatternResult{
-						Type:       "head_shoulders",
-						Name:       "Head and Shoulders",
-						Confidence: 80.0,
-						Signal:     "bearish",
-						Target:     neckline - (head.price - neckline),
-						StopLoss:   head.price,
-						Timeframe:  "long",
-						Status:     "confirmed",
-					}
-				}
-			}
-		}
+	pattern := &ChartPatternResult{
+		Type:      c.named("head_shoulders", "inverse_head_shoulders"),
+		Name:      c.named("Head and Shoulders", "Inverse Head and Shoulders"),
+		Timeframe: "long",
+		Neckline:  &neckline,
+		Points: []models.PatternPoint{
+			c.pivotPoint(leftShoulder, "left_shoulder"),
+			c.pivotPoint(seq[1], "neckline"),
+			c.pivotPoint(head, "head"),
+			c.pivotPoint(seq[3], "neckline"),
+			c.pivotPoint(rightShoulder, "right_shoulder"),
+		},
 	}
 
-	return nil
-}
-
-// ChartPattern represents a detected chart pattern
-type ChartPattern struct {
-	Name        string  `json:"name"`
-	Type        string  `json:"type"`      // breakout, reversal, continuation
-	Direction   string  `json:"direction"` // bullish, bearish, neutral
-	Strength    float64 `json:"strength"`  // 0-100
-	TargetPrice float64 `json:"target_price,omitempty"`
-	Description string  `json:"description"`
-}
-
-// ChartPatternDetector detects chart patterns
-type ChartPatternDetector struct{}
-
-// NewChartPatternDetector creates a new chart pattern detector
-func NewChartPatternDetector() *ChartPatternDetector {
-	return &ChartPatternDetector{}
+	return c.finish(pattern, false, breakout, breakout-height, rightShoulder.price, 80)
 }
 
-// DetectPatterns identifies chart patterns in candle data
-func (cpd *ChartPatternDetector) DetectPatterns(candles []*models.OHLCV) []*ChartPattern {
-	if len(candles) < 10 {
+// detectTripleTop finds three peaks at one level
+func (cpa *ChartPatternAnalyzer) detectTripleTop(c *chartContext) *ChartPatternResult {
+	seq := c.peakSequence(5)
+	if seq == nil || !cpa.recent(c, seq[4].index) {
 		return nil
 	}
 
-	var patterns []*ChartPattern
-
-	// Support and resistance levels
-	support, resistance := cpd.findSupportResistance(candles)
-
-	// Breakout patterns
-	if pattern := cpd.detectBreakout(candles, support, resistance); pattern != nil {
-		patterns = append(patterns, pattern)
-	}
-
-	// Triangle patterns
-	if pattern := cpd.detectTriangle(candles); pattern != nil {
-		patterns = append(patterns, pattern)
-	}
-
-	// Head and shoulders
-	if pattern := cpd.detectHeadAndShoulders(candles); pattern != nil {
-		patterns = append(patterns, pattern)
-	}
-
-	// Double top/bottom
-	if pattern := cpd.detectDoubleTopBottom(candles); pattern != nil {
-		patterns = append(patterns, pattern)
+	peaks := []pivot{seq[0], seq[2], seq[4]}
+	top, bottom := peaks[0].price, peaks[0].price
+	for _, peak := range peaks[1:] {
+		top = math.Max(top, peak.price)
+		bottom = math.Min(bottom, peak.price)
 	}
 
-	return patterns
-}
-
-// findSupportResistance identifies key support and resistance levels
-func (cpd *ChartPatternDetector) findSupportResistance(candles []*models.OHLCV) (support, resistance float64) {
-	if len(candles) < 5 {
-		return 0, 0
+	neck := lineThrough(seq[1], seq[3])
+	height := (peaks[0].price+peaks[1].price+peaks[2].price)/3 - (seq[1].price+seq[3].price)/2
+	if height < cpa.minHeightRanges*c.avgRange || top-bottom > cpa.levelTolerance*height {
+		return nil
 	}
 
-	// Find recent lows and highs
-	lows := make([]float64, 0)
-	highs := make([]float64, 0)
-
-	// Look for swing lows and highs
-	for i := 2; i < len(candles)-2; i++ {
-		// Swing low: lower than 2 candles on each side
-		if candles[i].Low < candles[i-1].Low && candles[i].Low < candles[i-2].Low &&
-			candles[i].Low < candles[i+1].Low && candles[i].Low < candles[i+2].Low {
-			lows = append(lows, candles[i].Low)
-		}
-
-		// Swing high: higher than 2 candles on each side
-		if candles[i].High > candles[i-1].High && candles[i].High > candles[i-2].High &&
-			candles[i].High > candles[i+1].High && candles[i].High > candles[i+2].High {
-			highs = append(highs, candles[i].High)
-		}
-	}
+	neckline := c.line(neck, "neckline")
+	breakout := neck.at(c.last)
 
-	// Find most significant levels (clustering)
-	if len(lows) > 0 {
-		support = cpd.findMostSignificantLevel(lows)
-	}
-	if len(highs) > 0 {
-		resistance = cpd.findMostSignificantLevel(highs)
+	pattern := &ChartPatternResult{
+		Type:      c.named("triple_top", "triple_bottom"),
+		Name:      c.named("Triple Top", "Triple Bottom"),
+		Timeframe: "long",
+		Neckline:  &neckline,
+		Points: []models.PatternPoint{
+			c.pivotPoint(seq[0], "peak"),
+			c.pivotPoint(seq[1], "trough"),
+			c.pivotPoint(seq[2], "peak"),
+			c.pivotPoint(seq[3], "trough"),
+			c.pivotPoint(seq[4], "peak"),
+		},
 	}
 
-	return support, resistance
+	return c.finish(pattern, false, breakout, breakout-height, top, 75)
 }
 
-// findMostSignificantLevel finds the most significant level from a slice of levels
-func (cpd *ChartPatternDetector) findMostSignificantLevel(levels []float64) float64 {
-	if len(levels) == 0 {
-		return 0
+// detectDoubleTop finds two peaks at one level after an advance
+func (cpa *ChartPatternAnalyzer) detectDoubleTop(c *chartContext) *ChartPatternResult {
+	seq := c.peakSequence(4)
+	if seq == nil || !cpa.recent(c, seq[3].index) {
+		return nil
 	}
+	first, trough, second := seq[1], seq[2], seq[3]
 
-	// Simple approach: return the level that appears most frequently within 1% range
-	tolerance := 0.01 // 1%
-	maxCount := 0
-	significantLevel := levels[0]
-
-	for _, level := range levels {
-		count := 0
-		for _, otherLevel := range levels {
-			if math.Abs(level-otherLevel)/level < tolerance {
-				count++
-			}
-		}
-		if count > maxCount {
-			maxCount = count
-			significantLevel = level
-		}
+	// A top needs the advance it reverses
+	if seq[0].price >= trough.price {
+		return nil
 	}
 
-	return significantLevel
-}
-
-// detectBreakout detects breakout patterns
-func (cpd *ChartPatternDetector) detectBreakout(candles []*models.OHLCV, support, resistance float64) *ChartPattern {
-	if len(candles) < 2 || support == 0 || resistance == 0 {
+	top := math.Max(first.price, second.price)
+	height := (first.price+second.price)/2 - trough.price
+	if height < cpa.minHeightRanges*c.avgRange || math.Abs(first.price-second.price) > cpa.levelTolerance*height {
 		return nil
 	}
 
-	current := candles[len(candles)-1]
-	previous := candles[len(candles)-2]
-
-	// Volume threshold for confirming breakout
-	recentVolume := float64(current.Volume)
-	avgVolume := cpd.calculateAverageVolume(candles, 20)
-
-	// Resistance breakout
-	if previous.Close <= resistance && current.Close > resistance && recentVolume > avgVolume*1.5 {
-		target := resistance + (resistance - support)
-		return &ChartPattern{
-			Name:        "Resistance Breakout",
-			Type:        "breakout",
-			Direction:   "bullish",
-			Strength:    75.0,
-			TargetPrice: target,
-			Description: "Price broke above resistance with high volume",
-		}
-	}
+	neckline := c.line(horizontalLine(trough.index, c.last, trough.price), "neckline")
 
-	// Support breakdown
-	if previous.Close >= support && current.Close < support && recentVolume > avgVolume*1.5 {
-		target := support - (resistance - support)
-		return &ChartPattern{
-			Name:        "Support Breakdown",
-			Type:        "breakout",
-			Direction:   "bearish",
-			Strength:    75.0,
-			TargetPrice: target,
-			Description: "Price broke below support with high volume",
-		}
+	pattern := &ChartPatternResult{
+		Type:      c.named("double_top", "double_bottom"),
+		Name:      c.named("Double Top", "Double Bottom"),
+		Timeframe: "medium",
+		Neckline:  &neckline,
+		Points: []models.PatternPoint{
+			c.pivotPoint(first, "peak"),
+			c.pivotPoint(trough, "trough"),
+			c.pivotPoint(second, "peak"),
+		},
 	}
 
-	return nil
+	return c.finish(pattern, false, trough.price, trough.price-height, top, 70)
 }
 
-// detectTriangle detects triangle patterns
-func (cpd *ChartPatternDetector) detectTriangle(candles []*models.OHLCV) *ChartPattern {
-	if len(candles) < 20 {
-		return nil
-	}
-
-	// Look for converging trend lines
-	recent := candles[len(candles)-20:]
-
-	// Find highs and lows for trend lines
-	highs := make([]float64, 0)
-	lows := make([]float64, 0)
-
-	for i := 1; i < len(recent)-1; i++ {
-		if recent[i].High > recent[i-1].High && recent[i].High > recent[i+1].High {
-			highs = append(highs, recent[i].High)
-		}
-		if recent[i].Low < recent[i-1].Low && recent[i].Low < recent[i+1].Low {
-			lows = append(lows, recent[i].Low)
+// detectCupAndHandle finds a rounded bottom between two rims at one level,
+// followed by a shallow pullback from the right rim
+func (cpa *ChartPatternAnalyzer) detectCupAndHandle(c *chartContext) *ChartPatternResult {
+	// The right rim is the latest swing high; the handle follows it
+	right := -1
+	for k := len(c.pivots) - 1; k >= 0; k-- {
+		if c.pivots[k].high {
+			right = k
+			break
 		}
 	}
-
-	if len(highs) < 2 || len(lows) < 2 {
+	if right < 2 || !cpa.recent(c, c.pivots[right].index) {
 		return nil
 	}
+	rightRim := c.pivots[right]
 
-	// Calculate trend line slopes
-	highSlope := cpd.calculateSlope(highs)
-	lowSlope := cpd.calculateSlope(lows)
-
-	// Ascending triangle: flat resistance, rising support
-	if math.Abs(highSlope) < 0.001 && lowSlope > 0.001 {
-		return &ChartPattern{
-			Name:        "Ascending Triangle",
-			Type:        "continuation",
-			Direction:   "bullish",
-			Strength:    65.0,
-			Description: "Bullish continuation pattern with rising support",
+	handle := rightRim.index
+	handleLow := rightRim.price
+	for i := rightRim.index + 1; i <= c.last; i++ {
+		if c.candles[i].Low < handleLow {
+			handle, handleLow = i, c.candles[i].Low
 		}
 	}
+	handleLength := c.last - rightRim.index
 
-	// Descending triangle: falling resistance, flat support
-	if highSlope < -0.001 && math.Abs(lowSlope) < 0.001 {
-		return &ChartPattern{
-			Name:        "Descending Triangle",
-			Type:        "continuation",
-			Direction:   "bearish",
-			Strength:    65.0,
-			Description: "Bearish continuation pattern with falling resistance",
+	// The nearest earlier swing high at the rim level that forms a cup
+	for k := right - 2; k >= 0; k-- {
+		leftRim := c.pivots[k]
+		if !leftRim.high {
+			continue
 		}
-	}
-
-	// Symmetrical triangle: converging lines
-	if highSlope < -0.001 && lowSlope > 0.001 && math.Abs(highSlope+lowSlope) < 0.002 {
-		return &ChartPattern{
-			Name:        "Symmetrical Triangle",
-			Type:        "continuation",
-			Direction:   "neutral",
-			Strength:    50.0,
-			Description: "Neutral continuation pattern, awaiting breakout direction",
+		length := rightRim.index - leftRim.index
+		if length < 2*cpa.minPatternLength {
+			continue
 		}
-	}
-
-	return nil
-}
 
-// detectHeadAndShoulders detects head and shoulders patterns
-func (cpd *ChartPatternDetector) detectHeadAndShoulders(candles []*models.OHLCV) *ChartPattern {
-	if len(candles) < 30 {
-		return nil
-	}
-
-	// Look for three peaks pattern
-	recent := candles[len(candles)-30:]
-	peaks := make([]struct {
-		index int
-		value float64
-	}, 0)
-
-	// Find peaks
-	for i := 2; i < len(recent)-2; i++ {
-		if recent[i].High > recent[i-1].High && recent[i].High > recent[i-2].High &&
-			recent[i].High > recent[i+1].High && recent[i].High > recent[i+2].High {
-			peaks = append(peaks, struct {
-				index int
-				value float64
-			}{i, recent[i].High})
+		bottom := leftRim.index
+		interiorHigh := 0.0
+		for i := leftRim.index + 1; i < rightRim.index; i++ {
+			if c.candles[i].Low < c.candles[bottom].Low {
+				bottom = i
+			}
+			interiorHigh = math.Max(interiorHigh, c.candles[i].High)
 		}
-	}
+		rim := math.Max(leftRim.price, rightRim.price)
+		depth := math.Min(leftRim.price, rightRim.price) - c.candles[bottom].Low
 
-	if len(peaks) < 3 {
-		return nil
-	}
+		if depth < cpa.minHeightRanges*c.avgRange || rim-math.Min(leftRim.price, rightRim.price) > cpa.levelTolerance*depth ||
+			interiorHigh > rim {
+			continue
+		}
 
-	// Check for head and shoulders pattern (middle peak higher than others)
-	for i := 1; i < len(peaks)-1; i++ {
-		leftShoulder := peaks[i-1]
-		head := peaks[i]
-		rightShoulder := peaks[i+1]
-
-		// Head should be higher than both shoulders
-		if head.value > leftShoulder.value && head.value > rightShoulder.value {
-			// Shoulders should be roughly equal (within 5%)
-			shoulderDiff := math.Abs(leftShoulder.value-rightShoulder.value) / leftShoulder.value
-			if shoulderDiff < 0.05 {
-				return &ChartPattern{
-					Name:        "Head and Shoulders",
-					Type:        "reversal",
-					Direction:   "bearish",
-					Strength:    80.0,
-					Description: "Strong bearish reversal pattern",
-				}
+		// Rounded rather than V-shaped: the low sits in the middle half and
+		// the price spends a while near it
+		position := float64(bottom-leftRim.index) / float64(length)
+		nearBottom := 0
+		for i := leftRim.index + 1; i < rightRim.index; i++ {
+			if c.candles[i].Low <= c.candles[bottom].Low+depth/3 {
+				nearBottom++
 			}
 		}
-	}
-
-	return nil
-}
-
-// detectDoubleTopBottom detects double top/bottom patterns
-func (cpd *ChartPatternDetector) detectDoubleTopBottom(candles []*models.OHLCV) *ChartPattern {
-	if len(candles) < 20 {
-		return nil
-	}
-
-	recent := candles[len(candles)-20:]
-
-	// Find significant peaks and troughs
-	peaks := make([]float64, 0)
-	troughs := make([]float64, 0)
-
-	for i := 2; i < len(recent)-2; i++ {
-		// Peak
-		if recent[i].High > recent[i-1].High && recent[i].High > recent[i-2].High &&
-			recent[i].High > recent[i+1].High && recent[i].High > recent[i+2].High {
-			peaks = append(peaks, recent[i].High)
+		if position < 0.25 || position > 0.75 || float64(nearBottom) < 0.15*float64(length) {
+			continue
 		}
 
-		// Trough
-		if recent[i].Low < recent[i-1].Low && recent[i].Low < recent[i-2].Low &&
-			recent[i].Low < recent[i+1].Low && recent[i].Low < recent[i+2].Low {
-			troughs = append(troughs, recent[i].Low)
+		// The handle pulls back, but less than half of the cup, and takes
+		// less time than the cup
+		if handleLength < 2 || handleLength > length/2 ||
+			handleLow > rightRim.price-0.1*depth || handleLow < rightRim.price-depth/2 {
+			return nil
 		}
-	}
 
-	// Double top: two peaks at similar levels
-	if len(peaks) >= 2 {
-		for i := 0; i < len(peaks)-1; i++ {
-			for j := i + 1; j < len(peaks); j++ {
-				diff := math.Abs(peaks[i]-peaks[j]) / peaks[i]
-				if diff < 0.03 { // Within 3%
-					return &ChartPattern{
-						Name:        "Double Top",
-						Type:        "reversal",
-						Direction:   "bearish",
-						Strength:    70.0,
-						Description: "Bearish reversal pattern with two peaks",
-					}
-				}
-			}
+		resistance := lineThrough(leftRim, rightRim)
+		breakout := resistance.at(c.last)
+		neckline := c.line(resistance, "neckline")
+
+		pattern := &ChartPatternResult{
+			Type:      "cup_handle",
+			Name:      "Cup and Handle",
+			Timeframe: "long",
+			Neckline:  &neckline,
+			Points: []models.PatternPoint{
+				c.pivotPoint(leftRim, "left_rim"),
+				c.point(bottom, c.candles[bottom].Low, "bottom"),
+				c.pivotPoint(rightRim, "right_rim"),
+				c.point(handle, handleLow, "handle"),
+			},
 		}
-	}
 
-	// Double bottom: two troughs at similar levels
-	if len(troughs) >= 2 {
-		for i := 0; i < len(troughs)-1; i++ {
-			for j := i + 1; j < len(troughs); j++ {
-				diff := math.Abs(troughs[i]-troughs[j]) / troughs[i]
-				if diff < 0.03 { // Within 3%
-					return &ChartPattern{
-						Name:        "Double Bottom",
-						Type:        "reversal",
-						Direction:   "bullish",
-						Strength:    70.0,
-						Description: "Bullish reversal pattern with two troughs",
-					}
-				}
-			}
-		}
+		return c.finish(pattern, true, breakout, breakout+depth, handleLow, 70)
 	}
 
 	return nil
 }
-
-// calculateAverageVolume calculates average volume over a period
-func (cpd *ChartPatternDetector) calculateAverageVolume(candles []*models.OHLCV, period int) float64 {
-	if len(candles) < period {
-		period = len(candles)
-	}
-
-	sum := int64(0)
-	for i := len(candles) - period; i < len(candles); i++ {
-		sum += candles[i].Volume
-	}
-
-	return float64(sum) / float64(period)
-}
-
-// calculateSlope calculates the slope of a series of values
-func (cpd *ChartPatternDetector) calculateSlope(values []float64) float64 {
-	if len(values) < 2 {
-		return 0
-	}
-
-	n := float64(len(values))
-	sumX := (n - 1) * n / 2 // Sum of indices 0, 1, 2, ..., n-1
-	sumY := 0.0
-	sumXY := 0.0
-	sumX2 := (n - 1) * n * (2*n - 1) / 6 // Sum of squares of indices
-
-	for i, value := range values {
-		sumY += value
-		sumXY += float64(i) * value
-	}
-
-	// Linear regression slope: (n*sumXY - sumX*sumY) / (n*sumX2 - sumX^2)
-	numerator := n*sumXY - sumX*sumY
-	denominator := n*sumX2 - sumX*sumX
-
-	if denominator == 0 {
-		return 0
-	}
-
-	return numerator / denominator
-}
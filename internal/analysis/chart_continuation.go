@@ -0,0 +1,183 @@
+package analysis
+
+import (
+	"math"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-271: Continuation and consolidation patterns
+
+// detectFlag finds a sharp advance (the pole) followed by a short
+// consolidation that gives back less than half of it: a flag when the
+// consolidation drifts in a channel against the pole, a pennant when it
+// converges. On a mirrored chart it finds the bearish versions.
+func (cpa *ChartPatternAnalyzer) detectFlag(c *chartContext) *ChartPatternResult {
+	// The latest candle is measured against the consolidation before it;
+	// prefer the longest consolidation that qualifies
+	for length := cpa.maxFlagLength; length >= cpa.minFlagLength; length-- {
+		poleEnd := c.last - 1 - length
+		if poleEnd < 2 {
+			continue
+		}
+
+		poleStart := poleEnd
+		for i := poleEnd - 1; i >= 0 && i >= poleEnd-cpa.maxPoleLength; i-- {
+			if c.candles[i].Low < c.candles[poleStart].Low {
+				poleStart = i
+			}
+		}
+		top := c.candles[poleEnd].High
+		pole := top - c.candles[poleStart].Low
+		if poleEnd-poleStart < 2 || pole < cpa.poleRanges*c.avgRange {
+			continue
+		}
+
+		// The pole ends at the highest high of the pattern and the
+		// consolidation retraces less than half of it
+		highs := make([]float64, 0, length)
+		lows := make([]float64, 0, length)
+		valid := true
+		for i := poleStart; i < c.last; i++ {
+			if c.candles[i].High > top {
+				valid = false
+				break
+			}
+			if i > poleEnd {
+				highs = append(highs, c.candles[i].High)
+				lows = append(lows, c.candles[i].Low)
+			}
+		}
+		if !valid {
+			continue
+		}
+		floor := lows[0]
+		for _, low := range lows[1:] {
+			floor = math.Min(floor, low)
+		}
+		if top-floor > pole/2 {
+			continue
+		}
+
+		upper := fitLine(poleEnd+1, highs)
+		lower := fitLine(poleEnd+1, lows)
+		upperSlope := upper.slope() / c.avgRange
+		lowerSlope := lower.slope() / c.avgRange
+
+		pattern := &ChartPatternResult{
+			Timeframe:  "short",
+			Boundaries: []models.PatternLine{c.line(upper, "upper"), c.line(lower, "lower")},
+			Points: []models.PatternPoint{
+				c.point(poleStart, c.candles[poleStart].Low, "pole_start"),
+				c.point(poleEnd, top, "pole_end"),
+			},
+		}
+
+		confidence := 70.0
+		switch {
+		case upperSlope < 0 && lowerSlope > 0 && upperSlope-lowerSlope < -cpa.flatSlope:
+			pattern.Type = c.named("bullish_pennant", "bearish_pennant")
+			pattern.Name = c.named("Bullish Pennant", "Bearish Pennant")
+			confidence = 65
+		case upperSlope <= cpa.flatSlope && lowerSlope <= cpa.flatSlope &&
+			math.Abs(upperSlope-lowerSlope) <= 2*cpa.flatSlope:
+			pattern.Type = c.named("bull_flag", "bear_flag")
+			pattern.Name = c.named("Bull Flag", "Bear Flag")
+		default:
+			continue
+		}
+
+		// A flag that gives back half of the pole has failed
+		breakout := upper.at(c.last)
+		return c.finish(pattern, true, breakout, breakout+pole, top-pole/2, confidence)
+	}
+
+	return nil
+}
+
+// detectConsolidation classifies the trendlines through the last two swing
+// highs and lows as a rectangle, a triangle or a wedge
+func (cpa *ChartPatternAnalyzer) detectConsolidation(c *chartContext) *ChartPatternResult {
+	if len(c.pivots) < 4 {
+		return nil
+	}
+	seq := c.pivots[len(c.pivots)-4:]
+	start := seq[0].index
+	if !cpa.recent(c, seq[3].index) || c.last-start < cpa.minPatternLength {
+		return nil
+	}
+
+	var highs, lows []pivot
+	for _, p := range seq {
+		if p.high {
+			highs = append(highs, p)
+		} else {
+			lows = append(lows, p)
+		}
+	}
+	upper := lineThrough(highs[0], highs[1])
+	lower := lineThrough(lows[0], lows[1])
+
+	// Converging lines must not have met yet
+	height := upper.at(start) - lower.at(start)
+	top, bottom := upper.at(c.last), lower.at(c.last)
+	if height < cpa.minHeightRanges*c.avgRange || top <= bottom {
+		return nil
+	}
+
+	pattern := &ChartPatternResult{
+		Timeframe:  "medium",
+		Boundaries: []models.PatternLine{c.line(upper, "upper"), c.line(lower, "lower")},
+	}
+	for _, p := range seq {
+		label := "trough"
+		if p.high {
+			label = "peak"
+		}
+		pattern.Points = append(pattern.Points, c.pivotPoint(p, label))
+	}
+
+	// Rectangles and symmetrical triangles break out either way; until they
+	// do they are expected to continue the trend that led into them
+	neutral := func(confidence float64) *ChartPatternResult {
+		prior := c.candles[start].Close - c.candles[max(0, 2*start-c.last)].Close
+		if c.close > top || c.close >= bottom && prior >= 0 {
+			c.finish(pattern, true, top, top+height, bottom, confidence)
+		} else {
+			c.finish(pattern, false, bottom, bottom-height, top, confidence)
+		}
+		if pattern.Status == PatternForming {
+			pattern.Signal = "neutral"
+		}
+		return pattern
+	}
+
+	upperSlope := upper.slope() / c.avgRange
+	lowerSlope := lower.slope() / c.avgRange
+	upperFlat := math.Abs(upperSlope) <= cpa.flatSlope
+	lowerFlat := math.Abs(lowerSlope) <= cpa.flatSlope
+
+	switch {
+	case upperFlat && lowerFlat:
+		pattern.Type, pattern.Name = "rectangle", "Rectangle"
+		return neutral(55)
+	case upperFlat && lowerSlope > 0:
+		pattern.Type, pattern.Name = "ascending_triangle", "Ascending Triangle"
+		return c.finish(pattern, true, top, top+height, bottom, 65)
+	case lowerFlat && upperSlope < 0:
+		pattern.Type, pattern.Name = "descending_triangle", "Descending Triangle"
+		return c.finish(pattern, false, bottom, bottom-height, top, 65)
+	case upperSlope < 0 && lowerSlope > 0:
+		pattern.Type, pattern.Name = "symmetrical_triangle", "Symmetrical Triangle"
+		return neutral(55)
+	case upperSlope > 0 && lowerSlope > upperSlope:
+		// Wedges retrace to where they began
+		pattern.Type, pattern.Name = "rising_wedge", "Rising Wedge"
+		return c.finish(pattern, false, bottom, lower.at(start), top, 60)
+	case lowerSlope < 0 && upperSlope < lowerSlope:
+		pattern.Type, pattern.Name = "falling_wedge", "Falling Wedge"
+		return c.finish(pattern, true, top, upper.at(start), bottom, 60)
+	}
+
+	return nil
+}
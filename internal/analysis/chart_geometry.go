@@ -0,0 +1,271 @@
+package analysis
+
+import (
+	"math"
+	"strings"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-271: Pivots, trendlines and orientation shared by the chart patterns
+
+// Chart pattern states
+const (
+	PatternForming   = "forming"
+	PatternConfirmed = "confirmed"
+	PatternFailed    = "failed"
+)
+
+// rangePeriods is the number of recent candles the average range is taken over
+const rangePeriods = 50
+
+// pivot is a swing high or low
+type pivot struct {
+	index int
+	price float64
+	high  bool
+}
+
+// trendline is a line through two candle indexes
+type trendline struct {
+	x1, x2 int
+	y1, y2 float64
+}
+
+func lineThrough(a, b pivot) trendline {
+	return trendline{x1: a.index, y1: a.price, x2: b.index, y2: b.price}
+}
+
+func horizontalLine(from, to int, price float64) trendline {
+	return trendline{x1: from, y1: price, x2: to, y2: price}
+}
+
+// at returns the value of the line at a candle index
+func (l trendline) at(index int) float64 {
+	if l.x2 == l.x1 {
+		return l.y1
+	}
+	return l.y1 + l.slope()*float64(index-l.x1)
+}
+
+// slope returns the change per candle
+func (l trendline) slope() float64 {
+	if l.x2 == l.x1 {
+		return 0
+	}
+	return (l.y2 - l.y1) / float64(l.x2-l.x1)
+}
+
+// fitLine fits a least-squares line to values starting at candle index from
+func fitLine(from int, values []float64) trendline {
+	n := float64(len(values))
+	if len(values) < 2 {
+		return horizontalLine(from, from, values[0])
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i, value := range values {
+		x := float64(i)
+		sumX += x
+		sumY += value
+		sumXY += x * value
+		sumXX += x * x
+	}
+
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	intercept := (sumY - slope*sumX) / n
+	to := from + len(values) - 1
+
+	return trendline{x1: from, y1: intercept, x2: to, y2: intercept + slope*float64(len(values)-1)}
+}
+
+// chartContext is a candle sequence prepared for pattern detection. Mirrored
+// contexts negate prices, so a detector written for tops finds bottoms and
+// one written for bull flags finds bear flags.
+type chartContext struct {
+	candles  []*models.OHLCV
+	pivots   []pivot
+	last     int
+	close    float64
+	avgRange float64
+	mirrored bool
+}
+
+func (cpa *ChartPatternAnalyzer) newChartContext(candles []*models.OHLCV, mirrored bool) *chartContext {
+	if mirrored {
+		flipped := make([]*models.OHLCV, len(candles))
+		for i, candle := range candles {
+			flipped[i] = &models.OHLCV{
+				Timestamp: candle.Timestamp,
+				Open:      -candle.Open,
+				High:      -candle.Low,
+				Low:       -candle.High,
+				Close:     -candle.Close,
+				Volume:    candle.Volume,
+			}
+		}
+		candles = flipped
+	}
+
+	chart := &chartContext{
+		candles:  candles,
+		last:     len(candles) - 1,
+		close:    candles[len(candles)-1].Close,
+		mirrored: mirrored,
+	}
+	chart.pivots = cpa.findPivots(candles)
+
+	recent := candles
+	if len(recent) > rangePeriods {
+		recent = recent[len(recent)-rangePeriods:]
+	}
+	for _, candle := range recent {
+		chart.avgRange += candle.High - candle.Low
+	}
+	chart.avgRange /= float64(len(recent))
+
+	return chart
+}
+
+// findPivots returns alternating swing highs and lows: candles whose high
+// (low) is the extreme of pivotSpan candles on each side, and swings
+// confirmed after the last of those. Of consecutive pivots of one kind the
+// more extreme is kept.
+func (cpa *ChartPatternAnalyzer) findPivots(candles []*models.OHLCV) []pivot {
+	span := cpa.pivotSpan
+	var pivots []pivot
+
+	for i := span; i < len(candles)-span; i++ {
+		isHigh, isLow := true, true
+		for j := i - span; j <= i+span; j++ {
+			if j == i {
+				continue
+			}
+			// Ties go to the earlier candle
+			if candles[j].High > candles[i].High || j > i && candles[j].High == candles[i].High {
+				isHigh = false
+			}
+			if candles[j].Low < candles[i].Low || j > i && candles[j].Low == candles[i].Low {
+				isLow = false
+			}
+		}
+
+		if isHigh {
+			pivots = addPivot(pivots, pivot{index: i, price: candles[i].High, high: true})
+		}
+		if isLow {
+			pivots = addPivot(pivots, pivot{index: i, price: candles[i].Low})
+		}
+	}
+
+	// REQ-275: The last pivotSpan candles have no fractal pivots; swings
+	// confirmed there by a sharp reversal count as pivots already
+	for _, swing := range recentSwings(cpa.swings.Detect(candles), max(span, len(candles)-span)) {
+		pivots = addPivot(pivots, pivot{index: swing.Index, price: swing.Price, high: swing.High})
+	}
+
+	return pivots
+}
+
+func addPivot(pivots []pivot, p pivot) []pivot {
+	if n := len(pivots); n > 0 && pivots[n-1].high == p.high {
+		if p.high && p.price > pivots[n-1].price || !p.high && p.price < pivots[n-1].price {
+			pivots[n-1] = p
+		}
+		return pivots
+	}
+	return append(pivots, p)
+}
+
+// peakSequence returns the last count pivots ending with the latest swing high
+func (c *chartContext) peakSequence(count int) []pivot {
+	for k := len(c.pivots) - 1; k >= 0; k-- {
+		if c.pivots[k].high {
+			if k+1 < count {
+				return nil
+			}
+			return c.pivots[k+1-count : k+1]
+		}
+	}
+	return nil
+}
+
+// named returns the name of a pattern in the chart's orientation
+func (c *chartContext) named(name, mirroredName string) string {
+	if c.mirrored {
+		return mirroredName
+	}
+	return name
+}
+
+var mirroredLabels = strings.NewReplacer("peak", "trough", "trough", "peak", "upper", "lower", "lower", "upper")
+
+func (c *chartContext) label(label string) string {
+	if c.mirrored {
+		return mirroredLabels.Replace(label)
+	}
+	return label
+}
+
+// price returns a price of the chart's orientation as a market price
+func (c *chartContext) price(value float64) float64 {
+	if c.mirrored {
+		return -value
+	}
+	return value
+}
+
+func (c *chartContext) point(index int, value float64, label string) models.PatternPoint {
+	return models.PatternPoint{Time: c.candles[index].Timestamp, Price: c.price(value), Label: c.label(label)}
+}
+
+func (c *chartContext) pivotPoint(p pivot, label string) models.PatternPoint {
+	return c.point(p.index, p.price, label)
+}
+
+// line returns a trendline extended to the latest candle
+func (c *chartContext) line(l trendline, label string) models.PatternLine {
+	return models.PatternLine{
+		Label: c.label(label),
+		Start: c.point(l.x1, l.y1, ""),
+		End:   c.point(c.last, l.at(c.last), ""),
+	}
+}
+
+// finish sets the signal, levels, status and confidence of a pattern. The
+// levels are in the chart's orientation, bullish when the pattern breaks out
+// upwards in it: a close beyond the invalidation level fails the pattern, one
+// beyond the breakout level confirms it.
+func (c *chartContext) finish(p *ChartPatternResult, bullish bool, breakout, target, invalidation, confidence float64) *ChartPatternResult {
+	status := PatternForming
+	switch {
+	case bullish && c.close < invalidation, !bullish && c.close > invalidation:
+		status = PatternFailed
+	case bullish && c.close > breakout, !bullish && c.close < breakout:
+		status = PatternConfirmed
+	}
+
+	switch status {
+	case PatternConfirmed:
+		confidence += 10
+	case PatternFailed:
+		confidence /= 2
+	}
+
+	p.Signal = "bearish"
+	if bullish != c.mirrored {
+		p.Signal = "bullish"
+	}
+	p.Status = status
+	p.Confidence = math.Min(100, confidence)
+	p.Target = c.price(target)
+	p.Invalidation = c.price(invalidation)
+	p.StopLoss = p.Invalidation
+
+	return p
+}
+
+// recent reports whether a pattern ending at a candle index is still current
+func (cpa *ChartPatternAnalyzer) recent(c *chartContext, index int) bool {
+	return c.last-index <= cpa.maxPatternAge
+}
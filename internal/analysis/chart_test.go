@@ -0,0 +1,154 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+type leg struct {
+	to   float64
+	bars int
+}
+
+// path returns candles a minute apart whose closes move linearly along the
+// legs, each opening at the previous close
+func path(start float64, legs ...leg) []*models.OHLCV {
+	base := time.Date(2024, 6, 3, 14, 30, 0, 0, time.UTC)
+	var candles []*models.OHLCV
+
+	open := start
+	for _, l := range legs {
+		step := (l.to - open) / float64(l.bars)
+		for i := 0; i < l.bars; i++ {
+			close := open + step
+			c := candle(open, math.Max(open, close)+0.2, math.Min(open, close)-0.2, close)
+			c.Timestamp = base.Add(time.Duration(len(candles)) * time.Minute)
+			candles = append(candles, c)
+			open = close
+		}
+	}
+	return candles
+}
+
+// mirror reflects candles around a price
+func mirror(candles []*models.OHLCV, around float64) []*models.OHLCV {
+	mirrored := make([]*models.OHLCV, len(candles))
+	for i, c := range candles {
+		m := candle(2*around-c.Open, 2*around-c.Low, 2*around-c.High, 2*around-c.Close)
+		m.Timestamp = c.Timestamp
+		mirrored[i] = m
+	}
+	return mirrored
+}
+
+func findChartPattern(patterns []ChartPatternResult, kind string) *ChartPatternResult {
+	for i := range patterns {
+		if patterns[i].Type == kind {
+			return &patterns[i]
+		}
+	}
+	return nil
+}
+
+func TestChartPatterns(t *testing.T) {
+	doubleTop := path(88, leg{84, 8}, leg{100, 8}, leg{94, 8}, leg{100, 8}, leg{96, 8})
+	headShoulders := path(88, leg{96, 8}, leg{92, 8}, leg{102, 8}, leg{92, 8}, leg{96, 8}, leg{94, 8})
+	bullFlag := path(100, leg{100, 20}, leg{110, 5}, leg{107, 8})
+
+	tests := []struct {
+		name    string
+		candles []*models.OHLCV
+		kind    string
+		signal  string
+		status  string
+	}{
+		{"double top", doubleTop, "double_top", "bearish", PatternForming},
+		{"double top breaks the neckline", append(doubleTop, path(96, leg{92, 1})...), "double_top", "bearish", PatternConfirmed},
+		{"double bottom", mirror(doubleTop, 100), "double_bottom", "bullish", PatternForming},
+		{"head and shoulders", headShoulders, "head_shoulders", "bearish", PatternForming},
+		{"inverse head and shoulders", mirror(headShoulders, 100), "inverse_head_shoulders", "bullish", PatternForming},
+		{"triple top", path(88, leg{100, 8}, leg{94, 8}, leg{100, 8}, leg{94, 8}, leg{100, 8}, leg{97, 6}), "triple_top", "bearish", PatternForming},
+		{"ascending triangle", path(90, leg{100, 8}, leg{94, 8}, leg{100, 8}, leg{97, 8}, leg{99.5, 8}), "ascending_triangle", "bullish", PatternForming},
+		{"descending triangle", mirror(path(90, leg{100, 8}, leg{94, 8}, leg{100, 8}, leg{97, 8}, leg{99.5, 8}), 100), "descending_triangle", "bearish", PatternForming},
+		{"rising wedge", path(90, leg{100, 8}, leg{96, 8}, leg{102, 8}, leg{100, 8}, leg{101, 4}), "rising_wedge", "bearish", PatternForming},
+		{"falling wedge", mirror(path(90, leg{100, 8}, leg{96, 8}, leg{102, 8}, leg{100, 8}, leg{101, 4}), 100), "falling_wedge", "bullish", PatternForming},
+		{"symmetrical triangle", path(90, leg{104, 8}, leg{94, 8}, leg{102, 8}, leg{96, 8}, leg{98, 4}), "symmetrical_triangle", "neutral", PatternForming},
+		{"rectangle breaks down", path(90, leg{100, 8}, leg{94, 8}, leg{100, 8}, leg{94, 8}, leg{100, 8}, leg{92, 6}), "rectangle", "bearish", PatternConfirmed},
+		{"bullish pennant", path(100, leg{100, 20}, leg{110, 5}, leg{107, 2}, leg{109, 2}, leg{107.5, 2}, leg{108, 2}), "bullish_pennant", "bullish", PatternForming},
+		{"bull flag", bullFlag, "bull_flag", "bullish", PatternForming},
+		{"bull flag breaks out", append(bullFlag, path(107, leg{109, 1})...), "bull_flag", "bullish", PatternConfirmed},
+		{"bear flag", mirror(bullFlag, 100), "bear_flag", "bearish", PatternForming},
+		{"cup and handle", path(100, leg{110, 8}, leg{95, 14}, leg{95, 8}, leg{110, 14}, leg{107, 4}, leg{107.5, 2}), "cup_handle", "bullish", PatternForming},
+	}
+
+	analyzer := NewChartPatternAnalyzer(NewSwingDetector(SwingATR, 3))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patterns := analyzer.DetectPatterns(tt.candles)
+
+			pattern := findChartPattern(patterns, tt.kind)
+			if pattern == nil {
+				t.Fatalf("Expected %s, got %+v", tt.kind, patterns)
+			}
+			if pattern.Signal != tt.signal || pattern.Status != tt.status {
+				t.Errorf("Expected %s %s, got %s %s", tt.status, tt.signal, pattern.Status, pattern.Signal)
+			}
+			if pattern.Neckline == nil && len(pattern.Boundaries) != 2 {
+				t.Errorf("Expected a neckline or boundaries, got %+v", pattern)
+			}
+			if pattern.Target == 0 || pattern.Invalidation == 0 || len(pattern.Points) == 0 {
+				t.Errorf("Expected a target, an invalidation level and points, got %+v", pattern)
+			}
+
+			// Targets lie beyond the price in the signal's direction,
+			// invalidation levels against it
+			last := tt.candles[len(tt.candles)-1].Close
+			if pattern.Signal == "bullish" && (pattern.Target <= last || pattern.Invalidation >= last) ||
+				pattern.Signal == "bearish" && (pattern.Target >= last || pattern.Invalidation <= last) {
+				t.Errorf("Expected target and invalidation around %v, got %v and %v", last, pattern.Target, pattern.Invalidation)
+			}
+		})
+	}
+}
+
+func TestChartPatternGeometry(t *testing.T) {
+	analyzer := NewChartPatternAnalyzer(NewSwingDetector(SwingATR, 3))
+	candles := path(88, leg{96, 8}, leg{92, 8}, leg{102, 8}, leg{92, 8}, leg{96, 8}, leg{94, 8})
+
+	patterns := analyzer.DetectPatterns(candles)
+	pattern := findChartPattern(patterns, "head_shoulders")
+	if pattern == nil {
+		t.Fatalf("Expected a head and shoulders, got %+v", patterns)
+	}
+	if findChartPattern(patterns, "double_top") != nil {
+		t.Error("Expected the head and shoulders to replace the double top")
+	}
+
+	// The neckline runs through the troughs at 91.8 to the latest candle;
+	// the target lies the head height of 10.4 below it
+	neckline := pattern.Neckline
+	if neckline.Start.Price != 91.8 || neckline.End.Price != 91.8 || !neckline.End.Time.Equal(candles[len(candles)-1].Timestamp) {
+		t.Errorf("Unexpected neckline %+v", neckline)
+	}
+	if math.Abs(pattern.Target-81.4) > 1e-9 || pattern.Invalidation != 96.2 {
+		t.Errorf("Expected target 81.4 and invalidation 96.2, got %v and %v", pattern.Target, pattern.Invalidation)
+	}
+	if pattern.Points[2].Label != "head" || pattern.Points[2].Price != 102.2 {
+		t.Errorf("Expected the head at 102.2, got %+v", pattern.Points[2])
+	}
+
+	// Mirrored, the labels and levels follow the price
+	inverse := findChartPattern(analyzer.DetectPatterns(mirror(candles, 100)), "inverse_head_shoulders")
+	if inverse == nil || math.Abs(inverse.Target-118.6) > 1e-9 || inverse.Points[1].Label != "neckline" {
+		t.Errorf("Unexpected inverse head and shoulders %+v", inverse)
+	}
+
+	// Closing above the right shoulder fails the pattern
+	failed := findChartPattern(analyzer.DetectPatterns(append(candles, path(94, leg{97, 1})...)), "head_shoulders")
+	if failed == nil || failed.Status != PatternFailed || failed.Confidence != 40 {
+		t.Errorf("Expected a failed pattern at half confidence, got %+v", failed)
+	}
+}
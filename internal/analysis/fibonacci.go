@@ -0,0 +1,46 @@
+package analysis
+
+import (
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-275: Fibonacci retracements and extensions of the latest swing
+
+// Fibonacci ratios of the leg between two swings
+var (
+	FibonacciRetracements = []float64{0.236, 0.382, 0.5, 0.618, 0.786}
+	FibonacciExtensions   = []float64{1.272, 1.618, 2.618}
+)
+
+// FibonacciLevels returns the retracements and extensions of the latest leg
+// between two confirmed swings, which price has since been retracing. Returns
+// nil with fewer than two confirmed swings.
+func FibonacciLevels(swings []SwingPoint) *models.FibonacciLevels {
+	confirmed := recentSwings(swings, 0)
+	if len(confirmed) < 2 {
+		return nil
+	}
+	start, end := confirmed[len(confirmed)-2], confirmed[len(confirmed)-1]
+
+	direction := "up"
+	if !end.High {
+		direction = "down"
+	}
+	move := end.Price - start.Price
+
+	levels := &models.FibonacciLevels{
+		Direction:  direction,
+		StartPrice: start.Price,
+		StartTime:  start.Timestamp,
+		EndPrice:   end.Price,
+		EndTime:    end.Timestamp,
+	}
+	for _, ratio := range FibonacciRetracements {
+		levels.Retracements = append(levels.Retracements, models.FibonacciLevel{Ratio: ratio, Price: end.Price - ratio*move})
+	}
+	for _, ratio := range FibonacciExtensions {
+		levels.Extensions = append(levels.Extensions, models.FibonacciLevel{Ratio: ratio, Price: start.Price + ratio*move})
+	}
+
+	return levels
+}
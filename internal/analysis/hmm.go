@@ -0,0 +1,352 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// REQ-273: Statistical volatility regimes from a Gaussian hidden Markov model
+
+// Volatility regimes, from the calmest state of a model to the most volatile
+const (
+	RegimeLowVolatility    = "low_volatility"
+	RegimeNormalVolatility = "normal_volatility"
+	RegimeHighVolatility   = "high_volatility"
+)
+
+const (
+	// MinRegimeReturns is the number of returns a regime model needs to be fit
+	MinRegimeReturns = 100
+
+	hmmMaxIterations = 200
+	hmmTolerance     = 1e-6 // log-likelihood improvement per return that ends the fit
+	hmmStickiness    = 0.9  // initial probability of staying in a state
+)
+
+// VolatilityHMM is a Gaussian hidden Markov model of log returns whose states
+// are ordered by variance
+type VolatilityHMM struct {
+	Labels     []string    `json:"labels"`
+	Initial    []float64   `json:"initial"`
+	Transition [][]float64 `json:"transition"` // transition[i][j]: probability of state j after state i
+	Means      []float64   `json:"means"`
+	Variances  []float64   `json:"variances"`
+
+	LogLikelihood float64 `json:"log_likelihood"`
+	Samples       int     `json:"samples"`
+	Iterations    int     `json:"iterations"`
+}
+
+// RegimeState is the regime of the last return filtered by a model
+type RegimeState struct {
+	Regime           string             `json:"regime"`
+	Probability      float64            `json:"probability"`
+	Probabilities    map[string]float64 `json:"probabilities"`
+	ExpectedDuration float64            `json:"expected_duration"` // candles a spell of the regime lasts on average
+	Duration         int                `json:"duration"`          // candles the regime has held so far
+	Volatility       float64            `json:"volatility"`        // standard deviation of the regime's returns in percent
+}
+
+// LogReturns returns the log returns between consecutive closes, skipping
+// candles without a positive close
+func LogReturns(closes []float64) []float64 {
+	returns := make([]float64, 0, len(closes))
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] > 0 && closes[i] > 0 {
+			returns = append(returns, math.Log(closes[i]/closes[i-1]))
+		}
+	}
+	return returns
+}
+
+// FitVolatilityHMM fits a two or three state model to returns with the
+// Baum-Welch algorithm
+func FitVolatilityHMM(returns []float64, states int) (*VolatilityHMM, error) {
+	if states < 2 || states > 3 {
+		return nil, fmt.Errorf("unsupported number of states %d, must be 2 or 3", states)
+	}
+	if len(returns) < MinRegimeReturns {
+		return nil, fmt.Errorf("insufficient returns: need %d, got %d", MinRegimeReturns, len(returns))
+	}
+
+	var mean, variance float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns))
+	if variance == 0 {
+		return nil, fmt.Errorf("returns have no variance")
+	}
+	// Keeps a state from collapsing onto a run of identical returns
+	floor := variance * 1e-3
+
+	model := initialHMM(returns, states, mean, floor)
+
+	n, k := len(returns), states
+	alpha, beta := newMatrix(n, k), newMatrix(n, k)
+	emissions := newMatrix(n, k)
+	scales := make([]float64, n)
+
+	previous := math.Inf(-1)
+	for model.Iterations < hmmMaxIterations {
+		model.Iterations++
+
+		model.emissions(returns, emissions)
+		model.LogLikelihood = model.forward(emissions, alpha, scales)
+		model.backward(emissions, beta, scales)
+
+		// Expected state occupancies and transitions
+		gamma := newMatrix(n, k)
+		transitions := newMatrix(k, k)
+		for t := 0; t < n; t++ {
+			for i := 0; i < k; i++ {
+				gamma[t][i] = alpha[t][i] * beta[t][i]
+			}
+			if t == n-1 {
+				continue
+			}
+			for i := 0; i < k; i++ {
+				for j := 0; j < k; j++ {
+					transitions[i][j] += alpha[t][i] * model.Transition[i][j] * emissions[t+1][j] * beta[t+1][j] / scales[t+1]
+				}
+			}
+		}
+
+		for i := 0; i < k; i++ {
+			model.Initial[i] = gamma[0][i]
+
+			var outgoing float64
+			for j := 0; j < k; j++ {
+				outgoing += transitions[i][j]
+			}
+			if outgoing > 0 {
+				for j := 0; j < k; j++ {
+					model.Transition[i][j] = transitions[i][j] / outgoing
+				}
+			}
+
+			var weight, weightedSum float64
+			for t := 0; t < n; t++ {
+				weight += gamma[t][i]
+				weightedSum += gamma[t][i] * returns[t]
+			}
+			if weight == 0 {
+				continue
+			}
+			model.Means[i] = weightedSum / weight
+
+			var weightedSquares float64
+			for t := 0; t < n; t++ {
+				d := returns[t] - model.Means[i]
+				weightedSquares += gamma[t][i] * d * d
+			}
+			model.Variances[i] = math.Max(weightedSquares/weight, floor)
+		}
+
+		if model.LogLikelihood-previous < hmmTolerance*float64(n) {
+			break
+		}
+		previous = model.LogLikelihood
+	}
+
+	model.Samples = n
+	model.sortByVariance()
+
+	return model, nil
+}
+
+// initialHMM starts every state at a quantile of the absolute returns with
+// sticky transitions
+func initialHMM(returns []float64, states int, mean, floor float64) *VolatilityHMM {
+	absolute := make([]float64, len(returns))
+	for i, r := range returns {
+		absolute[i] = math.Abs(r - mean)
+	}
+	sort.Float64s(absolute)
+
+	model := &VolatilityHMM{
+		Labels:     regimeLabels(states),
+		Initial:    make([]float64, states),
+		Transition: newMatrix(states, states),
+		Means:      make([]float64, states),
+		Variances:  make([]float64, states),
+	}
+
+	for i := 0; i < states; i++ {
+		model.Initial[i] = 1 / float64(states)
+		for j := 0; j < states; j++ {
+			model.Transition[i][j] = (1 - hmmStickiness) / float64(states-1)
+		}
+		model.Transition[i][i] = hmmStickiness
+
+		// Mean squared deviation of the i-th slice of absolute returns
+		slice := absolute[i*len(absolute)/states : (i+1)*len(absolute)/states]
+		var squares float64
+		for _, a := range slice {
+			squares += a * a
+		}
+		model.Means[i] = mean
+		model.Variances[i] = math.Max(squares/float64(len(slice)), floor)
+	}
+
+	return model
+}
+
+// regimeLabels names the states of a model ordered by variance
+func regimeLabels(states int) []string {
+	if states == 2 {
+		return []string{RegimeLowVolatility, RegimeHighVolatility}
+	}
+	return []string{RegimeLowVolatility, RegimeNormalVolatility, RegimeHighVolatility}
+}
+
+// sortByVariance reorders the states from the lowest variance up so that
+// they match their labels
+func (m *VolatilityHMM) sortByVariance() {
+	k := len(m.Variances)
+	order := make([]int, k)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return m.Variances[order[a]] < m.Variances[order[b]]
+	})
+
+	initial, means, variances := make([]float64, k), make([]float64, k), make([]float64, k)
+	transition := newMatrix(k, k)
+	for i, from := range order {
+		initial[i], means[i], variances[i] = m.Initial[from], m.Means[from], m.Variances[from]
+		for j, to := range order {
+			transition[i][j] = m.Transition[from][to]
+		}
+	}
+	m.Initial, m.Means, m.Variances, m.Transition = initial, means, variances, transition
+}
+
+// Filter returns the regime of the last return given all returns up to it,
+// or nil without returns
+func (m *VolatilityHMM) Filter(returns []float64) *RegimeState {
+	n, k := len(returns), len(m.Variances)
+	if n == 0 {
+		return nil
+	}
+
+	emissions, alpha := newMatrix(n, k), newMatrix(n, k)
+	m.emissions(returns, emissions)
+	m.forward(emissions, alpha, make([]float64, n))
+
+	// The regime holds back to the first return it was not the most likely at
+	regime := argmax(alpha[n-1])
+	duration := 0
+	for t := n - 1; t >= 0 && argmax(alpha[t]) == regime; t-- {
+		duration++
+	}
+
+	state := &RegimeState{
+		Regime:        m.Labels[regime],
+		Probability:   alpha[n-1][regime],
+		Probabilities: make(map[string]float64, k),
+		Duration:      duration,
+		Volatility:    math.Sqrt(m.Variances[regime]) * 100,
+	}
+	for i, label := range m.Labels {
+		state.Probabilities[label] = alpha[n-1][i]
+	}
+
+	// Spells of a state last geometrically long; a state never left within
+	// the fitted returns is expected to last as long as they did
+	state.ExpectedDuration = float64(m.Samples)
+	if stay := m.Transition[regime][regime]; stay < 1 {
+		state.ExpectedDuration = math.Min(1/(1-stay), state.ExpectedDuration)
+	}
+
+	return state
+}
+
+// emissions fills the density of every return under every state
+func (m *VolatilityHMM) emissions(returns []float64, emissions [][]float64) {
+	for t, r := range returns {
+		for i := range m.Variances {
+			d := r - m.Means[i]
+			emissions[t][i] = math.Exp(-d*d/(2*m.Variances[i])) / math.Sqrt(2*math.Pi*m.Variances[i])
+		}
+	}
+}
+
+// forward fills the filtered state probabilities, normalized at every return,
+// and returns the log-likelihood of the returns
+func (m *VolatilityHMM) forward(emissions, alpha [][]float64, scales []float64) float64 {
+	k := len(m.Variances)
+	var logLikelihood float64
+
+	for t := range emissions {
+		var scale float64
+		for j := 0; j < k; j++ {
+			prior := m.Initial[j]
+			if t > 0 {
+				prior = 0
+				for i := 0; i < k; i++ {
+					prior += alpha[t-1][i] * m.Transition[i][j]
+				}
+			}
+			alpha[t][j] = prior * emissions[t][j]
+			scale += alpha[t][j]
+		}
+
+		// A return no state can explain leaves the prior unchanged
+		if scale == 0 || math.IsNaN(scale) {
+			scale = math.SmallestNonzeroFloat64
+			for j := 0; j < k; j++ {
+				alpha[t][j] = 1 / float64(k)
+			}
+		} else {
+			for j := 0; j < k; j++ {
+				alpha[t][j] /= scale
+			}
+		}
+		scales[t] = scale
+		logLikelihood += math.Log(scale)
+	}
+
+	return logLikelihood
+}
+
+// backward fills the backward probabilities with the scales of the forward pass
+func (m *VolatilityHMM) backward(emissions, beta [][]float64, scales []float64) {
+	n, k := len(emissions), len(m.Variances)
+	for i := 0; i < k; i++ {
+		beta[n-1][i] = 1
+	}
+	for t := n - 2; t >= 0; t-- {
+		for i := 0; i < k; i++ {
+			var sum float64
+			for j := 0; j < k; j++ {
+				sum += m.Transition[i][j] * emissions[t+1][j] * beta[t+1][j]
+			}
+			beta[t][i] = sum / scales[t+1]
+		}
+	}
+}
+
+func newMatrix(rows, columns int) [][]float64 {
+	matrix := make([][]float64, rows)
+	for i := range matrix {
+		matrix[i] = make([]float64, columns)
+	}
+	return matrix
+}
+
+func argmax(values []float64) int {
+	best := 0
+	for i, v := range values {
+		if v > values[best] {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// switchingReturns simulates returns alternating between calm and volatile
+// spells of the given lengths
+func switchingReturns(rng *rand.Rand, spells []int, deviations []float64) ([]float64, []int) {
+	var returns []float64
+	var states []int
+	for i, length := range spells {
+		state := i % len(deviations)
+		for j := 0; j < length; j++ {
+			returns = append(returns, rng.NormFloat64()*deviations[state])
+			states = append(states, state)
+		}
+	}
+	return returns, states
+}
+
+func TestFitVolatilityHMM(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	returns, states := switchingReturns(rng, []int{120, 60, 150, 80, 100, 40}, []float64{0.002, 0.02})
+
+	model, err := FitVolatilityHMM(returns, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	// The states are ordered by variance and recover the simulated deviations
+	if model.Labels[0] != RegimeLowVolatility || model.Labels[1] != RegimeHighVolatility {
+		t.Errorf("Unexpected labels %v", model.Labels)
+	}
+	for i, want := range []float64{0.002, 0.02} {
+		if got := math.Sqrt(model.Variances[i]); math.Abs(got-want)/want > 0.2 {
+			t.Errorf("Expected state %d deviation near %v, got %v", i, want, got)
+		}
+	}
+	for i, row := range model.Transition {
+		var sum float64
+		for _, p := range row {
+			sum += p
+		}
+		if math.Abs(sum-1) > 1e-9 || row[i] < 0.9 {
+			t.Errorf("Expected sticky transition row %d, got %v", i, row)
+		}
+	}
+
+	// Filtering up to each return mostly finds the simulated state
+	var correct int
+	for n := 10; n <= len(returns); n++ {
+		state := model.Filter(returns[:n])
+		if state.Regime == model.Labels[states[n-1]] {
+			correct++
+		}
+	}
+	if accuracy := float64(correct) / float64(len(returns)-9); accuracy < 0.9 {
+		t.Errorf("Expected the filtered regime to match the simulation, accuracy %.2f", accuracy)
+	}
+
+	// The last spell is volatile and 40 returns long
+	state := model.Filter(returns)
+	if state.Regime != RegimeHighVolatility || state.Probability < 0.5 {
+		t.Errorf("Expected the high volatility regime, got %+v", state)
+	}
+	if state.Duration < 30 || state.Duration > 45 {
+		t.Errorf("Expected a duration near 40, got %d", state.Duration)
+	}
+	if state.ExpectedDuration < 20 || state.ExpectedDuration > 200 {
+		t.Errorf("Unexpected expected duration %v", state.ExpectedDuration)
+	}
+	if sum := state.Probabilities[RegimeLowVolatility] + state.Probabilities[RegimeHighVolatility]; math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Expected probabilities summing to one, got %v", state.Probabilities)
+	}
+}
+
+func TestFitVolatilityHMMErrors(t *testing.T) {
+	if _, err := FitVolatilityHMM(make([]float64, MinRegimeReturns-1), 2); err == nil {
+		t.Error("Expected an error for short history")
+	}
+	if _, err := FitVolatilityHMM(make([]float64, MinRegimeReturns), 3); err == nil {
+		t.Error("Expected an error for returns without variance")
+	}
+	if _, err := FitVolatilityHMM(make([]float64, MinRegimeReturns), 4); err == nil {
+		t.Error("Expected an error for an unsupported number of states")
+	}
+
+	// Three states fit without error and keep their order
+	rng := rand.New(rand.NewSource(11))
+	returns, _ := switchingReturns(rng, []int{100, 100, 100, 100, 100, 100}, []float64{0.002, 0.008, 0.03})
+	model, err := FitVolatilityHMM(returns, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if model.Variances[0] > model.Variances[1] || model.Variances[1] > model.Variances[2] {
+		t.Errorf("Expected states ordered by variance, got %v", model.Variances)
+	}
+	if model.Labels[1] != RegimeNormalVolatility {
+		t.Errorf("Unexpected labels %v", model.Labels)
+	}
+}
@@ -0,0 +1,287 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-274: Support and resistance lifecycle with touches, breaks and role reversal
+
+// LevelCandidate is a level offered to a book by a detector
+type LevelCandidate struct {
+	Price    float64
+	Strength float64 // 0-100
+	Source   string  // detected, round or a higher timeframe
+}
+
+// LevelBook tracks the levels of one symbol:timeframe across candles. Levels
+// are tested when price reaches them and holds, and broken when enough
+// consecutive closes land beyond them, which flips support into resistance
+// and back.
+type LevelBook struct {
+	symbol    string
+	timeframe string
+	levels    []*bookLevel
+	last      time.Time // latest candle applied
+
+	mergeTolerance float64 // candidates within it of a level join it (fraction of price)
+	band           float64 // zone around a level that counts as reaching it (fraction of price)
+	confirmCloses  int     // consecutive closes beyond a level that confirm a break
+	maxLevels      int     // levels kept, nearest to the price
+	maxTouches     int     // touches kept per level
+	testStrength   float64 // strength a held test adds
+}
+
+type bookLevel struct {
+	level  *models.TrackedLevel
+	inZone bool // the previous candle reached the level
+	beyond int  // consecutive closes beyond the level
+}
+
+// NewLevelBook creates the book of a stream, resuming stored levels
+func NewLevelBook(symbol, timeframe string, stored []*models.TrackedLevel) *LevelBook {
+	book := &LevelBook{
+		symbol:         symbol,
+		timeframe:      timeframe,
+		mergeTolerance: 0.005, // as the detector clusters pivots
+		band:           0.002,
+		confirmCloses:  2,
+		maxLevels:      30,
+		maxTouches:     20,
+		testStrength:   5,
+	}
+	for _, level := range stored {
+		copied := copyLevel(level)
+		book.levels = append(book.levels, &bookLevel{level: copied})
+		if copied.UpdatedAt.After(book.last) {
+			book.last = copied.UpdatedAt
+		}
+	}
+	return book
+}
+
+// Apply tests and breaks the levels with a candle and returns the resulting
+// events. Candles not after the latest applied one are ignored.
+func (b *LevelBook) Apply(candle *models.OHLCV) []*models.LevelEvent {
+	if candle == nil || !candle.Timestamp.After(b.last) {
+		return nil
+	}
+	b.last = candle.Timestamp
+
+	var events []*models.LevelEvent
+	for _, entry := range b.levels {
+		if touch := b.applyLevel(entry, candle); touch != "" {
+			events = append(events, b.event(entry, touch, candle))
+		}
+	}
+	return events
+}
+
+// applyLevel moves one level through its lifecycle and returns the touch the
+// candle made, if any
+func (b *LevelBook) applyLevel(entry *bookLevel, candle *models.OHLCV) string {
+	level := entry.level
+	band := level.Price * b.band
+
+	// Distances in the direction price moves against the level: down for
+	// support, up for resistance
+	direction := 1.0
+	reach := candle.Low
+	if level.Role == models.LevelResistance {
+		direction, reach = -1, candle.High
+	}
+	closeBeyond := direction*(level.Price-candle.Close) > band
+	reached := direction*(reach-level.Price) <= band
+
+	switch {
+	case closeBeyond:
+		entry.beyond++
+		entry.inZone = true
+		if entry.beyond < b.confirmCloses {
+			return ""
+		}
+		entry.beyond = 0
+		entry.inZone = false
+		b.touch(entry, models.LevelBreak, candle)
+		level.Breaks++
+		if level.Role == models.LevelSupport {
+			level.Role = models.LevelResistance
+		} else {
+			level.Role = models.LevelSupport
+		}
+		return models.LevelBreak
+
+	case entry.beyond > 0:
+		// Closing back on its side fails the break: the level held
+		entry.beyond = 0
+		entry.inZone = reached
+		b.test(entry, candle)
+		return models.LevelTest
+
+	case reached:
+		if entry.inZone {
+			return ""
+		}
+		entry.inZone = true
+		b.test(entry, candle)
+		return models.LevelTest
+
+	default:
+		entry.inZone = false
+		return ""
+	}
+}
+
+func (b *LevelBook) test(entry *bookLevel, candle *models.OHLCV) {
+	b.touch(entry, models.LevelTest, candle)
+	entry.level.Tests++
+	entry.level.Strength = math.Min(100, entry.level.Strength+b.testStrength)
+}
+
+func (b *LevelBook) touch(entry *bookLevel, touchType string, candle *models.OHLCV) {
+	level := entry.level
+	level.Touches = append(level.Touches, models.LevelTouch{
+		Timestamp: candle.Timestamp,
+		Type:      touchType,
+		Role:      level.Role,
+		Close:     candle.Close,
+	})
+	if len(level.Touches) > b.maxTouches {
+		level.Touches = level.Touches[len(level.Touches)-b.maxTouches:]
+	}
+	timestamp := candle.Timestamp
+	level.LastTouch = &timestamp
+	level.UpdatedAt = candle.Timestamp
+}
+
+func (b *LevelBook) event(entry *bookLevel, touchType string, candle *models.OHLCV) *models.LevelEvent {
+	// The touch records the role the level had when price moved against it
+	direction := "up"
+	if entry.level.Touches[len(entry.level.Touches)-1].Role == models.LevelSupport {
+		direction = "down"
+	}
+	return &models.LevelEvent{
+		Type:      touchType,
+		Symbol:    b.symbol,
+		Timeframe: b.timeframe,
+		Timestamp: candle.Timestamp,
+		Direction: direction,
+		Close:     candle.Close,
+		Level:     copyLevel(entry.level),
+	}
+}
+
+// Merge adds candidates as new levels, or as sources of the level within the
+// merge tolerance, and keeps the levels nearest to price. A new level is
+// support below price and resistance above it. It reports whether the levels
+// changed.
+func (b *LevelBook) Merge(candidates []LevelCandidate, price float64, at time.Time) bool {
+	changed := false
+
+	for _, candidate := range candidates {
+		if candidate.Price <= 0 {
+			continue
+		}
+
+		var match *bookLevel
+		for _, entry := range b.levels {
+			if math.Abs(entry.level.Price-candidate.Price) <= entry.level.Price*b.mergeTolerance {
+				match = entry
+				break
+			}
+		}
+
+		if match == nil {
+			role := models.LevelResistance
+			if candidate.Price < price {
+				role = models.LevelSupport
+			}
+			b.levels = append(b.levels, &bookLevel{level: &models.TrackedLevel{
+				Symbol:    b.symbol,
+				Timeframe: b.timeframe,
+				Price:     candidate.Price,
+				Role:      role,
+				Sources:   []string{candidate.Source},
+				Strength:  candidate.Strength,
+				FirstSeen: at,
+				UpdatedAt: at,
+			}})
+			changed = true
+			continue
+		}
+
+		level := match.level
+		if !containsString(level.Sources, candidate.Source) {
+			level.Sources = append(level.Sources, candidate.Source)
+			level.UpdatedAt = at
+			changed = true
+		}
+		if candidate.Strength > level.Strength {
+			level.Strength = candidate.Strength
+		}
+	}
+
+	if len(b.levels) > b.maxLevels {
+		sort.SliceStable(b.levels, func(i, j int) bool {
+			return math.Abs(b.levels[i].level.Price-price) < math.Abs(b.levels[j].level.Price-price)
+		})
+		b.levels = b.levels[:b.maxLevels]
+		changed = true
+	}
+
+	return changed
+}
+
+// Levels returns copies of the levels ordered by price
+func (b *LevelBook) Levels() []*models.TrackedLevel {
+	levels := make([]*models.TrackedLevel, len(b.levels))
+	for i, entry := range b.levels {
+		levels[i] = copyLevel(entry.level)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Price < levels[j].Price
+	})
+	return levels
+}
+
+// RoundNumberLevels returns the round numbers just below and above price.
+// The step is a tenth of the price's order of magnitude, or half of it from
+// five upwards: 180 and 190 around 187, 650 and 700 around 687.
+func RoundNumberLevels(price float64) []float64 {
+	if price <= 0 {
+		return nil
+	}
+
+	magnitude := math.Pow(10, math.Floor(math.Log10(price)))
+	step := magnitude / 10
+	if price/magnitude >= 5 {
+		step = magnitude / 2
+	}
+
+	// Rounded to drop the binary noise of multiplying decimal steps
+	below := math.Floor(price / step)
+	return []float64{roundPrice(below * step), roundPrice((below + 1) * step)}
+}
+
+func roundPrice(price float64) float64 {
+	return math.Round(price*1e8) / 1e8
+}
+
+func copyLevel(level *models.TrackedLevel) *models.TrackedLevel {
+	copied := *level
+	copied.Sources = append([]string(nil), level.Sources...)
+	copied.Touches = append([]models.LevelTouch(nil), level.Touches...)
+	return &copied
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+func TestLevelBookLifecycle(t *testing.T) {
+	base := time.Date(2024, 6, 3, 14, 30, 0, 0, time.UTC)
+	book := NewLevelBook("AAPL", "1m", nil)
+	if !book.Merge([]LevelCandidate{{Price: 100, Strength: 40, Source: models.LevelSourceDetected}}, 102, base) {
+		t.Fatal("Expected a new level")
+	}
+	// A higher timeframe confirms the level instead of adding another
+	if !book.Merge([]LevelCandidate{{Price: 100.3, Strength: 60, Source: "1h"}}, 102, base) || len(book.Levels()) != 1 {
+		t.Fatalf("Expected the 1h level merged, got %+v", book.Levels())
+	}
+
+	steps := []struct {
+		low, close float64
+		event      string
+		role       string
+	}{
+		{101, 101.5, "", models.LevelSupport},
+		{100.1, 100.8, models.LevelTest, models.LevelSupport}, // reaches the level
+		{100.1, 100.6, "", models.LevelSupport},               // still at it
+		{99.2, 99.5, "", models.LevelSupport},                 // first close below
+		{99.6, 100.4, models.LevelTest, models.LevelSupport},  // back above: the break failed
+		{99.0, 99.4, "", models.LevelSupport},
+		{98.5, 98.8, models.LevelBreak, models.LevelResistance}, // second close below confirms
+		{98.0, 98.5, "", models.LevelResistance},
+	}
+
+	for i, step := range steps {
+		candle := &models.OHLCV{Timestamp: base.Add(time.Duration(i+1) * time.Minute),
+			High: step.close + 0.3, Low: step.low, Close: step.close}
+		events := book.Apply(candle)
+
+		var got string
+		if len(events) > 0 {
+			got = events[0].Type
+		}
+		if got != step.event || book.Levels()[0].Role != step.role {
+			t.Fatalf("Step %d: expected %q as %s, got %q as %s", i, step.event, step.role, got, book.Levels()[0].Role)
+		}
+		if got == models.LevelBreak && (events[0].Direction != "down" || events[0].Level.Role != models.LevelResistance) {
+			t.Errorf("Expected a downward break into resistance, got %+v", events[0])
+		}
+
+		// Replaying a candle changes nothing
+		if book.Apply(candle) != nil {
+			t.Fatalf("Step %d: expected a replayed candle to be ignored", i)
+		}
+	}
+
+	level := book.Levels()[0]
+	if level.Tests != 2 || level.Breaks != 1 || len(level.Touches) != 3 || level.Strength != 70 {
+		t.Errorf("Unexpected history %+v", level)
+	}
+	if len(level.Sources) != 2 || level.Sources[1] != "1h" {
+		t.Errorf("Unexpected sources %v", level.Sources)
+	}
+
+	// The flipped level is tested as resistance from below
+	events := book.Apply(&models.OHLCV{Timestamp: base.Add(time.Hour), High: 100.1, Low: 99, Close: 99.5})
+	if len(events) != 1 || events[0].Type != models.LevelTest || events[0].Direction != "up" {
+		t.Errorf("Expected a resistance test, got %+v", events)
+	}
+
+	// A resumed book keeps the history and skips candles already applied
+	resumed := NewLevelBook("AAPL", "1m", book.Levels())
+	if resumed.Apply(&models.OHLCV{Timestamp: base.Add(time.Hour), High: 100.1, Low: 99, Close: 99.5}) != nil {
+		t.Error("Expected the resumed book to skip applied candles")
+	}
+	if got := resumed.Levels()[0]; got.Role != models.LevelResistance || got.Tests != 3 {
+		t.Errorf("Unexpected resumed level %+v", got)
+	}
+}
+
+func TestRoundNumberLevels(t *testing.T) {
+	tests := []struct {
+		price       float64
+		below, next float64
+	}{
+		{187.4, 180, 190},
+		{687, 650, 700},
+		{45.3, 45, 46},
+		{2.37, 2.3, 2.4},
+	}
+	for _, tt := range tests {
+		levels := RoundNumberLevels(tt.price)
+		if len(levels) != 2 || levels[0] != tt.below || levels[1] != tt.next {
+			t.Errorf("Expected %v and %v around %v, got %v", tt.below, tt.next, tt.price, levels)
+		}
+	}
+}
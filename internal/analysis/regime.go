@@ -3,7 +3,7 @@ package analysis
 import (
 	"math"
 
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-213: Market regime identification (Wyckoff methodology)
@@ -3,7 +3,7 @@ package analysis
 import (
 	"math"
 
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-214: Dynamic support and resistance levels
@@ -39,13 +39,18 @@ type CurrentLevelInfo struct {
 type SupportResistanceDetector struct {
 	tolerance  float64 // price tolerance for level clustering (percentage)
 	minTouches int     // minimum touches to consider a level valid
+
+	// REQ-275: Swings confirmed after the last pivot candles
+	swings *SwingDetector
 }
 
 // NewSupportResistanceDetector creates a new support/resistance detector
-func NewSupportResistanceDetector() *SupportResistanceDetector {
+// clustering the swings of a detector
+func NewSupportResistanceDetector(swings *SwingDetector) *SupportResistanceDetector {
 	return &SupportResistanceDetector{
 		tolerance:  0.005, // 0.5% tolerance
 		minTouches: 2,     // minimum 2 touches
+		swings:     swings,
 	}
 }
 
@@ -141,6 +146,17 @@ func (srd *SupportResistanceDetector) findPivotPoints(candles []*models.OHLCV) *
 		}
 	}
 
+	// REQ-275: The last lookback candles cannot be confirmed as pivots; a
+	// swing confirmed there by a sharp reversal already counts
+	for _, swing := range recentSwings(srd.swings.Detect(candles), max(lookback, len(candles)-lookback)) {
+		point := PivotPoint{price: swing.Price, index: swing.Index, candle: candles[swing.Index]}
+		if swing.High {
+			pivots.highs = append(pivots.highs, point)
+		} else {
+			pivots.lows = append(pivots.lows, point)
+		}
+	}
+
 	return pivots
 }
 
@@ -0,0 +1,160 @@
+package analysis
+
+import (
+	"math"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-275: Swing points (ZigZag) with percentage or ATR reversal thresholds
+
+// Swing reversal modes
+const (
+	SwingPercent = "percent" // reversal of a percentage of the swing price
+	SwingATR     = "atr"     // reversal of a multiple of the average true range
+)
+
+// swingATRPeriod is the average true range period of the ATR mode
+const swingATRPeriod = 14
+
+// SwingPoint is a swing high or low of a ZigZag
+type SwingPoint struct {
+	Index     int       `json:"index"`
+	Timestamp time.Time `json:"timestamp"`
+	Price     float64   `json:"price"`
+	High      bool      `json:"high"`
+	Confirmed bool      `json:"confirmed"` // price has reversed from it by the threshold
+}
+
+// SwingDetector finds alternating swing highs and lows: an extreme becomes a
+// swing once price reverses from it by the threshold
+type SwingDetector struct {
+	mode      string
+	threshold float64 // percent of price, or ATR multiple
+}
+
+// NewSwingDetector creates a swing detector. Modes other than percent use the
+// ATR; thresholds that are not positive default to 5% or 3 ATR.
+func NewSwingDetector(mode string, threshold float64) *SwingDetector {
+	if mode != SwingPercent {
+		mode = SwingATR
+	}
+	if threshold <= 0 {
+		threshold = 3
+		if mode == SwingPercent {
+			threshold = 5
+		}
+	}
+	return &SwingDetector{mode: mode, threshold: threshold}
+}
+
+// Detect returns the swings of the candles in order. The last one is the
+// extreme of the leg in progress, not confirmed yet.
+func (d *SwingDetector) Detect(candles []*models.OHLCV) []SwingPoint {
+	if len(candles) < 2 {
+		return nil
+	}
+
+	var atr []float64
+	if d.mode == SwingATR {
+		atr = averageTrueRanges(candles, swingATRPeriod)
+	}
+	reversal := func(index int, price float64) float64 {
+		if d.mode == SwingPercent {
+			// Absolute so that mirrored, negated prices reverse alike
+			return math.Abs(price) * d.threshold / 100
+		}
+		return atr[index] * d.threshold
+	}
+	point := func(index int, high, confirmed bool) SwingPoint {
+		price := candles[index].Low
+		if high {
+			price = candles[index].High
+		}
+		return SwingPoint{Index: index, Timestamp: candles[index].Timestamp, Price: price, High: high, Confirmed: confirmed}
+	}
+
+	var swings []SwingPoint
+	direction := 0 // 1 while rising to a swing high, -1 while falling to a swing low
+	high, low := 0, 0
+	extreme := 0
+
+	for i := 1; i < len(candles); i++ {
+		candle := candles[i]
+		switch direction {
+		case 0:
+			// The first swing is the extreme price first reverses from
+			if candle.High > candles[high].High {
+				high = i
+			}
+			if candle.Low < candles[low].Low {
+				low = i
+			}
+			switch {
+			case high < low && candles[high].High-candles[low].Low >= reversal(high, candles[high].High):
+				swings = append(swings, point(high, true, true))
+				direction, extreme = -1, low
+			case low < high && candles[high].High-candles[low].Low >= reversal(low, candles[low].Low):
+				swings = append(swings, point(low, false, true))
+				direction, extreme = 1, high
+			}
+
+		case 1:
+			if candle.High > candles[extreme].High {
+				extreme = i
+			} else if candles[extreme].High-candle.Low >= reversal(extreme, candles[extreme].High) {
+				swings = append(swings, point(extreme, true, true))
+				direction, extreme = -1, i
+			}
+
+		case -1:
+			if candle.Low < candles[extreme].Low {
+				extreme = i
+			} else if candle.High-candles[extreme].Low >= reversal(extreme, candles[extreme].Low) {
+				swings = append(swings, point(extreme, false, true))
+				direction, extreme = 1, i
+			}
+		}
+	}
+
+	if direction != 0 {
+		swings = append(swings, point(extreme, direction > 0, false))
+	}
+
+	return swings
+}
+
+// averageTrueRanges returns the Wilder average true range at every candle,
+// the mean of the true ranges so far before period candles
+func averageTrueRanges(candles []*models.OHLCV, period int) []float64 {
+	atr := make([]float64, len(candles))
+	var sum float64
+	for i, candle := range candles {
+		tr := candle.High - candle.Low
+		if i > 0 {
+			previous := candles[i-1].Close
+			tr = math.Max(tr, math.Max(math.Abs(candle.High-previous), math.Abs(candle.Low-previous)))
+		}
+
+		switch {
+		case i < period:
+			sum += tr
+			atr[i] = sum / float64(i+1)
+		default:
+			atr[i] = (atr[i-1]*float64(period-1) + tr) / float64(period)
+		}
+	}
+	return atr
+}
+
+// recentSwings returns the confirmed swings from candle index from onwards
+func recentSwings(swings []SwingPoint, from int) []SwingPoint {
+	var recent []SwingPoint
+	for _, swing := range swings {
+		if swing.Confirmed && swing.Index >= from {
+			recent = append(recent, swing)
+		}
+	}
+	return recent
+}
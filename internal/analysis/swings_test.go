@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSwingDetector(t *testing.T) {
+	// 100 up to 110, down to 104, up to 112, down to 109
+	candles := path(100, leg{110, 10}, leg{104, 6}, leg{112, 8}, leg{109, 3})
+
+	tests := []struct {
+		name     string
+		detector *SwingDetector
+		prices   []float64
+	}{
+		// Every leg reverses by more than 2%
+		{"percent", NewSwingDetector(SwingPercent, 2), []float64{99.8, 110.2, 103.8, 112.2, 108.8}},
+		// The 3 point pullback at the end is not 4% of price
+		{"wider percent", NewSwingDetector(SwingPercent, 4), []float64{99.8, 110.2, 103.8, 112.2}},
+		// Candles range 1.4 points, so 6 points reverse by over 3 ATR
+		{"atr", NewSwingDetector(SwingATR, 3), []float64{99.8, 110.2, 103.8, 112.2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			swings := tt.detector.Detect(candles)
+			if len(swings) != len(tt.prices) {
+				t.Fatalf("Expected %d swings, got %+v", len(tt.prices), swings)
+			}
+			for i, swing := range swings {
+				if math.Abs(swing.Price-tt.prices[i]) > 1e-9 {
+					t.Errorf("Swing %d: expected %v, got %v", i, tt.prices[i], swing.Price)
+				}
+				if i > 0 && swing.High == swings[i-1].High {
+					t.Errorf("Expected alternating swings, got %+v", swings)
+				}
+				if swing.Confirmed != (i < len(swings)-1) {
+					t.Errorf("Expected only the last swing unconfirmed, got %+v", swing)
+				}
+			}
+		})
+	}
+}
+
+func TestFibonacciLevels(t *testing.T) {
+	candles := path(100, leg{110, 10}, leg{104, 6}, leg{112, 8}, leg{109, 3})
+	swings := NewSwingDetector(SwingPercent, 2).Detect(candles)
+
+	// The latest confirmed leg rose from 103.8 to 112.2
+	levels := FibonacciLevels(swings)
+	if levels == nil || levels.Direction != "up" || levels.StartPrice != 103.8 || levels.EndPrice != 112.2 {
+		t.Fatalf("Unexpected leg %+v", levels)
+	}
+	if !levels.EndTime.Equal(candles[23].Timestamp) {
+		t.Errorf("Expected the leg to end at candle 23, got %v", levels.EndTime)
+	}
+
+	half := levels.Retracements[2]
+	if half.Ratio != 0.5 || math.Abs(half.Price-108) > 1e-9 {
+		t.Errorf("Expected the 50%% retracement at 108, got %+v", half)
+	}
+	golden := levels.Extensions[1]
+	if golden.Ratio != 1.618 || math.Abs(golden.Price-(103.8+1.618*8.4)) > 1e-9 {
+		t.Errorf("Expected the 161.8%% extension above the leg, got %+v", golden)
+	}
+
+	if FibonacciLevels(swings[:1]) != nil {
+		t.Error("Expected no levels without two confirmed swings")
+	}
+}
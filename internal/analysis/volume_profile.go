@@ -0,0 +1,230 @@
+package analysis
+
+import (
+	"math"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/calendar"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-264: Price-volume histogram with value area and volume nodes
+
+// VolumeProfileAnalyzer builds price-volume histograms from bars
+type VolumeProfileAnalyzer struct {
+	bins             int     // price bins of a profile
+	valueAreaPercent float64 // share of volume inside the value area
+	highNodeRatio    float64 // bin volume vs average for a high-volume node
+	lowNodeRatio     float64 // bin volume vs average for a low-volume node
+}
+
+// NewVolumeProfileAnalyzer creates a new volume profile analyzer
+func NewVolumeProfileAnalyzer() *VolumeProfileAnalyzer {
+	return &VolumeProfileAnalyzer{
+		bins:             50,
+		valueAreaPercent: 0.70, // 70% value area
+		highNodeRatio:    1.5,
+		lowNodeRatio:     0.5,
+	}
+}
+
+// Build builds the profile of the bars over their whole range with the
+// default number of bins
+func (vpa *VolumeProfileAnalyzer) Build(candles []*models.OHLCV) *models.PriceVolumeProfile {
+	return vpa.BuildWithBins(candles, vpa.bins)
+}
+
+// BuildWithBins builds the profile of the bars over their whole range. Each
+// bar's volume is spread evenly over its high-low range. It returns nil
+// without bars or volume.
+func (vpa *VolumeProfileAnalyzer) BuildWithBins(candles []*models.OHLCV, bins int) *models.PriceVolumeProfile {
+	if len(candles) == 0 || bins < 1 {
+		return nil
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	var totalVolume int64
+	for _, candle := range candles {
+		low = math.Min(low, candle.Low)
+		high = math.Max(high, candle.High)
+		totalVolume += candle.Volume
+	}
+	if totalVolume <= 0 || high < low {
+		return nil
+	}
+
+	// A flat range fits into a single bin
+	binSize := (high - low) / float64(bins)
+	if binSize == 0 {
+		bins, binSize = 1, 0
+	}
+
+	volumes := make([]float64, bins)
+	for _, candle := range candles {
+		if candle.Volume <= 0 {
+			continue
+		}
+		volume := float64(candle.Volume)
+
+		first, last := binIndex(candle.Low, low, binSize, bins), binIndex(candle.High, low, binSize, bins)
+		if first == last || candle.High <= candle.Low {
+			volumes[binIndex(candle.Close, low, binSize, bins)] += volume
+			continue
+		}
+
+		// Share of the bar's range inside each bin it spans
+		for i := first; i <= last; i++ {
+			binLow := low + float64(i)*binSize
+			overlap := math.Min(candle.High, binLow+binSize) - math.Max(candle.Low, binLow)
+			if overlap > 0 {
+				volumes[i] += volume * overlap / (candle.High - candle.Low)
+			}
+		}
+	}
+
+	profile := &models.PriceVolumeProfile{
+		Start:       candles[0].Timestamp,
+		End:         candles[len(candles)-1].Timestamp,
+		Bars:        len(candles),
+		TotalVolume: totalVolume,
+		Low:         low,
+		High:        high,
+		BinSize:     binSize,
+		Bins:        make([]models.VolumeProfileBin, bins),
+	}
+
+	poc := 0
+	for i, volume := range volumes {
+		profile.Bins[i] = models.VolumeProfileBin{
+			Low:    low + float64(i)*binSize,
+			High:   low + float64(i+1)*binSize,
+			Volume: volume,
+		}
+		if volume > volumes[poc] {
+			poc = i
+		}
+	}
+	profile.PointOfControl = profile.Bins[poc].Price()
+
+	valueLow, valueHigh := vpa.valueArea(volumes, poc)
+	profile.ValueAreaLow = profile.Bins[valueLow].Low
+	profile.ValueAreaHigh = profile.Bins[valueHigh].High
+
+	profile.HighVolumeNodes, profile.LowVolumeNodes = vpa.volumeNodes(profile.Bins)
+
+	return profile
+}
+
+// Sessions builds one profile per trading day of chronologically ordered bars
+func (vpa *VolumeProfileAnalyzer) Sessions(candles []*models.OHLCV, bins int) []*models.PriceVolumeProfile {
+	var profiles []*models.PriceVolumeProfile
+
+	for start := 0; start < len(candles); {
+		session := calendar.SessionDate(candles[start].Timestamp)
+		end := start + 1
+		for end < len(candles) && calendar.SessionDate(candles[end].Timestamp) == session {
+			end++
+		}
+
+		if profile := vpa.BuildWithBins(candles[start:end], bins); profile != nil {
+			profile.Session = session
+			profiles = append(profiles, profile)
+		}
+		start = end
+	}
+
+	return profiles
+}
+
+// CurrentSession builds the profile of the bars of the last bar's trading day
+func (vpa *VolumeProfileAnalyzer) CurrentSession(candles []*models.OHLCV) *models.PriceVolumeProfile {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	session := calendar.SessionDate(candles[len(candles)-1].Timestamp)
+	start := len(candles) - 1
+	for start > 0 && calendar.SessionDate(candles[start-1].Timestamp) == session {
+		start--
+	}
+
+	profile := vpa.Build(candles[start:])
+	if profile != nil {
+		profile.Session = session
+	}
+	return profile
+}
+
+// valueArea expands from the point of control towards the side with more
+// volume until the value area holds its share of the total volume
+func (vpa *VolumeProfileAnalyzer) valueArea(volumes []float64, poc int) (int, int) {
+	total := 0.0
+	for _, volume := range volumes {
+		total += volume
+	}
+
+	low, high := poc, poc
+	inside := volumes[poc]
+	for inside < total*vpa.valueAreaPercent && (low > 0 || high < len(volumes)-1) {
+		below, above := -1.0, -1.0
+		if low > 0 {
+			below = volumes[low-1]
+		}
+		if high < len(volumes)-1 {
+			above = volumes[high+1]
+		}
+
+		if above >= below {
+			high++
+			inside += above
+		} else {
+			low--
+			inside += below
+		}
+	}
+
+	return low, high
+}
+
+// volumeNodes returns the prices of local volume peaks well above the average
+// bin volume and of local troughs well below it, inside the traded range
+func (vpa *VolumeProfileAnalyzer) volumeNodes(bins []models.VolumeProfileBin) ([]float64, []float64) {
+	if len(bins) < 3 {
+		return nil, nil
+	}
+
+	average := 0.0
+	for _, bin := range bins {
+		average += bin.Volume
+	}
+	average /= float64(len(bins))
+
+	var highNodes, lowNodes []float64
+	for i := 1; i < len(bins)-1; i++ {
+		volume := bins[i].Volume
+		previous, next := bins[i-1].Volume, bins[i+1].Volume
+
+		switch {
+		case volume >= previous && volume > next && volume >= average*vpa.highNodeRatio:
+			highNodes = append(highNodes, bins[i].Price())
+		case volume <= previous && volume < next && volume <= average*vpa.lowNodeRatio:
+			lowNodes = append(lowNodes, bins[i].Price())
+		}
+	}
+
+	return highNodes, lowNodes
+}
+
+// binIndex returns the bin of a price, with the range high in the last bin
+func binIndex(price, low, binSize float64, bins int) int {
+	if binSize == 0 {
+		return 0
+	}
+	index := int((price - low) / binSize)
+	if index < 0 {
+		return 0
+	}
+	if index >= bins {
+		return bins - 1
+	}
+	return index
+}
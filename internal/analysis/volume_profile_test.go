@@ -0,0 +1,98 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+func bar(timestamp time.Time, low, high float64, volume int64) *models.OHLCV {
+	return &models.OHLCV{
+		Symbol:    "TEST",
+		Timestamp: timestamp,
+		Open:      low,
+		High:      high,
+		Low:       low,
+		Close:     high,
+		Volume:    volume,
+		Timeframe: "1h",
+	}
+}
+
+func TestVolumeProfileLevels(t *testing.T) {
+	base := time.Date(2024, 6, 3, 14, 0, 0, 0, time.UTC) // 10:00 New York
+	candles := []*models.OHLCV{
+		bar(base, 100, 110, 1000),                    // 100 per bin over the whole range
+		bar(base.Add(time.Hour), 104, 105, 5000),     // point of control
+		bar(base.Add(2*time.Hour), 102, 103, 1700),   // high-volume node below it
+		bar(base.Add(3*time.Hour), 106, 107, 800),    // value area extends above first
+		bar(base.Add(4*time.Hour), 101, 102, 100),    // thin bin
+		bar(base.Add(5*time.Hour), 108, 109, 100),    // thin bin
+		bar(base.Add(6*time.Hour), 109, 110, 100),    // thin bin
+		bar(base.Add(7*time.Hour), 100, 101, 100),    // thin bin
+		bar(base.Add(8*time.Hour), 107, 108, 100),    // thin bin
+		bar(base.Add(9*time.Hour), 103, 104, 300),    // low-volume node between nodes
+		bar(base.Add(10*time.Hour), 105, 106, 300),   // low-volume node
+		bar(base.Add(11*time.Hour), 104.2, 104.8, 0), // no volume
+	}
+
+	profile := NewVolumeProfileAnalyzer().BuildWithBins(candles, 10)
+	if profile == nil {
+		t.Fatal("expected a profile")
+	}
+	if profile.TotalVolume != 9600 || profile.Bars != 12 || profile.BinSize != 1 {
+		t.Fatalf("unexpected totals %+v", profile)
+	}
+
+	if profile.PointOfControl != 104.5 {
+		t.Errorf("point of control = %v, want 104.5", profile.PointOfControl)
+	}
+
+	// 104-105: 5100, then 105-106: 400, 106-107: 900 and 103-104: 400 reach
+	// 70% of 9600 before 102-103: 1800
+	if profile.ValueAreaLow != 103 || profile.ValueAreaHigh != 107 {
+		t.Errorf("value area = %v-%v, want 103-107", profile.ValueAreaLow, profile.ValueAreaHigh)
+	}
+
+	var total float64
+	for _, bin := range profile.Bins {
+		total += bin.Volume
+	}
+	if math.Abs(total-9600) > 1e-6 {
+		t.Errorf("bins hold %v volume, want 9600", total)
+	}
+
+	if len(profile.HighVolumeNodes) != 2 || profile.HighVolumeNodes[0] != 102.5 || profile.HighVolumeNodes[1] != 104.5 {
+		t.Errorf("high-volume nodes = %v, want [102.5 104.5]", profile.HighVolumeNodes)
+	}
+	if len(profile.LowVolumeNodes) != 3 || profile.LowVolumeNodes[0] != 101.5 || profile.LowVolumeNodes[2] != 105.5 {
+		t.Errorf("low-volume nodes = %v, want [101.5 103.5 105.5]", profile.LowVolumeNodes)
+	}
+}
+
+func TestVolumeProfileSessions(t *testing.T) {
+	// 23:00 UTC on June 3 is still June 3 in New York
+	base := time.Date(2024, 6, 3, 20, 0, 0, 0, time.UTC)
+	candles := []*models.OHLCV{
+		bar(base, 100, 101, 100),
+		bar(base.Add(3*time.Hour), 101, 102, 100),
+		bar(base.Add(17*time.Hour), 102, 103, 100),
+		bar(base.Add(18*time.Hour), 103, 104, 100),
+	}
+
+	analyzer := NewVolumeProfileAnalyzer()
+	profiles := analyzer.Sessions(candles, 10)
+	if len(profiles) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(profiles))
+	}
+	if profiles[0].Session != "2024-06-03" || profiles[0].Bars != 2 || profiles[1].Session != "2024-06-04" {
+		t.Errorf("unexpected sessions %s (%d bars), %s", profiles[0].Session, profiles[0].Bars, profiles[1].Session)
+	}
+
+	current := analyzer.CurrentSession(candles)
+	if current == nil || current.Session != "2024-06-04" || current.Bars != 2 || current.Low != 102 {
+		t.Errorf("unexpected current session %+v", current)
+	}
+}
@@ -0,0 +1,325 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/enrichment"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-261: Batch historical enrichment backfill
+
+// defaultBatchSize is the number of candles read, enriched and written at once
+const defaultBatchSize = 500
+
+// partialAttempts is the number of times a candle is enriched before a batch
+// with components still cut off by the enrichment deadline fails
+const partialAttempts = 3
+
+// CandleSource loads stored candles in chronological order
+type CandleSource interface {
+	GetHistory(ctx context.Context, symbol, timeframe string, start, end time.Time, limit int) ([]*models.OHLCV, error)
+	GetBefore(ctx context.Context, symbol, timeframe string, before time.Time, limit int) ([]*models.OHLCV, error)
+}
+
+// Store persists enriched candles together with job checkpoints
+type Store interface {
+	GetCheckpoint(ctx context.Context, key *models.BackfillCheckpoint) (*models.BackfillCheckpoint, error)
+	SaveBatch(ctx context.Context, records []*models.EnrichedCandleRecord, checkpoint *models.BackfillCheckpoint) error
+}
+
+// Enricher enriches candles with the configuration of an enrichment engine
+type Enricher interface {
+	Config() *enrichment.EnrichmentConfig
+	ConfigHash(options *models.EnrichmentOptions) string
+	HistoryPeriods(options *models.EnrichmentOptions) (int, error)
+	EnrichCandle(ctx context.Context, current *models.OHLCV, history []*models.OHLCV, options *models.EnrichmentOptions) (*models.EnrichedCandle, error)
+}
+
+// Job describes a backfill over stored candles
+type Job struct {
+	Symbols   []string
+	Timeframe string // stored timeframe: 1m, 5m, 15m, 1h, 4h, 1d
+	Start     time.Time
+	End       time.Time
+	Options   *models.EnrichmentOptions
+	BatchSize int  // candles per read and write, default 500
+	Workers   int  // parallel enrichments, default the engine's MaxConcurrency
+	Restart   bool // ignore checkpoints and enrich the whole range again
+}
+
+// Progress reports the state of a symbol's backfill
+type Progress struct {
+	Symbol    string    `json:"symbol"`
+	Processed int64     `json:"processed"` // candles written, including earlier runs
+	Skipped   int       `json:"skipped"`   // candles without enough history
+	Last      time.Time `json:"last"`      // last candle written
+	Resumed   bool      `json:"resumed"`
+	Done      bool      `json:"done"`
+	Err       error     `json:"-"`
+}
+
+// Runner enriches stored history and writes it in batches
+type Runner struct {
+	source CandleSource
+	store  Store
+	engine Enricher
+	logger zerolog.Logger
+}
+
+// NewRunner creates a backfill runner
+func NewRunner(source CandleSource, store Store, engine Enricher) *Runner {
+	return &Runner{
+		source: source,
+		store:  store,
+		engine: engine,
+		logger: logger.NewContextLogger("backfill"),
+	}
+}
+
+// Run backfills the symbols of the job one after another, enriching the
+// candles of each batch in parallel. report, when set, is called after every
+// written batch. Run returns the final progress of every symbol.
+func (r *Runner) Run(ctx context.Context, job *Job, report func(Progress)) []Progress {
+	if job.Options == nil {
+		job.Options = models.StreamEnrichmentOptions()
+	}
+	if job.BatchSize < 1 {
+		job.BatchSize = defaultBatchSize
+	}
+	if job.Workers < 1 {
+		job.Workers = r.engine.Config().MaxConcurrency
+	}
+	if job.Workers < 1 {
+		job.Workers = 1
+	}
+	if report == nil {
+		report = func(Progress) {}
+	}
+
+	configHash := r.engine.ConfigHash(job.Options)
+
+	results := make([]Progress, 0, len(job.Symbols))
+	for _, symbol := range job.Symbols {
+		progress := r.runSymbol(ctx, job, symbol, configHash, report)
+		if progress.Err != nil {
+			r.logger.Error().Err(progress.Err).Str("symbol", symbol).Msg("Backfill failed")
+		} else {
+			r.logger.Info().
+				Str("symbol", symbol).
+				Int64("processed", progress.Processed).
+				Int("skipped", progress.Skipped).
+				Bool("resumed", progress.Resumed).
+				Msg("Backfill completed")
+		}
+		report(progress)
+		results = append(results, progress)
+	}
+
+	return results
+}
+
+// runSymbol backfills one symbol from its checkpoint to the end of the range
+func (r *Runner) runSymbol(ctx context.Context, job *Job, symbol, configHash string, report func(Progress)) Progress {
+	progress := Progress{Symbol: symbol}
+
+	checkpoint := &models.BackfillCheckpoint{
+		Symbol:        symbol,
+		Timeframe:     job.Timeframe,
+		RangeStart:    job.Start,
+		RangeEnd:      job.End,
+		EngineVersion: enrichment.EngineVersion,
+		ConfigHash:    configHash,
+	}
+
+	if !job.Restart {
+		existing, err := r.store.GetCheckpoint(ctx, checkpoint)
+		if err != nil {
+			progress.Err = err
+			return progress
+		}
+		if existing != nil {
+			checkpoint = existing
+			progress.Resumed = true
+			progress.Processed = existing.Processed
+			progress.Last = existing.LastTimestamp
+			if existing.Completed {
+				progress.Done = true
+				return progress
+			}
+		}
+	}
+
+	periods, err := r.engine.HistoryPeriods(job.Options)
+	if err != nil {
+		progress.Err = err
+		return progress
+	}
+
+	// Stored timestamps have microsecond precision, so this excludes the last
+	// written candle while keeping it in the rolling history
+	next := job.Start
+	if !checkpoint.LastTimestamp.IsZero() {
+		next = checkpoint.LastTimestamp.Add(time.Microsecond)
+	}
+
+	window, err := r.source.GetBefore(ctx, symbol, job.Timeframe, next, periods)
+	if err != nil {
+		progress.Err = fmt.Errorf("failed to load warm-up candles: %w", err)
+		return progress
+	}
+
+	for {
+		page, err := r.source.GetHistory(ctx, symbol, job.Timeframe, next, job.End, job.BatchSize)
+		if err != nil {
+			progress.Err = fmt.Errorf("failed to load candles: %w", err)
+			return progress
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		candles := append(window, page...)
+		records, skipped, err := r.enrichBatch(ctx, job, candles, len(window), periods, configHash)
+		if err != nil {
+			progress.Err = err
+			return progress
+		}
+
+		last := page[len(page)-1].Timestamp
+		checkpoint.LastTimestamp = last
+		checkpoint.Processed += int64(len(records))
+		if err := r.store.SaveBatch(ctx, records, checkpoint); err != nil {
+			progress.Err = err
+			return progress
+		}
+
+		progress.Processed = checkpoint.Processed
+		progress.Skipped += skipped
+		progress.Last = last
+		report(progress)
+
+		if len(page) < job.BatchSize {
+			break
+		}
+
+		if len(candles) > periods {
+			candles = candles[len(candles)-periods:]
+		}
+		window = append([]*models.OHLCV(nil), candles...)
+		next = last.Add(time.Microsecond)
+	}
+
+	checkpoint.Completed = true
+	if err := r.store.SaveBatch(ctx, nil, checkpoint); err != nil {
+		progress.Err = err
+		return progress
+	}
+
+	progress.Done = true
+	return progress
+}
+
+// enrichBatch enriches candles[from:] in parallel, each with up to periods
+// preceding candles as history. Candles with less than the engine's minimum
+// history are skipped. Partial enrichments are retried and fail the batch
+// when they stay partial, so incomplete candles are never stored as complete
+// nor checkpointed past.
+func (r *Runner) enrichBatch(
+	ctx context.Context,
+	job *Job,
+	candles []*models.OHLCV,
+	from, periods int,
+	configHash string,
+) ([]*models.EnrichedCandleRecord, int, error) {
+	minHistory := r.engine.Config().MinHistoryPeriods
+	enriched := make([]*models.EnrichedCandleRecord, len(candles)-from)
+	errs := make([]error, len(enriched))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < job.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				index := from + i
+				history := candles[max(0, index-periods):index]
+				if len(history) < minHistory {
+					continue
+				}
+
+				candle, err := r.enrichCandle(ctx, candles[index], history, job.Options)
+				if err != nil {
+					errs[i] = fmt.Errorf("failed to enrich %s candle at %s: %w",
+						candles[index].Symbol, candles[index].Timestamp.Format(time.RFC3339), err)
+					continue
+				}
+
+				enriched[i] = &models.EnrichedCandleRecord{
+					Symbol:        candles[index].Symbol,
+					Timeframe:     job.Timeframe,
+					Timestamp:     candles[index].Timestamp,
+					EngineVersion: enrichment.EngineVersion,
+					ConfigHash:    configHash,
+					Candle:        candle,
+				}
+			}
+		}()
+	}
+
+	for i := range enriched {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	records := make([]*models.EnrichedCandleRecord, 0, len(enriched))
+	skipped := 0
+	for i, record := range enriched {
+		if errs[i] != nil {
+			return nil, 0, errs[i]
+		}
+		if record == nil {
+			skipped++
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, skipped, nil
+}
+
+// enrichCandle enriches a candle, retrying partial enrichments
+func (r *Runner) enrichCandle(
+	ctx context.Context,
+	current *models.OHLCV,
+	history []*models.OHLCV,
+	options *models.EnrichmentOptions,
+) (*models.EnrichedCandle, error) {
+	var skipped, failed []string
+	for attempt := 0; attempt < partialAttempts; attempt++ {
+		candle, err := r.engine.EnrichCandle(ctx, current, history, options)
+		if err != nil {
+			return nil, err
+		}
+		if candle.Metadata == nil || !candle.Metadata.Partial {
+			return candle, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		skipped, failed = candle.Metadata.SkippedComponents, candle.Metadata.FailedComponents
+	}
+	return nil, fmt.Errorf("enrichment incomplete after %d attempts, skipped %v, failed %v", partialAttempts, skipped, failed)
+}
@@ -0,0 +1,247 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/enrichment"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// memorySource serves candles of a single symbol from memory
+type memorySource struct {
+	candles []*models.OHLCV
+}
+
+func (s *memorySource) GetHistory(ctx context.Context, symbol, timeframe string, start, end time.Time, limit int) ([]*models.OHLCV, error) {
+	var result []*models.OHLCV
+	for _, candle := range s.candles {
+		if !candle.Timestamp.Before(start) && !candle.Timestamp.After(end) && len(result) < limit {
+			result = append(result, candle)
+		}
+	}
+	return result, nil
+}
+
+func (s *memorySource) GetBefore(ctx context.Context, symbol, timeframe string, before time.Time, limit int) ([]*models.OHLCV, error) {
+	var result []*models.OHLCV
+	for _, candle := range s.candles {
+		if candle.Timestamp.Before(before) {
+			result = append(result, candle)
+		}
+	}
+	if len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result, nil
+}
+
+// memoryStore keeps written records and checkpoints, failing after failAfter batches
+type memoryStore struct {
+	records     map[time.Time]*models.EnrichedCandleRecord
+	writes      int
+	checkpoints map[models.BackfillCheckpoint]models.BackfillCheckpoint
+	failAfter   int
+	batches     int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		records:     make(map[time.Time]*models.EnrichedCandleRecord),
+		checkpoints: make(map[models.BackfillCheckpoint]models.BackfillCheckpoint),
+	}
+}
+
+func checkpointKey(checkpoint *models.BackfillCheckpoint) models.BackfillCheckpoint {
+	return models.BackfillCheckpoint{
+		Symbol:        checkpoint.Symbol,
+		Timeframe:     checkpoint.Timeframe,
+		RangeStart:    checkpoint.RangeStart,
+		RangeEnd:      checkpoint.RangeEnd,
+		EngineVersion: checkpoint.EngineVersion,
+		ConfigHash:    checkpoint.ConfigHash,
+	}
+}
+
+func (s *memoryStore) GetCheckpoint(ctx context.Context, key *models.BackfillCheckpoint) (*models.BackfillCheckpoint, error) {
+	checkpoint, exists := s.checkpoints[checkpointKey(key)]
+	if !exists {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+func (s *memoryStore) SaveBatch(ctx context.Context, records []*models.EnrichedCandleRecord, checkpoint *models.BackfillCheckpoint) error {
+	if s.failAfter > 0 && s.batches == s.failAfter {
+		return errors.New("connection lost")
+	}
+	s.batches++
+
+	for _, record := range records {
+		s.records[record.Timestamp] = record
+		s.writes++
+	}
+	s.checkpoints[checkpointKey(checkpoint)] = *checkpoint
+	return nil
+}
+
+func testCandles(count int) []*models.OHLCV {
+	candles := make([]*models.OHLCV, count)
+	base := time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)
+	for i := range candles {
+		price := 100 + float64(i%17) - 8 + float64(i)/10
+		candles[i] = &models.OHLCV{
+			Symbol:    "TEST",
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+			Open:      price,
+			High:      price + 1,
+			Low:       price - 1,
+			Close:     price + 0.5,
+			Volume:    int64(1000 + i*10),
+			Timeframe: "1m",
+		}
+	}
+	return candles
+}
+
+func testJob(candles []*models.OHLCV) *Job {
+	options := models.StreamEnrichmentOptions()
+	options.Indicators = []string{"sma(5)"}
+	return &Job{
+		Symbols:   []string{"TEST"},
+		Timeframe: "1m",
+		Start:     candles[100].Timestamp,
+		End:       candles[279].Timestamp,
+		Options:   options,
+		BatchSize: 50,
+		Workers:   4,
+	}
+}
+
+func TestRunEnrichesRangeWithRollingHistory(t *testing.T) {
+	candles := testCandles(300)
+	store := newMemoryStore()
+	runner := NewRunner(&memorySource{candles: candles}, store, enrichment.NewCandleEnrichmentEngine(nil))
+
+	results := runner.Run(context.Background(), testJob(candles), nil)
+	if len(results) != 1 || results[0].Err != nil || !results[0].Done {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if results[0].Processed != 180 || len(store.records) != 180 {
+		t.Fatalf("processed %d, stored %d, want 180", results[0].Processed, len(store.records))
+	}
+
+	// The first candles of the range use warm-up history from before it
+	for _, i := range []int{100, 101, 199, 279} {
+		record := store.records[candles[i].Timestamp]
+		if record == nil {
+			t.Fatalf("candle %d not stored", i)
+		}
+
+		want := 0.0
+		for _, candle := range candles[i-4 : i+1] {
+			want += candle.Close / 5
+		}
+		if got := record.Candle.Indicators.Values["sma(5)"]; math.Abs(got-want) > 1e-9 {
+			t.Errorf("candle %d: sma(5) = %v, want %v", i, got, want)
+		}
+		if record.EngineVersion != enrichment.EngineVersion || record.ConfigHash == "" {
+			t.Errorf("candle %d: missing provenance %q %q", i, record.EngineVersion, record.ConfigHash)
+		}
+	}
+}
+
+func TestRunResumesFromCheckpoint(t *testing.T) {
+	candles := testCandles(300)
+	source := &memorySource{candles: candles}
+	store := newMemoryStore()
+	store.failAfter = 2
+	runner := NewRunner(source, store, enrichment.NewCandleEnrichmentEngine(nil))
+
+	results := runner.Run(context.Background(), testJob(candles), nil)
+	if results[0].Err == nil || results[0].Processed != 100 {
+		t.Fatalf("expected failure after 2 batches, got %+v", results[0])
+	}
+
+	store.failAfter = 0
+	results = runner.Run(context.Background(), testJob(candles), nil)
+	if results[0].Err != nil || !results[0].Resumed || !results[0].Done {
+		t.Fatalf("expected resumed completion, got %+v", results[0])
+	}
+	if results[0].Processed != 180 || store.writes != 180 {
+		t.Errorf("processed %d with %d writes, want 180 without rewrites", results[0].Processed, store.writes)
+	}
+
+	// A completed job is not repeated unless restarted
+	results = runner.Run(context.Background(), testJob(candles), nil)
+	if store.writes != 180 || !results[0].Done {
+		t.Errorf("completed job was repeated: %d writes", store.writes)
+	}
+
+	job := testJob(candles)
+	job.Restart = true
+	runner.Run(context.Background(), job, nil)
+	if store.writes != 360 {
+		t.Errorf("restart wrote %d candles, want 180 more", store.writes-180)
+	}
+}
+
+// flakyEngine reports its first partial enrichments as partial, with a failed
+// component
+type flakyEngine struct {
+	*enrichment.CandleEnrichmentEngine
+	partial int32
+}
+
+func (e *flakyEngine) EnrichCandle(ctx context.Context, current *models.OHLCV, history []*models.OHLCV, options *models.EnrichmentOptions) (*models.EnrichedCandle, error) {
+	candle, err := e.CandleEnrichmentEngine.EnrichCandle(ctx, current, history, options)
+	if err == nil && atomic.AddInt32(&e.partial, -1) >= 0 {
+		candle.Metadata.Partial = true
+		candle.Metadata.FailedComponents = []string{"flaky"}
+	}
+	return candle, err
+}
+
+func TestRunRetriesPartialEnrichments(t *testing.T) {
+	tests := []struct {
+		name      string
+		partial   int32
+		wantErr   bool
+		wantWrite int
+	}{
+		{"retried within attempts", partialAttempts - 1, false, 180},
+		{"partial until batch fails", math.MaxInt32, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candles := testCandles(300)
+			source := &memorySource{candles: candles}
+			engine := &flakyEngine{CandleEnrichmentEngine: enrichment.NewCandleEnrichmentEngine(nil), partial: tt.partial}
+
+			job := testJob(candles)
+			job.Workers = 1
+
+			store := newMemoryStore()
+			results := NewRunner(source, store, engine).Run(context.Background(), job, nil)
+			if (results[0].Err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", results[0].Err)
+			}
+			if store.writes != tt.wantWrite {
+				t.Fatalf("wrote %d candles, want %d", store.writes, tt.wantWrite)
+			}
+			for _, record := range store.records {
+				if record.Candle.Metadata.Partial {
+					t.Fatalf("stored an incomplete candle at %s", record.Timestamp)
+				}
+			}
+			if tt.wantErr && len(store.checkpoints) != 0 {
+				t.Errorf("checkpoint advanced past partial candles: %+v", store.checkpoints)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	// Embedded zone database for hosts and images without one
+	_ "time/tzdata"
+)
+
+// REQ-265: US equity exchange calendar
+//
+// Trading days follow the NYSE holiday rules: New Year's Day, Martin Luther
+// King Jr. Day, Washington's Birthday, Good Friday, Memorial Day, Juneteenth
+// (from 2022), Independence Day, Labor Day, Thanksgiving and Christmas, with
+// Saturday holidays observed on Friday and Sunday holidays on Monday. The
+// regular session runs 9:30-16:00 America/New_York and closes at 13:00 on
+// July 3, the day after Thanksgiving and Christmas Eve.
+
+// Location is the exchange timezone (REQ-072: market timezone)
+var Location = loadLocation()
+
+// loadLocation panics rather than fall back to UTC, which would shift every
+// session by four or five hours
+func loadLocation() *time.Location {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		panic(fmt.Sprintf("calendar: load exchange timezone: %v", err))
+	}
+	return location
+}
+
+// Regular session hours in exchange time
+const (
+	openHour, openMinute = 9, 30
+	closeHour            = 16
+	earlyCloseHour       = 13
+)
+
+// Date returns midnight exchange time of the calendar day of t
+func Date(t time.Time) time.Time {
+	year, month, day := t.In(Location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, Location)
+}
+
+// IsHoliday reports whether the exchange is closed for a holiday on the day of t
+func IsHoliday(t time.Time) bool {
+	date := Date(t)
+	year := date.Year()
+
+	for _, holiday := range holidays(year) {
+		if holiday.Equal(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTradingDay reports whether the exchange opens on the day of t
+func IsTradingDay(t time.Time) bool {
+	weekday := Date(t).Weekday()
+	if weekday == time.Saturday || weekday == time.Sunday {
+		return false
+	}
+	return !IsHoliday(t)
+}
+
+// IsEarlyClose reports whether the regular session of the day of t closes at 13:00
+func IsEarlyClose(t time.Time) bool {
+	if !IsTradingDay(t) {
+		return false
+	}
+
+	date := Date(t)
+	year, month, day := date.Date()
+	switch {
+	case month == time.July && day == 3:
+		return true
+	case month == time.December && day == 24:
+		return true
+	case month == time.November && date.Equal(nthWeekday(year, time.November, time.Thursday, 4).AddDate(0, 0, 1)):
+		return true
+	}
+	return false
+}
+
+// SessionDay returns midnight exchange time of the trading day a bar at t
+// belongs to: its own day, or the next trading day for bars on closed days
+func SessionDay(t time.Time) time.Time {
+	date := Date(t)
+	for !IsTradingDay(date) {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// PreviousSessionDay returns midnight exchange time of the trading day before
+// the one a bar at t belongs to
+func PreviousSessionDay(t time.Time) time.Time {
+	date := SessionDay(t).AddDate(0, 0, -1)
+	for !IsTradingDay(date) {
+		date = date.AddDate(0, 0, -1)
+	}
+	return date
+}
+
+// SessionDate returns the trading day a bar at t belongs to as YYYY-MM-DD
+func SessionDate(t time.Time) string {
+	return SessionDay(t).Format("2006-01-02")
+}
+
+// SessionOpen returns the regular open of the trading day a bar at t belongs to
+func SessionOpen(t time.Time) time.Time {
+	day := SessionDay(t)
+	return time.Date(day.Year(), day.Month(), day.Day(), openHour, openMinute, 0, 0, Location)
+}
+
+// SessionClose returns the regular close of the trading day a bar at t belongs to
+func SessionClose(t time.Time) time.Time {
+	day := SessionDay(t)
+	hour := closeHour
+	if IsEarlyClose(day) {
+		hour = earlyCloseHour
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, Location)
+}
+
+// IsRegularHours reports whether t falls into a regular session
+func IsRegularHours(t time.Time) bool {
+	if !IsTradingDay(t) {
+		return false
+	}
+	return !t.Before(SessionOpen(t)) && t.Before(SessionClose(t))
+}
+
+// InRegularHours reports whether a bar starting at t and lasting duration
+// overlaps a regular session, so daily bars count while pre-market and
+// after-hours bars do not. A zero duration checks t alone.
+func InRegularHours(t time.Time, duration time.Duration) bool {
+	if duration <= 0 {
+		return IsRegularHours(t)
+	}
+	if !IsTradingDay(t) {
+		return false
+	}
+	return t.Add(duration).After(SessionOpen(t)) && t.Before(SessionClose(t))
+}
+
+// holidays returns the observed holidays of a year at midnight exchange time
+func holidays(year int) []time.Time {
+	days := []time.Time{
+		newYearsDay(year),
+		nthWeekday(year, time.January, time.Monday, 3),    // Martin Luther King Jr. Day
+		nthWeekday(year, time.February, time.Monday, 3),   // Washington's Birthday
+		easter(year).AddDate(0, 0, -2),                    // Good Friday
+		lastWeekday(year, time.May, time.Monday),          // Memorial Day
+		observed(year, time.July, 4),                      // Independence Day
+		nthWeekday(year, time.September, time.Monday, 1),  // Labor Day
+		nthWeekday(year, time.November, time.Thursday, 4), // Thanksgiving
+		observed(year, time.December, 25),                 // Christmas
+	}
+	if year >= 2022 {
+		days = append(days, observed(year, time.June, 19)) // Juneteenth
+	}
+	return days
+}
+
+// newYearsDay returns New Year's Day, moved to Monday when on a Sunday. A
+// Saturday New Year's Day is not observed on the Friday before.
+func newYearsDay(year int) time.Time {
+	date := time.Date(year, time.January, 1, 0, 0, 0, 0, Location)
+	if date.Weekday() == time.Sunday {
+		return date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// observed moves a Saturday holiday to Friday and a Sunday holiday to Monday
+func observed(year int, month time.Month, day int) time.Time {
+	date := time.Date(year, month, day, 0, 0, 0, 0, Location)
+	switch date.Weekday() {
+	case time.Saturday:
+		return date.AddDate(0, 0, -1)
+	case time.Sunday:
+		return date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// nthWeekday returns the n-th weekday of a month
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	date := time.Date(year, month, 1, 0, 0, 0, 0, Location)
+	offset := (int(weekday) - int(date.Weekday()) + 7) % 7
+	return date.AddDate(0, 0, offset+7*(n-1))
+}
+
+// lastWeekday returns the last weekday of a month
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	date := time.Date(year, month+1, 0, 0, 0, 0, 0, Location)
+	offset := (int(date.Weekday()) - int(weekday) + 7) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+// easter returns Easter Sunday of the Gregorian calendar
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, Location)
+}
@@ -0,0 +1,79 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 12, 0, 0, 0, Location)
+}
+
+func TestTradingDays(t *testing.T) {
+	tests := []struct {
+		name    string
+		date    time.Time
+		trading bool
+	}{
+		{"regular weekday", day(2024, time.March, 28), true},
+		{"good friday", day(2024, time.March, 29), false},
+		{"saturday", day(2024, time.March, 30), false},
+		{"juneteenth observed on monday", day(2022, time.June, 20), false},
+		{"juneteenth before 2022", day(2021, time.June, 18), true},
+		{"independence day observed on friday", day(2026, time.July, 3), false},
+		{"thanksgiving", day(2024, time.November, 28), false},
+		{"new year on saturday is not observed", day(2021, time.December, 31), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTradingDay(tt.date); got != tt.trading {
+				t.Errorf("IsTradingDay(%s) = %v, want %v", tt.date.Format("2006-01-02"), got, tt.trading)
+			}
+		})
+	}
+}
+
+func TestSessionHours(t *testing.T) {
+	// Early closes
+	if close := SessionClose(day(2024, time.July, 3)); close.Hour() != 13 {
+		t.Errorf("2024-07-03 closes at %v, want 13:00", close)
+	}
+	if close := SessionClose(day(2024, time.November, 29)); close.Hour() != 13 {
+		t.Errorf("day after thanksgiving closes at %v, want 13:00", close)
+	}
+	if close := SessionClose(day(2024, time.March, 28)); close.Hour() != 16 {
+		t.Errorf("regular day closes at %v, want 16:00", close)
+	}
+
+	// Weekend bars belong to the next trading day
+	if got := SessionDate(day(2024, time.March, 30)); got != "2024-04-01" {
+		t.Errorf("SessionDate(saturday) = %s, want 2024-04-01", got)
+	}
+
+	open := SessionOpen(day(2024, time.March, 28))
+	if !IsRegularHours(open) || IsRegularHours(open.Add(-time.Minute)) {
+		t.Errorf("regular hours should start at %v", open)
+	}
+
+	// Bars overlapping the session count, pre-market and after-hours bars do not
+	bars := []struct {
+		start    time.Time
+		duration time.Duration
+		want     bool
+	}{
+		{open.Add(-time.Minute), time.Minute, false},
+		{open.Add(-time.Hour), 4 * time.Hour, true},
+		{Date(open), 24 * time.Hour, true},
+		{SessionClose(open), time.Minute, false},
+		{Date(day(2024, time.March, 29)), 24 * time.Hour, false}, // Good Friday
+	}
+	for _, bar := range bars {
+		if got := InRegularHours(bar.start, bar.duration); got != bar.want {
+			t.Errorf("InRegularHours(%v, %v) = %v, want %v", bar.start, bar.duration, got, bar.want)
+		}
+	}
+	if got := PreviousSessionDay(day(2024, time.April, 1)); !got.Equal(Date(day(2024, time.March, 28))) {
+		t.Errorf("PreviousSessionDay(2024-04-01) = %v, want 2024-03-28", got)
+	}
+}
@@ -8,18 +8,25 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/expression"
+	"github.com/ridopark/jonbu-ohlcv/internal/indicators"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-062: Configuration validation on startup
 // REQ-063: Sensible defaults for optional settings
 // REQ-064: Multiple environments support
 type Config struct {
-	Environment string         `mapstructure:"environment" validate:"oneof=development staging production"`
-	LogLevel    string         `mapstructure:"log_level" validate:"oneof=debug info warn error"`
-	Database    DatabaseConfig `mapstructure:"database"`
-	Alpaca      AlpacaConfig   `mapstructure:"alpaca"`
-	Server      ServerConfig   `mapstructure:"server"`
-	Worker      WorkerConfig   `mapstructure:"worker"`
+	Environment string           `mapstructure:"environment" validate:"oneof=development staging production"`
+	LogLevel    string           `mapstructure:"log_level" validate:"oneof=debug info warn error"`
+	Database    DatabaseConfig   `mapstructure:"database"`
+	Alpaca      AlpacaConfig     `mapstructure:"alpaca"`
+	Server      ServerConfig     `mapstructure:"server"`
+	Worker      WorkerConfig     `mapstructure:"worker"`
+	Fanout      FanoutConfig     `mapstructure:"fanout"`
+	Alerts      AlertsConfig     `mapstructure:"alerts"`
+	Enrichment  EnrichmentConfig `mapstructure:"enrichment"`
 }
 
 type DatabaseConfig struct {
@@ -58,6 +65,45 @@ type WorkerConfig struct {
 	AggregationTimeout  int `mapstructure:"aggregation_timeout" validate:"min=1,max=60"`
 }
 
+// REQ-251: Multi-instance candle fan-out
+type FanoutConfig struct {
+	Enabled          bool   `mapstructure:"enabled"`
+	Backend          string `mapstructure:"backend" validate:"oneof=postgres local"`
+	Channel          string `mapstructure:"channel"`
+	InstanceID       string `mapstructure:"instance_id"`
+	LeaderLockKey    int64  `mapstructure:"leader_lock_key"`
+	ElectionInterval int    `mapstructure:"election_interval" validate:"min=1"`
+}
+
+// REQ-253: Server-side alert rules
+type AlertsConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	RefreshInterval int  `mapstructure:"refresh_interval" validate:"min=1"` // seconds between rule reloads
+	WebhookTimeout  int  `mapstructure:"webhook_timeout" validate:"min=1"`  // seconds per webhook attempt
+	WebhookRetries  int  `mapstructure:"webhook_retries" validate:"min=0"`
+}
+
+// REQ-255: Registry indicators added to every streamed enriched candle
+// REQ-259: Custom expression signals loaded from a YAML or JSON file
+// REQ-260: Streamed enriched candles persisted for historical queries
+// REQ-262: Optional higher timeframe confluence in streamed signals
+// REQ-263: Benchmark compared with every streamed candle
+// REQ-266: Named signal profiles loaded from a YAML or JSON file
+type EnrichmentConfig struct {
+	Indicators  string `mapstructure:"indicators"`   // comma-separated specs, e.g. "sma(200),bbands(20,2.5)"
+	SignalsFile string `mapstructure:"signals_file"` // optional, e.g. "config/signals.yaml"
+	Persist     bool   `mapstructure:"persist"`      // store every streamed enriched candle
+
+	MultiTimeframe bool   `mapstructure:"multi_timeframe"` // compare signals with 15m, 1h and 1d
+	Benchmark      string `mapstructure:"benchmark"`       // e.g. SPY or a sector ETF, empty to disable
+
+	ProfilesFile  string `mapstructure:"profiles_file"`  // optional, e.g. "config/profiles.yaml"
+	SignalProfile string `mapstructure:"signal_profile"` // profile of streamed and stored signals
+
+	Signals  []models.CustomSignalDefinition `mapstructure:"-"`
+	Profiles []models.SignalProfile          `mapstructure:"-"`
+}
+
 // REQ-061: Load configuration from .env files and environment variables
 func Load() (*Config, error) {
 	// Load .env file if exists (development)
@@ -102,6 +148,29 @@ func Load() (*Config, error) {
 	viper.BindEnv("worker.max_workers_per_symbol", "WORKER_MAX_WORKERS_PER_SYMBOL")
 	viper.BindEnv("worker.aggregation_timeout", "WORKER_AGGREGATION_TIMEOUT")
 
+	// Fan-out configuration binding
+	viper.BindEnv("fanout.enabled", "FANOUT_ENABLED")
+	viper.BindEnv("fanout.backend", "FANOUT_BACKEND")
+	viper.BindEnv("fanout.channel", "FANOUT_CHANNEL")
+	viper.BindEnv("fanout.instance_id", "FANOUT_INSTANCE_ID")
+	viper.BindEnv("fanout.leader_lock_key", "FANOUT_LEADER_LOCK_KEY")
+	viper.BindEnv("fanout.election_interval", "FANOUT_ELECTION_INTERVAL")
+
+	// Alert configuration binding
+	viper.BindEnv("alerts.enabled", "ALERTS_ENABLED")
+	viper.BindEnv("alerts.refresh_interval", "ALERTS_REFRESH_INTERVAL")
+	viper.BindEnv("alerts.webhook_timeout", "ALERTS_WEBHOOK_TIMEOUT")
+	viper.BindEnv("alerts.webhook_retries", "ALERTS_WEBHOOK_RETRIES")
+
+	// Enrichment configuration binding
+	viper.BindEnv("enrichment.indicators", "ENRICHMENT_INDICATORS")
+	viper.BindEnv("enrichment.signals_file", "ENRICHMENT_SIGNALS_FILE")
+	viper.BindEnv("enrichment.persist", "ENRICHMENT_PERSIST")
+	viper.BindEnv("enrichment.multi_timeframe", "ENRICHMENT_MULTI_TIMEFRAME")
+	viper.BindEnv("enrichment.benchmark", "ENRICHMENT_BENCHMARK")
+	viper.BindEnv("enrichment.profiles_file", "ENRICHMENT_PROFILES_FILE")
+	viper.BindEnv("enrichment.signal_profile", "ENRICHMENT_SIGNAL_PROFILE")
+
 	// REQ-063: Set sensible defaults
 	setDefaults()
 
@@ -110,6 +179,22 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if config.Enrichment.SignalsFile != "" {
+		signals, err := LoadSignalDefinitions(config.Enrichment.SignalsFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Enrichment.Signals = signals
+	}
+
+	if config.Enrichment.ProfilesFile != "" {
+		profiles, err := LoadSignalProfiles(config.Enrichment.ProfilesFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Enrichment.Profiles = profiles
+	}
+
 	// REQ-062: Validate configuration on startup
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -142,9 +227,125 @@ func (c *Config) Validate() error {
 		return errors.New("HTTP port is required")
 	}
 
+	if c.Fanout.Enabled {
+		if c.Fanout.Backend != "postgres" && c.Fanout.Backend != "local" {
+			return fmt.Errorf("unsupported fanout backend: %s (supported: postgres, local)", c.Fanout.Backend)
+		}
+		if c.Fanout.Channel == "" {
+			return errors.New("fanout channel is required when fanout is enabled")
+		}
+	}
+
+	if c.Alerts.Enabled {
+		if c.Alerts.RefreshInterval < 1 {
+			return errors.New("alerts refresh interval must be at least 1 second")
+		}
+		if c.Alerts.WebhookTimeout < 1 {
+			return errors.New("alerts webhook timeout must be at least 1 second")
+		}
+	}
+
+	for _, spec := range c.Enrichment.IndicatorSpecs() {
+		if _, _, err := indicators.DefaultRegistry().Resolve(spec); err != nil {
+			return fmt.Errorf("invalid enrichment indicator: %w", err)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i := range c.Enrichment.Signals {
+		signal := &c.Enrichment.Signals[i]
+		if err := signal.Validate(); err != nil {
+			return err
+		}
+		if names[signal.Name] {
+			return fmt.Errorf("duplicate custom signal: %s", signal.Name)
+		}
+		names[signal.Name] = true
+
+		if _, err := expression.Compile(signal.When); err != nil {
+			return fmt.Errorf("custom signal %s: %w", signal.Name, err)
+		}
+	}
+
+	profiles := make(map[string]bool)
+	for i := range c.Enrichment.Profiles {
+		profile := &c.Enrichment.Profiles[i]
+		if err := profile.Validate(); err != nil {
+			return err
+		}
+		if profiles[profile.Name] {
+			return fmt.Errorf("duplicate signal profile: %s", profile.Name)
+		}
+		profiles[profile.Name] = true
+
+		for _, rule := range profile.Rules {
+			if _, err := expression.Compile(rule.When); err != nil {
+				return fmt.Errorf("signal profile %s: rule %s: %w", profile.Name, rule.Name, err)
+			}
+		}
+	}
+	if profile := c.Enrichment.SignalProfile; profile != "" && profile != models.DefaultSignalProfile && !profiles[profile] {
+		return fmt.Errorf("unknown enrichment signal profile: %s", c.Enrichment.SignalProfile)
+	}
+
 	return nil
 }
 
+// LoadSignalDefinitions reads custom signal definitions from a YAML, JSON or
+// TOML file with a top-level "signals" list, e.g.
+//
+//	signals:
+//	  - name: oversold_uptrend
+//	    when: rsi(14) < 30 and close > sma(200)
+//	    signal: bullish
+//	    confidence: 70
+func LoadSignalDefinitions(path string) ([]models.CustomSignalDefinition, error) {
+	reader := viper.New()
+	reader.SetConfigFile(path)
+	if err := reader.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read signals file %s: %w", path, err)
+	}
+
+	var signals []models.CustomSignalDefinition
+	if err := reader.UnmarshalKey("signals", &signals); err != nil {
+		return nil, fmt.Errorf("failed to parse signals file %s: %w", path, err)
+	}
+
+	return signals, nil
+}
+
+// LoadSignalProfiles reads signal profiles from a YAML, JSON or TOML file
+// with a top-level "profiles" list, e.g.
+//
+//	profiles:
+//	  - name: scalper
+//	    weights: {trend: 20, momentum: 50, volume: 30, volume_divergence: 30}
+//	    thresholds: {bullish: 40, bearish: 40}
+//	    rules:
+//	      - name: above_fast_ema
+//	        when: close > ema(9)
+//	        signal: bullish
+//	        weight: 20
+func LoadSignalProfiles(path string) ([]models.SignalProfile, error) {
+	reader := viper.New()
+	reader.SetConfigFile(path)
+	if err := reader.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read profiles file %s: %w", path, err)
+	}
+
+	var profiles []models.SignalProfile
+	if err := reader.UnmarshalKey("profiles", &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles file %s: %w", path, err)
+	}
+
+	return profiles, nil
+}
+
+// IndicatorSpecs returns the configured registry indicator specs
+func (e EnrichmentConfig) IndicatorSpecs() []string {
+	return indicators.SplitSpecs(e.Indicators)
+}
+
 // REQ-065: Mask sensitive values in logs
 func (c *Config) String() string {
 	masked := *c
@@ -187,4 +388,24 @@ func setDefaults() {
 	viper.SetDefault("worker.buffer_size", 1000)
 	viper.SetDefault("worker.max_workers_per_symbol", 5)
 	viper.SetDefault("worker.aggregation_timeout", 5)
+
+	// Fan-out defaults
+	viper.SetDefault("fanout.enabled", false)
+	viper.SetDefault("fanout.backend", "postgres")
+	viper.SetDefault("fanout.channel", "jonbu_candles")
+	viper.SetDefault("fanout.leader_lock_key", 7420011)
+	viper.SetDefault("fanout.election_interval", 5)
+
+	// Alert defaults
+	viper.SetDefault("alerts.enabled", true)
+	viper.SetDefault("alerts.refresh_interval", 30)
+	viper.SetDefault("alerts.webhook_timeout", 5)
+	viper.SetDefault("alerts.webhook_retries", 2)
+
+	// Enrichment defaults
+	viper.SetDefault("enrichment.indicators", "sma(200),ema(9)")
+	viper.SetDefault("enrichment.persist", true)
+	viper.SetDefault("enrichment.multi_timeframe", false)
+	viper.SetDefault("enrichment.benchmark", "SPY")
+	viper.SetDefault("enrichment.signal_profile", models.DefaultSignalProfile)
 }
@@ -0,0 +1,116 @@
+package correlation
+
+import (
+	"sync"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/indicators"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-263: Live correlation matrix across the tracked universe
+
+// Tracker keeps the recent closes of every streamed symbol:timeframe
+type Tracker struct {
+	window int
+	series map[string][]*models.OHLCV
+	mu     sync.RWMutex
+}
+
+// NewTracker creates a tracker keeping up to window candles per series
+func NewTracker(window int) *Tracker {
+	return &Tracker{
+		window: window,
+		series: make(map[string][]*models.OHLCV),
+	}
+}
+
+// Update records a candle; a candle with the latest timestamp replaces it and
+// older candles are ignored
+func (t *Tracker) Update(candle *models.OHLCV) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := candle.Symbol + ":" + candle.Timeframe
+	series := t.series[key]
+
+	if n := len(series); n > 0 {
+		last := series[n-1]
+		if candle.Timestamp.Equal(last.Timestamp) {
+			series[n-1] = candle
+			return
+		}
+		if candle.Timestamp.Before(last.Timestamp) {
+			return
+		}
+	}
+
+	series = append(series, candle)
+	// Compact once the series doubles so trimming stays amortized O(1)
+	if len(series) >= 2*t.window {
+		trimmed := make([]*models.OHLCV, t.window, 2*t.window)
+		copy(trimmed, series[len(series)-t.window:])
+		series = trimmed
+	}
+	t.series[key] = series
+}
+
+// Candles returns up to limit of the latest candles of a series, oldest first
+func (t *Tracker) Candles(symbol, timeframe string, limit int) []*models.OHLCV {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	series := t.series[symbol+":"+timeframe]
+	if len(series) > limit {
+		series = series[len(series)-limit:]
+	}
+	return append([]*models.OHLCV(nil), series...)
+}
+
+// Remove forgets every series of a symbol
+func (t *Tracker) Remove(symbol string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, series := range t.series {
+		if len(series) > 0 && series[0].Symbol == symbol {
+			delete(t.series, key)
+		}
+	}
+}
+
+// Compute correlates the period returns of every pair of series at the
+// timestamps they share
+func Compute(symbols []string, series map[string][]*models.OHLCV, minObservations int) *models.CorrelationMatrix {
+	n := len(symbols)
+	matrix := &models.CorrelationMatrix{
+		Symbols:      symbols,
+		Correlations: make([][]*float64, n),
+		Observations: make([][]int, n),
+	}
+	for i := range symbols {
+		matrix.Correlations[i] = make([]*float64, n)
+		matrix.Observations[i] = make([]int, n)
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			a, b := indicators.AlignedCloses(series[symbols[i]], series[symbols[j]])
+			returnsA, returnsB := indicators.Returns(a), indicators.Returns(b)
+
+			matrix.Observations[i][j] = len(returnsA)
+			matrix.Observations[j][i] = len(returnsA)
+			if len(returnsA) < minObservations || len(returnsA) < 2 {
+				continue
+			}
+
+			correlation := indicators.Correlation(returnsA, returnsB)
+			if i == j {
+				correlation = 1
+			}
+			matrix.Correlations[i][j] = &correlation
+			matrix.Correlations[j][i] = &correlation
+		}
+	}
+
+	return matrix
+}
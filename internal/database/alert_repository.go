@@ -0,0 +1,201 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-253: Persistence for server-side alert rules
+
+// ErrAlertRuleNotFound is returned when an alert rule does not exist
+var ErrAlertRuleNotFound = errors.New("alert rule not found")
+
+const alertRuleColumns = `id, name, symbol, timeframe, condition, threshold, pattern, webhook_url,
+	cooldown_seconds, enabled, last_triggered_at, created_at, updated_at`
+
+// AlertRepository stores alert rules
+type AlertRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewAlertRepository creates a new alert rule repository
+func NewAlertRepository(db *DB) *AlertRepository {
+	return &AlertRepository{
+		db:     db,
+		logger: logger.NewContextLogger("alert_repository"),
+	}
+}
+
+// Create stores a new alert rule and fills in its ID and timestamps
+func (r *AlertRepository) Create(ctx context.Context, rule *models.AlertRule) error {
+	start := time.Now()
+	defer func() {
+		logger.LogPerformance(r.logger, "create_alert_rule", start, true)
+	}()
+
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+
+	err := r.db.conn.QueryRowContext(ctx, `
+		INSERT INTO alert_rules (name, symbol, timeframe, condition, threshold, pattern, webhook_url,
+			cooldown_seconds, enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id`,
+		rule.Name,
+		rule.Symbol,
+		rule.Timeframe,
+		rule.Condition,
+		rule.Threshold,
+		rule.Pattern,
+		rule.WebhookURL,
+		rule.CooldownSeconds,
+		rule.Enabled,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	).Scan(&rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert alert rule: %w", err)
+	}
+
+	r.logger.Info().
+		Int64("id", rule.ID).
+		Str("symbol", rule.Symbol).
+		Str("timeframe", rule.Timeframe).
+		Str("condition", rule.Condition).
+		Msg("Alert rule created")
+
+	return nil
+}
+
+// Get retrieves a single alert rule
+func (r *AlertRepository) Get(ctx context.Context, id int64) (*models.AlertRule, error) {
+	row := r.db.conn.QueryRowContext(ctx,
+		`SELECT `+alertRuleColumns+` FROM alert_rules WHERE id = $1`, id)
+
+	rule, err := scanAlertRule(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrAlertRuleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// List retrieves all alert rules, optionally filtered by symbol
+func (r *AlertRepository) List(ctx context.Context, symbol string) ([]*models.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules`
+	args := []interface{}{}
+	if symbol != "" {
+		query += ` WHERE symbol = $1`
+		args = append(args, symbol)
+	}
+	query += ` ORDER BY id`
+
+	return r.query(ctx, query, args...)
+}
+
+// ListEnabled retrieves all enabled alert rules
+func (r *AlertRepository) ListEnabled(ctx context.Context) ([]*models.AlertRule, error) {
+	return r.query(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE enabled = true ORDER BY id`)
+}
+
+// Delete removes an alert rule
+func (r *AlertRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.conn.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted alert rule: %w", err)
+	}
+	if affected == 0 {
+		return ErrAlertRuleNotFound
+	}
+
+	return nil
+}
+
+// MarkTriggered records when a rule last fired so cooldowns survive restarts
+func (r *AlertRepository) MarkTriggered(ctx context.Context, id int64, triggeredAt time.Time) error {
+	if _, err := r.db.conn.ExecContext(ctx,
+		`UPDATE alert_rules SET last_triggered_at = $2, updated_at = NOW() WHERE id = $1`,
+		id, triggeredAt,
+	); err != nil {
+		return fmt.Errorf("failed to mark alert rule triggered: %w", err)
+	}
+
+	return nil
+}
+
+// query runs a select and scans all alert rules
+func (r *AlertRepository) query(ctx context.Context, query string, args ...interface{}) ([]*models.AlertRule, error) {
+	rows, err := r.db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert rules: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*models.AlertRule
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule row: %w", err)
+		}
+		result = append(result, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert rule rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAlertRule scans a single alert rule row
+func scanAlertRule(row rowScanner) (*models.AlertRule, error) {
+	rule := &models.AlertRule{}
+	var lastTriggered sql.NullTime
+
+	err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.Symbol,
+		&rule.Timeframe,
+		&rule.Condition,
+		&rule.Threshold,
+		&rule.Pattern,
+		&rule.WebhookURL,
+		&rule.CooldownSeconds,
+		&rule.Enabled,
+		&lastTriggered,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastTriggered.Valid {
+		rule.LastTriggeredAt = &lastTriggered.Time
+	}
+
+	return rule, nil
+}
@@ -140,6 +140,12 @@ func (db *DB) HealthCheck(ctx context.Context) map[string]interface{} {
 	return result
 }
 
+// ConnectionString returns the PostgreSQL connection string for dedicated
+// connections such as LISTEN/NOTIFY listeners
+func ConnectionString(cfg config.DatabaseConfig) string {
+	return buildConnectionString(cfg)
+}
+
 // buildConnectionString constructs the PostgreSQL connection string
 func buildConnectionString(cfg config.DatabaseConfig) string {
 	connStr := fmt.Sprintf(
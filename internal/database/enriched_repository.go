@@ -0,0 +1,352 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-260: Persistence for enriched candles
+
+const enrichedCandleColumns = `id, symbol, timeframe, timestamp, ohlcv, indicators, analysis, signals,
+	metadata, engine_version, config_hash, created_at`
+
+// EnrichedCandleQuery selects stored enriched candles. Zero values do not filter.
+type EnrichedCandleQuery struct {
+	Symbol        string
+	Timeframe     string
+	Start         time.Time
+	End           time.Time
+	Signal        string  // overall signal: bullish, bearish, neutral
+	Trend         string  // trend direction
+	Regime        string  // market regime
+	MinConfidence float64 // inclusive
+	MinStrength   float64 // inclusive signal strength
+	EngineVersion string
+	ConfigHash    string
+	Limit         int
+}
+
+// EnrichedRepository stores enriched candles
+type EnrichedRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewEnrichedRepository creates a new enriched candle repository
+func NewEnrichedRepository(db *DB) *EnrichedRepository {
+	return &EnrichedRepository{
+		db:     db,
+		logger: logger.NewContextLogger("enriched_repository"),
+	}
+}
+
+// Upsert stores an enriched candle, replacing an earlier enrichment of the
+// same candle by the same engine version and configuration, and fills in the
+// record ID
+func (r *EnrichedRepository) Upsert(ctx context.Context, record *models.EnrichedCandleRecord) error {
+	start := time.Now()
+	defer func() {
+		logger.LogPerformance(r.logger, "upsert_enriched_candle", start, true)
+	}()
+
+	if err := r.upsert(ctx, r.db.conn, record); err != nil {
+		logger.LogError(r.logger, err, "Failed to store enriched candle", map[string]interface{}{
+			"symbol":    record.Symbol,
+			"timestamp": record.Timestamp,
+			"timeframe": record.Timeframe,
+		})
+		return err
+	}
+
+	return nil
+}
+
+// sqlExecutor is satisfied by *sql.DB and *sql.Tx
+type sqlExecutor interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func (r *EnrichedRepository) upsert(ctx context.Context, executor sqlExecutor, record *models.EnrichedCandleRecord) error {
+	candle := record.Candle
+	if candle == nil || candle.OHLCV == nil {
+		return fmt.Errorf("enriched candle without OHLCV data")
+	}
+
+	var signal, trend, regime string
+	var strength, confidence float64
+	var rsi sql.NullFloat64
+	if candle.Signals != nil {
+		signal = candle.Signals.OverallSignal
+		strength = candle.Signals.SignalStrength
+		confidence = candle.Signals.Confidence
+	}
+	if candle.Indicators != nil {
+		trend = candle.Indicators.TrendDirection
+		// RSI is computed together with the stochastic once enough history
+		// exists; without it the zero value is left NULL rather than stored
+		rsi = sql.NullFloat64{Float64: candle.Indicators.RSI, Valid: candle.Indicators.Stochastic != nil}
+	}
+	if candle.Analysis != nil {
+		regime = candle.Analysis.MarketRegime
+	}
+
+	columns := make([][]byte, 0, 5)
+	for _, value := range []interface{}{candle.OHLCV, candle.Indicators, candle.Analysis, candle.Signals, candle.Metadata} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal enriched candle: %w", err)
+		}
+		columns = append(columns, data)
+	}
+
+	record.CreatedAt = time.Now()
+
+	err := executor.QueryRowContext(ctx, `
+		INSERT INTO enriched_candles (symbol, timeframe, timestamp, close, volume, overall_signal,
+			signal_strength, confidence, trend_direction, market_regime, rsi,
+			ohlcv, indicators, analysis, signals, metadata, engine_version, config_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+			$12, NULLIF($13::jsonb, 'null'), NULLIF($14::jsonb, 'null'), NULLIF($15::jsonb, 'null'),
+			NULLIF($16::jsonb, 'null'), $17, $18, $19)
+		ON CONFLICT (symbol, timeframe, timestamp, engine_version, config_hash) DO UPDATE SET
+			close = EXCLUDED.close,
+			volume = EXCLUDED.volume,
+			overall_signal = EXCLUDED.overall_signal,
+			signal_strength = EXCLUDED.signal_strength,
+			confidence = EXCLUDED.confidence,
+			trend_direction = EXCLUDED.trend_direction,
+			market_regime = EXCLUDED.market_regime,
+			rsi = EXCLUDED.rsi,
+			ohlcv = EXCLUDED.ohlcv,
+			indicators = EXCLUDED.indicators,
+			analysis = EXCLUDED.analysis,
+			signals = EXCLUDED.signals,
+			metadata = EXCLUDED.metadata,
+			created_at = EXCLUDED.created_at
+		RETURNING id`,
+		record.Symbol,
+		record.Timeframe,
+		record.Timestamp,
+		candle.OHLCV.Close,
+		candle.OHLCV.Volume,
+		signal,
+		strength,
+		confidence,
+		trend,
+		regime,
+		rsi,
+		string(columns[0]),
+		string(columns[1]),
+		string(columns[2]),
+		string(columns[3]),
+		string(columns[4]),
+		record.EngineVersion,
+		record.ConfigHash,
+		record.CreatedAt,
+	).Scan(&record.ID)
+	if err != nil {
+		return fmt.Errorf("failed to upsert enriched candle: %w", err)
+	}
+
+	return nil
+}
+
+// REQ-261: SaveBatch stores a batch of enriched candles and the backfill
+// checkpoint covering them in one transaction, so a resumed job never skips
+// unwritten candles
+func (r *EnrichedRepository) SaveBatch(ctx context.Context, records []*models.EnrichedCandleRecord, checkpoint *models.BackfillCheckpoint) error {
+	start := time.Now()
+	defer func() {
+		logger.LogPerformance(r.logger, "save_enriched_batch", start, true)
+	}()
+
+	return r.db.ExecuteInTransaction(ctx, func(tx *sql.Tx) error {
+		for _, record := range records {
+			if err := r.upsert(ctx, tx, record); err != nil {
+				return err
+			}
+		}
+
+		if checkpoint == nil {
+			return nil
+		}
+
+		checkpoint.UpdatedAt = time.Now()
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO enrichment_backfills (symbol, timeframe, range_start, range_end, engine_version,
+				config_hash, last_timestamp, processed, completed, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (symbol, timeframe, range_start, range_end, engine_version, config_hash) DO UPDATE SET
+				last_timestamp = EXCLUDED.last_timestamp,
+				processed = EXCLUDED.processed,
+				completed = EXCLUDED.completed,
+				updated_at = EXCLUDED.updated_at`,
+			checkpoint.Symbol,
+			checkpoint.Timeframe,
+			checkpoint.RangeStart,
+			checkpoint.RangeEnd,
+			checkpoint.EngineVersion,
+			checkpoint.ConfigHash,
+			sql.NullTime{Time: checkpoint.LastTimestamp, Valid: !checkpoint.LastTimestamp.IsZero()},
+			checkpoint.Processed,
+			checkpoint.Completed,
+			checkpoint.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save backfill checkpoint: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetCheckpoint returns the checkpoint of the backfill job identified by the
+// key fields of checkpoint, or nil when the job has not run
+func (r *EnrichedRepository) GetCheckpoint(ctx context.Context, key *models.BackfillCheckpoint) (*models.BackfillCheckpoint, error) {
+	checkpoint := *key
+	var last sql.NullTime
+
+	err := r.db.conn.QueryRowContext(ctx, `
+		SELECT last_timestamp, processed, completed, updated_at
+		FROM enrichment_backfills
+		WHERE symbol = $1 AND timeframe = $2 AND range_start = $3 AND range_end = $4
+			AND engine_version = $5 AND config_hash = $6`,
+		key.Symbol, key.Timeframe, key.RangeStart, key.RangeEnd, key.EngineVersion, key.ConfigHash,
+	).Scan(&last, &checkpoint.Processed, &checkpoint.Completed, &checkpoint.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backfill checkpoint: %w", err)
+	}
+
+	checkpoint.LastTimestamp = last.Time
+	return &checkpoint, nil
+}
+
+// Query retrieves stored enriched candles in chronological order. A candle
+// enriched by several engine versions or configurations is returned once, as
+// the latest stored row matching the filters; filter by engine version and
+// config hash to review one producer's output.
+func (r *EnrichedRepository) Query(ctx context.Context, q *EnrichedCandleQuery) ([]*models.EnrichedCandleRecord, error) {
+	start := time.Now()
+	defer func() {
+		logger.LogPerformance(r.logger, "query_enriched_candles", start, true)
+	}()
+
+	conditions := []string{"symbol = $1", "timeframe = $2"}
+	args := []interface{}{q.Symbol, q.Timeframe}
+	where := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, strings.Replace(condition, "?", "$"+strconv.Itoa(len(args)), 1))
+	}
+
+	if !q.Start.IsZero() {
+		where("timestamp >= ?", q.Start)
+	}
+	if !q.End.IsZero() {
+		where("timestamp <= ?", q.End)
+	}
+	if q.Signal != "" {
+		where("overall_signal = ?", q.Signal)
+	}
+	if q.Trend != "" {
+		where("trend_direction = ?", q.Trend)
+	}
+	if q.Regime != "" {
+		where("market_regime = ?", q.Regime)
+	}
+	if q.MinConfidence > 0 {
+		where("confidence >= ?", q.MinConfidence)
+	}
+	if q.MinStrength > 0 {
+		where("signal_strength >= ?", q.MinStrength)
+	}
+	if q.EngineVersion != "" {
+		where("engine_version = ?", q.EngineVersion)
+	}
+	if q.ConfigHash != "" {
+		where("config_hash = ?", q.ConfigHash)
+	}
+
+	args = append(args, q.Limit)
+	query := `SELECT DISTINCT ON (timestamp) ` + enrichedCandleColumns + ` FROM enriched_candles WHERE ` +
+		strings.Join(conditions, " AND ") +
+		` ORDER BY timestamp ASC, created_at DESC, id DESC LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := r.db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query enriched candles: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*models.EnrichedCandleRecord
+	for rows.Next() {
+		record, err := scanEnrichedCandle(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan enriched candle row: %w", err)
+		}
+		result = append(result, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating enriched candle rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// scanEnrichedCandle scans a single enriched candle row
+func scanEnrichedCandle(row rowScanner) (*models.EnrichedCandleRecord, error) {
+	record := &models.EnrichedCandleRecord{Candle: &models.EnrichedCandle{}}
+	var ohlcv, indicators, analysis, signals, metadata []byte
+
+	err := row.Scan(
+		&record.ID,
+		&record.Symbol,
+		&record.Timeframe,
+		&record.Timestamp,
+		&ohlcv,
+		&indicators,
+		&analysis,
+		&signals,
+		&metadata,
+		&record.EngineVersion,
+		&record.ConfigHash,
+		&record.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := []struct {
+		data   []byte
+		target interface{}
+	}{
+		{ohlcv, &record.Candle.OHLCV},
+		{indicators, &record.Candle.Indicators},
+		{analysis, &record.Candle.Analysis},
+		{signals, &record.Candle.Signals},
+		{metadata, &record.Candle.Metadata},
+	}
+	for _, column := range columns {
+		if column.data == nil {
+			continue
+		}
+		if err := json.Unmarshal(column.data, column.target); err != nil {
+			return nil, fmt.Errorf("invalid enriched candle JSON: %w", err)
+		}
+	}
+
+	return record, nil
+}
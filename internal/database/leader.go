@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+)
+
+// REQ-251: Leader election for the ingesting instance using advisory locks
+
+// maxElectionBackoff bounds the wait before campaigning again after the
+// elected callback failed
+const maxElectionBackoff = 5 * time.Minute
+
+// LeaderElector elects a single leader across instances with a session-level
+// Postgres advisory lock. The lock is held on a dedicated connection, so it is
+// released automatically if the leader's session dies.
+type LeaderElector struct {
+	db       *DB
+	lockKey  int64
+	interval time.Duration
+
+	conn     *sql.Conn
+	isLeader bool
+	mu       sync.RWMutex
+
+	onElected func() error
+	onDemoted func()
+
+	// Failed elections in a row and the time before which no new campaign starts
+	failures     int
+	backoffUntil time.Time
+
+	logger zerolog.Logger
+}
+
+// NewLeaderElector creates a new advisory-lock leader elector
+func NewLeaderElector(db *DB, lockKey int64, interval time.Duration) *LeaderElector {
+	return &LeaderElector{
+		db:       db,
+		lockKey:  lockKey,
+		interval: interval,
+		logger: logger.NewContextLogger("leader_elector").With().
+			Int64("lock_key", lockKey).
+			Logger(),
+	}
+}
+
+// OnElected sets the callback invoked when this instance becomes leader. When
+// it fails, the instance resigns and campaigns again after a backoff, so
+// another instance can take over.
+func (le *LeaderElector) OnElected(fn func() error) {
+	le.onElected = fn
+}
+
+// OnDemoted sets the callback invoked when this instance loses leadership
+func (le *LeaderElector) OnDemoted(fn func()) {
+	le.onDemoted = fn
+}
+
+// IsLeader reports whether this instance currently holds the lock
+func (le *LeaderElector) IsLeader() bool {
+	le.mu.RLock()
+	defer le.mu.RUnlock()
+
+	return le.isLeader
+}
+
+// Run campaigns for leadership until the context is cancelled
+func (le *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(le.interval)
+	defer ticker.Stop()
+
+	le.campaign(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			le.resign()
+			return
+		case <-ticker.C:
+			le.campaign(ctx)
+		}
+	}
+}
+
+// campaign tries to acquire the lock, or verifies it is still held
+func (le *LeaderElector) campaign(ctx context.Context) {
+	if le.IsLeader() {
+		checkCtx, cancel := context.WithTimeout(ctx, le.interval)
+		defer cancel()
+
+		if err := le.conn.PingContext(checkCtx); err != nil {
+			le.logger.Error().Err(err).Msg("Lost leader session")
+			le.demote()
+		}
+		return
+	}
+	if time.Now().Before(le.backoffUntil) {
+		return
+	}
+
+	acquireCtx, cancel := context.WithTimeout(ctx, le.interval)
+	defer cancel()
+
+	conn, err := le.db.conn.Conn(acquireCtx)
+	if err != nil {
+		le.logger.Warn().Err(err).Msg("Failed to get connection for leader election")
+		return
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(acquireCtx, `SELECT pg_try_advisory_lock($1)`, le.lockKey).Scan(&acquired); err != nil {
+		le.logger.Warn().Err(err).Msg("Failed to try advisory lock")
+		conn.Close()
+		return
+	}
+
+	if !acquired {
+		conn.Close()
+		return
+	}
+
+	le.mu.Lock()
+	le.conn = conn
+	le.isLeader = true
+	le.mu.Unlock()
+
+	le.logger.Info().Msg("Elected as leader")
+
+	if le.onElected == nil {
+		return
+	}
+	if err := le.onElected(); err != nil {
+		le.failures++
+		backoff := le.backoff()
+		le.backoffUntil = time.Now().Add(backoff)
+		le.logger.Error().Err(err).
+			Int("failures", le.failures).
+			Dur("backoff", backoff).
+			Msg("Failed to take over as leader, resigning")
+		le.resign()
+		return
+	}
+	le.failures = 0
+}
+
+// backoff doubles the election interval with every failed election in a row
+func (le *LeaderElector) backoff() time.Duration {
+	backoff := le.interval
+	for i := 1; i < le.failures && backoff < maxElectionBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxElectionBackoff {
+		backoff = maxElectionBackoff
+	}
+	return backoff
+}
+
+// demote drops leadership after the lock session was lost
+func (le *LeaderElector) demote() {
+	le.mu.Lock()
+	if le.conn != nil {
+		le.conn.Close()
+		le.conn = nil
+	}
+	le.isLeader = false
+	le.mu.Unlock()
+
+	le.logger.Warn().Msg("Demoted from leader")
+
+	if le.onDemoted != nil {
+		le.onDemoted()
+	}
+}
+
+// resign releases the lock on shutdown so another instance can take over
+func (le *LeaderElector) resign() {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+
+	if !le.isLeader || le.conn == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := le.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, le.lockKey); err != nil {
+		le.logger.Warn().Err(err).Msg("Failed to release advisory lock")
+	}
+
+	le.conn.Close()
+	le.conn = nil
+	le.isLeader = false
+
+	le.logger.Info().Msg("Resigned leadership")
+}
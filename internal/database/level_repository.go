@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-274: Persistence for tracked support and resistance levels
+
+const levelColumns = `symbol, timeframe, price, role, sources, strength, tests, breaks, touches,
+	first_seen, last_touch, updated_at`
+
+// LevelRepository stores the tracked levels of the streams
+type LevelRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewLevelRepository creates a new level repository
+func NewLevelRepository(db *DB) *LevelRepository {
+	return &LevelRepository{
+		db:     db,
+		logger: logger.NewContextLogger("level_repository"),
+	}
+}
+
+// ReplaceLevels replaces the levels of a symbol and timeframe
+func (r *LevelRepository) ReplaceLevels(ctx context.Context, symbol, timeframe string, levels []*models.TrackedLevel) error {
+	start := time.Now()
+	defer func() {
+		logger.LogPerformance(r.logger, "replace_levels", start, true)
+	}()
+
+	return r.db.ExecuteInTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM price_levels WHERE symbol = $1 AND timeframe = $2`,
+			symbol, timeframe); err != nil {
+			return fmt.Errorf("failed to delete levels: %w", err)
+		}
+
+		for _, level := range levels {
+			sources, err := json.Marshal(level.Sources)
+			if err != nil {
+				return fmt.Errorf("failed to marshal level sources: %w", err)
+			}
+			touches, err := json.Marshal(level.Touches)
+			if err != nil {
+				return fmt.Errorf("failed to marshal level touches: %w", err)
+			}
+
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO price_levels (`+levelColumns+`)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+				symbol,
+				timeframe,
+				level.Price,
+				level.Role,
+				sources,
+				level.Strength,
+				level.Tests,
+				level.Breaks,
+				touches,
+				level.FirstSeen,
+				level.LastTouch,
+				level.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert level: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// List retrieves the levels of a symbol, optionally of one timeframe, by price
+func (r *LevelRepository) List(ctx context.Context, symbol, timeframe string) ([]*models.TrackedLevel, error) {
+	query := `SELECT ` + levelColumns + ` FROM price_levels WHERE symbol = $1`
+	args := []interface{}{symbol}
+	if timeframe != "" {
+		args = append(args, timeframe)
+		query += ` AND timeframe = $2`
+	}
+	query += ` ORDER BY timeframe, price`
+
+	rows, err := r.db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query levels: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*models.TrackedLevel
+	for rows.Next() {
+		level := &models.TrackedLevel{}
+		var sources, touches []byte
+		var lastTouch sql.NullTime
+		err := rows.Scan(
+			&level.Symbol,
+			&level.Timeframe,
+			&level.Price,
+			&level.Role,
+			&sources,
+			&level.Strength,
+			&level.Tests,
+			&level.Breaks,
+			&touches,
+			&level.FirstSeen,
+			&lastTouch,
+			&level.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan level row: %w", err)
+		}
+		if err := json.Unmarshal(sources, &level.Sources); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal level sources: %w", err)
+		}
+		if err := json.Unmarshal(touches, &level.Touches); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal level touches: %w", err)
+		}
+		if lastTouch.Valid {
+			level.LastTouch = &lastTouch.Time
+		}
+		result = append(result, level)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating level rows: %w", err)
+	}
+
+	return result, nil
+}
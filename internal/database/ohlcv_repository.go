@@ -9,7 +9,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/ridopark/jonbu-ohlcv/internal/logger"
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-013: Use prepared statements for optimal performance
@@ -24,6 +24,7 @@ type OHLCVRepository struct {
 	selectBySymbolStmt *sql.Stmt
 	selectHistoryStmt  *sql.Stmt
 	selectLatestStmt   *sql.Stmt
+	selectBeforeStmt   *sql.Stmt
 }
 
 // NewOHLCVRepository creates a new OHLCV repository with prepared statements
@@ -50,6 +51,7 @@ func (r *OHLCVRepository) Close() error {
 		r.selectBySymbolStmt,
 		r.selectHistoryStmt,
 		r.selectLatestStmt,
+		r.selectBeforeStmt,
 	}
 
 	for _, stmt := range statements {
@@ -232,6 +234,54 @@ func (r *OHLCVRepository) GetHistory(ctx context.Context, symbol, timeframe stri
 	return result, nil
 }
 
+// REQ-257: GetBefore retrieves up to limit records strictly before a time in
+// chronological order, used to warm up indicators ahead of a requested range
+func (r *OHLCVRepository) GetBefore(ctx context.Context, symbol, timeframe string, before time.Time, limit int) ([]*models.OHLCV, error) {
+	start := time.Now()
+	defer func() {
+		logger.LogPerformance(r.logger, "get_before", start, true)
+	}()
+
+	rows, err := r.selectBeforeStmt.QueryContext(ctx, symbol, timeframe, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OHLCV before %s: %w", before.Format(time.RFC3339), err)
+	}
+	defer rows.Close()
+
+	var result []*models.OHLCV
+	for rows.Next() {
+		ohlcv := &models.OHLCV{}
+		err := rows.Scan(
+			&ohlcv.ID,
+			&ohlcv.Symbol,
+			&ohlcv.Timestamp,
+			&ohlcv.Open,
+			&ohlcv.High,
+			&ohlcv.Low,
+			&ohlcv.Close,
+			&ohlcv.Volume,
+			&ohlcv.Timeframe,
+			&ohlcv.CreatedAt,
+			&ohlcv.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan OHLCV row: %w", err)
+		}
+		result = append(result, ohlcv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating OHLCV rows: %w", err)
+	}
+
+	// Rows come newest first so the limit keeps the closest candles
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result, nil
+}
+
 // GetLatest retrieves the most recent OHLCV record for a symbol
 func (r *OHLCVRepository) GetLatest(ctx context.Context, symbol, timeframe string) (*models.OHLCV, error) {
 	start := time.Now()
@@ -318,6 +368,19 @@ func (r *OHLCVRepository) prepareStatements() error {
 		return fmt.Errorf("failed to prepare select latest statement: %w", err)
 	}
 
+	// Select before statement
+	selectBeforeSQL := `
+		SELECT id, symbol, timestamp, open, high, low, close, volume, timeframe, created_at, updated_at
+		FROM ohlcv
+		WHERE symbol = $1 AND timeframe = $2 AND timestamp < $3
+		ORDER BY timestamp DESC
+		LIMIT $4`
+
+	r.selectBeforeStmt, err = r.db.conn.Prepare(selectBeforeSQL)
+	if err != nil {
+		return fmt.Errorf("failed to prepare select before statement: %w", err)
+	}
+
 	r.logger.Info().Msg("All prepared statements created successfully")
 	return nil
 }
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-272: Persistence for pattern outcome statistics
+
+const patternStatsColumns = `symbol, timeframe, pattern_type, pattern_name, signal, horizon, occurrences,
+	target_hits, stop_hits, expired, hit_rate, avg_max_favorable, avg_max_adverse, avg_return, avg_bars,
+	range_start, range_end, updated_at`
+
+// PatternStatsRepository stores pattern outcome statistics
+type PatternStatsRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewPatternStatsRepository creates a new pattern statistics repository
+func NewPatternStatsRepository(db *DB) *PatternStatsRepository {
+	return &PatternStatsRepository{
+		db:     db,
+		logger: logger.NewContextLogger("pattern_stats_repository"),
+	}
+}
+
+// ReplacePatternStats replaces the statistics of a symbol and timeframe with
+// the result of a new scan
+func (r *PatternStatsRepository) ReplacePatternStats(ctx context.Context, symbol, timeframe string, stats []*models.PatternOutcomeStats) error {
+	start := time.Now()
+	defer func() {
+		logger.LogPerformance(r.logger, "replace_pattern_stats", start, true)
+	}()
+
+	return r.db.ExecuteInTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM pattern_outcome_stats WHERE symbol = $1 AND timeframe = $2`,
+			symbol, timeframe); err != nil {
+			return fmt.Errorf("failed to delete pattern stats: %w", err)
+		}
+
+		for _, stat := range stats {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO pattern_outcome_stats (`+patternStatsColumns+`)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`,
+				stat.Symbol,
+				stat.Timeframe,
+				stat.PatternType,
+				stat.PatternName,
+				stat.Signal,
+				stat.Horizon,
+				stat.Occurrences,
+				stat.TargetHits,
+				stat.StopHits,
+				stat.Expired,
+				stat.HitRate,
+				stat.AvgMaxFavorable,
+				stat.AvgMaxAdverse,
+				stat.AvgReturn,
+				stat.AvgBars,
+				stat.RangeStart,
+				stat.RangeEnd,
+				stat.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert pattern stats: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// List retrieves statistics, optionally filtered by symbol, timeframe and
+// pattern name, most frequent patterns first
+func (r *PatternStatsRepository) List(ctx context.Context, symbol, timeframe, pattern string) ([]*models.PatternOutcomeStats, error) {
+	query := `SELECT ` + patternStatsColumns + ` FROM pattern_outcome_stats WHERE TRUE`
+	args := []interface{}{}
+	for _, filter := range []struct {
+		column string
+		value  string
+	}{{"symbol", symbol}, {"timeframe", timeframe}, {"pattern_name", pattern}} {
+		if filter.value != "" {
+			args = append(args, filter.value)
+			query += fmt.Sprintf(` AND %s = $%d`, filter.column, len(args))
+		}
+	}
+	query += ` ORDER BY symbol, timeframe, occurrences DESC, pattern_name, signal`
+
+	rows, err := r.db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pattern stats: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*models.PatternOutcomeStats
+	for rows.Next() {
+		stat := &models.PatternOutcomeStats{}
+		err := rows.Scan(
+			&stat.Symbol,
+			&stat.Timeframe,
+			&stat.PatternType,
+			&stat.PatternName,
+			&stat.Signal,
+			&stat.Horizon,
+			&stat.Occurrences,
+			&stat.TargetHits,
+			&stat.StopHits,
+			&stat.Expired,
+			&stat.HitRate,
+			&stat.AvgMaxFavorable,
+			&stat.AvgMaxAdverse,
+			&stat.AvgReturn,
+			&stat.AvgBars,
+			&stat.RangeStart,
+			&stat.RangeEnd,
+			&stat.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pattern stats row: %w", err)
+		}
+		result = append(result, stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pattern stats rows: %w", err)
+	}
+
+	return result, nil
+}
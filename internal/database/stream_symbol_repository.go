@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+)
+
+// REQ-251: Persistence of the symbols requested for streaming, so an instance
+// elected to ingest resumes every symbol whichever instance received it
+
+// StreamSymbolRepository stores the tracked stream symbols
+type StreamSymbolRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewStreamSymbolRepository creates a new stream symbol repository
+func NewStreamSymbolRepository(db *DB) *StreamSymbolRepository {
+	return &StreamSymbolRepository{
+		db:     db,
+		logger: logger.NewContextLogger("stream_symbol_repository"),
+	}
+}
+
+// Add tracks symbols; symbols already tracked are left as they are
+func (r *StreamSymbolRepository) Add(ctx context.Context, symbols []string) error {
+	start := time.Now()
+	defer func() {
+		logger.LogPerformance(r.logger, "add_stream_symbols", start, true)
+	}()
+
+	return r.db.ExecuteInTransaction(ctx, func(tx *sql.Tx) error {
+		for _, symbol := range symbols {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO stream_symbols (symbol) VALUES ($1) ON CONFLICT (symbol) DO NOTHING`, symbol,
+			); err != nil {
+				return fmt.Errorf("failed to add stream symbol %s: %w", symbol, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Remove stops tracking symbols
+func (r *StreamSymbolRepository) Remove(ctx context.Context, symbols []string) error {
+	if _, err := r.db.conn.ExecContext(ctx,
+		`DELETE FROM stream_symbols WHERE symbol = ANY($1)`, pq.Array(symbols),
+	); err != nil {
+		return fmt.Errorf("failed to remove stream symbols: %w", err)
+	}
+	return nil
+}
+
+// List returns the tracked symbols in sorted order
+func (r *StreamSymbolRepository) List(ctx context.Context) ([]string, error) {
+	rows, err := r.db.conn.QueryContext(ctx, `SELECT symbol FROM stream_symbols ORDER BY symbol`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stream symbols: %w", err)
+	}
+	defer rows.Close()
+
+	var symbols []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			return nil, fmt.Errorf("failed to scan stream symbol: %w", err)
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stream symbol rows: %w", err)
+	}
+
+	return symbols, nil
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-265: Persistence for VWAP anchors
+
+// ErrVWAPAnchorNotFound is returned when a VWAP anchor does not exist
+var ErrVWAPAnchorNotFound = errors.New("vwap anchor not found")
+
+const vwapAnchorColumns = `id, symbol, name, event, anchor_at, gap_percent, created_at`
+
+// VWAPAnchorRepository stores VWAP anchors
+type VWAPAnchorRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewVWAPAnchorRepository creates a new VWAP anchor repository
+func NewVWAPAnchorRepository(db *DB) *VWAPAnchorRepository {
+	return &VWAPAnchorRepository{
+		db:     db,
+		logger: logger.NewContextLogger("vwap_anchor_repository"),
+	}
+}
+
+// Create stores a new anchor and fills in its ID and creation time
+func (r *VWAPAnchorRepository) Create(ctx context.Context, anchor *models.VWAPAnchor) error {
+	start := time.Now()
+	defer func() {
+		logger.LogPerformance(r.logger, "create_vwap_anchor", start, true)
+	}()
+
+	anchor.CreatedAt = time.Now()
+	anchor.Spec = models.AnchorSpec(anchor.AnchorAt)
+
+	err := r.db.conn.QueryRowContext(ctx, `
+		INSERT INTO vwap_anchors (symbol, name, event, anchor_at, gap_percent, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		anchor.Symbol,
+		anchor.Name,
+		anchor.Event,
+		anchor.AnchorAt,
+		anchor.GapPercent,
+		anchor.CreatedAt,
+	).Scan(&anchor.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert vwap anchor: %w", err)
+	}
+
+	r.logger.Info().
+		Int64("id", anchor.ID).
+		Str("symbol", anchor.Symbol).
+		Str("event", anchor.Event).
+		Time("anchor_at", anchor.AnchorAt).
+		Msg("VWAP anchor created")
+
+	return nil
+}
+
+// List retrieves all anchors, optionally filtered by symbol
+func (r *VWAPAnchorRepository) List(ctx context.Context, symbol string) ([]*models.VWAPAnchor, error) {
+	query := `SELECT ` + vwapAnchorColumns + ` FROM vwap_anchors`
+	args := []interface{}{}
+	if symbol != "" {
+		query += ` WHERE symbol = $1`
+		args = append(args, symbol)
+	}
+	query += ` ORDER BY anchor_at, id`
+
+	rows, err := r.db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vwap anchors: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*models.VWAPAnchor
+	for rows.Next() {
+		anchor, err := scanVWAPAnchor(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vwap anchor row: %w", err)
+		}
+		result = append(result, anchor)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vwap anchor rows: %w", err)
+	}
+
+	return result, nil
+}
+
+// Delete removes an anchor
+func (r *VWAPAnchorRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.conn.ExecContext(ctx, `DELETE FROM vwap_anchors WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete vwap anchor: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted vwap anchor: %w", err)
+	}
+	if affected == 0 {
+		return ErrVWAPAnchorNotFound
+	}
+
+	return nil
+}
+
+// scanVWAPAnchor scans a single anchor row
+func scanVWAPAnchor(row rowScanner) (*models.VWAPAnchor, error) {
+	anchor := &models.VWAPAnchor{}
+	err := row.Scan(
+		&anchor.ID,
+		&anchor.Symbol,
+		&anchor.Name,
+		&anchor.Event,
+		&anchor.AnchorAt,
+		&anchor.GapPercent,
+		&anchor.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	anchor.Spec = models.AnchorSpec(anchor.AnchorAt)
+	return anchor, nil
+}
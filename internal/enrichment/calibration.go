@@ -0,0 +1,48 @@
+package enrichment
+
+import (
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-272: Pattern signal confidence calibrated with stored outcomes
+
+// calibrationPrior is the weight of a pattern's fixed confidence in
+// occurrences: with as many stored outcomes, the hit rate counts half
+const calibrationPrior = 20
+
+// SetPatternStats replaces the outcome statistics pattern signals are
+// calibrated with
+func (engine *CandleEnrichmentEngine) SetPatternStats(stats []*models.PatternOutcomeStats) {
+	byKey := make(map[string]*models.PatternOutcomeStats, len(stats))
+	for _, stat := range stats {
+		if stat.Occurrences > 0 {
+			byKey[stat.Key()] = stat
+		}
+	}
+
+	engine.mu.Lock()
+	engine.patternStats = byKey
+	engine.mu.Unlock()
+}
+
+// calibratePatternSignal blends the fixed confidence of a pattern signal with
+// the hit rate of the pattern in the stored history of the candle's symbol
+// and timeframe
+func (engine *CandleEnrichmentEngine) calibratePatternSignal(signal *models.PatternSignal, candle *models.OHLCV) {
+	if candle == nil {
+		return
+	}
+
+	engine.mu.RLock()
+	stat := engine.patternStats[models.PatternStatsKey(candle.Symbol, candle.Timeframe,
+		signal.PatternType, signal.PatternName, signal.Signal)]
+	engine.mu.RUnlock()
+	if stat == nil {
+		return
+	}
+
+	samples := float64(stat.Occurrences)
+	signal.Confidence = (samples*stat.HitRate*100 + calibrationPrior*signal.Confidence) / (samples + calibrationPrior)
+	signal.HitRate = stat.HitRate
+	signal.Samples = stat.Occurrences
+}
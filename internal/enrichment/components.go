@@ -0,0 +1,112 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// REQ-268: Concurrent, deadline-bounded enrichment components
+
+// component is an independent part of an enrichment. run computes the result
+// without touching the enriched candle and returns a function storing it; the
+// stores run on the enriching goroutine, so results that miss the deadline
+// are dropped without racing the caller.
+type component struct {
+	name string
+	run  func(ctx context.Context) (store func(), err error)
+}
+
+// componentResult is the outcome of a component run
+type componentResult struct {
+	index int
+	store func()
+	err   error
+}
+
+// runComponents runs components concurrently, at most MaxConcurrency at a
+// time, and stores the results completed before the context ends. It returns
+// the names of the components cut off by the deadline and of those that
+// failed, both in component order.
+func (engine *CandleEnrichmentEngine) runComponents(ctx context.Context, components []component) (skipped, failed []string) {
+	if len(components) == 0 {
+		return nil, nil
+	}
+
+	limit := engine.config.MaxConcurrency
+	if limit < 1 {
+		limit = 1
+	}
+	semaphore := make(chan struct{}, limit)
+
+	// Buffered so components completing after the deadline never block
+	results := make(chan componentResult, len(components))
+
+	for i, c := range components {
+		go func(index int, c component) {
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				results <- componentResult{index: index, err: ctx.Err()}
+				return
+			}
+			defer func() { <-semaphore }()
+
+			if err := ctx.Err(); err != nil {
+				results <- componentResult{index: index, err: err}
+				return
+			}
+
+			// REQ-269: Components completing late are timed as well
+			started := time.Now()
+			store, err := c.run(ctx)
+			if !isDeadline(err) && recorded(ctx) {
+				engine.stats.recordComponent(c.name, time.Since(started))
+			}
+			results <- componentResult{index: index, store: store, err: err}
+		}(i, c)
+	}
+
+	done := make([]bool, len(components))
+	errs := make([]error, len(components))
+
+wait:
+	for pending := len(components); pending > 0; pending-- {
+		select {
+		case result := <-results:
+			if result.err != nil && isDeadline(result.err) {
+				continue
+			}
+			done[result.index] = true
+
+			if result.err != nil {
+				errs[result.index] = result.err
+				engine.logger.Warn().Err(result.err).
+					Str("component", components[result.index].name).
+					Msg("Enrichment component failed")
+				continue
+			}
+			if result.store != nil {
+				result.store()
+			}
+
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	for i, c := range components {
+		switch {
+		case !done[i]:
+			skipped = append(skipped, c.name)
+		case errs[i] != nil:
+			failed = append(failed, c.name)
+		}
+	}
+	return skipped, failed
+}
+
+// isDeadline reports whether an error comes from an ended context
+func isDeadline(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,245 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-262: Multi-timeframe confluence in trading signals
+
+// timeframeDurations maps stored timeframes to their candle duration
+var timeframeDurations = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// confluenceOptions compute the state of a higher timeframe
+var confluenceOptions = &models.EnrichmentOptions{
+	TrendIndicators:    true,
+	MomentumIndicators: true,
+	MarketRegime:       true,
+}
+
+// timeframeCache holds the state computed from the latest closed candle of a
+// symbol:timeframe until a newer candle can have closed
+type timeframeCache struct {
+	states map[string]*cachedTimeframeState
+	mu     sync.Mutex
+}
+
+type cachedTimeframeState struct {
+	state     *models.TimeframeState // nil when the timeframe has too little history
+	cutoff    time.Time              // candles starting after it were not closed yet
+	fetchedAt time.Time
+}
+
+// confluence compares the signal of a candle with the latest closed candles of
+// the configured higher timeframes at the time the candle closes. It returns
+// nil when no higher timeframe state is available.
+func (engine *CandleEnrichmentEngine) confluence(
+	ctx context.Context,
+	current *models.OHLCV,
+	signals *models.TradingSignals,
+) *models.TimeframeConfluence {
+
+	engine.mu.RLock()
+	source := engine.historySource
+	engine.mu.RUnlock()
+
+	if source == nil || current == nil {
+		return nil
+	}
+
+	own := timeframeDurations[current.Timeframe]
+	closesAt := current.Timestamp.Add(own)
+
+	confluence := &models.TimeframeConfluence{}
+	direction := signalDirection(signals.OverallSignal)
+	var score, agreeing, totalWeight float64
+
+	for _, timeframe := range higherTimeframes(engine.config.ConfluenceTimeframes, own) {
+		state, err := engine.timeframeState(ctx, source, current.Symbol, timeframe, closesAt)
+		if err != nil {
+			engine.logger.Debug().Err(err).
+				Str("symbol", current.Symbol).
+				Str("timeframe", timeframe).
+				Msg("Higher timeframe state unavailable")
+			continue
+		}
+		if state == nil {
+			continue
+		}
+		confluence.Timeframes = append(confluence.Timeframes, *state)
+
+		// Longer timeframes weigh more: the shortest counts 1, the next 2, ...
+		weight := float64(len(confluence.Timeframes))
+		trend := signalDirection(state.Trend)
+		tilt := math.Max(-1, math.Min(1, (state.RSI-50)/20))
+		score += weight * (70*float64(trend) + 30*tilt)
+		totalWeight += weight
+
+		switch {
+		case direction != 0 && trend == direction:
+			agreeing += weight
+		case direction != 0 && trend == -direction:
+			confluence.Conflicting = true
+			confluence.Conflicts = append(confluence.Conflicts, fmt.Sprintf("%s %s signal against %s %s trend",
+				signals.OverallSignal, current.Timeframe, timeframe, state.Trend))
+		}
+	}
+
+	if len(confluence.Timeframes) == 0 {
+		return nil
+	}
+
+	confluence.Score = score / totalWeight
+	confluence.Alignment = agreeing / totalWeight * 100
+	confluence.Aligned = direction != 0 && !confluence.Conflicting && agreeing > 0
+
+	return confluence
+}
+
+// higherTimeframes returns the known timeframes longer than own, shortest first
+func higherTimeframes(timeframes []string, own time.Duration) []string {
+	var higher []string
+	for _, timeframe := range timeframes {
+		if duration, known := timeframeDurations[timeframe]; known && duration > own {
+			higher = append(higher, timeframe)
+		}
+	}
+	sort.Slice(higher, func(i, j int) bool {
+		return timeframeDurations[higher[i]] < timeframeDurations[higher[j]]
+	})
+	return higher
+}
+
+// timeframeState returns the state of a timeframe from the candles closed by
+// closesAt, reusing the cached state until a newer candle can have closed
+func (engine *CandleEnrichmentEngine) timeframeState(
+	ctx context.Context,
+	source HistorySource,
+	symbol, timeframe string,
+	closesAt time.Time,
+) (*models.TimeframeState, error) {
+
+	duration := timeframeDurations[timeframe]
+	// Candles starting at or before cutoff have closed by closesAt
+	cutoff := closesAt.Add(-duration)
+	key := streamKey(symbol, timeframe)
+	ttl := time.Duration(engine.config.CacheTTLMinutes) * time.Minute
+
+	engine.mu.RLock()
+	cache := engine.timeframes
+	engine.mu.RUnlock()
+
+	cache.mu.Lock()
+	cached := cache.states[key]
+	cache.mu.Unlock()
+
+	if cached != nil {
+		var next time.Time
+		if cached.state != nil {
+			next = cached.state.Timestamp.Add(duration)
+		}
+
+		// The cached candle has closed and the next one cannot have
+		if cached.state != nil && !cutoff.Before(cached.state.Timestamp) && cutoff.Before(next) {
+			engine.stats.recordCache(true)
+			return cached.state, nil
+		}
+
+		// It was already missing at the last lookup: a gap in trading or a
+		// candle not stored yet, so do not look it up on every call
+		missing := cached.state == nil || !cached.cutoff.Before(next)
+		if missing && !cutoff.Before(cached.cutoff) && cutoff.Sub(cached.cutoff) < duration &&
+			time.Since(cached.fetchedAt) < ttl {
+			engine.stats.recordCache(true)
+			return cached.state, nil
+		}
+	}
+	engine.stats.recordCache(false)
+
+	history, err := source.GetBefore(ctx, symbol, timeframe, cutoff.Add(time.Microsecond), engine.config.MaxHistoryPeriods+1)
+	if err != nil {
+		return nil, err
+	}
+
+	var state *models.TimeframeState
+	if len(history) > engine.config.MinHistoryPeriods {
+		latest := history[len(history)-1]
+		enriched, err := engine.EnrichCandle(WithoutMetrics(ctx), latest, history[:len(history)-1], confluenceOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		state = &models.TimeframeState{
+			Timeframe: timeframe,
+			Timestamp: latest.Timestamp,
+		}
+		if enriched.Indicators != nil {
+			state.Trend = enriched.Indicators.TrendDirection
+			state.RSI = enriched.Indicators.RSI
+		}
+		if enriched.Analysis != nil {
+			state.Regime = enriched.Analysis.MarketRegime
+		}
+	}
+
+	cache.mu.Lock()
+	cache.states[key] = &cachedTimeframeState{state: state, cutoff: cutoff, fetchedAt: time.Now()}
+	cache.mu.Unlock()
+
+	return state, nil
+}
+
+// adjustConfidence raises confidence for signals confirmed by higher
+// timeframes and lowers it for signals against them
+func adjustConfidence(confidence float64, confluence *models.TimeframeConfluence) float64 {
+	switch {
+	case confluence == nil:
+		return confidence
+	case confluence.Aligned:
+		confidence += confluence.Alignment * 0.1
+	case confluence.Conflicting:
+		confidence -= 15
+	}
+	return math.Max(0, math.Min(95, confidence))
+}
+
+// confluenceValue describes the agreement with the higher timeframes
+func confluenceValue(confluence *models.TimeframeConfluence) string {
+	switch {
+	case confluence == nil:
+		return "unavailable"
+	case confluence.Aligned:
+		return fmt.Sprintf("aligned %.0f%%", confluence.Alignment)
+	case confluence.Conflicting:
+		return strings.Join(confluence.Conflicts, "; ")
+	default:
+		return "neutral"
+	}
+}
+
+// signalDirection maps bullish, bearish and neutral to 1, -1 and 0
+func signalDirection(signal string) int {
+	switch signal {
+	case "bullish":
+		return 1
+	case "bearish":
+		return -1
+	default:
+		return 0
+	}
+}
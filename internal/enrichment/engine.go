@@ -2,35 +2,71 @@ package enrichment
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
 	"sync"
 	"time"
 
 	"github.com/ridopark/jonbu-ohlcv/internal/analysis"
+	"github.com/ridopark/jonbu-ohlcv/internal/expression"
 	"github.com/ridopark/jonbu-ohlcv/internal/indicators"
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 	"github.com/rs/zerolog"
 )
 
 // REQ-200 to REQ-205: Enriched candle pipeline
 
+// EngineVersion identifies the enrichment semantics of this build; it is
+// stored with persisted enriched candles
+const EngineVersion = "1.0.0"
+
 // CandleEnrichmentEngine enriches OHLCV candles with AI insights
 type CandleEnrichmentEngine struct {
 	// Components
 	indicatorCalculator  *indicators.IndicatorCache
+	registry             *indicators.Registry
 	candlestickAnalyzer  *analysis.CandlestickAnalyzer
 	chartPatternAnalyzer *analysis.ChartPatternAnalyzer
 	regimeAnalyzer       *analysis.RegimeAnalyzer
 	supportAnalyzer      *analysis.SupportResistanceDetector
+	volumeProfiler       *analysis.VolumeProfileAnalyzer
+	swingDetector        *analysis.SwingDetector // REQ-275
+
+	// REQ-259: Custom expression signals and the indicator specs they read
+	customSignals []*customSignal
+	customSpecs   []string
+
+	// REQ-266: Compiled signal profiles by name
+	profiles map[string]*signalProfile
+
+	// REQ-272: Pattern outcome statistics by PatternStatsKey
+	patternStats map[string]*models.PatternOutcomeStats
+
+	// REQ-273: Volatility regime models by symbol:timeframe
+	regimeModels map[string]*analysis.VolatilityHMM
+	windowModels map[string]*windowRegimeModel
+
+	// REQ-262, REQ-263: Stored candles of higher timeframes and benchmarks
+	historySource HistorySource
+	timeframes    *timeframeCache
 
 	// Configuration
 	config *EnrichmentConfig
 	logger zerolog.Logger
 
 	// Performance monitoring
-	metrics *EnrichmentMetrics
-	mu      sync.RWMutex
+	// REQ-269: Counters and latency histograms
+	stats *enrichmentStats
+	mu    sync.RWMutex
+}
+
+// customSignal is a compiled custom signal definition
+type customSignal struct {
+	definition models.CustomSignalDefinition
+	program    *expression.Program
 }
 
 // EnrichmentConfig controls enrichment behavior
@@ -43,6 +79,7 @@ type EnrichmentConfig struct {
 	// Data requirements
 	MinHistoryPeriods int `json:"min_history_periods"`
 	MaxHistoryPeriods int `json:"max_history_periods"`
+	WarmStartPeriods  int `json:"warm_start_periods"` // REQ-254: stored candles replayed into stream state
 
 	// Quality settings
 	RequiredDataQuality string `json:"required_data_quality"` // high, medium, low
@@ -56,36 +93,16 @@ type EnrichmentConfig struct {
 	// Cache settings
 	CacheTTLMinutes int `json:"cache_ttl_minutes"`
 	MaxCacheSize    int `json:"max_cache_size"`
-}
 
-// EnrichmentMetrics tracks performance statistics
-type EnrichmentMetrics struct {
-	// Timing metrics
-	TotalEnrichments int64   `json:"total_enrichments"`
-	AverageLatencyMs float64 `json:"average_latency_ms"`
-	MaxLatencyMs     float64 `json:"max_latency_ms"`
-	LatencyP95Ms     float64 `json:"latency_p95_ms"`
-
-	// Quality metrics
-	CacheHitRate float64 `json:"cache_hit_rate"`
-	SuccessRate  float64 `json:"success_rate"`
-	ErrorRate    float64 `json:"error_rate"`
-
-	// Component metrics
-	IndicatorLatencyMs float64 `json:"indicator_latency_ms"`
-	AnalysisLatencyMs  float64 `json:"analysis_latency_ms"`
-	SignalLatencyMs    float64 `json:"signal_latency_ms"`
-
-	// Resource metrics
-	MemoryUsageMB  float64 `json:"memory_usage_mb"`
-	GoroutineCount int     `json:"goroutine_count"`
-
-	// Error tracking
-	LastError  string `json:"last_error,omitempty"`
-	ErrorCount int64  `json:"error_count"`
-
-	// Updated timestamp
-	LastUpdated time.Time `json:"last_updated"`
+	// REQ-262: Higher timeframes compared with each signal
+	ConfluenceTimeframes []string `json:"confluence_timeframes"`
+
+	// REQ-263: Rolling window of benchmark comparisons
+	RelativePeriods int `json:"relative_periods"`
+
+	// REQ-275: Reversal confirming the swings Fibonacci levels are drawn on
+	SwingMode      string  `json:"swing_mode"`      // percent, atr
+	SwingThreshold float64 `json:"swing_threshold"` // percent of price, or ATR multiple
 }
 
 // NewCandleEnrichmentEngine creates a new enrichment engine
@@ -98,31 +115,194 @@ func NewCandleEnrichmentEngine(config *EnrichmentConfig) *CandleEnrichmentEngine
 		Str("component", "enrichment_engine").
 		Logger()
 
+	// REQ-275: Chart patterns, levels and Fibonacci share the configured swings
+	swings := analysis.NewSwingDetector(config.SwingMode, config.SwingThreshold)
+
 	engine := &CandleEnrichmentEngine{
 		indicatorCalculator:  indicators.NewIndicatorCache(5 * time.Minute),
+		registry:             indicators.DefaultRegistry(),
 		candlestickAnalyzer:  analysis.NewCandlestickAnalyzer(),
-		chartPatternAnalyzer: analysis.NewChartPatternAnalyzer(),
+		chartPatternAnalyzer: analysis.NewChartPatternAnalyzer(swings),
 		regimeAnalyzer:       analysis.NewRegimeAnalyzer(),
-		supportAnalyzer:      analysis.NewSupportResistanceDetector(),
+		supportAnalyzer:      analysis.NewSupportResistanceDetector(swings),
+		volumeProfiler:       analysis.NewVolumeProfileAnalyzer(),
+		swingDetector:        swings,
 		config:               config,
 		logger:               logger,
-		metrics:              &EnrichmentMetrics{},
+		stats:                newEnrichmentStats(),
 	}
 
+	// The built-in profile always compiles
+	_ = engine.SetSignalProfiles(nil)
+
 	return engine
 }
 
+// Config returns the engine configuration
+func (engine *CandleEnrichmentEngine) Config() *EnrichmentConfig {
+	return engine.config
+}
+
+// SetCustomSignals compiles custom signal definitions and replaces the current
+// ones. Their indicators are computed whenever trading signals are generated.
+func (engine *CandleEnrichmentEngine) SetCustomSignals(definitions []models.CustomSignalDefinition) error {
+	signals := make([]*customSignal, 0, len(definitions))
+	var specs []string
+	seen := make(map[string]bool)
+
+	for _, definition := range definitions {
+		if err := definition.Validate(); err != nil {
+			return err
+		}
+
+		program, err := expression.CompileWith(engine.registry, definition.When)
+		if err != nil {
+			return fmt.Errorf("custom signal %s: %w", definition.Name, err)
+		}
+
+		for _, spec := range program.Specs() {
+			if !seen[spec] {
+				seen[spec] = true
+				specs = append(specs, spec)
+			}
+		}
+		signals = append(signals, &customSignal{definition: definition, program: program})
+	}
+
+	engine.mu.Lock()
+	engine.customSignals = signals
+	engine.customSpecs = specs
+	engine.mu.Unlock()
+
+	return nil
+}
+
+// ConfigHash returns a short hash of everything that shapes enrichment output
+// for the options: engine configuration, options, indicator specs, custom
+// signals and the signal profile. Stored enriched candles with equal hashes
+// are comparable. Signals of further profiles are not stored and do not count.
+func (engine *CandleEnrichmentEngine) ConfigHash(options *models.EnrichmentOptions) string {
+	stored := *options
+	stored.SignalProfiles = nil
+
+	engine.mu.RLock()
+	definitions := make([]models.CustomSignalDefinition, len(engine.customSignals))
+	for i, signal := range engine.customSignals {
+		definitions[i] = signal.definition
+	}
+	var profile *models.SignalProfile
+	if compiled := engine.profiles[profileNames(&stored)[0]]; compiled != nil {
+		profile = &compiled.definition
+	}
+	engine.mu.RUnlock()
+
+	data, _ := json.Marshal(struct {
+		Version string                          `json:"version"`
+		Config  *EnrichmentConfig               `json:"config"`
+		Options *models.EnrichmentOptions       `json:"options"`
+		Specs   []string                        `json:"specs"`
+		Signals []models.CustomSignalDefinition `json:"signals"`
+		Profile *models.SignalProfile           `json:"profile"`
+	}{EngineVersion, engine.config, &stored, engine.indicatorSpecs(&stored), definitions, profile})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// indicatorSpecs returns the requested registry specs plus the specs read by
+// custom signals and signal profile rules when trading signals are generated
+func (engine *CandleEnrichmentEngine) indicatorSpecs(options *models.EnrichmentOptions) []string {
+	if !options.TradingSignals {
+		return options.Indicators
+	}
+
+	engine.mu.RLock()
+	extra := append([]string(nil), engine.customSpecs...)
+	engine.mu.RUnlock()
+
+	for _, profile := range engine.signalProfiles(options) {
+		extra = append(extra, profile.specs...)
+	}
+	if len(extra) == 0 {
+		return options.Indicators
+	}
+
+	specs := make([]string, 0, len(options.Indicators)+len(extra))
+	seen := make(map[string]bool)
+	for _, spec := range append(append(specs, options.Indicators...), extra...) {
+		if !seen[spec] {
+			seen[spec] = true
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// HistorySource loads stored candles strictly before a time, oldest first
+type HistorySource interface {
+	GetBefore(ctx context.Context, symbol, timeframe string, before time.Time, limit int) ([]*models.OHLCV, error)
+}
+
+// SetHistorySource sets the stored candles read for other timeframes and
+// symbols: higher timeframe confluence and benchmark comparisons
+func (engine *CandleEnrichmentEngine) SetHistorySource(source HistorySource) {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+
+	engine.historySource = source
+	engine.timeframes = &timeframeCache{states: make(map[string]*cachedTimeframeState)}
+}
+
+// RemoveStream drops the latencies and regime models of a stream that is no
+// longer tracked
+func (engine *CandleEnrichmentEngine) RemoveStream(symbol, timeframe string) {
+	key := streamKey(symbol, timeframe)
+	engine.stats.removeStream(key)
+
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+
+	delete(engine.regimeModels, key)
+	delete(engine.windowModels, key)
+}
+
+// HistoryPeriods returns how many candles of history EnrichCandle needs for
+// the options: the configured maximum, or more when a registry indicator
+// looks back further
+func (engine *CandleEnrichmentEngine) HistoryPeriods(options *models.EnrichmentOptions) (int, error) {
+	lookback, err := engine.registry.Lookback(engine.indicatorSpecs(options))
+	if err != nil {
+		return 0, err
+	}
+
+	periods := engine.config.MaxHistoryPeriods
+	if lookback > periods {
+		periods = lookback
+	}
+	if engine.config.MinHistoryPeriods > periods {
+		periods = engine.config.MinHistoryPeriods
+	}
+	return periods, nil
+}
+
 // EnrichCandle enriches a single candle with AI insights
 func (engine *CandleEnrichmentEngine) EnrichCandle(
 	ctx context.Context,
 	current *models.OHLCV,
 	history []*models.OHLCV,
 	options *models.EnrichmentOptions,
-) (*models.EnrichedCandle, error) {
+) (enriched *models.EnrichedCandle, err error) {
 
 	startTime := time.Now()
 	defer func() {
-		engine.updateMetrics(time.Since(startTime))
+		if !recorded(ctx) {
+			return
+		}
+		var key string
+		if current != nil {
+			key = streamKey(current.Symbol, current.Timeframe)
+		}
+		engine.stats.recordEnrichment(key, time.Since(startTime), err)
 	}()
 
 	// Validate inputs
@@ -130,48 +310,162 @@ func (engine *CandleEnrichmentEngine) EnrichCandle(
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
+	// Technical indicators, computed alongside the analysis
+	var computeIndicators func() (*models.TechnicalIndicators, error)
+	if hasIndicatorOptions(options) {
+		computeIndicators = func() (*models.TechnicalIndicators, error) {
+			return engine.calculateIndicators(current, history, options)
+		}
+	}
+
+	return engine.enrich(ctx, startTime, current, history, nil, computeIndicators, options)
+}
+
+// EnrichStream enriches the latest candle of a stream from its incremental
+// indicator state instead of recomputing indicators over the history
+func (engine *CandleEnrichmentEngine) EnrichStream(
+	ctx context.Context,
+	stream *StreamState,
+	options *models.EnrichmentOptions,
+) (enriched *models.EnrichedCandle, err error) {
+
+	startTime := time.Now()
+	var key string
+	defer func() {
+		engine.stats.recordEnrichment(key, time.Since(startTime), err)
+	}()
+
+	if stream == nil {
+		return nil, fmt.Errorf("validation failed: stream state is required")
+	}
+
+	// Snapshot the window and indicators together so a concurrent update
+	// cannot interleave between them
+	stream.mu.Lock()
+	window := stream.recent()
+	var technical *models.TechnicalIndicators
+	var specErr error
+	indicatorStart := time.Now()
+	if len(window) > 0 && options != nil && hasIndicatorOptions(options) {
+		technical = engine.indicatorsFromState(stream.indicators, window, options)
+		if specs := engine.indicatorSpecs(options); len(specs) > 0 {
+			technical.Values, specErr = stream.specValues(specs)
+		}
+		if options.VolumeIndicators {
+			technical.AnchoredVWAPs = stream.anchoredVWAPs()
+		}
+	}
+	stream.mu.Unlock()
+
+	if len(window) == 0 {
+		return nil, fmt.Errorf("validation failed: stream has no candles")
+	}
+	if technical != nil {
+		engine.stats.recordComponent("indicators", time.Since(indicatorStart))
+	}
+	if specErr != nil {
+		return nil, fmt.Errorf("indicator calculation failed: %w", specErr)
+	}
+
+	current := window[len(window)-1]
+	history := window[:len(window)-1]
+	key = streamKey(current.Symbol, current.Timeframe)
+
+	if err := engine.validateInputs(current, history, options); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	return engine.enrich(ctx, startTime, current, history, technical, nil, options)
+}
+
+// enrich runs the enrichment components around the indicators, either
+// precomputed or computed as a component. Independent components run
+// concurrently within the enrichment deadline, signals after the indicators,
+// benchmark and analysis their rules read.
+// REQ-268: Components cut off by the deadline leave a partial candle listing them
+func (engine *CandleEnrichmentEngine) enrich(
+	ctx context.Context,
+	startTime time.Time,
+	current *models.OHLCV,
+	history []*models.OHLCV,
+	technical *models.TechnicalIndicators,
+	computeIndicators func() (*models.TechnicalIndicators, error),
+	options *models.EnrichmentOptions,
+) (*models.EnrichedCandle, error) {
+
 	// Create context with timeout
 	enrichCtx, cancel := context.WithTimeout(ctx, time.Duration(engine.config.TimeoutMs)*time.Millisecond)
 	defer cancel()
 
 	// Prepare enriched candle
 	enriched := &models.EnrichedCandle{
-		OHLCV: current,
+		OHLCV:      current,
+		Indicators: technical,
 		Metadata: &models.CandleMetadata{
 			GeneratedAt:   time.Now(),
-			EngineVersion: "1.0.0",
+			EngineVersion: EngineVersion,
 		},
 	}
 
+	// Components share one copy so none appends into the caller's history
+	allCandles := make([]*models.OHLCV, 0, len(history)+1)
+	allCandles = append(allCandles, history...)
+	allCandles = append(allCandles, current)
+
+	var components []component
+	var indicatorErr error
+	var relative *models.RelativeData
+
 	// Technical indicators
-	if options.TrendIndicators || options.MomentumIndicators ||
-		options.VolatilityIndicators || options.VolumeIndicators {
-		indicators, err := engine.calculateIndicators(enrichCtx, current, history, options)
-		if err != nil {
-			engine.logger.Warn().Err(err).Msg("Failed to calculate indicators")
-		} else {
-			enriched.Indicators = indicators
-		}
+	if computeIndicators != nil {
+		components = append(components, component{name: "indicators", run: func(ctx context.Context) (func(), error) {
+			technical, err := computeIndicators()
+			return func() { enriched.Indicators, indicatorErr = technical, err }, nil
+		}})
+	}
+
+	// REQ-263: Benchmark comparison
+	if (technical != nil || computeIndicators != nil) && options.Benchmark != "" {
+		components = append(components, component{name: "benchmark", run: func(ctx context.Context) (func(), error) {
+			data := engine.relativeData(ctx, current, history, options.Benchmark)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return func() { relative = data }, nil
+		}})
 	}
 
 	// Market analysis
 	if options.CandlestickPatterns || options.ChartPatterns ||
-		options.MarketRegime || options.SupportResistance {
-		analysis, err := engine.performAnalysis(enrichCtx, current, history, options)
-		if err != nil {
-			engine.logger.Warn().Err(err).Msg("Failed to perform analysis")
-		} else {
-			enriched.Analysis = analysis
-		}
+		options.MarketRegime || options.SupportResistance || options.VolumeProfile {
+		enriched.Analysis = &models.MarketAnalysis{}
+		marketContext(enriched.Analysis, current, history)
+		components = append(components, engine.analysisComponents(allCandles, options, enriched.Analysis)...)
 	}
 
-	// Generate trading signals
+	skipped, failed := engine.runComponents(enrichCtx, components)
+	if indicatorErr != nil {
+		return nil, fmt.Errorf("indicator calculation failed: %w", indicatorErr)
+	}
+	if enriched.Indicators != nil {
+		enriched.Indicators.Relative = relative
+	}
+
+	// Generate trading signals, first for the selected profile
+	// REQ-266: then for each further requested profile
 	if options.TradingSignals {
-		signals, err := engine.generateSignals(enrichCtx, enriched, options)
-		if err != nil {
-			engine.logger.Warn().Err(err).Msg("Failed to generate trading signals")
-		} else {
-			enriched.Signals = signals
+		signalSkipped, signalFailed := engine.runComponents(enrichCtx, engine.signalComponents(enriched, options))
+		skipped = append(skipped, signalSkipped...)
+		failed = append(failed, signalFailed...)
+	}
+
+	if len(skipped) > 0 || len(failed) > 0 {
+		enriched.Metadata.Partial = true
+		enriched.Metadata.SkippedComponents = skipped
+		enriched.Metadata.FailedComponents = failed
+
+		if recorded(ctx) {
+			engine.stats.recordPartial(skipped, failed)
 		}
 	}
 
@@ -191,241 +485,416 @@ func (engine *CandleEnrichmentEngine) EnrichCandle(
 		Str("symbol", current.Symbol).
 		Dur("processing_time", time.Since(startTime)).
 		Float64("signal_strength", getSignalStrength(enriched)).
+		Strs("skipped_components", skipped).
 		Msg("Candle enrichment completed")
 
 	return enriched, nil
 }
 
-// calculateIndicators computes technical indicators using the actual available methods
+// signalComponents returns a signal generation component per selected
+// profile, named "signals" for the primary profile and "signals:<name>" for
+// the others
+func (engine *CandleEnrichmentEngine) signalComponents(
+	enriched *models.EnrichedCandle,
+	options *models.EnrichmentOptions,
+) []component {
+
+	// Signals read a snapshot so a late component never sees the candle
+	// its caller already received
+	snapshot := *enriched
+
+	var components []component
+	for i, profile := range engine.signalProfiles(options) {
+		primary, profile := i == 0, profile
+		name := "signals"
+		if !primary {
+			name += ":" + profile.definition.Name
+		}
+
+		components = append(components, component{name: name, run: func(ctx context.Context) (func(), error) {
+			signals, err := engine.generateSignals(ctx, &snapshot, options, profile)
+			if err != nil {
+				return nil, fmt.Errorf("profile %s: %w", profile.definition.Name, err)
+			}
+
+			return func() {
+				if primary {
+					enriched.Signals = signals
+					return
+				}
+				if enriched.ProfileSignals == nil {
+					enriched.ProfileSignals = make(map[string]*models.TradingSignals)
+				}
+				enriched.ProfileSignals[profile.definition.Name] = signals
+			}, nil
+		}})
+	}
+	return components
+}
+
+// calculateIndicators computes technical indicators by replaying the history
+// through incremental indicator state, so batch and streaming enrichment
+// share a single definition of every indicator
 func (engine *CandleEnrichmentEngine) calculateIndicators(
-	ctx context.Context,
 	current *models.OHLCV,
 	history []*models.OHLCV,
 	options *models.EnrichmentOptions,
 ) (*models.TechnicalIndicators, error) {
 
-	allCandles := append(history, current)
+	allCandles := make([]*models.OHLCV, 0, len(history)+1)
+	allCandles = append(allCandles, history...)
+	allCandles = append(allCandles, current)
 
-	// Calculate individual indicators
-	indicators := &models.TechnicalIndicators{}
+	state := indicators.NewIndicatorStateFromHistory(allCandles)
+	result := engine.indicatorsFromState(state, allCandles, options)
+
+	// REQ-255: Registry indicators selected by spec
+	if specs := engine.indicatorSpecs(options); len(specs) > 0 {
+		values, err := indicators.ComputeSpecs(engine.registry, specs, allCandles)
+		if err != nil {
+			return nil, err
+		}
+		result.Values = values
+	}
+
+	return result, nil
+}
+
+// indicatorsFromState maps an indicator state onto the enriched indicator set.
+// candles is the recent window ending with the state's latest candle; it is only
+// used for the lookback comparisons of the trend and volume analysis.
+func (engine *CandleEnrichmentEngine) indicatorsFromState(
+	state *indicators.IndicatorState,
+	candles []*models.OHLCV,
+	options *models.EnrichmentOptions,
+) *models.TechnicalIndicators {
+
+	result := &models.TechnicalIndicators{}
+	periods := state.Count()
+	currentPrice := state.Last().Close
 
-	// Use actual available methods from our indicator packages
 	if options.TrendIndicators {
-		// SMA calculations
-		if len(allCandles) >= 20 {
-			sma20 := engine.calculateSMA(allCandles, 20)
-			indicators.SMA20 = sma20
+		trend := state.Trend()
+		result.SMA20 = trend.SMA20
+		result.SMA50 = trend.SMA50
+
+		if periods >= 12 {
+			result.EMA12 = trend.EMA12
+		}
+
+		if periods >= 26 {
+			result.EMA26 = trend.EMA26
 		}
 
-		if len(allCandles) >= 50 {
-			sma50 := engine.calculateSMA(allCandles, 50)
-			indicators.SMA50 = sma50
+		// The signal line needs 9 MACD values on top of the slow EMA
+		if periods >= 34 {
+			result.MACD = &models.MACDData{
+				Line:      trend.MACD,
+				Signal:    trend.MACDSignal,
+				Histogram: trend.MACDHist,
+			}
+		}
+
+		// REQ-256: Trend strength indicators
+		trendStrength := state.TrendStrength()
+
+		if periods >= 15 {
+			result.ADX = &models.ADXData{
+				PlusDI:    trendStrength.PlusDI,
+				MinusDI:   trendStrength.MinusDI,
+				Strength:  "weak",
+				Direction: trendDirection(trendStrength.PlusDI >= trendStrength.MinusDI),
+			}
+			if periods >= 28 {
+				result.ADX.ADX = trendStrength.ADX
+				result.ADX.Strength = trendStrength.TrendStrengthLevel()
+			}
 		}
 
-		// EMA calculations
-		if len(allCandles) >= 12 {
-			ema12 := engine.calculateEMA(allCandles, 12)
-			indicators.EMA12 = ema12
+		if periods >= 2 {
+			result.ParabolicSAR = &models.ParabolicSARData{
+				Value: trendStrength.PSAR,
+				Trend: trendDirection(trendStrength.PSARBullish),
+			}
 		}
 
-		if len(allCandles) >= 26 {
-			ema26 := engine.calculateEMA(allCandles, 26)
-			indicators.EMA26 = ema26
+		if periods >= 11 {
+			result.SuperTrend = &models.SuperTrendData{
+				Value: trendStrength.SuperTrend,
+				Trend: trendDirection(trendStrength.SuperTrendBullish),
+			}
 		}
 
-		// MACD
-		if len(allCandles) >= 26 {
-			macd := engine.calculateMACD(allCandles)
-			indicators.MACD = macd
+		if periods >= 78 {
+			cloud := trendStrength.Ichimoku
+			result.Ichimoku = &models.IchimokuData{
+				Tenkan:   cloud.Tenkan,
+				Kijun:    cloud.Kijun,
+				SenkouA:  cloud.SenkouA,
+				SenkouB:  cloud.SenkouB,
+				CloudA:   cloud.CloudA,
+				CloudB:   cloud.CloudB,
+				Position: cloud.CloudPosition(currentPrice),
+			}
 		}
 
 		// Trend analysis
-		indicators.TrendDirection = engine.determineTrendDirection(allCandles)
-		indicators.TrendStrength = engine.calculateTrendStrength(allCandles)
+		result.TrendDirection = engine.determineTrendDirection(periods, currentPrice, trend)
+		result.TrendStrength = engine.calculateTrendStrength(candles)
 	}
 
 	if options.MomentumIndicators {
-		// RSI
-		if len(allCandles) >= 14 {
-			rsi := engine.calculateRSI(allCandles, 14)
-			indicators.RSI = rsi
-		}
-
-		// Stochastic
-		if len(allCandles) >= 14 {
-			stochastic := engine.calculateStochastic(allCandles, 14, 3)
-			indicators.Stochastic = stochastic
+		momentum := state.Momentum()
+
+		if periods >= 14 {
+			result.RSI = momentum.RSI
+			result.Stochastic = &models.StochasticData{
+				K:         momentum.StochasticK,
+				D:         momentum.StochasticD,
+				Condition: stochasticCondition(momentum.StochasticK),
+			}
+			result.WilliamsR = momentum.WilliamsR
 		}
 
-		// Williams %R
-		if len(allCandles) >= 14 {
-			williamsR := engine.calculateWilliamsR(allCandles, 14)
-			indicators.WilliamsR = williamsR
+		if periods >= 20 {
+			result.CCI = state.Channels().CCI
 		}
 
 		// Momentum analysis
-		indicators.MomentumDirection = engine.determineMomentumDirection(allCandles)
-		indicators.MomentumStrength = engine.calculateMomentumStrength(allCandles)
+		result.MomentumDirection = engine.determineMomentumDirection(periods, momentum.RSI)
+		result.MomentumStrength = engine.calculateMomentumStrength(periods, momentum.RSI)
 	}
 
 	if options.VolatilityIndicators {
-		// Bollinger Bands
-		if len(allCandles) >= 20 {
-			bollingerBands := engine.calculateBollingerBands(allCandles, 20, 2.0)
-			indicators.BollingerBands = bollingerBands
+		volatility := state.Volatility()
+
+		if periods >= 20 {
+			result.BollingerBands = bollingerBandsData(volatility, currentPrice)
+		}
+
+		if periods >= 15 {
+			result.ATR = volatility.ATR
 		}
 
-		// ATR
-		if len(allCandles) >= 14 {
-			atr := engine.calculateATR(allCandles, 14)
-			indicators.ATR = atr
+		// REQ-256: Keltner and Donchian channels
+		if periods >= 20 {
+			channels := state.Channels()
+			result.Keltner = channelData(channels.KeltnerUpper, channels.KeltnerMiddle, channels.KeltnerLower, currentPrice)
+			result.Donchian = channelData(channels.DonchianUpper, channels.DonchianMiddle, channels.DonchianLower, currentPrice)
 		}
 
 		// Volatility analysis
-		indicators.VolatilityLevel = engine.determineVolatilityLevel(allCandles)
-		indicators.VolatilityPercent = engine.calculateVolatilityPercent(allCandles)
+		result.VolatilityLevel = engine.determineVolatilityLevel(periods, volatility.ATR, currentPrice)
+		result.VolatilityPercent = engine.calculateVolatilityPercent(periods, volatility.ATR, currentPrice)
 	}
 
 	if options.VolumeIndicators {
-		// VWAP
-		vwap := engine.calculateVWAP(allCandles)
-		indicators.VWAP = vwap
-
-		// OBV
-		obv := engine.calculateOBV(allCandles)
-		indicators.OBV = obv
-
-		// Volume MA
-		if len(allCandles) >= 20 {
-			volumeMA := engine.calculateVolumeMA(allCandles, 20)
-			indicators.VolumeMA = volumeMA
-		}
+		volume := state.Volume()
 
-		// Accumulation/Distribution
-		accumDist := engine.calculateAccumulationDistribution(allCandles)
-		indicators.AccumDist = accumDist
+		result.VWAP = volume.VWAP
+		if result.VWAP == 0 {
+			result.VWAP = currentPrice
+		}
+		result.VWAPBands = volume.VWAPBands
+		result.OBV = volume.OBV
+		result.VolumeMA = volume.VolumeMA
+		result.AccumDist = volume.AccDist
+
+		moneyFlow := state.MoneyFlow()
+		if periods >= 15 {
+			result.MFI = moneyFlow.MFI
+		}
+		if periods >= 20 {
+			result.CMF = moneyFlow.CMF
+		}
 
 		// Volume analysis
-		indicators.VolumeConfirmation = engine.determineVolumeConfirmation(allCandles)
-		indicators.RelativeVolume = engine.calculateRelativeVolume(allCandles)
+		result.VolumeConfirmation = engine.determineVolumeConfirmation(candles)
+		result.RelativeVolume = engine.calculateRelativeVolume(candles)
 	}
 
-	return indicators, nil
+	return result
 }
 
-// performAnalysis conducts market context analysis
-func (engine *CandleEnrichmentEngine) performAnalysis(
-	ctx context.Context,
-	current *models.OHLCV,
-	history []*models.OHLCV,
+// analysisComponents returns the market analysis components selected by the
+// options; each stores its result into market
+func (engine *CandleEnrichmentEngine) analysisComponents(
+	allCandles []*models.OHLCV,
 	options *models.EnrichmentOptions,
-) (*models.MarketAnalysis, error) {
+	market *models.MarketAnalysis,
+) []component {
 
-	analysis := &models.MarketAnalysis{}
+	var components []component
 
 	// Candlestick patterns
 	if options.CandlestickPatterns {
-		recentCandles := getRecentCandles(append(history, current), 5)
-		patterns := engine.candlestickAnalyzer.DetectPatterns(recentCandles)
-		analysis.CandlestickPatterns = patterns
+		components = append(components, component{name: "candlestick_patterns", run: func(ctx context.Context) (func(), error) {
+			// REQ-270: Scored patterns in the context of the trend before them
+			patterns := engine.candlestickAnalyzer.DetectPatterns(getRecentCandles(allCandles, analysis.CandlestickLookback))
+
+			names := make([]string, len(patterns))
+			details := make([]models.CandlestickPatternResult, len(patterns))
+			for i, pattern := range patterns {
+				names[i] = pattern.Name
+				details[i] = models.CandlestickPatternResult{
+					Name:        pattern.Name,
+					Signal:      pattern.Type,
+					Strength:    pattern.Strength,
+					Candles:     pattern.Candles,
+					Description: pattern.Description,
+				}
+			}
+			return func() { market.CandlestickPatterns, market.CandlestickDetails = names, details }, nil
+		}})
 	}
 
 	// Chart patterns
 	if options.ChartPatterns && engine.config.EnableAdvancedPatterns {
-		allCandles := append(history, current)
-		chartPatterns := engine.chartPatternAnalyzer.DetectPatterns(allCandles)
-
-		// Convert to enriched format
-		analysis.ChartPatterns = make([]models.ChartPatternResult, len(chartPatterns))
-		for i, pattern := range chartPatterns {
-			analysis.ChartPatterns[i] = models.ChartPatternResult{
-				Type:       pattern.Type,
-				Name:       pattern.Name,
-				Confidence: pattern.Confidence,
-				Signal:     pattern.Signal,
-				Target:     pattern.Target,
-				StopLoss:   pattern.StopLoss,
-				Timeframe:  pattern.Timeframe,
-				Status:     pattern.Status,
+		components = append(components, component{name: "chart_patterns", run: func(ctx context.Context) (func(), error) {
+			chartPatterns := engine.chartPatternAnalyzer.DetectPatterns(allCandles)
+
+			// Convert to enriched format
+			results := make([]models.ChartPatternResult, len(chartPatterns))
+			for i, pattern := range chartPatterns {
+				results[i] = models.ChartPatternResult{
+					Type:       pattern.Type,
+					Name:       pattern.Name,
+					Confidence: pattern.Confidence,
+					Signal:     pattern.Signal,
+					Target:     pattern.Target,
+					StopLoss:   pattern.StopLoss,
+					Timeframe:  pattern.Timeframe,
+					Status:     pattern.Status,
+
+					Neckline:     pattern.Neckline,
+					Boundaries:   pattern.Boundaries,
+					Points:       pattern.Points,
+					Invalidation: pattern.Invalidation,
+				}
 			}
-		}
+			return func() { market.ChartPatterns = results }, nil
+		}})
 	}
 
 	// Market regime
 	if options.MarketRegime && engine.config.EnableMarketRegime {
-		allCandles := append(history, current)
-		regime := engine.regimeAnalyzer.DetectRegime(allCandles)
-		analysis.MarketRegime = regime.Phase
+		components = append(components, component{name: "market_regime", run: func(ctx context.Context) (func(), error) {
+			regime := engine.regimeAnalyzer.DetectRegime(allCandles)
+			// REQ-273: Statistical volatility regime alongside the Wyckoff phase
+			volatility := engine.volatilityRegime(allCandles)
+			return func() { market.MarketRegime, market.VolatilityRegime = regime.Phase, volatility }, nil
+		}})
 	}
 
 	// Support and resistance
 	if options.SupportResistance && engine.config.EnableSupportResistance {
-		allCandles := append(history, current)
-		srLevels := engine.supportAnalyzer.DetectLevels(allCandles)
-		analysis.SupportResistance = convertSRLevels(srLevels)
+		components = append(components, component{name: "support_resistance", run: func(ctx context.Context) (func(), error) {
+			levels := convertSRLevels(engine.supportAnalyzer.DetectLevels(allCandles))
+			// REQ-275: Fibonacci levels of the latest swing
+			fibonacci := analysis.FibonacciLevels(engine.swingDetector.Detect(allCandles))
+			return func() { market.SupportResistance, market.Fibonacci = levels, fibonacci }, nil
+		}})
+	}
+
+	// REQ-264: Volume profile of the session's bars within the history
+	if options.VolumeProfile {
+		components = append(components, component{name: "volume_profile", run: func(ctx context.Context) (func(), error) {
+			profile := engine.volumeProfiler.CurrentSession(allCandles)
+			if profile != nil {
+				profile.Bins = nil // levels only, the histogram is served by the REST API
+			}
+			return func() { market.SessionProfile = profile }, nil
+		}})
 	}
 
-	// Market context
+	return components
+}
+
+// marketContext fills the calendar context of the analysis, which is cheap
+// enough to never be skipped
+func marketContext(analysis *models.MarketAnalysis, current *models.OHLCV, history []*models.OHLCV) {
 	analysis.MarketPhase = getMarketPhase(current.Timestamp)
 	analysis.SessionType = getSessionType(current.Timestamp)
 	analysis.DayOfWeek = current.Timestamp.Weekday().String()
 	analysis.MarketHours = isMarketHours(current.Timestamp)
 	analysis.VolumeProfile = getVolumeProfile(current.Volume, history)
-
-	return analysis, nil
 }
 
-// generateSignals creates trading signals from enriched data
+// generateSignals creates trading signals from enriched data, scored by a
+// signal profile
 func (engine *CandleEnrichmentEngine) generateSignals(
 	ctx context.Context,
 	enriched *models.EnrichedCandle,
 	options *models.EnrichmentOptions,
+	profile *signalProfile,
 ) (*models.TradingSignals, error) {
 
-	signals := &models.TradingSignals{}
+	weights := profile.definition.Weights
+	thresholds := profile.definition.Thresholds
+	weighting := profile.definition.Confidence
+
+	signals := &models.TradingSignals{Profile: profile.definition.Name}
 
 	// Component signals
+	var trendStrength, momentumStrength float64
 	if enriched.Indicators != nil {
 		signals.TrendSignal = enriched.Indicators.TrendDirection
 		signals.MomentumSignal = enriched.Indicators.MomentumDirection
 		signals.VolumeSignal = enriched.Indicators.VolumeConfirmation
+		trendStrength = enriched.Indicators.TrendStrength
+		momentumStrength = enriched.Indicators.MomentumStrength
 	}
 
+	// REQ-267: Contributing factors of the score and the confidence
+	factors := &signalFactors{}
+
 	// Overall signal calculation
 	signalScore := 0.0
 	signalCount := 0.0
 
-	// Trend component (40% weight)
-	if signals.TrendSignal == "bullish" {
-		signalScore += 40
-	} else if signals.TrendSignal == "bearish" {
-		signalScore -= 40
-	}
-	signalCount += 40
-
-	// Momentum component (35% weight)
-	if signals.MomentumSignal == "bullish" {
-		signalScore += 35
-	} else if signals.MomentumSignal == "bearish" {
-		signalScore -= 35
-	}
-	signalCount += 35
-
-	// Volume confirmation (25% weight)
+	// Trend component
+	trendPoints := weights.Trend * float64(signalDirection(signals.TrendSignal))
+	signalScore += trendPoints
+	signalCount += weights.Trend
+	factors.addScore(models.FactorIndicator, "trend", directionValue(signals.TrendSignal, trendStrength),
+		"bullish trend adds the weight, bearish subtracts it", weights.Trend, trendPoints)
+
+	// Momentum component
+	momentumPoints := weights.Momentum * float64(signalDirection(signals.MomentumSignal))
+	signalScore += momentumPoints
+	signalCount += weights.Momentum
+	factors.addScore(models.FactorIndicator, "momentum", directionValue(signals.MomentumSignal, momentumStrength),
+		"bullish momentum adds the weight, bearish subtracts it", weights.Momentum, momentumPoints)
+
+	// Volume confirmation
+	volumePoints := 0.0
 	if signals.VolumeSignal == "confirmed" {
-		signalScore += 25
+		volumePoints = weights.Volume
 	} else if signals.VolumeSignal == "divergent" {
-		signalScore -= 15
+		volumePoints = -weights.VolumeDivergence
+	}
+	signalScore += volumePoints
+	signalCount += weights.Volume
+	volumeValue := signals.VolumeSignal
+	if volumeValue == "" {
+		volumeValue = "unavailable"
 	}
-	signalCount += 25
+	factors.addScore(models.FactorIndicator, "volume", volumeValue,
+		fmt.Sprintf("confirmed volume adds the weight, divergent volume subtracts %g", weights.VolumeDivergence),
+		weights.Volume, volumePoints)
+
+	// REQ-266: Rule components of the profile
+	ruleScore, ruleWeight := profile.ruleScore(enriched, factors)
+	signalScore += ruleScore
+	signalCount += ruleWeight
 
 	// Normalize signal
 	if signalCount > 0 {
 		normalizedScore := signalScore / signalCount * 100
 
-		if normalizedScore > 60 {
+		if normalizedScore > thresholds.Bullish {
 			signals.OverallSignal = "bullish"
-		} else if normalizedScore < -60 {
+		} else if normalizedScore < -thresholds.Bearish {
 			signals.OverallSignal = "bearish"
 		} else {
 			signals.OverallSignal = "neutral"
@@ -435,31 +904,69 @@ func (engine *CandleEnrichmentEngine) generateSignals(
 	}
 
 	// Confidence calculation
-	confidence := 50.0 // Base confidence
+	confidence := weighting.Base
+	factors.addConfidence(models.FactorProfile, "base", profile.definition.Name,
+		"base confidence of the profile", weighting.Base, weighting.Base)
 
 	if enriched.Indicators != nil {
 		// Higher confidence with strong trend
-		confidence += enriched.Indicators.TrendStrength * 0.3
+		confidence += enriched.Indicators.TrendStrength * weighting.TrendStrength
+		factors.addConfidence(models.FactorIndicator, "trend_strength", fmt.Sprintf("%.1f", trendStrength),
+			"weight per point of trend strength", weighting.TrendStrength, trendStrength*weighting.TrendStrength)
 
 		// Higher confidence with strong momentum
-		confidence += enriched.Indicators.MomentumStrength * 0.2
+		confidence += enriched.Indicators.MomentumStrength * weighting.MomentumStrength
+		factors.addConfidence(models.FactorIndicator, "momentum_strength", fmt.Sprintf("%.1f", momentumStrength),
+			"weight per point of momentum strength", weighting.MomentumStrength, momentumStrength*weighting.MomentumStrength)
 
 		// Volume confirmation increases confidence
 		if enriched.Indicators.VolumeConfirmation == "confirmed" {
-			confidence += 15
+			confidence += weighting.VolumeConfirmation
+			factors.addConfidence(models.FactorIndicator, "volume_confirmation", "confirmed",
+				"confirmed volume adds the weight", weighting.VolumeConfirmation, weighting.VolumeConfirmation)
 		}
 	}
 
-	// Pattern confirmation
-	if enriched.Analysis != nil && len(enriched.Analysis.CandlestickPatterns) > 0 {
-		confidence += 10
+	// Pattern confirmation by the patterns the profile counts
+	if enriched.Analysis != nil {
+		for _, pattern := range enriched.Analysis.CandlestickDetails {
+			if profile.allowsPattern(pattern.Name) {
+				confidence += weighting.Pattern
+				factors.addConfidence(models.FactorPattern, pattern.Name,
+					fmt.Sprintf("formed (%s, strength %.0f)", pattern.Signal, pattern.Strength),
+					"a candlestick pattern of the profile adds the weight", weighting.Pattern, weighting.Pattern)
+				break
+			}
+		}
+	}
+
+	maxConfidence := weighting.Max
+	if maxConfidence == 0 {
+		maxConfidence = 100
+	}
+
+	signals.Confidence = math.Max(0, math.Min(maxConfidence, confidence))
+	if signals.Confidence != confidence {
+		factors.addConfidence(models.FactorProfile, "cap", fmt.Sprintf("%.1f", confidence),
+			fmt.Sprintf("confidence is kept between 0 and %g", maxConfidence), maxConfidence, signals.Confidence-confidence)
 	}
 
-	if confidence > 95 {
-		confidence = 95
+	// REQ-262: Higher timeframe confluence
+	if options.MultiTimeframe {
+		signals.Confluence = engine.confluence(ctx, enriched.OHLCV, signals)
+		before := signals.Confidence
+		signals.Confidence = adjustConfidence(signals.Confidence, signals.Confluence)
+		if signals.Confidence != before {
+			factors.addConfidence(models.FactorConfluence, "higher_timeframes", confluenceValue(signals.Confluence),
+				"aligned higher timeframes add a tenth of the alignment, conflicting ones subtract 15",
+				0, signals.Confidence-before)
+		}
 	}
 
-	signals.Confidence = confidence
+	signals.Factors = factors.list(signalCount)
+
+	// REQ-275: Stop and targets at the Fibonacci and support/resistance levels
+	selectTradeLevels(enriched, signals)
 
 	// Risk assessment
 	if options.RiskAssessment {
@@ -471,14 +978,62 @@ func (engine *CandleEnrichmentEngine) generateSignals(
 
 	// Pattern-based signals
 	if enriched.Analysis != nil {
-		signals.PatternSignals = generatePatternSignals(enriched.Analysis)
+		for _, signal := range generatePatternSignals(enriched.Analysis) {
+			if profile.allowsPattern(signal.PatternName) {
+				engine.calibratePatternSignal(&signal, enriched.OHLCV)
+				signals.PatternSignals = append(signals.PatternSignals, signal)
+			}
+		}
 	}
 
+	// REQ-259: Custom expression signals
+	signals.CustomSignals = engine.evaluateCustomSignals(enriched, signals)
+
 	return signals, nil
 }
 
+// evaluateCustomSignals returns the custom signals whose expression matches
+func (engine *CandleEnrichmentEngine) evaluateCustomSignals(
+	enriched *models.EnrichedCandle,
+	signals *models.TradingSignals,
+) []models.CustomSignal {
+
+	engine.mu.RLock()
+	custom := engine.customSignals
+	engine.mu.RUnlock()
+
+	if len(custom) == 0 {
+		return nil
+	}
+
+	// Expressions may read the overall signal computed above
+	candidate := *enriched
+	candidate.Signals = signals
+	env := expression.NewCandleEnv(&candidate)
+
+	var matched []models.CustomSignal
+	for _, signal := range custom {
+		if signal.program.Match(env) {
+			matched = append(matched, models.CustomSignal{
+				Name:       signal.definition.Name,
+				Signal:     signal.definition.Signal,
+				Confidence: signal.definition.Confidence,
+			})
+		}
+	}
+
+	return matched
+}
+
 // Helper functions
 
+// hasIndicatorOptions reports whether any indicator group is requested
+func hasIndicatorOptions(options *models.EnrichmentOptions) bool {
+	return options.TrendIndicators || options.MomentumIndicators ||
+		options.VolatilityIndicators || options.VolumeIndicators ||
+		len(options.Indicators) > 0
+}
+
 func DefaultEnrichmentConfig() *EnrichmentConfig {
 	return &EnrichmentConfig{
 		MaxConcurrency:          4,
@@ -486,6 +1041,7 @@ func DefaultEnrichmentConfig() *EnrichmentConfig {
 		EnableProfiling:         false,
 		MinHistoryPeriods:       20,
 		MaxHistoryPeriods:       200,
+		WarmStartPeriods:        500,
 		RequiredDataQuality:     "medium",
 		EnableValidation:        true,
 		EnableAdvancedPatterns:  true,
@@ -493,6 +1049,10 @@ func DefaultEnrichmentConfig() *EnrichmentConfig {
 		EnableSupportResistance: true,
 		CacheTTLMinutes:         5,
 		MaxCacheSize:            1000,
+		ConfluenceTimeframes:    []string{"15m", "1h", "1d"},
+		RelativePeriods:         50,
+		SwingMode:               analysis.SwingATR,
+		SwingThreshold:          3,
 	}
 }
 
@@ -510,6 +1070,18 @@ func (engine *CandleEnrichmentEngine) validateInputs(current *models.OHLCV, hist
 		return fmt.Errorf("enrichment options are required")
 	}
 
+	for _, spec := range options.Indicators {
+		if _, _, err := engine.registry.Resolve(spec); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range profileNames(options) {
+		if !engine.HasSignalProfile(name) {
+			return fmt.Errorf("unknown signal profile: %s", name)
+		}
+	}
+
 	return nil
 }
 
@@ -521,42 +1093,18 @@ func (engine *CandleEnrichmentEngine) validateOutput(enriched *models.EnrichedCa
 	return nil
 }
 
-func (engine *CandleEnrichmentEngine) updateMetrics(duration time.Duration) {
-	engine.mu.Lock()
-	defer engine.mu.Unlock()
-
-	engine.metrics.TotalEnrichments++
-	latencyMs := float64(duration.Nanoseconds()) / 1e6
-
-	// Update average latency
-	if engine.metrics.AverageLatencyMs == 0 {
-		engine.metrics.AverageLatencyMs = latencyMs
-	} else {
-		engine.metrics.AverageLatencyMs = (engine.metrics.AverageLatencyMs + latencyMs) / 2
-	}
-
-	// Update max latency
-	if latencyMs > engine.metrics.MaxLatencyMs {
-		engine.metrics.MaxLatencyMs = latencyMs
-	}
-
-	engine.metrics.LastUpdated = time.Now()
-}
-
 func (engine *CandleEnrichmentEngine) completeMetadata(enriched *models.EnrichedCandle, startTime time.Time, options *models.EnrichmentOptions) {
 	enriched.Metadata.ProcessingTimeMs = float64(time.Since(startTime).Nanoseconds()) / 1e6
 	enriched.Metadata.DataQuality = "high"
 	enriched.Metadata.IndicatorCoverage = calculateIndicatorCoverage(enriched.Indicators)
 
 	// Cache statistics
-	engine.mu.RLock()
-	enriched.Metadata.CacheEfficiency = engine.metrics.CacheHitRate
-	engine.mu.RUnlock()
+	enriched.Metadata.CacheEfficiency = engine.stats.cacheHitRate()
 }
 
-// Placeholder implementations for indicator calculations
-// These would call the actual indicator functions from our indicators package
+// Indicator helpers
 
+// calculateSMA returns the close SMA of the given window, used for lookback comparisons
 func (engine *CandleEnrichmentEngine) calculateSMA(candles []*models.OHLCV, period int) float64 {
 	if len(candles) < period {
 		return 0
@@ -570,233 +1118,6 @@ func (engine *CandleEnrichmentEngine) calculateSMA(candles []*models.OHLCV, peri
 	return sum / float64(period)
 }
 
-func (engine *CandleEnrichmentEngine) calculateEMA(candles []*models.OHLCV, period int) float64 {
-	if len(candles) < period {
-		return 0
-	}
-
-	// Simplified EMA calculation
-	multiplier := 2.0 / (float64(period) + 1.0)
-	ema := candles[0].Close
-
-	for i := 1; i < len(candles); i++ {
-		ema = (candles[i].Close * multiplier) + (ema * (1 - multiplier))
-	}
-
-	return ema
-}
-
-func (engine *CandleEnrichmentEngine) calculateMACD(candles []*models.OHLCV) *models.MACDData {
-	if len(candles) < 26 {
-		return &models.MACDData{}
-	}
-
-	ema12 := engine.calculateEMA(candles, 12)
-	ema26 := engine.calculateEMA(candles, 26)
-	macdLine := ema12 - ema26
-
-	return &models.MACDData{
-		Line:      macdLine,
-		Signal:    macdLine * 0.9, // Simplified signal line
-		Histogram: macdLine * 0.1, // Simplified histogram
-	}
-}
-
-func (engine *CandleEnrichmentEngine) calculateRSI(candles []*models.OHLCV, period int) float64 {
-	if len(candles) < period+1 {
-		return 50 // Neutral RSI
-	}
-
-	gains := 0.0
-	losses := 0.0
-
-	for i := len(candles) - period; i < len(candles); i++ {
-		if i == 0 {
-			continue
-		}
-
-		change := candles[i].Close - candles[i-1].Close
-		if change > 0 {
-			gains += change
-		} else {
-			losses += -change
-		}
-	}
-
-	avgGain := gains / float64(period)
-	avgLoss := losses / float64(period)
-
-	if avgLoss == 0 {
-		return 100
-	}
-
-	rs := avgGain / avgLoss
-	rsi := 100 - (100 / (1 + rs))
-
-	return rsi
-}
-
-func (engine *CandleEnrichmentEngine) calculateStochastic(candles []*models.OHLCV, kPeriod, dPeriod int) *models.StochasticData {
-	if len(candles) < kPeriod {
-		return &models.StochasticData{K: 50, D: 50, Condition: "neutral"}
-	}
-
-	// Find highest high and lowest low in the period
-	highestHigh := candles[len(candles)-kPeriod].High
-	lowestLow := candles[len(candles)-kPeriod].Low
-
-	for i := len(candles) - kPeriod + 1; i < len(candles); i++ {
-		if candles[i].High > highestHigh {
-			highestHigh = candles[i].High
-		}
-		if candles[i].Low < lowestLow {
-			lowestLow = candles[i].Low
-		}
-	}
-
-	currentClose := candles[len(candles)-1].Close
-	k := ((currentClose - lowestLow) / (highestHigh - lowestLow)) * 100
-	d := k * 0.9 // Simplified D calculation
-
-	condition := "neutral"
-	if k < 20 {
-		condition = "oversold"
-	} else if k > 80 {
-		condition = "overbought"
-	}
-
-	return &models.StochasticData{
-		K:         k,
-		D:         d,
-		Condition: condition,
-	}
-}
-
-func (engine *CandleEnrichmentEngine) calculateWilliamsR(candles []*models.OHLCV, period int) float64 {
-	if len(candles) < period {
-		return -50 // Neutral Williams %R
-	}
-
-	// Similar to Stochastic but inverted
-	stoch := engine.calculateStochastic(candles, period, 3)
-	return stoch.K - 100
-}
-
-func (engine *CandleEnrichmentEngine) calculateBollingerBands(candles []*models.OHLCV, period int, stdDev float64) *models.BollingerBandsData {
-	if len(candles) < period {
-		currentPrice := candles[len(candles)-1].Close
-		return &models.BollingerBandsData{
-			Upper:     currentPrice * 1.02,
-			Middle:    currentPrice,
-			Lower:     currentPrice * 0.98,
-			Bandwidth: 4.0,
-			Position:  "middle",
-		}
-	}
-
-	sma := engine.calculateSMA(candles, period)
-
-	// Calculate standard deviation
-	variance := 0.0
-	for i := len(candles) - period; i < len(candles); i++ {
-		variance += math.Pow(candles[i].Close-sma, 2)
-	}
-	variance /= float64(period)
-	standardDeviation := math.Sqrt(variance)
-
-	upper := sma + (stdDev * standardDeviation)
-	lower := sma - (stdDev * standardDeviation)
-	bandwidth := ((upper - lower) / sma) * 100
-
-	currentPrice := candles[len(candles)-1].Close
-	position := "middle"
-	if currentPrice < lower {
-		position = "below_lower"
-	} else if currentPrice > upper {
-		position = "above_upper"
-	}
-
-	return &models.BollingerBandsData{
-		Upper:     upper,
-		Middle:    sma,
-		Lower:     lower,
-		Bandwidth: bandwidth,
-		Position:  position,
-	}
-}
-
-func (engine *CandleEnrichmentEngine) calculateATR(candles []*models.OHLCV, period int) float64 {
-	if len(candles) < period+1 {
-		return 0
-	}
-
-	trueRanges := make([]float64, 0, period)
-
-	for i := len(candles) - period; i < len(candles); i++ {
-		if i == 0 {
-			continue
-		}
-
-		high := candles[i].High
-		low := candles[i].Low
-		prevClose := candles[i-1].Close
-
-		tr1 := high - low
-		tr2 := math.Abs(high - prevClose)
-		tr3 := math.Abs(low - prevClose)
-
-		trueRange := math.Max(tr1, math.Max(tr2, tr3))
-		trueRanges = append(trueRanges, trueRange)
-	}
-
-	sum := 0.0
-	for _, tr := range trueRanges {
-		sum += tr
-	}
-
-	return sum / float64(len(trueRanges))
-}
-
-func (engine *CandleEnrichmentEngine) calculateVWAP(candles []*models.OHLCV) float64 {
-	if len(candles) == 0 {
-		return 0
-	}
-
-	totalPriceVolume := 0.0
-	totalVolume := int64(0)
-
-	for _, candle := range candles {
-		typicalPrice := (candle.High + candle.Low + candle.Close) / 3
-		totalPriceVolume += typicalPrice * float64(candle.Volume)
-		totalVolume += candle.Volume
-	}
-
-	if totalVolume == 0 {
-		return candles[len(candles)-1].Close
-	}
-
-	return totalPriceVolume / float64(totalVolume)
-}
-
-func (engine *CandleEnrichmentEngine) calculateOBV(candles []*models.OHLCV) float64 {
-	if len(candles) < 2 {
-		return 0
-	}
-
-	obv := 0.0
-
-	for i := 1; i < len(candles); i++ {
-		if candles[i].Close > candles[i-1].Close {
-			obv += float64(candles[i].Volume)
-		} else if candles[i].Close < candles[i-1].Close {
-			obv -= float64(candles[i].Volume)
-		}
-		// If close is equal to previous close, OBV remains unchanged
-	}
-
-	return obv
-}
-
 func (engine *CandleEnrichmentEngine) calculateVolumeMA(candles []*models.OHLCV, period int) float64 {
 	if len(candles) < period {
 		return 0
@@ -810,38 +1131,16 @@ func (engine *CandleEnrichmentEngine) calculateVolumeMA(candles []*models.OHLCV,
 	return float64(sum) / float64(period)
 }
 
-func (engine *CandleEnrichmentEngine) calculateAccumulationDistribution(candles []*models.OHLCV) float64 {
-	if len(candles) == 0 {
-		return 0
-	}
-
-	ad := 0.0
-
-	for _, candle := range candles {
-		if candle.High != candle.Low {
-			moneyFlowMultiplier := ((candle.Close - candle.Low) - (candle.High - candle.Close)) / (candle.High - candle.Low)
-			moneyFlowVolume := moneyFlowMultiplier * float64(candle.Volume)
-			ad += moneyFlowVolume
-		}
-	}
-
-	return ad
-}
-
 // Analysis helper functions
 
-func (engine *CandleEnrichmentEngine) determineTrendDirection(candles []*models.OHLCV) string {
-	if len(candles) < 20 {
+func (engine *CandleEnrichmentEngine) determineTrendDirection(periods int, currentPrice float64, trend *indicators.TrendIndicators) string {
+	if periods < 20 {
 		return "neutral"
 	}
 
-	sma20 := engine.calculateSMA(candles, 20)
-	sma50 := engine.calculateSMA(candles, 50)
-	currentPrice := candles[len(candles)-1].Close
-
-	if currentPrice > sma20 && sma20 > sma50 {
+	if currentPrice > trend.SMA20 && trend.SMA20 > trend.SMA50 {
 		return "bullish"
-	} else if currentPrice < sma20 && sma20 < sma50 {
+	} else if currentPrice < trend.SMA20 && trend.SMA20 < trend.SMA50 {
 		return "bearish"
 	}
 
@@ -867,13 +1166,11 @@ func (engine *CandleEnrichmentEngine) calculateTrendStrength(candles []*models.O
 	return strength
 }
 
-func (engine *CandleEnrichmentEngine) determineMomentumDirection(candles []*models.OHLCV) string {
-	if len(candles) < 14 {
+func (engine *CandleEnrichmentEngine) determineMomentumDirection(periods int, rsi float64) string {
+	if periods < 14 {
 		return "neutral"
 	}
 
-	rsi := engine.calculateRSI(candles, 14)
-
 	if rsi > 60 {
 		return "bullish"
 	} else if rsi < 40 {
@@ -883,25 +1180,20 @@ func (engine *CandleEnrichmentEngine) determineMomentumDirection(candles []*mode
 	return "neutral"
 }
 
-func (engine *CandleEnrichmentEngine) calculateMomentumStrength(candles []*models.OHLCV) float64 {
-	if len(candles) < 14 {
+func (engine *CandleEnrichmentEngine) calculateMomentumStrength(periods int, rsi float64) float64 {
+	if periods < 14 {
 		return 50
 	}
 
-	rsi := engine.calculateRSI(candles, 14)
-
 	// Convert RSI to momentum strength (distance from 50)
 	return math.Abs(rsi-50) * 2
 }
 
-func (engine *CandleEnrichmentEngine) determineVolatilityLevel(candles []*models.OHLCV) string {
-	if len(candles) < 14 {
+func (engine *CandleEnrichmentEngine) determineVolatilityLevel(periods int, atr, currentPrice float64) string {
+	if periods < 14 {
 		return "normal"
 	}
 
-	atr := engine.calculateATR(candles, 14)
-	currentPrice := candles[len(candles)-1].Close
-
 	atrPercent := (atr / currentPrice) * 100
 
 	if atrPercent > 3.0 {
@@ -913,14 +1205,11 @@ func (engine *CandleEnrichmentEngine) determineVolatilityLevel(candles []*models
 	return "normal"
 }
 
-func (engine *CandleEnrichmentEngine) calculateVolatilityPercent(candles []*models.OHLCV) float64 {
-	if len(candles) < 14 {
+func (engine *CandleEnrichmentEngine) calculateVolatilityPercent(periods int, atr, currentPrice float64) float64 {
+	if periods < 14 {
 		return 0
 	}
 
-	atr := engine.calculateATR(candles, 14)
-	currentPrice := candles[len(candles)-1].Close
-
 	return (atr / currentPrice) * 100
 }
 
@@ -956,6 +1245,64 @@ func (engine *CandleEnrichmentEngine) calculateRelativeVolume(candles []*models.
 	return currentVolume / avgVolume
 }
 
+// stochasticCondition classifies %K into oversold, neutral or overbought
+func stochasticCondition(k float64) string {
+	if k < 20 {
+		return "oversold"
+	} else if k > 80 {
+		return "overbought"
+	}
+	return "neutral"
+}
+
+// bollingerBandsData converts bands into the enriched representation
+func bollingerBandsData(volatility *indicators.VolatilityIndicators, currentPrice float64) *models.BollingerBandsData {
+	bands := &models.BollingerBandsData{
+		Upper:    volatility.BollingerUpper,
+		Middle:   volatility.BollingerMiddle,
+		Lower:    volatility.BollingerLower,
+		Position: "middle",
+	}
+
+	if bands.Middle != 0 {
+		bands.Bandwidth = ((bands.Upper - bands.Lower) / bands.Middle) * 100
+	}
+
+	if currentPrice < bands.Lower {
+		bands.Position = "below_lower"
+	} else if currentPrice > bands.Upper {
+		bands.Position = "above_upper"
+	}
+
+	return bands
+}
+
+// channelData converts a price channel into the enriched representation
+func channelData(upper, middle, lower, currentPrice float64) *models.ChannelData {
+	channel := &models.ChannelData{
+		Upper:    upper,
+		Middle:   middle,
+		Lower:    lower,
+		Position: "middle",
+	}
+
+	if currentPrice < lower {
+		channel.Position = "below_lower"
+	} else if currentPrice > upper {
+		channel.Position = "above_upper"
+	}
+
+	return channel
+}
+
+// trendDirection names a trend flag as bullish or bearish
+func trendDirection(bullish bool) string {
+	if bullish {
+		return "bullish"
+	}
+	return "bearish"
+}
+
 // Additional helper functions
 
 func getSignalStrength(enriched *models.EnrichedCandle) float64 {
@@ -1119,23 +1466,28 @@ func calculateRiskLevel(enriched *models.EnrichedCandle) string {
 	return "low"
 }
 
-func generatePatternSignals(analysis *models.MarketAnalysis) []models.PatternSignal {
+func generatePatternSignals(market *models.MarketAnalysis) []models.PatternSignal {
 	signals := make([]models.PatternSignal, 0)
 
 	// Convert candlestick patterns to signals
-	for _, pattern := range analysis.CandlestickPatterns {
+	// REQ-270: scored by the detector
+	for _, pattern := range market.CandlestickDetails {
 		signal := models.PatternSignal{
 			PatternType: "candlestick",
-			PatternName: pattern,
-			Signal:      inferPatternSignal(pattern),
-			Confidence:  75.0,
+			PatternName: pattern.Name,
+			Signal:      pattern.Signal,
+			Confidence:  pattern.Strength,
 			TimeHorizon: "short",
 		}
 		signals = append(signals, signal)
 	}
 
 	// Convert chart patterns to signals
-	for _, pattern := range analysis.ChartPatterns {
+	for _, pattern := range market.ChartPatterns {
+		// REQ-271: A failed pattern no longer points anywhere
+		if pattern.Status == analysis.PatternFailed {
+			continue
+		}
 		signal := models.PatternSignal{
 			PatternType: "chart",
 			PatternName: pattern.Name,
@@ -1151,25 +1503,6 @@ func generatePatternSignals(analysis *models.MarketAnalysis) []models.PatternSig
 	return signals
 }
 
-func inferPatternSignal(patternName string) string {
-	bullishPatterns := []string{"hammer", "doji", "engulfing_bullish", "morning_star"}
-	bearishPatterns := []string{"shooting_star", "engulfing_bearish", "evening_star"}
-
-	for _, pattern := range bullishPatterns {
-		if patternName == pattern {
-			return "bullish"
-		}
-	}
-
-	for _, pattern := range bearishPatterns {
-		if patternName == pattern {
-			return "bearish"
-		}
-	}
-
-	return "neutral"
-}
-
 func calculateIndicatorCoverage(indicators *models.TechnicalIndicators) float64 {
 	if indicators == nil {
 		return 0
@@ -2,10 +2,15 @@ package enrichment
 
 import (
 	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/internal/analysis"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 func TestCandleEnrichmentEngine(t *testing.T) {
@@ -140,6 +145,174 @@ func TestInsufficientHistory(t *testing.T) {
 	}
 }
 
+func TestEnrichStreamMatchesBatch(t *testing.T) {
+	engine := NewCandleEnrichmentEngine(nil)
+	options := models.DefaultEnrichmentOptions()
+	options.Indicators = []string{"sma(200)", "bbands(20,2.5)"}
+	candles := generateTestCandles(300)
+
+	manager := NewStreamStateManager(engine.config.MaxHistoryPeriods, engine.config.WarmStartPeriods)
+	stream := manager.WarmStart("TEST", "1min", candles[:250])
+
+	ctx := context.Background()
+	for i := 250; i < len(candles); i++ {
+		if !stream.Update(candles[i]) {
+			t.Fatalf("candle %d rejected", i)
+		}
+
+		streamed, err := engine.EnrichStream(ctx, stream, options)
+		if err != nil {
+			t.Fatalf("Failed to enrich stream: %v", err)
+		}
+
+		batch, err := engine.EnrichCandle(ctx, candles[i], candles[:i], options)
+		if err != nil {
+			t.Fatalf("Failed to enrich candle: %v", err)
+		}
+
+		got, want := streamed.Indicators, batch.Indicators
+		if math.Abs(got.SMA50-want.SMA50) > 1e-9 || got.EMA26 != want.EMA26 ||
+			math.Abs(got.RSI-want.RSI) > 1e-9 || math.Abs(got.ATR-want.ATR) > 1e-9 ||
+			got.OBV != want.OBV || got.VWAP != want.VWAP {
+			t.Fatalf("candle %d: streaming indicators %+v differ from batch %+v", i, got, want)
+		}
+		for key, value := range want.Values {
+			if math.Abs(got.Values[key]-value) > 1e-9 {
+				t.Fatalf("candle %d: %s streaming=%v batch=%v", i, key, got.Values[key], value)
+			}
+		}
+		if len(got.Values) != 4 {
+			t.Fatalf("candle %d: expected 4 spec values, got %v", i, got.Values)
+		}
+	}
+
+	if stream.Update(candles[len(candles)-1]) {
+		t.Error("Expected replayed candle to be ignored")
+	}
+}
+
+func TestCustomSignals(t *testing.T) {
+	engine := NewCandleEnrichmentEngine(nil)
+	err := engine.SetCustomSignals([]models.CustomSignalDefinition{
+		{Name: "above_average", When: "close > sma(5) - 100 and rsi(14) >= 0", Signal: "bullish", Confidence: 60},
+		{Name: "never", When: "close < 0", Signal: "bearish", Confidence: 50},
+	})
+	if err != nil {
+		t.Fatalf("Failed to set custom signals: %v", err)
+	}
+
+	candles := generateTestCandles(60)
+	options := models.DefaultEnrichmentOptions()
+	enriched, err := engine.EnrichCandle(context.Background(), candles[59], candles[:59], options)
+	if err != nil {
+		t.Fatalf("Failed to enrich candle: %v", err)
+	}
+
+	custom := enriched.Signals.CustomSignals
+	if len(custom) != 1 || custom[0].Name != "above_average" || custom[0].Signal != "bullish" {
+		t.Fatalf("Expected only above_average to match, got %+v", custom)
+	}
+	if _, exists := enriched.Indicators.Values["sma(5)"]; !exists {
+		t.Errorf("Expected the signal's indicators to be computed, got %v", enriched.Indicators.Values)
+	}
+
+	if err := engine.SetCustomSignals([]models.CustomSignalDefinition{
+		{Name: "typo", When: "clse > 1", Signal: "bullish", Confidence: 50},
+	}); err == nil {
+		t.Error("Expected error for an invalid expression")
+	}
+}
+
+func TestSignalProfiles(t *testing.T) {
+	engine := NewCandleEnrichmentEngine(nil)
+	err := engine.SetSignalProfiles([]models.SignalProfile{{
+		Name:       "rules_only",
+		Thresholds: models.SignalThresholds{Bullish: 50, Bearish: 50},
+		Confidence: models.ConfidenceWeights{Base: 40},
+		Rules: []models.SignalRule{
+			{Name: "positive", When: "close > ema(9) - 1000", Signal: "bullish", Weight: 30},
+			{Name: "never", When: "close < 0", Signal: "bearish", Weight: 10},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to set signal profiles: %v", err)
+	}
+
+	candles := generateTestCandles(60)
+	options := models.DefaultEnrichmentOptions()
+	options.SignalProfiles = []string{"rules_only"}
+	enriched, err := engine.EnrichCandle(context.Background(), candles[59], candles[:59], options)
+	if err != nil {
+		t.Fatalf("Failed to enrich candle: %v", err)
+	}
+
+	if enriched.Signals.Profile != models.DefaultSignalProfile {
+		t.Errorf("Expected default profile signals, got %q", enriched.Signals.Profile)
+	}
+
+	signals := enriched.ProfileSignals["rules_only"]
+	if signals == nil {
+		t.Fatalf("Expected rules_only profile signals, got %v", enriched.ProfileSignals)
+	}
+	// 30 of 40 rule weight is bullish: a score of 75
+	if signals.Profile != "rules_only" || signals.OverallSignal != "bullish" || signals.SignalStrength != 75 {
+		t.Errorf("Unexpected rules_only signals: %+v", signals)
+	}
+	if signals.Confidence != 40 {
+		t.Errorf("Expected base confidence 40, got %v", signals.Confidence)
+	}
+	if first := signals.Factors[0]; first.Name != "positive" || !strings.HasPrefix(first.Value, "matched: ema(9)=") {
+		t.Errorf("Expected the matched rule to lead the factors, got %+v", first)
+	}
+	if _, exists := enriched.Indicators.Values["ema(9)"]; !exists {
+		t.Errorf("Expected the rule indicators to be computed, got %v", enriched.Indicators.Values)
+	}
+
+	options.SignalProfile = "missing"
+	if _, err := engine.EnrichCandle(context.Background(), candles[59], candles[:59], options); err == nil {
+		t.Error("Expected error for an unknown signal profile")
+	}
+}
+
+func TestSignalFactors(t *testing.T) {
+	engine := NewCandleEnrichmentEngine(nil)
+	candles := generateTestCandles(60)
+	enriched, err := engine.EnrichCandle(context.Background(), candles[59], candles[:59], models.DefaultEnrichmentOptions())
+	if err != nil {
+		t.Fatalf("Failed to enrich candle: %v", err)
+	}
+
+	signals := enriched.Signals
+	var score, confidence float64
+	seenConfidence := false
+	for i, factor := range signals.Factors {
+		switch factor.Affects {
+		case models.FactorScore:
+			if seenConfidence {
+				t.Fatalf("Score factor %s listed after confidence factors", factor.Name)
+			}
+			score += factor.Contribution
+		case models.FactorConfidence:
+			seenConfidence = true
+			confidence += factor.Contribution
+		default:
+			t.Fatalf("Unexpected factor target %q", factor.Affects)
+		}
+
+		if i > 0 && signals.Factors[i-1].Affects == factor.Affects &&
+			math.Abs(signals.Factors[i-1].Contribution) < math.Abs(factor.Contribution) {
+			t.Errorf("Factors not ordered by contribution: %+v", signals.Factors)
+		}
+	}
+
+	if math.Abs(math.Abs(score)-signals.SignalStrength) > 1e-9 {
+		t.Errorf("Score factors add up to %v, signal strength is %v", score, signals.SignalStrength)
+	}
+	if math.Abs(confidence-signals.Confidence) > 1e-9 {
+		t.Errorf("Confidence factors add up to %v, confidence is %v", confidence, signals.Confidence)
+	}
+}
+
 // Helper function to generate test candles
 func generateTestCandles(count int) []*models.OHLCV {
 	candles := make([]*models.OHLCV, count)
@@ -161,3 +334,487 @@ func generateTestCandles(count int) []*models.OHLCV {
 
 	return candles
 }
+
+// timeframeCandles serves generated candles per timeframe and counts lookups
+type timeframeCandles struct {
+	candles map[string][]*models.OHLCV
+	lookups int
+}
+
+func (s *timeframeCandles) GetBefore(ctx context.Context, symbol, timeframe string, before time.Time, limit int) ([]*models.OHLCV, error) {
+	s.lookups++
+	var result []*models.OHLCV
+	for _, candle := range s.candles[timeframe] {
+		if candle.Timestamp.Before(before) {
+			result = append(result, candle)
+		}
+	}
+	if len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result, nil
+}
+
+// trendingCandles generates count candles of a timeframe ending at end, moving
+// step per candle
+func trendingCandles(count int, timeframe string, end time.Time, step float64) []*models.OHLCV {
+	duration := timeframeDurations[timeframe]
+	candles := make([]*models.OHLCV, count)
+	for i := range candles {
+		price := 100 + float64(i)*step + float64(i%3)*0.2
+		candles[i] = &models.OHLCV{
+			Symbol:    "TEST",
+			Timeframe: timeframe,
+			Timestamp: end.Add(-time.Duration(count-i) * duration),
+			Open:      price - step/2,
+			High:      price + 1,
+			Low:       price - 1,
+			Close:     price,
+			Volume:    1000,
+		}
+	}
+	return candles
+}
+
+func TestConfluenceMarksSignalAgainstHigherTrend(t *testing.T) {
+	now := time.Date(2024, 3, 5, 15, 0, 0, 0, time.UTC)
+	source := &timeframeCandles{candles: map[string][]*models.OHLCV{
+		"1h": trendingCandles(100, "1h", now, 0.5),
+		"1d": trendingCandles(100, "1d", now, -0.5),
+	}}
+	// A daily candle that has not closed yet must not be used
+	source.candles["1d"] = append(source.candles["1d"], &models.OHLCV{
+		Symbol: "TEST", Timeframe: "1d", Timestamp: now, Open: 1, High: 500, Low: 1, Close: 500, Volume: 1000,
+	})
+
+	engine := NewCandleEnrichmentEngine(nil)
+	engine.SetHistorySource(source)
+
+	current := &models.OHLCV{Symbol: "TEST", Timeframe: "1m", Timestamp: now.Add(30 * time.Minute), Close: 100}
+	confluence := engine.confluence(context.Background(), current, &models.TradingSignals{OverallSignal: "bullish"})
+	if confluence == nil {
+		t.Fatal("Expected confluence")
+	}
+
+	if len(confluence.Timeframes) != 2 || confluence.Timeframes[0].Trend != "bullish" || confluence.Timeframes[1].Trend != "bearish" {
+		t.Fatalf("Expected bullish 1h and bearish 1d states, got %+v", confluence.Timeframes)
+	}
+	if confluence.Timeframes[1].Timestamp.Equal(now) {
+		t.Error("Used the daily candle that has not closed")
+	}
+	if !confluence.Conflicting || confluence.Aligned {
+		t.Errorf("Expected a conflicting signal, got %+v", confluence)
+	}
+	if len(confluence.Conflicts) != 1 || confluence.Conflicts[0] != "bullish 1m signal against 1d bearish trend" {
+		t.Errorf("Unexpected conflicts %v", confluence.Conflicts)
+	}
+	if confluence.Score >= 0 {
+		t.Errorf("Expected the heavier daily downtrend to dominate the score, got %v", confluence.Score)
+	}
+
+	// The next minute reuses the cached states
+	lookups := source.lookups
+	current.Timestamp = current.Timestamp.Add(time.Minute)
+	engine.confluence(context.Background(), current, &models.TradingSignals{OverallSignal: "bearish"})
+	if source.lookups != lookups {
+		t.Errorf("Expected cached states, got %d new lookups", source.lookups-lookups)
+	}
+}
+
+// slowBenchmark serves no candles and blocks benchmark lookups until the
+// enrichment deadline
+type slowBenchmark struct{}
+
+func (slowBenchmark) GetBefore(ctx context.Context, symbol, timeframe string, before time.Time, limit int) ([]*models.OHLCV, error) {
+	if symbol != "SPY" {
+		return nil, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestPartialEnrichment(t *testing.T) {
+	config := DefaultEnrichmentConfig()
+	config.TimeoutMs = 50
+	engine := NewCandleEnrichmentEngine(config)
+	engine.SetHistorySource(slowBenchmark{})
+
+	history := generateTestCandles(50)
+	current := &models.OHLCV{Symbol: "TEST", Timeframe: "1m", Timestamp: time.Now(), Open: 100, High: 105, Low: 99, Close: 103, Volume: 10000}
+	options := &models.EnrichmentOptions{
+		TrendIndicators:     true,
+		MomentumIndicators:  true,
+		CandlestickPatterns: true,
+		MarketRegime:        true,
+		Benchmark:           "SPY",
+	}
+
+	started := time.Now()
+	enriched, err := engine.EnrichCandle(context.Background(), current, history, options)
+	if err != nil {
+		t.Fatalf("Expected a partial candle, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("Enrichment ignored the deadline, took %v", elapsed)
+	}
+
+	if !enriched.Metadata.Partial || len(enriched.Metadata.SkippedComponents) != 1 || enriched.Metadata.SkippedComponents[0] != "benchmark" {
+		t.Fatalf("Expected only the benchmark skipped, got %+v", enriched.Metadata)
+	}
+	if enriched.Indicators == nil || enriched.Indicators.Relative != nil {
+		t.Error("Expected indicators without benchmark data")
+	}
+	if enriched.Analysis == nil || enriched.Analysis.MarketRegime == "" {
+		t.Error("Expected the market analysis")
+	}
+	if metrics := engine.Metrics(0); metrics.PartialEnrichments != 1 || metrics.SkippedComponents["benchmark"] != 1 {
+		t.Errorf("Expected one partial enrichment skipping the benchmark, got %d %v", metrics.PartialEnrichments, metrics.SkippedComponents)
+	}
+
+	// An ended context skips every component but still returns the candle
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	options.TradingSignals = true
+	enriched, err = engine.EnrichCandle(ctx, current, history, options)
+	if err != nil {
+		t.Fatalf("Expected a partial candle, got %v", err)
+	}
+	if enriched.Indicators != nil || enriched.Signals != nil {
+		t.Error("Expected no results after the deadline")
+	}
+	if got := strings.Join(enriched.Metadata.SkippedComponents, ","); got != "indicators,benchmark,candlestick_patterns,market_regime,signals" {
+		t.Errorf("Unexpected skipped components %s", got)
+	}
+}
+
+func TestRunComponentsReportsFailures(t *testing.T) {
+	engine := NewCandleEnrichmentEngine(nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	stored := false
+	skipped, failed := engine.runComponents(ctx, []component{
+		{name: "stored", run: func(ctx context.Context) (func(), error) { return func() { stored = true }, nil }},
+		{name: "broken", run: func(ctx context.Context) (func(), error) { return nil, errors.New("no data") }},
+		{name: "slow", run: func(ctx context.Context) (func(), error) { <-ctx.Done(); return nil, ctx.Err() }},
+	})
+
+	if !stored || strings.Join(skipped, ",") != "slow" || strings.Join(failed, ",") != "broken" {
+		t.Errorf("Expected slow skipped and broken failed, got skipped %v, failed %v, stored %v", skipped, failed, stored)
+	}
+}
+
+func TestPatternCalibration(t *testing.T) {
+	engine := NewCandleEnrichmentEngine(nil)
+	engine.SetPatternStats([]*models.PatternOutcomeStats{
+		{Symbol: "AAPL", Timeframe: "1m", PatternType: "candlestick", PatternName: "hammer", Signal: "bullish",
+			Occurrences: 60, TargetHits: 24, HitRate: 0.4},
+	})
+	candle := &models.OHLCV{Symbol: "AAPL", Timeframe: "1m"}
+
+	// 60 outcomes at 40% weigh three times the fixed 80
+	signal := models.PatternSignal{PatternType: "candlestick", PatternName: "hammer", Signal: "bullish", Confidence: 80}
+	engine.calibratePatternSignal(&signal, candle)
+	if signal.Confidence != 50 || signal.HitRate != 0.4 || signal.Samples != 60 {
+		t.Errorf("Expected confidence 50 from 60 samples, got %+v", signal)
+	}
+
+	// Other symbols, timeframes and directions keep their fixed confidence
+	for _, other := range []*models.OHLCV{{Symbol: "MSFT", Timeframe: "1m"}, {Symbol: "AAPL", Timeframe: "5m"}} {
+		signal := models.PatternSignal{PatternType: "candlestick", PatternName: "hammer", Signal: "bullish", Confidence: 80}
+		engine.calibratePatternSignal(&signal, other)
+		if signal.Confidence != 80 || signal.Samples != 0 {
+			t.Errorf("Expected no calibration for %s %s, got %+v", other.Symbol, other.Timeframe, signal)
+		}
+	}
+}
+
+func TestVolatilityRegime(t *testing.T) {
+	// A calm stretch followed by a volatile one
+	rng := rand.New(rand.NewSource(3))
+	base := time.Date(2024, 6, 3, 14, 30, 0, 0, time.UTC)
+	candles := make([]*models.OHLCV, 240)
+	price := 100.0
+	for i := range candles {
+		deviation := 0.001
+		if i >= 180 {
+			deviation = 0.015
+		}
+		price *= math.Exp(rng.NormFloat64() * deviation)
+		candles[i] = &models.OHLCV{Symbol: "AAPL", Timeframe: "1m", Timestamp: base.Add(time.Duration(i) * time.Minute),
+			Open: price, High: price, Low: price, Close: price, Volume: 1000}
+	}
+
+	engine := NewCandleEnrichmentEngine(nil)
+	if regime := engine.volatilityRegime(candles[:50]); regime != nil {
+		t.Errorf("Expected no regime without a model or enough returns, got %+v", regime)
+	}
+
+	regime := engine.volatilityRegime(candles)
+	if regime == nil || regime.Model != "window" || regime.Regime != analysis.RegimeHighVolatility || regime.Samples != len(candles)-1 {
+		t.Fatalf("Expected the high volatility regime of a window model, got %+v", regime)
+	}
+	if regime.ExpectedDuration <= 1 || regime.Duration < 1 || len(regime.Probabilities) != RegimeStates {
+		t.Errorf("Unexpected regime %+v", regime)
+	}
+
+	// The window model is reused until half of its window has turned over
+	fitted := engine.windowModels[streamKey("AAPL", "1m")]
+	engine.volatilityRegime(candles[1:])
+	if engine.windowModels[streamKey("AAPL", "1m")] != fitted {
+		t.Error("Expected the window model reused for the next candle")
+	}
+	engine.volatilityRegime(candles[:110])
+	if engine.windowModels[streamKey("AAPL", "1m")] == fitted {
+		t.Error("Expected the window model refit for a window half a window away")
+	}
+
+	// A stored model is used even for a short window
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close
+	}
+	model, err := analysis.FitVolatilityHMM(analysis.LogReturns(closes), RegimeStates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.SetRegimeModel("AAPL", "1m", model)
+	if regime := engine.volatilityRegime(candles[:50]); regime == nil || regime.Model != "stored" || regime.Regime == analysis.RegimeHighVolatility {
+		t.Errorf("Expected a calm regime of the stored model, got %+v", regime)
+	}
+	if len(engine.windowModels) != 0 {
+		t.Error("Expected the stored model to replace the window model")
+	}
+	engine.RemoveStream("AAPL", "1m")
+	if regime := engine.volatilityRegime(candles[:50]); regime != nil || len(engine.regimeModels) != 0 {
+		t.Errorf("Expected the stored model dropped with the stream, got %+v", regime)
+	}
+
+	// Changes are reported per stream after its first regime
+	tracker := NewRegimeTracker()
+	observeModel := func(symbol, name, source string) *models.RegimeChange {
+		return tracker.Observe(&models.EnrichedCandle{
+			OHLCV:    &models.OHLCV{Symbol: symbol, Timeframe: "1m"},
+			Analysis: &models.MarketAnalysis{VolatilityRegime: &models.VolatilityRegime{Regime: name, Model: source}},
+		})
+	}
+	observe := func(symbol, name string) *models.RegimeChange {
+		return observeModel(symbol, name, "stored")
+	}
+
+	// Window model regimes are not tracked
+	observeModel("AAPL", analysis.RegimeHighVolatility, "window")
+	if change := observe("AAPL", analysis.RegimeLowVolatility); change != nil {
+		t.Errorf("Expected no change on the first regime, got %+v", change)
+	}
+	if change := observe("MSFT", analysis.RegimeHighVolatility); change != nil {
+		t.Errorf("Expected streams tracked apart, got %+v", change)
+	}
+	if change := observe("AAPL", analysis.RegimeLowVolatility); change != nil {
+		t.Errorf("Expected no change while the regime holds, got %+v", change)
+	}
+	change := observe("AAPL", analysis.RegimeHighVolatility)
+	if change == nil || change.From != analysis.RegimeLowVolatility || change.To != analysis.RegimeHighVolatility || change.Symbol != "AAPL" {
+		t.Errorf("Expected a change to high volatility, got %+v", change)
+	}
+
+	// A removed stream starts over without a previous regime
+	tracker.Remove("AAPL", "1m")
+	if change := observe("AAPL", analysis.RegimeLowVolatility); change != nil {
+		t.Errorf("Expected no change for the first regime after the removal, got %+v", change)
+	}
+}
+
+func TestLevelTracker(t *testing.T) {
+	base := time.Date(2024, 6, 3, 15, 0, 0, 0, time.UTC)
+
+	// Hourly candles swinging between 95 and 105
+	hourly := make([]*models.OHLCV, 60)
+	for i := range hourly {
+		price := 100 + 5*math.Sin(float64(i)*math.Pi/6)
+		hourly[i] = &models.OHLCV{Symbol: "TEST", Timeframe: "1h", Timestamp: base.Add(-time.Duration(60-i) * time.Hour),
+			Open: price, High: price + 0.2, Low: price - 0.2, Close: price, Volume: 1000}
+	}
+	source := &timeframeCandles{candles: map[string][]*models.OHLCV{"1h": hourly}}
+
+	engine := NewCandleEnrichmentEngine(nil)
+	engine.SetHistorySource(source)
+	tracker := NewLevelTracker(engine)
+
+	observe := func(minute int, low, close float64, detected []*models.SupportResistanceLevel) *LevelUpdate {
+		return tracker.Observe(&models.EnrichedCandle{
+			OHLCV: &models.OHLCV{Symbol: "TEST", Timeframe: "1m", Timestamp: base.Add(time.Duration(minute) * time.Minute),
+				High: close + 0.1, Low: low, Close: close},
+			Analysis: &models.MarketAnalysis{SupportResistance: &models.SupportResistanceLevels{Support: detected}},
+		})
+	}
+
+	countSources := func(update *LevelUpdate) map[string]int {
+		sources := make(map[string]int)
+		for _, level := range update.Levels {
+			for _, source := range level.Sources {
+				sources[source]++
+			}
+			if (level.Price < 100.2) != (level.Role == models.LevelSupport) {
+				t.Errorf("Expected the role of %v to follow its side of the price, got %s", level.Price, level.Role)
+			}
+		}
+		return sources
+	}
+
+	// Higher timeframe levels are looked up in the background
+	update := observe(0, 100, 100.2, []*models.SupportResistanceLevel{{Price: 99, Type: "support", Strength: 50}})
+	if !update.Changed || len(update.Events) != 0 {
+		t.Fatalf("Expected new levels without events, got %+v", update)
+	}
+	if sources := countSources(update); sources[models.LevelSourceDetected] != 1 || sources[models.LevelSourceRound] != 2 || sources["1h"] != 0 {
+		t.Errorf("Expected detected and round levels, got %v", sources)
+	}
+	tracker.wait()
+	// One lookup each for 15m, 1h and 1d
+	if source.lookups != 3 {
+		t.Errorf("Expected 3 higher timeframe lookups, got %d", source.lookups)
+	}
+
+	// The next candle reports them. Two closes below the detected support
+	// break it; the higher timeframes are not looked up again before their
+	// next candle closes
+	update = observe(1, 98.9, 98.7, nil)
+	if len(update.Events) != 0 || !update.Changed {
+		t.Errorf("Expected the 1h levels without events on the first close below, got %+v", update.Events)
+	}
+	if sources := countSources(update); sources["1h"] < 2 {
+		t.Errorf("Expected 1h levels, got %v", sources)
+	}
+	// The round number 100 breaks along with it
+	update = observe(2, 98.5, 98.6, nil)
+	tracker.wait()
+	var broken *models.LevelEvent
+	for _, event := range update.Events {
+		if event.Level.Price == 99 {
+			broken = event
+		}
+	}
+	if len(update.Events) != 2 || broken == nil || broken.Type != models.LevelBreak ||
+		broken.Level.Role != models.LevelResistance || broken.Direction != "down" {
+		t.Errorf("Expected the 99 support broken into resistance, got %+v", broken)
+	}
+	if !update.Changed || source.lookups != 3 {
+		t.Errorf("Expected changed levels without lookups, got %v after %d lookups", update.Changed, source.lookups)
+	}
+
+	// Stored levels resume only streams not tracked yet
+	tracker.Restore("TEST", "1m", nil)
+	if update := observe(3, 98.5, 98.6, nil); update.Changed {
+		t.Errorf("Expected no change, got %+v", update)
+	}
+
+	// Removed streams start over from their stored levels
+	tracker.Remove("TEST", "1m")
+	tracker.Restore("TEST", "1m", nil)
+	if update := observe(4, 98.5, 98.6, nil); !update.Changed || len(update.Events) != 0 {
+		t.Errorf("Expected fresh levels after the removal, got %+v", update)
+	}
+	tracker.wait()
+}
+
+// blockingSource holds lookups of one symbol until released
+type blockingSource struct {
+	symbol  string
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSource) GetBefore(ctx context.Context, symbol, timeframe string, before time.Time, limit int) ([]*models.OHLCV, error) {
+	if symbol == s.symbol {
+		select {
+		case s.started <- struct{}{}:
+		default:
+		}
+		<-s.release
+	}
+	return nil, nil
+}
+
+func TestLevelTrackerLookupsDoNotBlockStreams(t *testing.T) {
+	source := &blockingSource{symbol: "SLOW", started: make(chan struct{}, 1), release: make(chan struct{})}
+	engine := NewCandleEnrichmentEngine(nil)
+	engine.SetHistorySource(source)
+	tracker := NewLevelTracker(engine)
+
+	observe := func(symbol string, minute int) *LevelUpdate {
+		return tracker.Observe(&models.EnrichedCandle{
+			OHLCV: &models.OHLCV{Symbol: symbol, Timeframe: "1m", Timestamp: time.Date(2024, 6, 3, 15, minute, 0, 0, time.UTC),
+				High: 100.1, Low: 99.9, Close: 100},
+		})
+	}
+
+	// Neither the stream waiting for its lookups nor another one blocks
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if update := observe("SLOW", 0); !update.Changed {
+			t.Errorf("Expected the round number levels of the slow stream, got %+v", update)
+		}
+		<-source.started
+		if update := observe("FAST", 0); !update.Changed {
+			t.Errorf("Expected the round number levels of the other stream, got %+v", update)
+		}
+		observe("SLOW", 1)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected candles to be applied while a lookup is pending")
+	}
+
+	close(source.release)
+	tracker.wait()
+}
+
+func TestSelectTradeLevels(t *testing.T) {
+	enriched := &models.EnrichedCandle{
+		OHLCV:      &models.OHLCV{Close: 108},
+		Indicators: &models.TechnicalIndicators{ATR: 1},
+		Analysis: &models.MarketAnalysis{
+			Fibonacci: &models.FibonacciLevels{
+				Direction:  "up",
+				StartPrice: 100,
+				EndPrice:   112,
+				Retracements: []models.FibonacciLevel{
+					{Ratio: 0.382, Price: 107.416}, {Ratio: 0.5, Price: 106}, {Ratio: 0.618, Price: 104.584},
+				},
+				Extensions: []models.FibonacciLevel{{Ratio: 1.272, Price: 115.264}, {Ratio: 1.618, Price: 119.416}},
+			},
+			SupportResistance: &models.SupportResistanceLevels{
+				Resistance: []*models.SupportResistanceLevel{{Price: 110}, {Price: 112.2}},
+			},
+		},
+	}
+
+	// The stop goes a quarter ATR below the 38.2% retracement; the swing
+	// high at 112 and the resistance at 112.2 are one target
+	signals := &models.TradingSignals{OverallSignal: "bullish"}
+	selectTradeLevels(enriched, signals)
+	if signals.EntryLevel != 108 || signals.StopLoss != 107.166 {
+		t.Errorf("Expected entry 108 with the stop at 107.166, got %v and %v", signals.EntryLevel, signals.StopLoss)
+	}
+	if want := []float64{110, 112, 115.264}; len(signals.TakeProfit) != len(want) ||
+		signals.TakeProfit[0] != want[0] || signals.TakeProfit[1] != want[1] || signals.TakeProfit[2] != want[2] {
+		t.Errorf("Expected targets %v, got %v", want, signals.TakeProfit)
+	}
+
+	// A bearish signal mirrors them
+	signals = &models.TradingSignals{OverallSignal: "bearish"}
+	selectTradeLevels(enriched, signals)
+	if signals.StopLoss != 110.25 || len(signals.TakeProfit) != 3 || signals.TakeProfit[0] != 107.416 {
+		t.Errorf("Unexpected bearish levels: stop %v, targets %v", signals.StopLoss, signals.TakeProfit)
+	}
+
+	// Neutral signals get none
+	signals = &models.TradingSignals{OverallSignal: "neutral"}
+	selectTradeLevels(enriched, signals)
+	if signals.EntryLevel != 0 || signals.StopLoss != 0 || signals.TakeProfit != nil {
+		t.Errorf("Expected no levels for a neutral signal, got %+v", signals)
+	}
+}
@@ -0,0 +1,105 @@
+package enrichment
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-267: Contributing factors of trading signals
+
+// signalFactors collects the factors of a signal while it is scored
+type signalFactors struct {
+	score      []models.SignalFactor
+	confidence []models.SignalFactor
+}
+
+// addScore records a score component with its raw signed points
+func (f *signalFactors) addScore(kind, name, value, rule string, weight, points float64) {
+	f.score = append(f.score, models.SignalFactor{
+		Kind:         kind,
+		Name:         name,
+		Value:        value,
+		Rule:         rule,
+		Weight:       weight,
+		Contribution: points,
+		Affects:      models.FactorScore,
+	})
+}
+
+// addConfidence records points added to or taken from the confidence
+func (f *signalFactors) addConfidence(kind, name, value, rule string, weight, points float64) {
+	f.confidence = append(f.confidence, models.SignalFactor{
+		Kind:         kind,
+		Name:         name,
+		Value:        value,
+		Rule:         rule,
+		Weight:       weight,
+		Contribution: points,
+		Affects:      models.FactorConfidence,
+	})
+}
+
+// list scales the raw score points by the total weight into points of the
+// normalized score and orders each group by the size of its contribution
+func (f *signalFactors) list(totalWeight float64) []models.SignalFactor {
+	factors := make([]models.SignalFactor, 0, len(f.score)+len(f.confidence))
+
+	for _, factor := range f.score {
+		if totalWeight > 0 {
+			factor.Contribution = factor.Contribution / totalWeight * 100
+		}
+		factors = append(factors, factor)
+	}
+	byImpact(factors)
+
+	confidence := append([]models.SignalFactor(nil), f.confidence...)
+	byImpact(confidence)
+
+	return append(factors, confidence...)
+}
+
+// byImpact orders factors by the absolute size of their contribution
+func byImpact(factors []models.SignalFactor) {
+	sort.SliceStable(factors, func(i, j int) bool {
+		return math.Abs(factors[i].Contribution) > math.Abs(factors[j].Contribution)
+	})
+}
+
+// directionValue describes a component direction with its strength
+func directionValue(direction string, strength float64) string {
+	if direction == "" {
+		return "unavailable"
+	}
+	return fmt.Sprintf("%s (strength %.1f)", direction, strength)
+}
+
+// ruleValue describes whether a rule matched and the indicator values it read
+func ruleValue(matched bool, specs []string, values map[string]float64) string {
+	text := "not matched"
+	if matched {
+		text = "matched"
+	}
+
+	var read []string
+	for _, spec := range specs {
+		var keys []string
+		for key := range values {
+			if key == spec || strings.HasPrefix(key, spec+".") {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			read = append(read, fmt.Sprintf("%s=%.6g", key, values[key]))
+		}
+	}
+	if len(read) == 0 {
+		return text
+	}
+	return text + ": " + strings.Join(read, ", ")
+}
@@ -0,0 +1,247 @@
+package enrichment
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/analysis"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-274: Support and resistance levels tracked per stream
+
+const (
+	// roundNumberStrength is the strength of a round number without other sources
+	roundNumberStrength = 30
+
+	// higherLookupTimeout bounds the background lookups of higher timeframe levels
+	higherLookupTimeout = 10 * time.Second
+)
+
+// LevelTracker keeps the support and resistance levels of every stream with
+// their touch history. Levels come from the stream's detected levels, the
+// levels of its higher timeframes and round numbers.
+type LevelTracker struct {
+	engine  *CandleEnrichmentEngine
+	books   map[string]*trackedBook
+	mu      sync.Mutex     // guards books; each book has its own lock
+	lookups sync.WaitGroup // background higher timeframe lookups
+}
+
+type trackedBook struct {
+	book   *analysis.LevelBook
+	higher map[string]time.Time // cutoff of the higher timeframe levels looked up last
+
+	// Latest candle, and whether background lookups changed the levels since
+	lastClose float64
+	lastAt    time.Time
+	changed   bool
+
+	mu sync.Mutex
+}
+
+// LevelUpdate is the outcome of a candle for the levels of its stream
+type LevelUpdate struct {
+	Events  []*models.LevelEvent
+	Levels  []*models.TrackedLevel // set when Changed
+	Changed bool                   // levels were added, dropped, tested or broken
+}
+
+// NewLevelTracker creates a tracker reading higher timeframes through the
+// engine's history source
+func NewLevelTracker(engine *CandleEnrichmentEngine) *LevelTracker {
+	return &LevelTracker{
+		engine: engine,
+		books:  make(map[string]*trackedBook),
+	}
+}
+
+// Restore resumes the stored levels of a stream that is not tracked yet
+func (t *LevelTracker) Restore(symbol, timeframe string, levels []*models.TrackedLevel) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := streamKey(symbol, timeframe)
+	if _, exists := t.books[key]; !exists {
+		t.books[key] = &trackedBook{
+			book:   analysis.NewLevelBook(symbol, timeframe, levels),
+			higher: make(map[string]time.Time),
+		}
+	}
+}
+
+// Remove drops the levels of a stream that is no longer tracked, so a later
+// Restore resumes its stored levels
+func (t *LevelTracker) Remove(symbol, timeframe string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.books, streamKey(symbol, timeframe))
+}
+
+// Observe applies an enriched candle to the levels of its stream, then merges
+// the candle's detected levels and the round numbers around its close. Higher
+// timeframe levels are looked up in the background once per closed candle of
+// each timeframe; the update of the next candle reports what they changed.
+func (t *LevelTracker) Observe(enriched *models.EnrichedCandle) *LevelUpdate {
+	if enriched == nil || enriched.OHLCV == nil {
+		return &LevelUpdate{}
+	}
+	candle := enriched.OHLCV
+
+	t.mu.Lock()
+	key := streamKey(candle.Symbol, candle.Timeframe)
+	tracked := t.books[key]
+	if tracked == nil {
+		tracked = &trackedBook{
+			book:   analysis.NewLevelBook(candle.Symbol, candle.Timeframe, nil),
+			higher: make(map[string]time.Time),
+		}
+		t.books[key] = tracked
+	}
+	t.mu.Unlock()
+
+	// Started once the candle is applied, so they merge at its close or later
+	if due := tracked.dueTimeframes(t.engine, candle); len(due) > 0 {
+		t.lookups.Add(1)
+		defer func() { go t.mergeHigher(tracked, candle, due) }()
+	}
+
+	tracked.mu.Lock()
+	defer tracked.mu.Unlock()
+
+	update := &LevelUpdate{Events: tracked.book.Apply(candle)}
+	tracked.lastClose, tracked.lastAt = candle.Close, candle.Timestamp
+
+	var candidates []analysis.LevelCandidate
+	if enriched.Analysis != nil && enriched.Analysis.SupportResistance != nil {
+		candidates = append(candidates, detectedCandidates(enriched.Analysis.SupportResistance)...)
+	}
+	for _, price := range analysis.RoundNumberLevels(candle.Close) {
+		candidates = append(candidates, analysis.LevelCandidate{Price: price, Strength: roundNumberStrength, Source: models.LevelSourceRound})
+	}
+
+	merged := tracked.book.Merge(candidates, candle.Close, candle.Timestamp)
+	update.Changed = merged || tracked.changed || len(update.Events) > 0
+	tracked.changed = false
+	if update.Changed {
+		update.Levels = tracked.book.Levels()
+	}
+
+	return update
+}
+
+// mergeHigher looks up the levels of the due higher timeframes and merges
+// them at the stream's latest close
+func (t *LevelTracker) mergeHigher(tracked *trackedBook, candle *models.OHLCV, due map[string]time.Time) {
+	defer t.lookups.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), higherLookupTimeout)
+	defer cancel()
+
+	candidates := t.higherCandidates(ctx, due, candle)
+	if len(candidates) == 0 {
+		return
+	}
+
+	tracked.mu.Lock()
+	defer tracked.mu.Unlock()
+
+	if tracked.book.Merge(candidates, tracked.lastClose, tracked.lastAt) {
+		tracked.changed = true
+	}
+}
+
+// wait blocks until the background lookups started so far are done
+func (t *LevelTracker) wait() {
+	t.lookups.Wait()
+}
+
+// detectedCandidates offers the levels detected on the stream's own candles
+func detectedCandidates(levels *models.SupportResistanceLevels) []analysis.LevelCandidate {
+	var candidates []analysis.LevelCandidate
+	for _, group := range [][]*models.SupportResistanceLevel{levels.Support, levels.Resistance} {
+		for _, level := range group {
+			candidates = append(candidates, analysis.LevelCandidate{
+				Price:    level.Price,
+				Strength: level.Strength,
+				Source:   models.LevelSourceDetected,
+			})
+		}
+	}
+	return candidates
+}
+
+// dueTimeframes returns the cutoffs of the configured higher timeframes that
+// closed a candle since their levels were merged last, by the time the
+// stream's candle closes, and marks them merged
+func (tracked *trackedBook) dueTimeframes(engine *CandleEnrichmentEngine, candle *models.OHLCV) map[string]time.Time {
+	engine.mu.RLock()
+	source := engine.historySource
+	engine.mu.RUnlock()
+	if source == nil {
+		return nil
+	}
+
+	own := timeframeDurations[candle.Timeframe]
+	closesAt := candle.Timestamp.Add(own)
+
+	tracked.mu.Lock()
+	defer tracked.mu.Unlock()
+
+	due := make(map[string]time.Time)
+	for _, timeframe := range higherTimeframes(engine.config.ConfluenceTimeframes, own) {
+		duration := timeframeDurations[timeframe]
+		cutoff := closesAt.Add(-duration)
+		if last, seen := tracked.higher[timeframe]; seen && cutoff.Sub(last) < duration {
+			continue
+		}
+		// Failed lookups wait for the next candle of the timeframe as well
+		tracked.higher[timeframe] = cutoff
+		due[timeframe] = cutoff
+	}
+	return due
+}
+
+// higherCandidates detects the levels of the due higher timeframes from their
+// candles closed by each cutoff
+func (t *LevelTracker) higherCandidates(ctx context.Context, due map[string]time.Time, candle *models.OHLCV) []analysis.LevelCandidate {
+	engine := t.engine
+	engine.mu.RLock()
+	source := engine.historySource
+	engine.mu.RUnlock()
+	if source == nil || len(due) == 0 {
+		return nil
+	}
+
+	var candidates []analysis.LevelCandidate
+	for _, timeframe := range higherTimeframes(engine.config.ConfluenceTimeframes, timeframeDurations[candle.Timeframe]) {
+		cutoff, isDue := due[timeframe]
+		if !isDue {
+			continue
+		}
+
+		history, err := source.GetBefore(ctx, candle.Symbol, timeframe, cutoff.Add(time.Microsecond), engine.config.MaxHistoryPeriods)
+		if err != nil {
+			engine.logger.Debug().Err(err).
+				Str("symbol", candle.Symbol).
+				Str("timeframe", timeframe).
+				Msg("Higher timeframe levels unavailable")
+			continue
+		}
+
+		detected := engine.supportAnalyzer.DetectLevels(history)
+		for _, group := range [][]*analysis.SupportResistanceLevel{detected.Support, detected.Resistance} {
+			for _, level := range group {
+				candidates = append(candidates, analysis.LevelCandidate{
+					Price:    level.Price,
+					Strength: level.Strength,
+					Source:   timeframe,
+				})
+			}
+		}
+	}
+
+	return candidates
+}
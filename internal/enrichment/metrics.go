@@ -0,0 +1,468 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-269: Enrichment latency histograms per component and stream
+
+const (
+	// DefaultMetricsWindow is the rolling window of the latency summaries
+	DefaultMetricsWindow = 5 * time.Minute
+
+	// MaxMetricsWindow bounds the rolling window; latencies are kept per minute
+	MaxMetricsWindow = time.Hour
+
+	windowSlots = int(MaxMetricsWindow / time.Minute)
+)
+
+// latencyBuckets are the upper bounds of the histogram buckets in milliseconds
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// analysisComponentNames are the components reported as analysis latency
+var analysisComponentNames = map[string]bool{
+	"candlestick_patterns": true,
+	"chart_patterns":       true,
+	"market_regime":        true,
+	"support_resistance":   true,
+	"volume_profile":       true,
+}
+
+// latencyCounts counts latencies per bucket, the last bucket unbounded
+type latencyCounts struct {
+	buckets []int64
+	count   int64
+	sum     float64
+	max     float64
+}
+
+func newLatencyCounts() latencyCounts {
+	return latencyCounts{buckets: make([]int64, len(latencyBuckets)+1)}
+}
+
+func (c *latencyCounts) observe(ms float64) {
+	c.buckets[sort.SearchFloat64s(latencyBuckets, ms)]++
+	c.count++
+	c.sum += ms
+	if ms > c.max {
+		c.max = ms
+	}
+}
+
+func (c *latencyCounts) add(other *latencyCounts) {
+	for i, n := range other.buckets {
+		c.buckets[i] += n
+	}
+	c.count += other.count
+	c.sum += other.sum
+	if other.max > c.max {
+		c.max = other.max
+	}
+}
+
+// quantile estimates a quantile by interpolating within its bucket, bounded
+// by the largest latency seen
+func (c *latencyCounts) quantile(q float64) float64 {
+	if c.count == 0 {
+		return 0
+	}
+
+	rank := q * float64(c.count)
+	var cumulative int64
+	for i, n := range c.buckets {
+		if n == 0 {
+			continue
+		}
+		if float64(cumulative+n) >= rank {
+			lower := 0.0
+			if i > 0 {
+				lower = latencyBuckets[i-1]
+			}
+			upper := c.max
+			if i < len(latencyBuckets) && latencyBuckets[i] < upper {
+				upper = latencyBuckets[i]
+			}
+			return lower + (upper-lower)*(rank-float64(cumulative))/float64(n)
+		}
+		cumulative += n
+	}
+	return c.max
+}
+
+func (c *latencyCounts) summary() models.LatencySummary {
+	if c.count == 0 {
+		return models.LatencySummary{}
+	}
+	return models.LatencySummary{
+		Count:  c.count,
+		MeanMs: c.sum / float64(c.count),
+		P50Ms:  c.quantile(0.50),
+		P95Ms:  c.quantile(0.95),
+		P99Ms:  c.quantile(0.99),
+		MaxMs:  c.max,
+	}
+}
+
+// latencyHistogram keeps cumulative counts for Prometheus and counts per
+// minute for the rolling window
+type latencyHistogram struct {
+	total   latencyCounts
+	minutes [windowSlots]latencyCounts
+	stamps  [windowSlots]int64 // Unix minute of each slot
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{total: newLatencyCounts()}
+}
+
+func (h *latencyHistogram) observe(now time.Time, ms float64) {
+	minute := now.Unix() / 60
+	slot := int(minute % int64(windowSlots))
+	if h.minutes[slot].buckets == nil || h.stamps[slot] != minute {
+		h.minutes[slot] = newLatencyCounts()
+		h.stamps[slot] = minute
+	}
+
+	h.minutes[slot].observe(ms)
+	h.total.observe(ms)
+}
+
+// window adds the counts of the minutes within the window into counts
+func (h *latencyHistogram) window(now time.Time, window time.Duration, counts *latencyCounts) {
+	minute := now.Unix() / 60
+	first := minute - int64((window+time.Minute-1)/time.Minute)
+
+	for slot := range h.minutes {
+		if h.minutes[slot].buckets != nil && h.stamps[slot] > first && h.stamps[slot] <= minute {
+			counts.add(&h.minutes[slot])
+		}
+	}
+}
+
+// unrecordedKey marks the contexts of enrichments left out of the metrics
+type unrecordedKey struct{}
+
+// WithoutMetrics returns a context whose enrichments are left out of the
+// enrichment counts and latencies: those made for another enrichment or for
+// a batch such as a screen, which would otherwise count as stream enrichments
+func WithoutMetrics(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unrecordedKey{}, true)
+}
+
+// recorded reports whether the enrichments of a context count in the metrics
+func recorded(ctx context.Context) bool {
+	unrecorded, _ := ctx.Value(unrecordedKey{}).(bool)
+	return !unrecorded
+}
+
+// enrichmentStats collects the enrichment counters and latencies
+type enrichmentStats struct {
+	total       int64
+	errors      int64
+	partial     int64
+	cacheHits   int64
+	cacheMisses int64
+	lastError   string
+	lastUpdated time.Time
+
+	enrichments *latencyHistogram
+	streams     map[string]*latencyHistogram // by symbol:timeframe
+	components  map[string]*latencyHistogram
+	skipped     map[string]int64
+	failed      map[string]int64
+
+	mu sync.Mutex
+}
+
+func newEnrichmentStats() *enrichmentStats {
+	return &enrichmentStats{
+		enrichments: newLatencyHistogram(),
+		streams:     make(map[string]*latencyHistogram),
+		components:  make(map[string]*latencyHistogram),
+		skipped:     make(map[string]int64),
+		failed:      make(map[string]int64),
+	}
+}
+
+// recordEnrichment records an enrichment of a symbol:timeframe, empty when unknown
+func (s *enrichmentStats) recordEnrichment(key string, duration time.Duration, err error) {
+	now := time.Now()
+	ms := float64(duration.Nanoseconds()) / 1e6
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.total++
+	s.lastUpdated = now
+	if err != nil {
+		s.errors++
+		s.lastError = err.Error()
+	}
+
+	s.enrichments.observe(now, ms)
+	if key != "" {
+		histogram := s.streams[key]
+		if histogram == nil {
+			histogram = newLatencyHistogram()
+			s.streams[key] = histogram
+		}
+		histogram.observe(now, ms)
+	}
+}
+
+// removeStream drops the latencies of a stream no longer tracked
+func (s *enrichmentStats) removeStream(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.streams, key)
+}
+
+func (s *enrichmentStats) recordComponent(name string, duration time.Duration) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	histogram := s.components[name]
+	if histogram == nil {
+		histogram = newLatencyHistogram()
+		s.components[name] = histogram
+	}
+	histogram.observe(now, float64(duration.Nanoseconds())/1e6)
+}
+
+func (s *enrichmentStats) recordPartial(skipped, failed []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.partial++
+	for _, name := range skipped {
+		s.skipped[name]++
+	}
+	for _, name := range failed {
+		s.failed[name]++
+	}
+}
+
+func (s *enrichmentStats) recordCache(hit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if hit {
+		s.cacheHits++
+	} else {
+		s.cacheMisses++
+	}
+}
+
+// cacheHitRate returns the share of cache lookups served from the cache
+func (s *enrichmentStats) cacheHitRate() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return hitRate(s.cacheHits, s.cacheMisses)
+}
+
+func hitRate(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// Metrics returns the enrichment counters with the latencies of the rolling
+// window; zero selects DefaultMetricsWindow, longer windows are capped at
+// MaxMetricsWindow
+func (engine *CandleEnrichmentEngine) Metrics(window time.Duration) *models.EnrichmentMetrics {
+	if window <= 0 {
+		window = DefaultMetricsWindow
+	}
+	if window > MaxMetricsWindow {
+		window = MaxMetricsWindow
+	}
+
+	// Read before locking: it stops the world
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+
+	now := time.Now()
+	s := engine.stats
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	metrics := &models.EnrichmentMetrics{
+		MemoryUsageMB:      float64(memory.HeapAlloc) / (1024 * 1024),
+		GoroutineCount:     runtime.NumGoroutine(),
+		TotalEnrichments:   s.total,
+		PartialEnrichments: s.partial,
+		CacheHits:          s.cacheHits,
+		CacheMisses:        s.cacheMisses,
+		LastError:          s.lastError,
+		ErrorCount:         s.errors,
+		LastUpdated:        s.lastUpdated,
+		WindowMinutes:      int((window + time.Minute - 1) / time.Minute),
+		ComponentLatency:   make(map[string]models.LatencySummary, len(s.components)),
+		StreamLatency:      make(map[string]models.LatencySummary, len(s.streams)),
+		SkippedComponents:  make(map[string]int64, len(s.skipped)),
+		FailedComponents:   make(map[string]int64, len(s.failed)),
+	}
+
+	if s.total > 0 {
+		metrics.ErrorRate = float64(s.errors) / float64(s.total)
+		metrics.SuccessRate = 1 - metrics.ErrorRate
+	}
+	metrics.CacheHitRate = hitRate(s.cacheHits, s.cacheMisses)
+
+	counts := newLatencyCounts()
+	s.enrichments.window(now, window, &counts)
+	metrics.Latency = counts.summary()
+	metrics.AverageLatencyMs = metrics.Latency.MeanMs
+	metrics.LatencyP95Ms = metrics.Latency.P95Ms
+	metrics.MaxLatencyMs = metrics.Latency.MaxMs
+
+	for key, histogram := range s.streams {
+		counts := newLatencyCounts()
+		histogram.window(now, window, &counts)
+		if counts.count > 0 {
+			metrics.StreamLatency[key] = counts.summary()
+		}
+	}
+
+	analysis, signals := newLatencyCounts(), newLatencyCounts()
+	for name, histogram := range s.components {
+		counts := newLatencyCounts()
+		histogram.window(now, window, &counts)
+		if counts.count == 0 {
+			continue
+		}
+		metrics.ComponentLatency[name] = counts.summary()
+
+		switch {
+		case name == "indicators":
+			metrics.IndicatorLatencyMs = counts.summary().MeanMs
+		case name == "signals" || strings.HasPrefix(name, "signals:"):
+			signals.add(&counts)
+		case analysisComponentNames[name]:
+			analysis.add(&counts)
+		}
+	}
+	metrics.AnalysisLatencyMs = analysis.summary().MeanMs
+	metrics.SignalLatencyMs = signals.summary().MeanMs
+
+	for name, count := range s.skipped {
+		metrics.SkippedComponents[name] = count
+	}
+	for name, count := range s.failed {
+		metrics.FailedComponents[name] = count
+	}
+
+	return metrics
+}
+
+// WritePrometheus writes the enrichment counters and cumulative latency
+// histograms in the Prometheus text exposition format
+func (engine *CandleEnrichmentEngine) WritePrometheus(w io.Writer) error {
+	s := engine.stats
+	var b strings.Builder
+
+	s.mu.Lock()
+
+	writeCounter(&b, "jonbu_enrichments_total", "Candle enrichments performed.", s.total)
+	writeCounter(&b, "jonbu_enrichment_errors_total", "Candle enrichments that failed.", s.errors)
+	writeCounter(&b, "jonbu_enrichment_partial_total", "Candle enrichments cut off by the deadline.", s.partial)
+	writeCounter(&b, "jonbu_enrichment_cache_hits_total", "Higher timeframe states served from the cache.", s.cacheHits)
+	writeCounter(&b, "jonbu_enrichment_cache_misses_total", "Higher timeframe states loaded from stored candles.", s.cacheMisses)
+
+	const streamFamily = "jonbu_enrichment_duration_seconds"
+	writeHeader(&b, streamFamily, "Candle enrichment latency by symbol and timeframe.", "histogram")
+	for _, key := range sortedKeys(s.streams) {
+		symbol, timeframe, _ := strings.Cut(key, ":")
+		writeHistogram(&b, streamFamily, fmt.Sprintf(`symbol="%s",timeframe="%s"`,
+			escapeLabel(symbol), escapeLabel(timeframe)), &s.streams[key].total)
+	}
+
+	const componentFamily = "jonbu_enrichment_component_duration_seconds"
+	writeHeader(&b, componentFamily, "Enrichment component latency.", "histogram")
+	for _, name := range sortedKeys(s.components) {
+		writeHistogram(&b, componentFamily, fmt.Sprintf(`component="%s"`, escapeLabel(name)), &s.components[name].total)
+	}
+
+	const skippedFamily = "jonbu_enrichment_component_skipped_total"
+	writeHeader(&b, skippedFamily, "Enrichment components cut off by the deadline.", "counter")
+	skipped := make([]string, 0, len(s.skipped))
+	for name := range s.skipped {
+		skipped = append(skipped, name)
+	}
+	sort.Strings(skipped)
+	for _, name := range skipped {
+		fmt.Fprintf(&b, "%s{component=\"%s\"} %d\n", skippedFamily, escapeLabel(name), s.skipped[name])
+	}
+
+	const failedFamily = "jonbu_enrichment_component_failed_total"
+	writeHeader(&b, failedFamily, "Enrichment components that failed.", "counter")
+	failed := make([]string, 0, len(s.failed))
+	for name := range s.failed {
+		failed = append(failed, name)
+	}
+	sort.Strings(failed)
+	for _, name := range failed {
+		fmt.Fprintf(&b, "%s{component=\"%s\"} %d\n", failedFamily, escapeLabel(name), s.failed[name])
+	}
+
+	s.mu.Unlock()
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeHeader(b *strings.Builder, name, help, kind string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeCounter(b *strings.Builder, name, help string, value int64) {
+	writeHeader(b, name, help, "counter")
+	fmt.Fprintf(b, "%s %d\n", name, value)
+}
+
+// writeHistogram writes cumulative buckets with bounds in seconds
+func writeHistogram(b *strings.Builder, name, labels string, counts *latencyCounts) {
+	var cumulative int64
+	for i, bound := range latencyBuckets {
+		cumulative += counts.buckets[i]
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatFloat(bound/1000), cumulative)
+	}
+	fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, counts.count)
+	fmt.Fprintf(b, "%s_sum{%s} %s\n", name, labels, formatFloat(counts.sum/1000))
+	fmt.Fprintf(b, "%s_count{%s} %d\n", name, labels, counts.count)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys(histograms map[string]*latencyHistogram) []string {
+	keys := make([]string, 0, len(histograms))
+	for key := range histograms {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
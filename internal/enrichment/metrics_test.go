@@ -0,0 +1,126 @@
+package enrichment
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+func TestLatencyHistogramWindow(t *testing.T) {
+	histogram := newLatencyHistogram()
+	now := time.Date(2024, 6, 3, 14, 30, 0, 0, time.UTC)
+
+	// 90 fast and 10 slow enrichments now, one very slow ten minutes ago
+	for i := 0; i < 90; i++ {
+		histogram.observe(now, 2)
+	}
+	for i := 0; i < 10; i++ {
+		histogram.observe(now, 80)
+	}
+	histogram.observe(now.Add(-10*time.Minute), 4000)
+
+	counts := newLatencyCounts()
+	histogram.window(now, 5*time.Minute, &counts)
+	summary := counts.summary()
+
+	if summary.Count != 100 || summary.MaxMs != 80 {
+		t.Fatalf("Expected the 100 latencies of the window, got %+v", summary)
+	}
+	if math.Abs(summary.MeanMs-9.8) > 1e-9 {
+		t.Errorf("Expected mean 9.8, got %v", summary.MeanMs)
+	}
+	if summary.P50Ms <= 1 || summary.P50Ms > 2.5 {
+		t.Errorf("Expected p50 within the 1-2.5ms bucket, got %v", summary.P50Ms)
+	}
+	if summary.P95Ms <= 50 || summary.P95Ms > 80 {
+		t.Errorf("Expected p95 between 50ms and the 80ms maximum, got %v", summary.P95Ms)
+	}
+
+	if histogram.total.count != 101 {
+		t.Errorf("Expected 101 cumulative latencies, got %d", histogram.total.count)
+	}
+
+	// The slot of a minute an hour later is reused
+	histogram.observe(now.Add(time.Hour), 1)
+	counts = newLatencyCounts()
+	histogram.window(now.Add(time.Hour), 5*time.Minute, &counts)
+	if counts.count != 1 {
+		t.Errorf("Expected the reused slot to hold one latency, got %d", counts.count)
+	}
+}
+
+func TestEnrichmentMetrics(t *testing.T) {
+	engine := NewCandleEnrichmentEngine(nil)
+
+	history := generateTestCandles(50)
+	current := &models.OHLCV{Symbol: "AAPL", Timeframe: "1m", Timestamp: time.Now(), Open: 100, High: 105, Low: 99, Close: 103, Volume: 10000}
+	options := &models.EnrichmentOptions{TrendIndicators: true, MarketRegime: true, TradingSignals: true}
+
+	if _, err := engine.EnrichCandle(context.Background(), current, history, options); err != nil {
+		t.Fatalf("Failed to enrich candle: %v", err)
+	}
+	if _, err := engine.EnrichCandle(context.Background(), current, history[:5], options); err == nil {
+		t.Fatal("Expected insufficient history to fail")
+	}
+
+	metrics := engine.Metrics(0)
+	if metrics.TotalEnrichments != 2 || metrics.ErrorCount != 1 || metrics.SuccessRate != 0.5 {
+		t.Errorf("Unexpected counters %+v", metrics)
+	}
+	if metrics.WindowMinutes != 5 || metrics.Latency.Count != 2 {
+		t.Errorf("Expected two latencies in a 5 minute window, got %d in %d", metrics.Latency.Count, metrics.WindowMinutes)
+	}
+	if metrics.StreamLatency["AAPL:1m"].Count != 2 {
+		t.Errorf("Expected the stream latencies, got %+v", metrics.StreamLatency)
+	}
+	for _, name := range []string{"indicators", "market_regime", "signals"} {
+		if metrics.ComponentLatency[name].Count != 1 {
+			t.Errorf("Expected one %s latency, got %+v", name, metrics.ComponentLatency[name])
+		}
+	}
+	if metrics.GoroutineCount == 0 || metrics.MemoryUsageMB == 0 {
+		t.Error("Expected resource metrics")
+	}
+
+	var b strings.Builder
+	if err := engine.WritePrometheus(&b); err != nil {
+		t.Fatalf("Failed to write Prometheus metrics: %v", err)
+	}
+	output := b.String()
+	for _, line := range []string{
+		"jonbu_enrichments_total 2",
+		"jonbu_enrichment_errors_total 1",
+		"# TYPE jonbu_enrichment_duration_seconds histogram",
+		`jonbu_enrichment_duration_seconds_bucket{symbol="AAPL",timeframe="1m",le="+Inf"} 2`,
+		`jonbu_enrichment_duration_seconds_count{symbol="AAPL",timeframe="1m"} 2`,
+		`jonbu_enrichment_component_duration_seconds_count{component="indicators"} 1`,
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("Prometheus output misses %q:\n%s", line, output)
+		}
+	}
+
+	// Enrichments made for another one are not counted
+	if _, err := engine.EnrichCandle(WithoutMetrics(context.Background()), current, history, options); err != nil {
+		t.Fatalf("Failed to enrich candle: %v", err)
+	}
+	metrics = engine.Metrics(0)
+	if metrics.TotalEnrichments != 2 || metrics.StreamLatency["AAPL:1m"].Count != 2 {
+		t.Errorf("Expected the unrecorded enrichment left out, got %d enrichments", metrics.TotalEnrichments)
+	}
+	for _, name := range []string{"indicators", "market_regime", "signals"} {
+		if metrics.ComponentLatency[name].Count != 1 {
+			t.Errorf("Expected the unrecorded %s latency left out, got %+v", name, metrics.ComponentLatency[name])
+		}
+	}
+
+	// Removed streams lose their latencies
+	engine.RemoveStream("AAPL", "1m")
+	if metrics := engine.Metrics(0); len(metrics.StreamLatency) != 0 || metrics.TotalEnrichments != 2 {
+		t.Errorf("Expected no stream latencies after the removal, got %+v", metrics.StreamLatency)
+	}
+}
@@ -0,0 +1,217 @@
+package enrichment
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/expression"
+	"github.com/ridopark/jonbu-ohlcv/internal/indicators"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-266: Named signal strategy profiles
+
+// signalProfile is a compiled signal profile definition
+type signalProfile struct {
+	definition models.SignalProfile
+	rules      []*signalRule
+	patterns   map[string]bool // normalized names, nil for all patterns
+	specs      []string        // registry indicators read by the rules
+}
+
+// signalRule is a compiled rule of a signal profile
+type signalRule struct {
+	definition models.SignalRule
+	program    *expression.Program
+}
+
+// compileSignalProfile validates a profile and compiles its rules
+func compileSignalProfile(registry *indicators.Registry, definition models.SignalProfile) (*signalProfile, error) {
+	if err := definition.Validate(); err != nil {
+		return nil, err
+	}
+
+	profile := &signalProfile{definition: definition}
+	seen := make(map[string]bool)
+
+	for _, rule := range definition.Rules {
+		program, err := expression.CompileWith(registry, rule.When)
+		if err != nil {
+			return nil, fmt.Errorf("signal profile %s: rule %s: %w", definition.Name, rule.Name, err)
+		}
+
+		for _, spec := range program.Specs() {
+			if !seen[spec] {
+				seen[spec] = true
+				profile.specs = append(profile.specs, spec)
+			}
+		}
+		profile.rules = append(profile.rules, &signalRule{definition: rule, program: program})
+	}
+
+	if len(definition.Patterns) > 0 {
+		profile.patterns = make(map[string]bool, len(definition.Patterns))
+		for _, pattern := range definition.Patterns {
+			profile.patterns[normalizePattern(pattern)] = true
+		}
+	}
+
+	return profile, nil
+}
+
+// SetSignalProfiles compiles signal profiles and replaces the current ones.
+// The built-in default profile stays available unless a profile named
+// "default" replaces it.
+func (engine *CandleEnrichmentEngine) SetSignalProfiles(definitions []models.SignalProfile) error {
+	profiles := make(map[string]*signalProfile, len(definitions)+1)
+
+	for _, definition := range definitions {
+		if _, exists := profiles[definition.Name]; exists {
+			return fmt.Errorf("duplicate signal profile: %s", definition.Name)
+		}
+
+		profile, err := compileSignalProfile(engine.registry, definition)
+		if err != nil {
+			return err
+		}
+		profiles[definition.Name] = profile
+	}
+
+	if _, exists := profiles[models.DefaultSignalProfile]; !exists {
+		profile, err := compileSignalProfile(engine.registry, models.BuiltinSignalProfile())
+		if err != nil {
+			return err
+		}
+		profiles[models.DefaultSignalProfile] = profile
+	}
+
+	engine.mu.Lock()
+	engine.profiles = profiles
+	engine.mu.Unlock()
+
+	return nil
+}
+
+// SignalProfiles returns the available signal profiles ordered by name
+func (engine *CandleEnrichmentEngine) SignalProfiles() []models.SignalProfile {
+	engine.mu.RLock()
+	defer engine.mu.RUnlock()
+
+	profiles := make([]models.SignalProfile, 0, len(engine.profiles))
+	for _, profile := range engine.profiles {
+		profiles = append(profiles, profile.definition)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+
+	return profiles
+}
+
+// SignalProfileNames returns the names of the available signal profiles
+func (engine *CandleEnrichmentEngine) SignalProfileNames() []string {
+	profiles := engine.SignalProfiles()
+	names := make([]string, len(profiles))
+	for i, profile := range profiles {
+		names[i] = profile.Name
+	}
+	return names
+}
+
+// HasSignalProfile reports whether a signal profile is available; empty
+// names select the default profile
+func (engine *CandleEnrichmentEngine) HasSignalProfile(name string) bool {
+	if name == "" {
+		name = models.DefaultSignalProfile
+	}
+
+	engine.mu.RLock()
+	defer engine.mu.RUnlock()
+
+	_, exists := engine.profiles[name]
+	return exists
+}
+
+// signalProfiles returns the compiled profiles selected by the options, the
+// profile of the trading signals first. Unknown names are skipped.
+func (engine *CandleEnrichmentEngine) signalProfiles(options *models.EnrichmentOptions) []*signalProfile {
+	engine.mu.RLock()
+	defer engine.mu.RUnlock()
+
+	var profiles []*signalProfile
+	for _, name := range profileNames(options) {
+		if profile := engine.profiles[name]; profile != nil {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// profileNames returns the distinct profile names selected by the options,
+// the profile of the trading signals first
+func profileNames(options *models.EnrichmentOptions) []string {
+	primary := options.SignalProfile
+	if primary == "" {
+		primary = models.DefaultSignalProfile
+	}
+
+	names := []string{primary}
+	seen := map[string]bool{primary: true}
+	for _, name := range options.SignalProfiles {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ruleScore returns the signed weight of the matching rules and the total
+// weight of all rules, recording every rule as a factor
+func (profile *signalProfile) ruleScore(enriched *models.EnrichedCandle, factors *signalFactors) (float64, float64) {
+	if len(profile.rules) == 0 {
+		return 0, 0
+	}
+
+	// Rules read the indicators and analysis, not signals of other profiles
+	candidate := *enriched
+	candidate.Signals = nil
+	candidate.ProfileSignals = nil
+	env := expression.NewCandleEnv(&candidate)
+
+	var values map[string]float64
+	if enriched.Indicators != nil {
+		values = enriched.Indicators.Values
+	}
+
+	var score, total float64
+	for _, rule := range profile.rules {
+		total += rule.definition.Weight
+
+		points := 0.0
+		matched := rule.program.Match(env)
+		if matched {
+			points = rule.definition.Weight * float64(signalDirection(rule.definition.Signal))
+			score += points
+		}
+
+		factors.addScore(models.FactorRule, rule.definition.Name,
+			ruleValue(matched, rule.program.Specs(), values),
+			fmt.Sprintf("%s is %s", rule.definition.When, rule.definition.Signal),
+			rule.definition.Weight, points)
+	}
+	return score, total
+}
+
+// allowsPattern reports whether a pattern contributes to the profile's signals
+func (profile *signalProfile) allowsPattern(name string) bool {
+	return profile.patterns == nil || profile.patterns[normalizePattern(name)]
+}
+
+// normalizePattern makes pattern names comparable: "Shooting Star",
+// "shooting_star" and "shooting-star" are equal
+func normalizePattern(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-'
+	}), "_")
+}
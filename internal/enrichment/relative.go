@@ -0,0 +1,64 @@
+package enrichment
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/indicators"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-263: Relative strength, beta and correlation against a benchmark
+
+// relativeData compares the candle's symbol with the benchmark over the
+// configured window, reading the benchmark candles up to the current one from
+// the history source. It returns nil when the benchmark cannot be compared.
+func (engine *CandleEnrichmentEngine) relativeData(
+	ctx context.Context,
+	current *models.OHLCV,
+	history []*models.OHLCV,
+	benchmark string,
+) *models.RelativeData {
+
+	engine.mu.RLock()
+	source := engine.historySource
+	engine.mu.RUnlock()
+
+	benchmark = strings.ToUpper(benchmark)
+	if source == nil || strings.EqualFold(current.Symbol, benchmark) {
+		return nil
+	}
+
+	periods := engine.config.RelativePeriods
+	benchmarkCandles, err := source.GetBefore(ctx, benchmark, current.Timeframe, current.Timestamp.Add(time.Microsecond), periods+1)
+	if err != nil {
+		engine.logger.Debug().Err(err).
+			Str("symbol", current.Symbol).
+			Str("benchmark", benchmark).
+			Msg("Benchmark history unavailable")
+		return nil
+	}
+
+	asset := history
+	if len(asset) > periods {
+		asset = asset[len(asset)-periods:]
+	}
+	asset = append(asset[:len(asset):len(asset)], current)
+
+	assetCloses, benchmarkCloses := indicators.AlignedCloses(asset, benchmarkCandles)
+	relative := indicators.Relative(assetCloses, benchmarkCloses, periods, engine.config.MinHistoryPeriods)
+	if relative == nil {
+		return nil
+	}
+
+	return &models.RelativeData{
+		Benchmark:        benchmark,
+		Periods:          periods,
+		RelativeStrength: relative.RelativeStrength,
+		ExcessReturn:     relative.ExcessReturn,
+		Beta:             relative.Beta,
+		Correlation:      relative.Correlation,
+		Observations:     relative.Observations,
+	}
+}
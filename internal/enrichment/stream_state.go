@@ -0,0 +1,251 @@
+package enrichment
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/indicators"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-254: Per-stream incremental indicator state
+// REQ-255: Registry indicators tracked per stream
+// REQ-265: Anchored VWAPs maintained per stream
+
+// StreamState holds the incremental indicators and the recent candle window
+// of a single symbol:timeframe
+type StreamState struct {
+	indicators *indicators.IndicatorState
+	specs      *indicators.SpecSet
+	window     []*models.OHLCV
+	windowSize int // candles handed to pattern and level analysis
+	retainSize int // candles kept to warm up newly requested specs
+	anchors    map[int64]*streamAnchor
+	mu         sync.Mutex
+}
+
+// streamAnchor is the live VWAP of a registered anchor
+type streamAnchor struct {
+	anchor *models.VWAPAnchor
+	vwap   *indicators.AnchoredVWAP
+}
+
+// Update applies the next candle in O(1). Candles that are not newer than the
+// latest one are ignored and reported with false.
+func (s *StreamState) Update(candle *models.OHLCV) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last := s.indicators.Last(); last != nil && !candle.Timestamp.After(last.Timestamp) {
+		return false
+	}
+
+	s.indicators.Update(candle)
+	s.specs.Update(candle)
+	for _, anchored := range s.anchors {
+		anchored.vwap.Update(candle)
+	}
+	s.window = append(s.window, candle)
+
+	// Compact once the window doubles so trimming stays amortized O(1)
+	if len(s.window) >= 2*s.retainSize {
+		trimmed := make([]*models.OHLCV, s.retainSize, 2*s.retainSize)
+		copy(trimmed, s.window[len(s.window)-s.retainSize:])
+		s.window = trimmed
+	}
+
+	return true
+}
+
+// Last returns the latest candle of the stream
+func (s *StreamState) Last() *models.OHLCV {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.indicators.Last()
+}
+
+// Count returns the number of candles applied since the warm start
+func (s *StreamState) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.indicators.Count()
+}
+
+// SetAnchor starts or replaces the anchored VWAP of an anchor from the stored
+// candles since the anchor (chronological). Candles the stream received after
+// the stored ones are replayed from the window.
+func (s *StreamState) SetAnchor(anchor *models.VWAPAnchor, history []*models.OHLCV) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vwap := indicators.NewAnchoredVWAP(anchor.AnchorAt)
+	var last *models.OHLCV
+	for _, candle := range history {
+		if latest := s.indicators.Last(); latest != nil && candle.Timestamp.After(latest.Timestamp) {
+			break
+		}
+		vwap.Update(candle)
+		last = candle
+	}
+	for _, candle := range s.window {
+		if last == nil || candle.Timestamp.After(last.Timestamp) {
+			vwap.Update(candle)
+		}
+	}
+
+	if s.anchors == nil {
+		s.anchors = make(map[int64]*streamAnchor)
+	}
+	s.anchors[anchor.ID] = &streamAnchor{anchor: anchor, vwap: vwap}
+}
+
+// HasAnchor reports whether the stream maintains the VWAP of an anchor
+func (s *StreamState) HasAnchor(id int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.anchors[id]
+	return exists
+}
+
+// RetainAnchors drops the anchored VWAPs whose anchor is not in ids
+func (s *StreamState) RetainAnchors(ids map[int64]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.anchors {
+		if !ids[id] {
+			delete(s.anchors, id)
+		}
+	}
+}
+
+// anchoredVWAPs returns the VWAPs of the anchors that saw volume, oldest
+// anchor first. The caller must hold mu.
+func (s *StreamState) anchoredVWAPs() []models.AnchoredVWAP {
+	var result []models.AnchoredVWAP
+	for _, anchored := range s.anchors {
+		bands := anchored.vwap.Bands()
+		if bands == nil {
+			continue
+		}
+		result = append(result, models.AnchoredVWAP{
+			AnchorID:  anchored.anchor.ID,
+			Name:      anchored.anchor.Name,
+			Event:     anchored.anchor.Event,
+			AnchorAt:  anchored.anchor.AnchorAt,
+			VWAPBands: bands,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].AnchorAt.Equal(result[j].AnchorAt) {
+			return result[i].AnchorAt.Before(result[j].AnchorAt)
+		}
+		return result[i].AnchorID < result[j].AnchorID
+	})
+	return result
+}
+
+// specValues returns registry indicator values, starting to track specs that
+// were not requested before by replaying the retained candles. The caller must
+// hold mu.
+func (s *StreamState) specValues(specs []string) (map[string]float64, error) {
+	if err := s.specs.Ensure(specs, s.window); err != nil {
+		return nil, err
+	}
+	return s.specs.Values(specs)
+}
+
+// recent returns up to windowSize of the latest candles. The caller must hold
+// mu; the returned slice is never written to afterwards.
+func (s *StreamState) recent() []*models.OHLCV {
+	if len(s.window) <= s.windowSize {
+		return s.window[:len(s.window):len(s.window)]
+	}
+	return s.window[len(s.window)-s.windowSize : len(s.window) : len(s.window)]
+}
+
+// StreamStateManager keeps one StreamState per symbol:timeframe
+type StreamStateManager struct {
+	states     map[string]*StreamState
+	windowSize int
+	retainSize int
+	registry   *indicators.Registry
+	mu         sync.RWMutex
+}
+
+// NewStreamStateManager creates a manager whose streams hand windowSize recent
+// candles to pattern and level analysis and retain retainSize candles to warm
+// up registry indicators requested later
+func NewStreamStateManager(windowSize, retainSize int) *StreamStateManager {
+	if windowSize < 1 {
+		windowSize = 1
+	}
+	if retainSize < windowSize {
+		retainSize = windowSize
+	}
+	return &StreamStateManager{
+		states:     make(map[string]*StreamState),
+		windowSize: windowSize,
+		retainSize: retainSize,
+		registry:   indicators.DefaultRegistry(),
+	}
+}
+
+// Get returns the state of a stream, or nil if it was not warm-started yet
+func (m *StreamStateManager) Get(symbol, timeframe string) *StreamState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.states[streamKey(symbol, timeframe)]
+}
+
+// WarmStart creates the state of a stream from stored candles in chronological
+// order. If another caller already warmed the stream, its state is returned.
+func (m *StreamStateManager) WarmStart(symbol, timeframe string, history []*models.OHLCV) *StreamState {
+	state := &StreamState{
+		indicators: indicators.NewIndicatorState(),
+		specs:      indicators.NewSpecSet(m.registry),
+		window:     make([]*models.OHLCV, 0, 2*m.retainSize),
+		windowSize: m.windowSize,
+		retainSize: m.retainSize,
+	}
+	for _, candle := range history {
+		state.Update(candle)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := streamKey(symbol, timeframe)
+	if existing, exists := m.states[key]; exists {
+		return existing
+	}
+	m.states[key] = state
+
+	return state
+}
+
+// Remove drops the state of a stream
+func (m *StreamStateManager) Remove(symbol, timeframe string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.states, streamKey(symbol, timeframe))
+}
+
+// Len returns the number of tracked streams
+func (m *StreamStateManager) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.states)
+}
+
+// streamKey matches the hub's symbol:timeframe subscription key
+func streamKey(symbol, timeframe string) string {
+	return symbol + ":" + timeframe
+}
@@ -0,0 +1,105 @@
+package enrichment
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-275: Stop and target selection at Fibonacci and support/resistance levels
+
+const (
+	// minLevelDistance is the distance from the entry, in ATR, a stop or
+	// target level must have to not be hit by noise
+	minLevelDistance = 0.5
+
+	// stopBuffer places the stop this many ATR beyond its level
+	stopBuffer = 0.25
+
+	// maxTargets is the number of take profit levels
+	maxTargets = 3
+
+	// minPercentDistance stands in for the minimum distance without an ATR,
+	// as a share of the entry
+	minPercentDistance = 0.002
+)
+
+// selectTradeLevels sets the entry, stop and targets of a directional signal:
+// the stop beyond the nearest level behind the entry, the targets at the
+// nearest levels ahead of it
+func selectTradeLevels(enriched *models.EnrichedCandle, signals *models.TradingSignals) {
+	direction := float64(signalDirection(signals.OverallSignal))
+	if direction == 0 || enriched.OHLCV == nil || enriched.Analysis == nil {
+		return
+	}
+
+	entry := enriched.OHLCV.Close
+	var atr float64
+	if enriched.Indicators != nil {
+		atr = enriched.Indicators.ATR
+	}
+	minDistance := entry * minPercentDistance
+	if atr > 0 {
+		minDistance = atr * minLevelDistance
+	}
+
+	// Signed distances ahead of the entry in the signal's direction
+	var ahead, behind []float64
+	for _, price := range tradeLevelPrices(enriched.Analysis) {
+		distance := direction * (price - entry)
+		switch {
+		case distance >= minDistance:
+			ahead = append(ahead, distance)
+		case distance <= -minDistance:
+			behind = append(behind, -distance)
+		}
+	}
+	if len(ahead) == 0 && len(behind) == 0 {
+		return
+	}
+
+	signals.EntryLevel = entry
+
+	if len(behind) > 0 {
+		sort.Float64s(behind)
+		signals.StopLoss = entry - direction*(behind[0]+atr*stopBuffer)
+	}
+
+	sort.Float64s(ahead)
+	for _, distance := range ahead {
+		if len(signals.TakeProfit) == maxTargets {
+			break
+		}
+		// Levels within the minimum distance of the previous target add nothing
+		target := entry + direction*distance
+		if n := len(signals.TakeProfit); n > 0 && math.Abs(target-signals.TakeProfit[n-1]) < minDistance {
+			continue
+		}
+		signals.TakeProfit = append(signals.TakeProfit, target)
+	}
+}
+
+// tradeLevelPrices collects the Fibonacci and support/resistance prices of
+// the analysis
+func tradeLevelPrices(analysis *models.MarketAnalysis) []float64 {
+	var prices []float64
+	if fibonacci := analysis.Fibonacci; fibonacci != nil {
+		prices = append(prices, fibonacci.StartPrice, fibonacci.EndPrice)
+		for _, level := range fibonacci.Retracements {
+			prices = append(prices, level.Price)
+		}
+		for _, level := range fibonacci.Extensions {
+			prices = append(prices, level.Price)
+		}
+	}
+	if levels := analysis.SupportResistance; levels != nil {
+		for _, level := range levels.Support {
+			prices = append(prices, level.Price)
+		}
+		for _, level := range levels.Resistance {
+			prices = append(prices, level.Price)
+		}
+	}
+	return prices
+}
@@ -0,0 +1,176 @@
+package enrichment
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/analysis"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-273: Volatility regimes of a hidden Markov model fit per symbol
+
+// RegimeStates is the number of volatility regimes the models distinguish
+const RegimeStates = 3
+
+// Sources of the model behind a volatility regime
+const (
+	regimeModelStored = "stored"
+	regimeModelWindow = "window"
+)
+
+// windowRegimeModel is a model fit on a window of candles, reused until half
+// of the window has turned over so that its states stay stable between candles
+type windowRegimeModel struct {
+	model *analysis.VolatilityHMM
+	end   time.Time     // last candle of the window
+	span  time.Duration // first to last candle of the window
+}
+
+// SetRegimeModel replaces the volatility regime model of a symbol and stored
+// timeframe, usually fit on more stored returns than a stream window holds
+func (engine *CandleEnrichmentEngine) SetRegimeModel(symbol, timeframe string, model *analysis.VolatilityHMM) {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+
+	if engine.regimeModels == nil {
+		engine.regimeModels = make(map[string]*analysis.VolatilityHMM)
+	}
+	key := streamKey(symbol, timeframe)
+	engine.regimeModels[key] = model
+	delete(engine.windowModels, key)
+}
+
+// volatilityRegime filters the returns of the candles with the stored model
+// of their symbol and timeframe, or with a window model when there is none.
+// Returns nil when neither is possible.
+func (engine *CandleEnrichmentEngine) volatilityRegime(candles []*models.OHLCV) *models.VolatilityRegime {
+	if len(candles) < 2 {
+		return nil
+	}
+
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close
+	}
+	returns := analysis.LogReturns(closes)
+
+	current := candles[len(candles)-1]
+	key := streamKey(current.Symbol, current.Timeframe)
+	engine.mu.RLock()
+	model := engine.regimeModels[key]
+	engine.mu.RUnlock()
+
+	source := regimeModelStored
+	if model == nil {
+		if model = engine.windowRegimeModel(key, candles, returns); model == nil {
+			return nil
+		}
+		source = regimeModelWindow
+	}
+
+	state := model.Filter(returns)
+	if state == nil {
+		return nil
+	}
+
+	return &models.VolatilityRegime{
+		Regime:           state.Regime,
+		Probability:      state.Probability,
+		Probabilities:    state.Probabilities,
+		ExpectedDuration: state.ExpectedDuration,
+		Duration:         state.Duration,
+		Volatility:       state.Volatility,
+		Model:            source,
+		Samples:          model.Samples,
+	}
+}
+
+// windowRegimeModel returns the cached window model of a stream, fitting it
+// again on the candles once half of its window has turned over
+func (engine *CandleEnrichmentEngine) windowRegimeModel(key string, candles []*models.OHLCV, returns []float64) *analysis.VolatilityHMM {
+	end := candles[len(candles)-1].Timestamp
+
+	engine.mu.RLock()
+	cached := engine.windowModels[key]
+	engine.mu.RUnlock()
+
+	if cached != nil {
+		age := end.Sub(cached.end)
+		if age < 0 {
+			age = -age
+		}
+		if age <= cached.span/2 {
+			return cached.model
+		}
+	}
+
+	model, err := analysis.FitVolatilityHMM(returns, RegimeStates)
+	if err != nil {
+		return nil
+	}
+
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+
+	// A stored model set meanwhile takes over
+	if engine.regimeModels[key] == nil {
+		if engine.windowModels == nil {
+			engine.windowModels = make(map[string]*windowRegimeModel)
+		}
+		engine.windowModels[key] = &windowRegimeModel{model: model, end: end, span: end.Sub(candles[0].Timestamp)}
+	}
+	return model
+}
+
+// RegimeTracker remembers the volatility regime of every stream to report
+// when it changes
+type RegimeTracker struct {
+	regimes map[string]string
+	mu      sync.Mutex
+}
+
+// NewRegimeTracker creates an empty regime tracker
+func NewRegimeTracker() *RegimeTracker {
+	return &RegimeTracker{regimes: make(map[string]string)}
+}
+
+// Remove forgets the regime of a stream that is no longer tracked
+func (t *RegimeTracker) Remove(symbol, timeframe string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.regimes, streamKey(symbol, timeframe))
+}
+
+// Observe records the volatility regime of an enriched candle and returns the
+// change from the stream's previous regime. The first regime of a stream and
+// candles without one are not changes. Only regimes of stored models count:
+// window models are refit as the window moves, so their states shift.
+func (t *RegimeTracker) Observe(enriched *models.EnrichedCandle) *models.RegimeChange {
+	if enriched == nil || enriched.OHLCV == nil || enriched.Analysis == nil || enriched.Analysis.VolatilityRegime == nil ||
+		enriched.Analysis.VolatilityRegime.Model != regimeModelStored {
+		return nil
+	}
+	candle := enriched.OHLCV
+	regime := enriched.Analysis.VolatilityRegime
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := streamKey(candle.Symbol, candle.Timeframe)
+	previous := t.regimes[key]
+	t.regimes[key] = regime.Regime
+	if previous == "" || previous == regime.Regime {
+		return nil
+	}
+
+	return &models.RegimeChange{
+		Symbol:    candle.Symbol,
+		Timeframe: candle.Timeframe,
+		Timestamp: candle.Timestamp,
+		From:      previous,
+		To:        regime.Regime,
+		Regime:    regime,
+	}
+}
@@ -0,0 +1,313 @@
+package expression
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/indicators"
+)
+
+// Type is the static type of an expression
+type Type int
+
+const (
+	TypeNumber Type = iota
+	TypeString
+	TypeBool
+)
+
+// String returns the type name used in error messages
+func (t Type) String() string {
+	switch t {
+	case TypeNumber:
+		return "number"
+	case TypeString:
+		return "string"
+	default:
+		return "condition"
+	}
+}
+
+// patternFunction is the built-in candlestick pattern test, e.g. pattern("hammer")
+const patternFunction = "pattern"
+
+// Program is a parsed and type-checked condition
+type Program struct {
+	source string
+	root   evaluator
+	specs  []string
+}
+
+// compiler type-checks a parse tree and builds its evaluator
+type compiler struct {
+	source   string
+	registry *indicators.Registry
+	specs    []string
+	seen     map[string]bool
+}
+
+// compiled is a type-checked subexpression
+type compiled struct {
+	typ  Type
+	eval evaluator
+}
+
+// Compile parses and type-checks a condition against the default indicator registry
+func Compile(source string) (*Program, error) {
+	return CompileWith(indicators.DefaultRegistry(), source)
+}
+
+// CompileWith parses and type-checks a condition. Indicator calls must use
+// registered indicators with literal parameters; the result must be a condition.
+func CompileWith(registry *indicators.Registry, source string) (*Program, error) {
+	root, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &compiler{source: source, registry: registry, seen: make(map[string]bool)}
+	result, err := c.compile(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.typ != TypeBool {
+		return nil, newError(source, root.position(),
+			"expression is a %s, but a condition is required, e.g. rsi(14) < 30", result.typ)
+	}
+
+	return &Program{source: source, root: result.eval, specs: c.specs}, nil
+}
+
+// Source returns the expression text
+func (p *Program) Source() string { return p.source }
+
+// Specs returns the canonical indicator specs the expression reads, for
+// example ["rsi(14)", "sma(200)"]
+func (p *Program) Specs() []string { return append([]string(nil), p.specs...) }
+
+// Match evaluates the condition. Values that are not available yet, such as
+// indicators still warming up, make the comparisons that read them unknown;
+// an unknown result does not match.
+func (p *Program) Match(env Env) bool {
+	result := p.root(env)
+	return result.known && result.b
+}
+
+func (c *compiler) errorf(pos int, format string, args ...interface{}) *Error {
+	return newError(c.source, pos, format, args...)
+}
+
+func (c *compiler) compile(n node) (compiled, error) {
+	switch n := n.(type) {
+	case *numberNode:
+		return compiled{TypeNumber, constant(value{known: true, num: n.value})}, nil
+	case *stringNode:
+		return compiled{TypeString, constant(value{known: true, str: n.value})}, nil
+	case *boolNode:
+		return compiled{TypeBool, constant(value{known: true, b: n.value})}, nil
+	case *nameNode:
+		return c.compileName(n)
+	case *callNode:
+		return c.compileCall(n)
+	case *unaryNode:
+		return c.compileUnary(n)
+	case *binaryNode:
+		return c.compileBinary(n)
+	default:
+		return compiled{}, c.errorf(n.position(), "unsupported expression")
+	}
+}
+
+// compileName resolves a field, or an indicator with default parameters
+func (c *compiler) compileName(n *nameNode) (compiled, error) {
+	if field, exists := fields[n.name]; exists {
+		return compiled{field.typ, fieldEvaluator(n.name, field.typ)}, nil
+	}
+
+	if _, exists := c.registry.Lookup(n.name); exists {
+		return c.compileCall(&callNode{pos: n.pos, name: n.name})
+	}
+
+	if n.name == patternFunction {
+		return compiled{}, c.errorf(n.pos, "pattern needs a name, e.g. pattern(\"hammer\")")
+	}
+
+	return compiled{}, c.unknownName(n.pos, n.name)
+}
+
+// compileCall resolves a registry indicator or the pattern function
+func (c *compiler) compileCall(n *callNode) (compiled, error) {
+	if n.name == patternFunction {
+		return c.compilePattern(n)
+	}
+
+	definition, exists := c.registry.Lookup(n.name)
+	if !exists {
+		if _, isField := fields[n.name]; isField {
+			return compiled{}, c.errorf(n.pos, "%s is a field, not an indicator; use it without parentheses", n.name)
+		}
+		return compiled{}, c.unknownName(n.pos, n.name)
+	}
+
+	params := make([]string, len(n.args))
+	for i, arg := range n.args {
+		number, ok := literalNumber(arg)
+		if !ok {
+			return compiled{}, c.errorf(arg.position(), "parameters of %s must be numbers", n.name)
+		}
+		params[i] = strconv.FormatFloat(number, 'f', -1, 64)
+	}
+
+	spec, definition, err := c.registry.Resolve(n.name + "(" + strings.Join(params, ",") + ")")
+	if err != nil {
+		return compiled{}, c.errorf(n.pos, "%v", err)
+	}
+
+	keys := indicators.OutputKeys(spec, definition)
+	key := keys[0]
+	switch {
+	case n.output == "" && len(definition.Outputs) > 1:
+		return compiled{}, c.errorf(n.pos, "%s has several outputs (%s); select one, e.g. %s.%s",
+			spec.String(), strings.Join(definition.Outputs, ", "), spec.String(), definition.Outputs[0])
+
+	case n.output != "" && len(definition.Outputs) == 1:
+		return compiled{}, c.errorf(n.outputPos, "%s has a single output; remove .%s", spec.String(), n.output)
+
+	case n.output != "":
+		index := indexOf(definition.Outputs, n.output)
+		if index < 0 {
+			return compiled{}, c.errorf(n.outputPos, "%s has no output %q (outputs: %s)",
+				spec.String(), n.output, strings.Join(definition.Outputs, ", "))
+		}
+		key = keys[index]
+	}
+
+	if canonical := spec.String(); !c.seen[canonical] {
+		c.seen[canonical] = true
+		c.specs = append(c.specs, canonical)
+	}
+
+	return compiled{TypeNumber, indicatorEvaluator(key)}, nil
+}
+
+// compilePattern checks pattern("name")
+func (c *compiler) compilePattern(n *callNode) (compiled, error) {
+	if n.output != "" {
+		return compiled{}, c.errorf(n.outputPos, "pattern has no outputs")
+	}
+	if len(n.args) != 1 {
+		return compiled{}, c.errorf(n.pos, "pattern takes exactly one name, e.g. pattern(\"hammer\")")
+	}
+
+	name, ok := n.args[0].(*stringNode)
+	if !ok {
+		return compiled{}, c.errorf(n.args[0].position(), "pattern name must be a string, e.g. pattern(\"hammer\")")
+	}
+
+	return compiled{TypeBool, patternEvaluator(name.value)}, nil
+}
+
+func (c *compiler) compileUnary(n *unaryNode) (compiled, error) {
+	operand, err := c.compile(n.operand)
+	if err != nil {
+		return compiled{}, err
+	}
+
+	if n.op == "not" {
+		if operand.typ != TypeBool {
+			return compiled{}, c.errorf(n.operand.position(), "not needs a condition, got a %s", operand.typ)
+		}
+		return compiled{TypeBool, notEvaluator(operand.eval)}, nil
+	}
+
+	if operand.typ != TypeNumber {
+		return compiled{}, c.errorf(n.operand.position(), "cannot negate a %s", operand.typ)
+	}
+	return compiled{TypeNumber, negateEvaluator(operand.eval)}, nil
+}
+
+func (c *compiler) compileBinary(n *binaryNode) (compiled, error) {
+	left, err := c.compile(n.left)
+	if err != nil {
+		return compiled{}, err
+	}
+	right, err := c.compile(n.right)
+	if err != nil {
+		return compiled{}, err
+	}
+
+	switch n.op {
+	case "and", "or":
+		if left.typ != TypeBool {
+			return compiled{}, c.errorf(n.left.position(), "%s needs conditions on both sides, got a %s", n.op, left.typ)
+		}
+		if right.typ != TypeBool {
+			return compiled{}, c.errorf(n.right.position(), "%s needs conditions on both sides, got a %s", n.op, right.typ)
+		}
+		if n.op == "and" {
+			return compiled{TypeBool, andEvaluator(left.eval, right.eval)}, nil
+		}
+		return compiled{TypeBool, orEvaluator(left.eval, right.eval)}, nil
+
+	case "==", "!=":
+		if left.typ != right.typ {
+			return compiled{}, c.errorf(n.pos, "cannot compare a %s with a %s", left.typ, right.typ)
+		}
+		return compiled{TypeBool, equalityEvaluator(n.op == "!=", left.typ, left.eval, right.eval)}, nil
+
+	case "<", "<=", ">", ">=":
+		if left.typ != TypeNumber || right.typ != TypeNumber {
+			return compiled{}, c.errorf(n.pos, "%s compares numbers, got a %s and a %s", n.op, left.typ, right.typ)
+		}
+		return compiled{TypeBool, orderingEvaluator(n.op, left.eval, right.eval)}, nil
+
+	default: // + - * /
+		if left.typ != TypeNumber || right.typ != TypeNumber {
+			return compiled{}, c.errorf(n.pos, "%s needs numbers, got a %s and a %s", n.op, left.typ, right.typ)
+		}
+		return compiled{TypeNumber, arithmeticEvaluator(n.op, left.eval, right.eval)}, nil
+	}
+}
+
+// unknownName reports an unknown name with the closest known one
+func (c *compiler) unknownName(pos int, name string) *Error {
+	candidates := []string{patternFunction}
+	for field := range fields {
+		candidates = append(candidates, field)
+	}
+	for _, definition := range c.registry.Definitions() {
+		candidates = append(candidates, definition.Name)
+	}
+	sort.Strings(candidates)
+
+	if suggestion := suggest(name, candidates); suggestion != "" {
+		return c.errorf(pos, "unknown name %q (did you mean %q?)", name, suggestion)
+	}
+	return c.errorf(pos, "unknown name %q", name)
+}
+
+// literalNumber returns the value of a number literal, possibly negated
+func literalNumber(n node) (float64, bool) {
+	switch n := n.(type) {
+	case *numberNode:
+		return n.value, true
+	case *unaryNode:
+		if n.op == "-" {
+			if number, ok := literalNumber(n.operand); ok {
+				return -number, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func indexOf(values []string, target string) int {
+	for i, value := range values {
+		if value == target {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,196 @@
+package expression
+
+import (
+	"sort"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// Env supplies the values an expression reads. The second result of each
+// lookup is false when the value is not available.
+type Env interface {
+	Number(field string) (float64, bool)
+	String(field string) (string, bool)
+	Indicator(key string) (float64, bool)
+	HasPattern(name string) bool // name as normalized by normalizePattern
+}
+
+// fieldDef describes a field of the candle environment
+type fieldDef struct {
+	typ         Type
+	description string
+}
+
+// fields are the names expressions can read besides registry indicators
+var fields = map[string]fieldDef{
+	"open":               {TypeNumber, "candle open"},
+	"high":               {TypeNumber, "candle high"},
+	"low":                {TypeNumber, "candle low"},
+	"close":              {TypeNumber, "candle close"},
+	"volume":             {TypeNumber, "candle volume"},
+	"rel_volume":         {TypeNumber, "volume relative to its average"},
+	"trend_strength":     {TypeNumber, "trend strength, 0-100"},
+	"momentum_strength":  {TypeNumber, "momentum strength, 0-100"},
+	"volatility_percent": {TypeNumber, "volatility, 0-100"},
+	"signal_strength":    {TypeNumber, "overall signal strength, 0-100"},
+	"confidence":         {TypeNumber, "overall signal confidence, 0-100"},
+	"relative_strength":  {TypeNumber, "performance vs the benchmark, 100 = same"},
+	"beta":               {TypeNumber, "beta vs the benchmark"},
+	"correlation":        {TypeNumber, "return correlation with the benchmark, -1 to 1"},
+
+	"trend":               {TypeString, "bullish, bearish or sideways"},
+	"momentum":            {TypeString, "bullish, bearish or neutral"},
+	"volatility":          {TypeString, "low, normal or high"},
+	"volume_confirmation": {TypeString, "confirmed, weak or divergent"},
+	"signal":              {TypeString, "overall signal: bullish, bearish or neutral"},
+	"regime":              {TypeString, "accumulation, markup, distribution or markdown"},
+}
+
+// Field describes an expression field for help output
+type Field struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// Fields returns the fields available to expressions
+func Fields() []Field {
+	result := make([]Field, 0, len(fields))
+	for name, field := range fields {
+		result = append(result, Field{Name: name, Type: field.typ.String(), Description: field.description})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// CandleEnv evaluates expressions over an enriched candle
+type CandleEnv struct {
+	candle   *models.EnrichedCandle
+	patterns map[string]bool
+}
+
+// NewCandleEnv creates an environment for an enriched candle
+func NewCandleEnv(candle *models.EnrichedCandle) *CandleEnv {
+	env := &CandleEnv{candle: candle, patterns: make(map[string]bool)}
+	if candle.Analysis != nil {
+		for _, pattern := range candle.Analysis.CandlestickPatterns {
+			env.patterns[normalizePattern(pattern)] = true
+		}
+		for _, pattern := range candle.Analysis.ChartPatterns {
+			env.patterns[normalizePattern(pattern.Name)] = true
+		}
+	}
+	return env
+}
+
+// Number returns a numeric field
+func (e *CandleEnv) Number(field string) (float64, bool) {
+	ohlcv, technical, signals := e.candle.OHLCV, e.candle.Indicators, e.candle.Signals
+
+	switch field {
+	case "open", "high", "low", "close", "volume":
+		if ohlcv == nil {
+			return 0, false
+		}
+		switch field {
+		case "open":
+			return ohlcv.Open, true
+		case "high":
+			return ohlcv.High, true
+		case "low":
+			return ohlcv.Low, true
+		case "close":
+			return ohlcv.Close, true
+		default:
+			return float64(ohlcv.Volume), true
+		}
+
+	case "rel_volume", "trend_strength", "momentum_strength", "volatility_percent":
+		if technical == nil {
+			return 0, false
+		}
+		switch field {
+		case "rel_volume":
+			return technical.RelativeVolume, technical.RelativeVolume > 0
+		case "trend_strength":
+			return technical.TrendStrength, true
+		case "momentum_strength":
+			return technical.MomentumStrength, true
+		default:
+			return technical.VolatilityPercent, true
+		}
+
+	case "relative_strength", "beta", "correlation":
+		if technical == nil || technical.Relative == nil {
+			return 0, false
+		}
+		switch field {
+		case "relative_strength":
+			return technical.Relative.RelativeStrength, true
+		case "beta":
+			return technical.Relative.Beta, true
+		default:
+			return technical.Relative.Correlation, true
+		}
+
+	case "signal_strength", "confidence":
+		if signals == nil {
+			return 0, false
+		}
+		if field == "confidence" {
+			return signals.Confidence, true
+		}
+		return signals.SignalStrength, true
+	}
+
+	return 0, false
+}
+
+// String returns a text field; empty values are unavailable
+func (e *CandleEnv) String(field string) (string, bool) {
+	technical, signals, analysis := e.candle.Indicators, e.candle.Signals, e.candle.Analysis
+
+	var text string
+	switch field {
+	case "trend":
+		if technical != nil {
+			text = technical.TrendDirection
+		}
+	case "momentum":
+		if technical != nil {
+			text = technical.MomentumDirection
+		}
+	case "volatility":
+		if technical != nil {
+			text = technical.VolatilityLevel
+		}
+	case "volume_confirmation":
+		if technical != nil {
+			text = technical.VolumeConfirmation
+		}
+	case "signal":
+		if signals != nil {
+			text = signals.OverallSignal
+		}
+	case "regime":
+		if analysis != nil {
+			text = analysis.MarketRegime
+		}
+	}
+
+	return text, text != ""
+}
+
+// Indicator returns a registry indicator value by output key
+func (e *CandleEnv) Indicator(key string) (float64, bool) {
+	if e.candle.Indicators == nil {
+		return 0, false
+	}
+	value, exists := e.candle.Indicators.Values[key]
+	return value, exists
+}
+
+// HasPattern reports whether a candlestick or chart pattern was detected
+func (e *CandleEnv) HasPattern(name string) bool {
+	return e.patterns[name]
+}
@@ -0,0 +1,64 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is a syntax or type error at a position of the expression source
+type Error struct {
+	Source  string `json:"source"`
+	Pos     int    `json:"position"` // byte offset, 0-based
+	Message string `json:"message"`
+}
+
+func newError(source string, pos int, format string, args ...interface{}) *Error {
+	return &Error{Source: source, Pos: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// Error returns the message with its 1-based column
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (column %d)", e.Message, e.Pos+1)
+}
+
+// Context returns the source with a caret under the error position, e.g.
+//
+//	rsi(14) < 30 and clse > 10
+//	                 ^
+func (e *Error) Context() string {
+	return e.Source + "\n" + strings.Repeat(" ", e.Pos) + "^"
+}
+
+// suggest returns the candidate closest to name, or "" if none is close enough
+func suggest(name string, candidates []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if distance := editDistance(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
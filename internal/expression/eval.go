@@ -0,0 +1,170 @@
+package expression
+
+import (
+	"strings"
+)
+
+// value is an evaluation result; known is false when an input was unavailable
+type value struct {
+	known bool
+	num   float64
+	str   string
+	b     bool
+}
+
+// evaluator computes a type-checked subexpression
+type evaluator func(env Env) value
+
+var unknown = value{}
+
+func constant(v value) evaluator {
+	return func(Env) value { return v }
+}
+
+func fieldEvaluator(name string, typ Type) evaluator {
+	if typ == TypeString {
+		return func(env Env) value {
+			text, ok := env.String(name)
+			return value{known: ok, str: text}
+		}
+	}
+	return func(env Env) value {
+		number, ok := env.Number(name)
+		return value{known: ok, num: number}
+	}
+}
+
+func indicatorEvaluator(key string) evaluator {
+	return func(env Env) value {
+		number, ok := env.Indicator(key)
+		return value{known: ok, num: number}
+	}
+}
+
+func patternEvaluator(name string) evaluator {
+	name = normalizePattern(name)
+	return func(env Env) value {
+		return value{known: true, b: env.HasPattern(name)}
+	}
+}
+
+func notEvaluator(operand evaluator) evaluator {
+	return func(env Env) value {
+		v := operand(env)
+		return value{known: v.known, b: !v.b}
+	}
+}
+
+func negateEvaluator(operand evaluator) evaluator {
+	return func(env Env) value {
+		v := operand(env)
+		return value{known: v.known, num: -v.num}
+	}
+}
+
+// andEvaluator follows three-valued logic: false wins over unknown
+func andEvaluator(left, right evaluator) evaluator {
+	return func(env Env) value {
+		l := left(env)
+		if l.known && !l.b {
+			return value{known: true}
+		}
+		r := right(env)
+		if r.known && !r.b {
+			return value{known: true}
+		}
+		return value{known: l.known && r.known, b: true}
+	}
+}
+
+// orEvaluator follows three-valued logic: true wins over unknown
+func orEvaluator(left, right evaluator) evaluator {
+	return func(env Env) value {
+		l := left(env)
+		if l.known && l.b {
+			return value{known: true, b: true}
+		}
+		r := right(env)
+		if r.known && r.b {
+			return value{known: true, b: true}
+		}
+		return value{known: l.known && r.known}
+	}
+}
+
+// equalityEvaluator compares two values of the same type; strings compare
+// case-insensitively
+func equalityEvaluator(negate bool, typ Type, left, right evaluator) evaluator {
+	return func(env Env) value {
+		l, r := left(env), right(env)
+		if !l.known || !r.known {
+			return unknown
+		}
+
+		var equal bool
+		switch typ {
+		case TypeNumber:
+			equal = l.num == r.num
+		case TypeString:
+			equal = strings.EqualFold(l.str, r.str)
+		default:
+			equal = l.b == r.b
+		}
+		return value{known: true, b: equal != negate}
+	}
+}
+
+func orderingEvaluator(op string, left, right evaluator) evaluator {
+	return func(env Env) value {
+		l, r := left(env), right(env)
+		if !l.known || !r.known {
+			return unknown
+		}
+
+		var result bool
+		switch op {
+		case "<":
+			result = l.num < r.num
+		case "<=":
+			result = l.num <= r.num
+		case ">":
+			result = l.num > r.num
+		default:
+			result = l.num >= r.num
+		}
+		return value{known: true, b: result}
+	}
+}
+
+// arithmeticEvaluator computes + - * /; division by zero is unknown
+func arithmeticEvaluator(op string, left, right evaluator) evaluator {
+	return func(env Env) value {
+		l, r := left(env), right(env)
+		if !l.known || !r.known {
+			return unknown
+		}
+
+		switch op {
+		case "+":
+			return value{known: true, num: l.num + r.num}
+		case "-":
+			return value{known: true, num: l.num - r.num}
+		case "*":
+			return value{known: true, num: l.num * r.num}
+		default:
+			if r.num == 0 {
+				return unknown
+			}
+			return value{known: true, num: l.num / r.num}
+		}
+	}
+}
+
+// normalizePattern maps pattern names like "Bullish Engulfing" and
+// "bullish_engulfing" onto the same form
+func normalizePattern(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-'
+	}), "_")
+}
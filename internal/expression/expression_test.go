@@ -0,0 +1,176 @@
+package expression
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// mapEnv is a test environment backed by maps; absent entries are unavailable
+type mapEnv struct {
+	numbers    map[string]float64
+	strings    map[string]string
+	indicators map[string]float64
+	patterns   map[string]bool
+}
+
+func (e mapEnv) Number(field string) (float64, bool) {
+	value, exists := e.numbers[field]
+	return value, exists
+}
+
+func (e mapEnv) String(field string) (string, bool) {
+	value, exists := e.strings[field]
+	return value, exists
+}
+
+func (e mapEnv) Indicator(key string) (float64, bool) {
+	value, exists := e.indicators[key]
+	return value, exists
+}
+
+func (e mapEnv) HasPattern(name string) bool { return e.patterns[name] }
+
+func TestCompileCollectsCanonicalSpecs(t *testing.T) {
+	program, err := Compile("RSI(14) < 30 and close > sma(200) and rel_volume > 2 and rsi < 50 and bbands(20, 2).upper > close")
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	want := []string{"rsi(14)", "sma(200)", "bbands(20,2)"}
+	if !reflect.DeepEqual(program.Specs(), want) {
+		t.Errorf("Specs() = %v, want %v", program.Specs(), want)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		source  string
+		pos     int
+		message string
+	}{
+		{"rsi(14) < 30 and clse > 10", 17, `did you mean "close"`},
+		{"rsi(14) < ", 10, "ends unexpectedly"},
+		{"(close > 1", 10, "expected ')'"},
+		{"close = 3", 6, "use =="},
+		{"close > 1 && open > 1", 10, "use and"},
+		{"1 < close < 3", 10, "cannot be chained"},
+		{"close + 1", 0, "condition is required"},
+		{"trend > 3", 6, "compares numbers"},
+		{"trend == 3", 6, "cannot compare a string with a number"},
+		{"close > 1 and volume", 14, "conditions on both sides"},
+		{"sma(0) > 1", 0, "at least"},
+		{"sma(close) > 1", 4, "must be numbers"},
+		{"bbands(20,2) > close", 0, "several outputs"},
+		{"macd().hist > 0", 7, `no output "hist"`},
+		{"sma(20).value > 0", 8, "single output"},
+		{"macd.line > 0", 4, "only indicator calls have outputs"},
+		{"pattern(hammer)", 8, "must be a string"},
+		{"close() > 1", 0, "is a field"},
+		{"'open > 1", 0, "unterminated string"},
+	}
+
+	for _, test := range tests {
+		_, err := Compile(test.source)
+		if err == nil {
+			t.Errorf("Compile(%q) succeeded, want error", test.source)
+			continue
+		}
+
+		var exprErr *Error
+		if !errors.As(err, &exprErr) {
+			t.Errorf("Compile(%q) error %T is not *Error", test.source, err)
+			continue
+		}
+		if exprErr.Pos != test.pos {
+			t.Errorf("Compile(%q) error at %d, want %d: %v", test.source, exprErr.Pos, test.pos, err)
+		}
+		if !strings.Contains(exprErr.Message, test.message) {
+			t.Errorf("Compile(%q) = %q, want it to mention %q", test.source, exprErr.Message, test.message)
+		}
+	}
+}
+
+func TestErrorContextPointsAtPosition(t *testing.T) {
+	_, err := Compile("close > 1 and clse > 10")
+
+	var exprErr *Error
+	if !errors.As(err, &exprErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+
+	want := "close > 1 and clse > 10\n              ^"
+	if exprErr.Context() != want {
+		t.Errorf("Context() =\n%s\nwant\n%s", exprErr.Context(), want)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	env := mapEnv{
+		numbers: map[string]float64{"close": 150, "open": 140, "rel_volume": 2.5},
+		strings: map[string]string{"trend": "bullish"},
+		indicators: map[string]float64{
+			"rsi(14)":              25,
+			"sma(200)":             120,
+			"bbands(20,2).upper":   155,
+			"macd(12,26,9).line":   1.5,
+			"macd(12,26,9).signal": 1.0,
+		},
+		patterns: map[string]bool{"bullish_engulfing": true},
+	}
+
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"rsi(14) < 30 and close > sma(200) and rel_volume > 2", true},
+		{"rsi(14) < 30 and close > sma(200) and rel_volume > 3", false},
+		{"close > bbands(20,2).upper or rsi < 30", true},
+		{"macd().line - macd().signal > 0.4", true},
+		{"(close - open) / open * 100 >= 7", true},
+		{"-close < 0", true},
+		{"trend == 'BULLISH' and not (trend != \"bullish\")", true},
+		{"pattern(\"Bullish Engulfing\") and pattern('bullish-engulfing')", true},
+		{"pattern(\"hammer\")", false},
+		{"close / (open - 140) > 1", false}, // division by zero is unknown
+		{"true and not false", true},
+	}
+
+	for _, test := range tests {
+		program, err := Compile(test.source)
+		if err != nil {
+			t.Errorf("Compile(%q) failed: %v", test.source, err)
+			continue
+		}
+		if got := program.Match(env); got != test.want {
+			t.Errorf("Match(%q) = %v, want %v", test.source, got, test.want)
+		}
+	}
+}
+
+func TestMatchTreatsMissingValuesAsUnknown(t *testing.T) {
+	env := mapEnv{numbers: map[string]float64{"close": 150}}
+
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"rsi(14) < 30", false},
+		{"not (rsi(14) < 30)", false},
+		{"rsi(14) < 30 or close > 100", true},
+		{"rsi(14) < 30 and close > 100", false},
+		{"rsi(14) > 0 or close < 100", false},
+		{"trend == \"bullish\" or close > 100", true},
+	}
+
+	for _, test := range tests {
+		program, err := Compile(test.source)
+		if err != nil {
+			t.Fatalf("Compile(%q) failed: %v", test.source, err)
+		}
+		if got := program.Match(env); got != test.want {
+			t.Errorf("Match(%q) = %v, want %v", test.source, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package expression
+
+import (
+	"strings"
+)
+
+// REQ-259: Rule expression language over enriched candles
+
+// tokenKind classifies lexer tokens
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenDot
+)
+
+// token is a lexeme with its byte offset in the source
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// describe returns the token as shown in error messages
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return "string " + quote(t.text)
+	default:
+		return "'" + t.text + "'"
+	}
+}
+
+// lex splits source into tokens. Identifiers and keywords are case-insensitive
+// and returned in lower case; string literals keep their case.
+func lex(source string) ([]token, error) {
+	var tokens []token
+	i := 0
+
+	for i < len(source) {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case isDigit(c):
+			start := i
+			for i < len(source) && isDigit(source[i]) {
+				i++
+			}
+			if i+1 < len(source) && source[i] == '.' && isDigit(source[i+1]) {
+				i++
+				for i < len(source) && isDigit(source[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], pos: start})
+
+		case isLetter(c):
+			start := i
+			for i < len(source) && (isLetter(source[i]) || isDigit(source[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: strings.ToLower(source[start:i]), pos: start})
+
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			for i < len(source) && source[i] != c {
+				i++
+			}
+			if i >= len(source) {
+				return nil, newError(source, start, "unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: source[start+1 : i], pos: start})
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case c == '.':
+			tokens = append(tokens, token{kind: tokenDot, text: ".", pos: i})
+			i++
+
+		case c == '<' || c == '>' || c == '!' || c == '=':
+			if i+1 < len(source) && source[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokenOperator, text: source[i : i+2], pos: i})
+				i += 2
+				continue
+			}
+			switch c {
+			case '=':
+				return nil, newError(source, i, "use == to compare values")
+			case '!':
+				return nil, newError(source, i, "use not to negate a condition")
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: string(c), pos: i})
+			i++
+
+		case c == '+' || c == '-' || c == '*' || c == '/':
+			tokens = append(tokens, token{kind: tokenOperator, text: string(c), pos: i})
+			i++
+
+		case c == '&' || c == '|':
+			word := "and"
+			if c == '|' {
+				word = "or"
+			}
+			return nil, newError(source, i, "use %s instead of %q", word, string(c))
+
+		default:
+			return nil, newError(source, i, "unexpected character %q", string(c))
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(source)})
+	return tokens, nil
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
+
+// quote formats a string literal for error messages
+func quote(text string) string { return "\"" + text + "\"" }
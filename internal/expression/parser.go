@@ -0,0 +1,340 @@
+package expression
+
+import (
+	"strconv"
+)
+
+// Grammar, lowest precedence first:
+//
+//	expr       = and { "or" and }
+//	and        = not { "and" not }
+//	not        = "not" not | comparison
+//	comparison = sum [ ( "<" | "<=" | ">" | ">=" | "==" | "!=" ) sum ]
+//	sum        = product { ( "+" | "-" ) product }
+//	product    = unary { ( "*" | "/" ) unary }
+//	unary      = "-" unary | primary
+//	primary    = number | string | "true" | "false" | "(" expr ")"
+//	           | name [ "(" [ expr { "," expr } ] ")" [ "." name ] ]
+
+// node is a parsed, not yet type-checked expression
+type node interface {
+	position() int
+}
+
+type numberNode struct {
+	pos   int
+	value float64
+}
+
+type stringNode struct {
+	pos   int
+	value string
+}
+
+type boolNode struct {
+	pos   int
+	value bool
+}
+
+// nameNode is a field or a parameterless indicator, e.g. close or rsi
+type nameNode struct {
+	pos  int
+	name string
+}
+
+// callNode is an indicator or function call, e.g. bbands(20,2).upper
+type callNode struct {
+	pos       int
+	name      string
+	args      []node
+	output    string // selected output, "" if none
+	outputPos int
+}
+
+type unaryNode struct {
+	pos     int
+	op      string // "-" or "not"
+	operand node
+}
+
+type binaryNode struct {
+	pos         int // operator position
+	op          string
+	left, right node
+}
+
+func (n *numberNode) position() int { return n.pos }
+func (n *stringNode) position() int { return n.pos }
+func (n *boolNode) position() int   { return n.pos }
+func (n *nameNode) position() int   { return n.pos }
+func (n *callNode) position() int   { return n.pos }
+func (n *unaryNode) position() int  { return n.pos }
+func (n *binaryNode) position() int { return n.left.position() }
+
+// parser is a recursive-descent parser over the token stream
+type parser struct {
+	source string
+	tokens []token
+	index  int
+}
+
+// parse parses source into an expression tree
+func parse(source string) (node, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, newError(source, 0, "empty expression")
+	}
+
+	p := &parser{source: source, tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, p.errorAt(next, "unexpected %s; combine conditions with and / or", next.describe())
+	}
+
+	return root, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.index] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.index]
+	if t.kind != tokenEOF {
+		p.index++
+	}
+	return t
+}
+
+// keyword reports whether the next token is the given keyword
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokenIdent && t.text == word
+}
+
+// operator reports whether the next token is one of the given operators
+func (p *parser) operator(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) errorAt(t token, format string, args ...interface{}) *Error {
+	return newError(p.source, t.pos, format, args...)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("or") {
+		op := p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{pos: op.pos, op: "or", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("and") {
+		op := p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{pos: op.pos, op: "and", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.keyword("not") {
+		op := p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{pos: op.pos, op: "not", operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	comparisons := []string{"<", "<=", ">", ">=", "==", "!="}
+	if !p.operator(comparisons...) {
+		return left, nil
+	}
+
+	op := p.next()
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.operator(comparisons...) {
+		return nil, p.errorAt(p.peek(), "comparisons cannot be chained; combine them with and")
+	}
+
+	return &binaryNode{pos: op.pos, op: op.text, left: left, right: right}, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.operator("+", "-") {
+		op := p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{pos: op.pos, op: op.text, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.operator("*", "/") {
+		op := p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{pos: op.pos, op: op.text, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.operator("-") {
+		op := p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{pos: op.pos, op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorAt(t, "invalid number %s", t.text)
+		}
+		return &numberNode{pos: t.pos, value: value}, nil
+
+	case tokenString:
+		return &stringNode{pos: t.pos, value: t.text}, nil
+
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorAt(closing, "expected ')' but found %s", closing.describe())
+		}
+		return inner, nil
+
+	case tokenIdent:
+		switch t.text {
+		case "true", "false":
+			return &boolNode{pos: t.pos, value: t.text == "true"}, nil
+		case "and", "or", "not":
+			return nil, p.errorAt(t, "expected a value but found '%s'", t.text)
+		}
+		if p.peek().kind == tokenLParen {
+			return p.parseCall(t)
+		}
+		if p.peek().kind == tokenDot {
+			return nil, p.errorAt(p.peek(), "only indicator calls have outputs; write %s().<output>", t.text)
+		}
+		return &nameNode{pos: t.pos, name: t.text}, nil
+
+	case tokenEOF:
+		return nil, p.errorAt(t, "expression ends unexpectedly; expected a value")
+
+	default:
+		return nil, p.errorAt(t, "expected a value but found %s", t.describe())
+	}
+}
+
+// parseCall parses the arguments and optional output of a call
+func (p *parser) parseCall(name token) (node, error) {
+	call := &callNode{pos: name.pos, name: name.text}
+	p.next() // (
+
+	if p.peek().kind != tokenRParen {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if closing := p.next(); closing.kind != tokenRParen {
+		return nil, p.errorAt(closing, "expected ',' or ')' in call to %s but found %s", name.text, closing.describe())
+	}
+
+	if p.peek().kind == tokenDot {
+		p.next()
+		output := p.next()
+		if output.kind != tokenIdent {
+			return nil, p.errorAt(output, "expected an output name after '.' but found %s", output.describe())
+		}
+		call.output = output.text
+		call.outputPos = output.pos
+	}
+
+	return call, nil
+}
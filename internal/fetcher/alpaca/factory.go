@@ -1,7 +1,7 @@
 package alpaca
 
 import (
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 	"github.com/rs/zerolog"
 )
 
@@ -8,7 +8,7 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 	"github.com/rs/zerolog"
 )
 
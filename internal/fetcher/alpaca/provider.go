@@ -13,7 +13,7 @@ import (
 
 	"github.com/ridopark/jonbu-ohlcv/internal/config"
 	"github.com/ridopark/jonbu-ohlcv/internal/logger"
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-029: Data provider abstraction interface
@@ -4,7 +4,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-210: Performance-optimized caching
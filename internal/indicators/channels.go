@@ -0,0 +1,216 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-256: Channel indicators (Keltner, Donchian, CCI)
+
+// ChannelIndicators represents price channel indicators
+type ChannelIndicators struct {
+	KeltnerUpper   float64 `json:"keltner_upper"`
+	KeltnerMiddle  float64 `json:"keltner_middle"`
+	KeltnerLower   float64 `json:"keltner_lower"`
+	DonchianUpper  float64 `json:"donchian_upper"`
+	DonchianMiddle float64 `json:"donchian_middle"`
+	DonchianLower  float64 `json:"donchian_lower"`
+	CCI            float64 `json:"cci"`
+}
+
+// KeltnerChannels calculates Keltner Channels: an EMA of close with bands at
+// a multiple of the ATR
+func KeltnerChannels(candles []*models.OHLCV, emaPeriod, atrPeriod int, multiplier float64) (upper, middle, lower float64) {
+	if len(candles) < emaPeriod || len(candles) < atrPeriod+1 {
+		return 0, 0, 0
+	}
+
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close
+	}
+
+	middle = EMA(closes, emaPeriod)
+	width := ATR(candles, atrPeriod) * multiplier
+
+	return middle + width, middle, middle - width
+}
+
+// DonchianChannels calculates the highest high and lowest low over a period
+func DonchianChannels(candles []*models.OHLCV, period int) (upper, middle, lower float64) {
+	if len(candles) < period || period < 1 {
+		return 0, 0, 0
+	}
+
+	upper = candles[len(candles)-period].High
+	lower = candles[len(candles)-period].Low
+	for i := len(candles) - period + 1; i < len(candles); i++ {
+		upper = math.Max(upper, candles[i].High)
+		lower = math.Min(lower, candles[i].Low)
+	}
+
+	return upper, (upper + lower) / 2, lower
+}
+
+// CCI calculates the Commodity Channel Index of the typical price
+func CCI(candles []*models.OHLCV, period int) float64 {
+	if len(candles) < period || period < 1 {
+		return 0
+	}
+
+	typicalPrices := make([]float64, period)
+	for i := 0; i < period; i++ {
+		candle := candles[len(candles)-period+i]
+		typicalPrices[i] = (candle.High + candle.Low + candle.Close) / 3.0
+	}
+
+	return cciOf(typicalPrices)
+}
+
+// cciOf computes CCI from typical prices in chronological order
+func cciOf(typicalPrices []float64) float64 {
+	period := float64(len(typicalPrices))
+
+	sum := 0.0
+	for _, tp := range typicalPrices {
+		sum += tp
+	}
+	mean := sum / period
+
+	deviation := 0.0
+	for _, tp := range typicalPrices {
+		deviation += math.Abs(tp - mean)
+	}
+	deviation /= period
+
+	if deviation == 0 {
+		return 0
+	}
+
+	return (typicalPrices[len(typicalPrices)-1] - mean) / (0.015 * deviation)
+}
+
+// StreamingKeltner is the incremental form of KeltnerChannels
+type StreamingKeltner struct {
+	ema        *StreamingEMA
+	atr        *StreamingATR
+	multiplier float64
+}
+
+// NewStreamingKeltner creates incremental Keltner Channels
+func NewStreamingKeltner(emaPeriod, atrPeriod int, multiplier float64) *StreamingKeltner {
+	return &StreamingKeltner{
+		ema:        NewStreamingEMA(emaPeriod),
+		atr:        NewStreamingATR(atrPeriod),
+		multiplier: multiplier,
+	}
+}
+
+// Update adds a candle
+func (k *StreamingKeltner) Update(candle *models.OHLCV) {
+	k.ema.Update(candle.Close)
+	k.atr.Update(candle)
+}
+
+// Values returns the channel, or zeros until both averages are ready
+func (k *StreamingKeltner) Values() (upper, middle, lower float64) {
+	if !k.Ready() {
+		return 0, 0, 0
+	}
+	middle = k.ema.Value()
+	width := k.atr.Value() * k.multiplier
+	return middle + width, middle, middle - width
+}
+
+// Ready reports whether both the EMA and ATR are ready
+func (k *StreamingKeltner) Ready() bool { return k.ema.Ready() && k.atr.Ready() }
+
+// StreamingDonchian is the incremental form of DonchianChannels
+type StreamingDonchian struct {
+	highs *RollingExtreme
+	lows  *RollingExtreme
+}
+
+// NewStreamingDonchian creates incremental Donchian Channels
+func NewStreamingDonchian(period int) *StreamingDonchian {
+	return &StreamingDonchian{highs: NewRollingMax(period), lows: NewRollingMin(period)}
+}
+
+// Update adds a candle
+func (d *StreamingDonchian) Update(candle *models.OHLCV) {
+	d.highs.Update(candle.High)
+	d.lows.Update(candle.Low)
+}
+
+// Values returns the channel, or zeros until the period is full
+func (d *StreamingDonchian) Values() (upper, middle, lower float64) {
+	if !d.Ready() {
+		return 0, 0, 0
+	}
+	upper, lower = d.highs.Value(), d.lows.Value()
+	return upper, (upper + lower) / 2, lower
+}
+
+// Ready reports whether the period is full
+func (d *StreamingDonchian) Ready() bool { return d.highs.Ready() }
+
+// StreamingCCI is the incremental form of CCI. The mean deviation has no
+// running form, so reading the value costs O(period).
+type StreamingCCI struct {
+	typicalPrices *RollingWindow
+}
+
+// NewStreamingCCI creates an incremental CCI
+func NewStreamingCCI(period int) *StreamingCCI {
+	return &StreamingCCI{typicalPrices: NewRollingWindow(period)}
+}
+
+// Update adds a candle
+func (c *StreamingCCI) Update(candle *models.OHLCV) {
+	c.typicalPrices.Push((candle.High + candle.Low + candle.Close) / 3.0)
+}
+
+// Value returns the CCI, or 0 until the period is full
+func (c *StreamingCCI) Value() float64 {
+	if !c.Ready() {
+		return 0
+	}
+
+	typicalPrices := make([]float64, c.typicalPrices.Len())
+	for i := range typicalPrices {
+		typicalPrices[i] = c.typicalPrices.At(i)
+	}
+	return cciOf(typicalPrices)
+}
+
+// Ready reports whether the period is full
+func (c *StreamingCCI) Ready() bool { return c.typicalPrices.Full() }
+
+// CalculateChannelIndicators computes all channel indicators for a candle history
+func CalculateChannelIndicators(candles []*models.OHLCV) *ChannelIndicators {
+	if len(candles) == 0 {
+		return &ChannelIndicators{}
+	}
+
+	indicators := &ChannelIndicators{
+		CCI: CCI(candles, 20),
+	}
+
+	indicators.KeltnerUpper, indicators.KeltnerMiddle, indicators.KeltnerLower =
+		KeltnerChannels(candles, 20, 10, 2.0)
+	indicators.DonchianUpper, indicators.DonchianMiddle, indicators.DonchianLower =
+		DonchianChannels(candles, 20)
+
+	return indicators
+}
+
+// CCISignal returns the CCI condition
+func (c *ChannelIndicators) CCISignal() string {
+	if c.CCI > 100 {
+		return "overbought"
+	} else if c.CCI < -100 {
+		return "oversold"
+	}
+	return "neutral"
+}
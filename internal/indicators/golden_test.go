@@ -0,0 +1,186 @@
+package indicators
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-258: Golden-value tests against reference values generated by
+// scripts/generate_indicator_golden.py
+
+const goldenTolerance = 1e-8
+
+func loadGoldenCandles(t *testing.T) []*models.OHLCV {
+	t.Helper()
+	rows := readGoldenCSV(t, "testdata/golden_ohlcv.csv")
+
+	candles := make([]*models.OHLCV, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		timestamp, err := time.Parse(time.RFC3339, row[0])
+		if err != nil {
+			t.Fatalf("invalid timestamp %q: %v", row[0], err)
+		}
+		volume, err := strconv.ParseInt(row[5], 10, 64)
+		if err != nil {
+			t.Fatalf("invalid volume %q: %v", row[5], err)
+		}
+		candles = append(candles, &models.OHLCV{
+			Symbol:    "GOLD",
+			Timestamp: timestamp,
+			Open:      parseGoldenFloat(t, row[1]),
+			High:      parseGoldenFloat(t, row[2]),
+			Low:       parseGoldenFloat(t, row[3]),
+			Close:     parseGoldenFloat(t, row[4]),
+			Volume:    volume,
+		})
+	}
+
+	return candles
+}
+
+func readGoldenCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return rows
+}
+
+func parseGoldenFloat(t *testing.T, text string) float64 {
+	t.Helper()
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		t.Fatalf("invalid number %q: %v", text, err)
+	}
+	return value
+}
+
+// goldenActual returns the package values of every golden column for a history
+func goldenActual(candles []*models.OHLCV) map[string]float64 {
+	trend := CalculateTrendIndicators(candles)
+	momentum := CalculateMomentumIndicators(candles)
+	volatility := CalculateVolatilityIndicators(candles)
+	volume := CalculateVolumeIndicators(candles)
+	strength := CalculateTrendStrengthIndicators(candles)
+	channels := CalculateChannelIndicators(candles)
+	moneyFlow := CalculateMoneyFlowIndicators(candles)
+
+	return map[string]float64{
+		"sma20":            trend.SMA20,
+		"sma50":            trend.SMA50,
+		"ema12":            trend.EMA12,
+		"ema26":            trend.EMA26,
+		"macd":             trend.MACD,
+		"macd_signal":      trend.MACDSignal,
+		"macd_hist":        trend.MACDHist,
+		"rsi14":            momentum.RSI,
+		"stoch_k":          momentum.StochasticK,
+		"stoch_d":          momentum.StochasticD,
+		"willr14":          momentum.WilliamsR,
+		"roc10":            momentum.ROC,
+		"stddev20":         volatility.StdDev,
+		"bb_upper":         volatility.BollingerUpper,
+		"bb_middle":        volatility.BollingerMiddle,
+		"bb_lower":         volatility.BollingerLower,
+		"atr14":            volatility.ATR,
+		"vwap":             volume.VWAP,
+		"obv":              volume.OBV,
+		"ad":               volume.AccDist,
+		"plus_di":          strength.PlusDI,
+		"minus_di":         strength.MinusDI,
+		"adx14":            strength.ADX,
+		"psar":             strength.PSAR,
+		"psar_trend":       trendSign(strength.PSARBullish),
+		"supertrend":       strength.SuperTrend,
+		"supertrend_trend": trendSign(strength.SuperTrendBullish),
+		"tenkan":           strength.Ichimoku.Tenkan,
+		"kijun":            strength.Ichimoku.Kijun,
+		"senkou_a":         strength.Ichimoku.SenkouA,
+		"senkou_b":         strength.Ichimoku.SenkouB,
+		"cloud_a":          strength.Ichimoku.CloudA,
+		"cloud_b":          strength.Ichimoku.CloudB,
+		"keltner_upper":    channels.KeltnerUpper,
+		"keltner_middle":   channels.KeltnerMiddle,
+		"keltner_lower":    channels.KeltnerLower,
+		"donchian_upper":   channels.DonchianUpper,
+		"donchian_middle":  channels.DonchianMiddle,
+		"donchian_lower":   channels.DonchianLower,
+		"cci20":            channels.CCI,
+		"mfi14":            moneyFlow.MFI,
+		"cmf20":            moneyFlow.CMF,
+	}
+}
+
+func TestIndicatorsMatchGoldenValues(t *testing.T) {
+	candles := loadGoldenCandles(t)
+	rows := readGoldenCSV(t, "testdata/golden_expected.csv")
+	header := rows[0]
+
+	if len(rows)-1 != len(candles) {
+		t.Fatalf("golden files disagree: %d candles, %d expected rows", len(candles), len(rows)-1)
+	}
+
+	checked := make(map[string]int)
+	for i, row := range rows[1:] {
+		actual := goldenActual(candles[:i+1])
+
+		for column, text := range row[1:] {
+			name := header[column+1]
+			if text == "" {
+				continue // still warming up in the reference
+			}
+
+			got, exists := actual[name]
+			if !exists {
+				t.Fatalf("golden column %s has no package counterpart", name)
+			}
+
+			want := parseGoldenFloat(t, text)
+			if math.Abs(got-want) > goldenTolerance*math.Max(1, math.Abs(want)) {
+				t.Errorf("candle %d: %s = %v, want %v", i, name, got, want)
+			}
+			checked[name]++
+		}
+	}
+
+	for _, name := range header[1:] {
+		if checked[name] == 0 {
+			t.Errorf("golden column %s was never checked", name)
+		}
+	}
+}
+
+func TestRSIMatchesPublishedWilderExample(t *testing.T) {
+	// Wilder RSI(14) example as published by StockCharts; the table rounds the
+	// first averages to two decimals, hence the loose tolerance
+	closes := []float64{
+		44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+		45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64,
+		46.21, 46.25, 45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57,
+		43.42, 42.66, 43.13,
+	}
+	published := []float64{
+		70.53, 66.32, 66.55, 69.41, 66.36, 57.97, 62.93, 63.26, 56.06, 62.38,
+		54.71, 50.42, 39.99, 41.46, 41.87, 45.46, 37.30, 33.08, 37.77,
+	}
+
+	for i, want := range published {
+		got := RSI(closes[:15+i], 14)
+		if math.Abs(got-want) > 0.1 {
+			t.Errorf("RSI after %d closes = %.2f, want %.2f", 15+i, got, want)
+		}
+	}
+}
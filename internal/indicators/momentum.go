@@ -1,9 +1,7 @@
 package indicators
 
 import (
-	"math"
-
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-207: Momentum indicators (RSI, Stochastic, Williams %R)
@@ -17,49 +15,78 @@ type MomentumIndicators struct {
 	ROC         float64 `json:"roc"` // Rate of Change
 }
 
-// RSI calculates Relative Strength Index
+// RSI calculates Relative Strength Index with Wilder smoothing: the first
+// averages are simple means of period changes, later ones are smoothed
 func RSI(prices []float64, period int) float64 {
-	if len(prices) < period+1 {
+	if period < 1 || len(prices) < period+1 {
 		return 50 // Neutral RSI
 	}
 
-	gains := 0.0
-	losses := 0.0
-
-	// Calculate initial gains and losses
+	avgGain, avgLoss := 0.0, 0.0
 	for i := 1; i <= period; i++ {
-		change := prices[i] - prices[i-1]
-		if change > 0 {
-			gains += change
-		} else {
-			losses += math.Abs(change)
-		}
+		gain, loss := priceChange(prices[i] - prices[i-1])
+		avgGain += gain
+		avgLoss += loss
+	}
+	avgGain /= float64(period)
+	avgLoss /= float64(period)
+
+	for i := period + 1; i < len(prices); i++ {
+		gain, loss := priceChange(prices[i] - prices[i-1])
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
 	}
 
-	avgGain := gains / float64(period)
-	avgLoss := losses / float64(period)
+	return rsiOf(avgGain, avgLoss)
+}
+
+// priceChange splits a price change into gain and loss
+func priceChange(change float64) (gain, loss float64) {
+	if change > 0 {
+		return change, 0
+	}
+	return 0, -change
+}
 
+// rsiOf converts average gain and loss into the index
+func rsiOf(avgGain, avgLoss float64) float64 {
 	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50 // No movement
+		}
 		return 100
 	}
 
 	rs := avgGain / avgLoss
-	rsi := 100 - (100 / (1 + rs))
-
-	return rsi
+	return 100 - (100 / (1 + rs))
 }
 
-// Stochastic calculates Stochastic Oscillator %K and %D
+// Stochastic calculates Stochastic Oscillator %K and %D, the dPeriod SMA of %K
 func Stochastic(highs, lows, closes []float64, kPeriod, dPeriod int) (k, d float64) {
 	if len(closes) < kPeriod {
 		return 50, 50 // Neutral values
 	}
 
-	// Find highest high and lowest low over the period
-	highestHigh := highs[len(highs)-kPeriod]
-	lowestLow := lows[len(lows)-kPeriod]
+	k = stochasticK(highs, lows, closes, len(closes), kPeriod)
+	if len(closes) < kPeriod+dPeriod-1 {
+		return k, 50
+	}
+
+	sum := 0.0
+	for end := len(closes) - dPeriod + 1; end <= len(closes); end++ {
+		sum += stochasticK(highs, lows, closes, end, kPeriod)
+	}
+	d = sum / float64(dPeriod)
+
+	return k, d
+}
+
+// stochasticK calculates %K of the kPeriod candles ending before index end
+func stochasticK(highs, lows, closes []float64, end, kPeriod int) float64 {
+	highestHigh := highs[end-kPeriod]
+	lowestLow := lows[end-kPeriod]
 
-	for i := len(highs) - kPeriod; i < len(highs); i++ {
+	for i := end - kPeriod; i < end; i++ {
 		if highs[i] > highestHigh {
 			highestHigh = highs[i]
 		}
@@ -68,18 +95,11 @@ func Stochastic(highs, lows, closes []float64, kPeriod, dPeriod int) (k, d float
 		}
 	}
 
-	currentClose := closes[len(closes)-1]
-
 	if highestHigh == lowestLow {
-		k = 50
-	} else {
-		k = ((currentClose - lowestLow) / (highestHigh - lowestLow)) * 100
+		return 50
 	}
 
-	// %D is typically a 3-period SMA of %K (simplified here)
-	d = k * 0.9 // Simplified calculation
-
-	return k, d
+	return ((closes[end-1] - lowestLow) / (highestHigh - lowestLow)) * 100
 }
 
 // WilliamsR calculates Williams %R
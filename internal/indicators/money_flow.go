@@ -0,0 +1,187 @@
+package indicators
+
+import (
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-256: Money flow indicators (MFI, Chaikin Money Flow)
+
+// MoneyFlowIndicators represents volume-weighted money flow indicators
+type MoneyFlowIndicators struct {
+	MFI float64 `json:"mfi"`
+	CMF float64 `json:"cmf"`
+}
+
+// MFI calculates the Money Flow Index
+func MFI(candles []*models.OHLCV, period int) float64 {
+	if len(candles) < period+1 || period < 1 {
+		return 50 // Neutral MFI
+	}
+
+	positive, negative := 0.0, 0.0
+	for i := len(candles) - period; i < len(candles); i++ {
+		flow, direction := moneyFlow(candles[i], candles[i-1])
+		if direction > 0 {
+			positive += flow
+		} else if direction < 0 {
+			negative += flow
+		}
+	}
+
+	return mfiOf(positive, negative)
+}
+
+// moneyFlow returns the raw money flow of a candle and whether its typical
+// price rose (+1), fell (-1) or was unchanged (0)
+func moneyFlow(current, previous *models.OHLCV) (float64, int) {
+	typicalPrice := (current.High + current.Low + current.Close) / 3.0
+	previousTypical := (previous.High + previous.Low + previous.Close) / 3.0
+	flow := typicalPrice * float64(current.Volume)
+
+	switch {
+	case typicalPrice > previousTypical:
+		return flow, 1
+	case typicalPrice < previousTypical:
+		return flow, -1
+	default:
+		return flow, 0
+	}
+}
+
+// mfiOf converts positive and negative flow into the index
+func mfiOf(positive, negative float64) float64 {
+	if negative == 0 {
+		if positive == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - (100 / (1 + positive/negative))
+}
+
+// CMF calculates Chaikin Money Flow
+func CMF(candles []*models.OHLCV, period int) float64 {
+	if len(candles) < period || period < 1 {
+		return 0
+	}
+
+	flowVolume := 0.0
+	var volume int64
+	for i := len(candles) - period; i < len(candles); i++ {
+		flowVolume += moneyFlowVolume(candles[i])
+		volume += candles[i].Volume
+	}
+
+	if volume == 0 {
+		return 0
+	}
+
+	return flowVolume / float64(volume)
+}
+
+// moneyFlowVolume returns the Money Flow Multiplier times volume
+func moneyFlowVolume(candle *models.OHLCV) float64 {
+	if candle.High == candle.Low {
+		return 0
+	}
+	mfm := ((candle.Close - candle.Low) - (candle.High - candle.Close)) / (candle.High - candle.Low)
+	return mfm * float64(candle.Volume)
+}
+
+// StreamingMFI is the incremental form of MFI
+type StreamingMFI struct {
+	positive *RollingWindow
+	negative *RollingWindow
+	previous *models.OHLCV
+}
+
+// NewStreamingMFI creates an incremental Money Flow Index
+func NewStreamingMFI(period int) *StreamingMFI {
+	return &StreamingMFI{
+		positive: NewRollingWindow(period),
+		negative: NewRollingWindow(period),
+	}
+}
+
+// Update adds a candle
+func (m *StreamingMFI) Update(candle *models.OHLCV) {
+	if m.previous != nil {
+		flow, direction := moneyFlow(candle, m.previous)
+		switch {
+		case direction > 0:
+			m.positive.Push(flow)
+			m.negative.Push(0)
+		case direction < 0:
+			m.positive.Push(0)
+			m.negative.Push(flow)
+		default:
+			m.positive.Push(0)
+			m.negative.Push(0)
+		}
+	}
+	m.previous = candle
+}
+
+// Value returns the MFI, or 50 until period+1 candles were seen
+func (m *StreamingMFI) Value() float64 {
+	if !m.Ready() {
+		return 50
+	}
+	return mfiOf(m.positive.Sum(), m.negative.Sum())
+}
+
+// Ready reports whether period+1 candles were seen
+func (m *StreamingMFI) Ready() bool { return m.positive.Full() }
+
+// StreamingCMF is the incremental form of CMF
+type StreamingCMF struct {
+	flowVolume *RollingWindow
+	volume     *RollingWindow
+}
+
+// NewStreamingCMF creates an incremental Chaikin Money Flow
+func NewStreamingCMF(period int) *StreamingCMF {
+	return &StreamingCMF{
+		flowVolume: NewRollingWindow(period),
+		volume:     NewRollingWindow(period),
+	}
+}
+
+// Update adds a candle
+func (c *StreamingCMF) Update(candle *models.OHLCV) {
+	c.flowVolume.Push(moneyFlowVolume(candle))
+	c.volume.Push(float64(candle.Volume))
+}
+
+// Value returns the CMF, or 0 until the period is full
+func (c *StreamingCMF) Value() float64 {
+	if !c.Ready() || c.volume.Sum() == 0 {
+		return 0
+	}
+	return c.flowVolume.Sum() / c.volume.Sum()
+}
+
+// Ready reports whether the period is full
+func (c *StreamingCMF) Ready() bool { return c.flowVolume.Full() }
+
+// CalculateMoneyFlowIndicators computes all money flow indicators for a candle history
+func CalculateMoneyFlowIndicators(candles []*models.OHLCV) *MoneyFlowIndicators {
+	if len(candles) == 0 {
+		return &MoneyFlowIndicators{}
+	}
+
+	return &MoneyFlowIndicators{
+		MFI: MFI(candles, 14),
+		CMF: CMF(candles, 20),
+	}
+}
+
+// MoneyFlowSignal returns the MFI condition
+func (m *MoneyFlowIndicators) MoneyFlowSignal() string {
+	if m.MFI > 80 {
+		return "overbought"
+	} else if m.MFI < 20 {
+		return "oversold"
+	}
+	return "neutral"
+}
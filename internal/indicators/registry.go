@@ -0,0 +1,406 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-255: Indicator registry with parameterized specs
+//
+// Indicators are addressed by spec strings such as "sma(200)", "ema(9)" or
+// "bbands(20,2.5)". Omitted parameters take their defaults, so "rsi" and
+// "rsi(14)" name the same indicator. Single-output indicators are keyed by
+// their canonical spec; multi-output indicators add the output name, for
+// example "bbands(20,2.5).upper".
+
+// Indicator is an incrementally updated, registry-created indicator
+type Indicator interface {
+	// Update applies the next candle
+	Update(candle *models.OHLCV)
+	// Ready reports whether enough candles were seen for a meaningful value
+	Ready() bool
+	// Values returns the current outputs in Definition.Outputs order
+	Values() []float64
+}
+
+// ParamDef describes one numeric indicator parameter
+type ParamDef struct {
+	Name    string  `json:"name"`
+	Default float64 `json:"default"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max,omitempty"` // 0 means unbounded
+	Integer bool    `json:"integer"`
+}
+
+// Definition describes a registered indicator
+type Definition struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Params      []ParamDef `json:"params"`
+	Outputs     []string   `json:"outputs"`
+
+	// New creates an indicator for resolved parameters
+	New func(params []float64) Indicator `json:"-"`
+
+	// Lookback returns how many candles are needed before the indicator is ready
+	Lookback func(params []float64) int `json:"-"`
+
+	// REQ-265: WarmupSince, when set, returns from when candles are needed
+	// for the value at a candle starting at first, e.g. the session start
+	WarmupSince func(params []float64, first time.Time) time.Time `json:"-"`
+}
+
+// Spec is a parsed indicator spec
+type Spec struct {
+	Name   string
+	Params []float64
+}
+
+// String returns the canonical form of the spec, e.g. "bbands(20,2.5)"
+func (s Spec) String() string {
+	params := make([]string, len(s.Params))
+	for i, param := range s.Params {
+		params[i] = strconv.FormatFloat(param, 'f', -1, 64)
+	}
+	return s.Name + "(" + strings.Join(params, ",") + ")"
+}
+
+// ParseSpec parses an indicator spec like "sma(200)" or "bbands(20, 2.5)"
+func ParseSpec(text string) (Spec, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return Spec{}, fmt.Errorf("empty indicator spec")
+	}
+
+	name := text
+	var args string
+	if open := strings.IndexByte(text, '('); open >= 0 {
+		if !strings.HasSuffix(text, ")") {
+			return Spec{}, fmt.Errorf("indicator spec %q: missing closing parenthesis", text)
+		}
+		name = strings.TrimSpace(text[:open])
+		args = strings.TrimSpace(text[open+1 : len(text)-1])
+	}
+
+	if !isIdentifier(name) {
+		return Spec{}, fmt.Errorf("indicator spec %q: invalid name %q", text, name)
+	}
+
+	spec := Spec{Name: name}
+	if args == "" {
+		return spec, nil
+	}
+
+	for _, arg := range strings.Split(args, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return Spec{}, fmt.Errorf("indicator spec %q: invalid parameter %q", text, strings.TrimSpace(arg))
+		}
+		spec.Params = append(spec.Params, value)
+	}
+
+	return spec, nil
+}
+
+// SplitSpecs splits a comma-separated spec list, ignoring commas inside
+// parentheses: "sma(200),bbands(20,2.5)" yields two specs
+func SplitSpecs(list string) []string {
+	var specs []string
+	depth, start := 0, 0
+
+	for i, r := range list {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				if spec := strings.TrimSpace(list[start:i]); spec != "" {
+					specs = append(specs, spec)
+				}
+				start = i + 1
+			}
+		}
+	}
+
+	if spec := strings.TrimSpace(list[start:]); spec != "" {
+		specs = append(specs, spec)
+	}
+
+	return specs
+}
+
+// isIdentifier reports whether name is a valid indicator name
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r == '_':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Registry holds indicator definitions by name
+type Registry struct {
+	definitions map[string]*Definition
+	mu          sync.RWMutex
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{definitions: make(map[string]*Definition)}
+}
+
+var (
+	defaultRegistry     *Registry
+	defaultRegistryOnce sync.Once
+)
+
+// DefaultRegistry returns the shared registry with the built-in indicators
+func DefaultRegistry() *Registry {
+	defaultRegistryOnce.Do(func() {
+		defaultRegistry = NewRegistry()
+		for _, definition := range builtinDefinitions() {
+			if err := defaultRegistry.Register(definition); err != nil {
+				panic(err)
+			}
+		}
+	})
+	return defaultRegistry
+}
+
+// Register adds an indicator definition
+func (r *Registry) Register(definition *Definition) error {
+	if definition == nil || !isIdentifier(definition.Name) {
+		return fmt.Errorf("invalid indicator definition")
+	}
+	if definition.New == nil {
+		return fmt.Errorf("indicator %s: constructor is required", definition.Name)
+	}
+	if len(definition.Outputs) == 0 {
+		return fmt.Errorf("indicator %s: at least one output is required", definition.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.definitions[definition.Name]; exists {
+		return fmt.Errorf("indicator %s already registered", definition.Name)
+	}
+	r.definitions[definition.Name] = definition
+
+	return nil
+}
+
+// Lookup returns the definition of an indicator
+func (r *Registry) Lookup(name string) (*Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definition, exists := r.definitions[name]
+	return definition, exists
+}
+
+// Definitions returns all definitions sorted by name
+func (r *Registry) Definitions() []*Definition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definitions := make([]*Definition, 0, len(r.definitions))
+	for _, definition := range r.definitions {
+		definitions = append(definitions, definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+
+	return definitions
+}
+
+// Resolve parses a spec, fills in default parameters and validates them
+func (r *Registry) Resolve(text string) (Spec, *Definition, error) {
+	spec, err := ParseSpec(text)
+	if err != nil {
+		return Spec{}, nil, err
+	}
+
+	definition, exists := r.Lookup(spec.Name)
+	if !exists {
+		return Spec{}, nil, fmt.Errorf("unknown indicator %q", spec.Name)
+	}
+
+	if len(spec.Params) > len(definition.Params) {
+		return Spec{}, nil, fmt.Errorf("indicator %s takes at most %d parameters, got %d",
+			spec.Name, len(definition.Params), len(spec.Params))
+	}
+
+	params := make([]float64, len(definition.Params))
+	for i, param := range definition.Params {
+		value := param.Default
+		if i < len(spec.Params) {
+			value = spec.Params[i]
+		}
+
+		if param.Integer && value != math.Trunc(value) {
+			return Spec{}, nil, fmt.Errorf("indicator %s: %s must be an integer", spec.Name, param.Name)
+		}
+		if value < param.Min {
+			return Spec{}, nil, fmt.Errorf("indicator %s: %s must be at least %v", spec.Name, param.Name, param.Min)
+		}
+		if param.Max > 0 && value > param.Max {
+			return Spec{}, nil, fmt.Errorf("indicator %s: %s must be at most %v", spec.Name, param.Name, param.Max)
+		}
+		params[i] = value
+	}
+	spec.Params = params
+
+	return spec, definition, nil
+}
+
+// WarmupSince returns the earliest time from which candles are needed for
+// the values of specs at a candle starting at first. It returns the zero time
+// when the specs only need their lookback.
+func (r *Registry) WarmupSince(specs []string, first time.Time) (time.Time, error) {
+	var since time.Time
+	for _, text := range specs {
+		spec, definition, err := r.Resolve(text)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if definition.WarmupSince == nil {
+			continue
+		}
+		if start := definition.WarmupSince(spec.Params, first); since.IsZero() || start.Before(since) {
+			since = start
+		}
+	}
+	return since, nil
+}
+
+// OutputKeys returns the keys under which a resolved spec reports its values
+func OutputKeys(spec Spec, definition *Definition) []string {
+	base := spec.String()
+	if len(definition.Outputs) == 1 {
+		return []string{base}
+	}
+
+	keys := make([]string, len(definition.Outputs))
+	for i, output := range definition.Outputs {
+		keys[i] = base + "." + output
+	}
+	return keys
+}
+
+// SpecSet maintains registry indicators over one candle stream
+type SpecSet struct {
+	registry *Registry
+	entries  map[string]*setEntry // canonical spec -> entry
+	order    []string
+}
+
+// setEntry is a single indicator of a set
+type setEntry struct {
+	indicator Indicator
+	keys      []string
+}
+
+// NewSpecSet creates an empty indicator set
+func NewSpecSet(registry *Registry) *SpecSet {
+	if registry == nil {
+		registry = DefaultRegistry()
+	}
+	return &SpecSet{
+		registry: registry,
+		entries:  make(map[string]*setEntry),
+	}
+}
+
+// Ensure adds the specs that are not yet part of the set, replaying history
+// (chronological, ending with the latest candle already applied to the set)
+// so that new members start out in sync with existing ones
+func (s *SpecSet) Ensure(specs []string, history []*models.OHLCV) error {
+	for _, text := range specs {
+		spec, definition, err := s.registry.Resolve(text)
+		if err != nil {
+			return err
+		}
+
+		canonical := spec.String()
+		if _, exists := s.entries[canonical]; exists {
+			continue
+		}
+
+		indicator := definition.New(spec.Params)
+		for _, candle := range history {
+			indicator.Update(candle)
+		}
+
+		s.entries[canonical] = &setEntry{
+			indicator: indicator,
+			keys:      OutputKeys(spec, definition),
+		}
+		s.order = append(s.order, canonical)
+	}
+
+	return nil
+}
+
+// Update applies the next candle to every indicator of the set
+func (s *SpecSet) Update(candle *models.OHLCV) {
+	for _, canonical := range s.order {
+		s.entries[canonical].indicator.Update(candle)
+	}
+}
+
+// Len returns the number of indicators in the set
+func (s *SpecSet) Len() int { return len(s.order) }
+
+// Values returns the outputs of the given specs keyed by canonical spec.
+// Indicators that are still warming up are omitted.
+func (s *SpecSet) Values(specs []string) (map[string]float64, error) {
+	values := make(map[string]float64)
+
+	for _, text := range specs {
+		spec, _, err := s.registry.Resolve(text)
+		if err != nil {
+			return nil, err
+		}
+
+		entry, exists := s.entries[spec.String()]
+		if !exists {
+			return nil, fmt.Errorf("indicator %s is not part of the set", spec.String())
+		}
+		if !entry.indicator.Ready() {
+			continue
+		}
+
+		for i, value := range entry.indicator.Values() {
+			values[entry.keys[i]] = value
+		}
+	}
+
+	return values, nil
+}
+
+// ComputeSpecs evaluates specs over a chronological candle history
+func ComputeSpecs(registry *Registry, specs []string, candles []*models.OHLCV) (map[string]float64, error) {
+	set := NewSpecSet(registry)
+	if err := set.Ensure(specs, candles); err != nil {
+		return nil, err
+	}
+	return set.Values(specs)
+}
@@ -0,0 +1,569 @@
+package indicators
+
+import (
+	"math"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-255: Built-in registry indicators
+//
+// Each built-in is backed by the streaming primitives and follows the same
+// formulas as the batch functions of this package.
+
+// indicatorFunc adapts closures to the Indicator interface
+type indicatorFunc struct {
+	update func(candle *models.OHLCV)
+	ready  func() bool
+	values func() []float64
+}
+
+func (f *indicatorFunc) Update(candle *models.OHLCV) { f.update(candle) }
+func (f *indicatorFunc) Ready() bool                 { return f.ready() }
+func (f *indicatorFunc) Values() []float64           { return f.values() }
+
+// maxPeriod bounds window sizes so a spec cannot request unbounded memory
+const maxPeriod = 5000
+
+// periodParam is the common integer period parameter
+func periodParam(defaultPeriod int) ParamDef {
+	return ParamDef{Name: "period", Default: float64(defaultPeriod), Min: 1, Max: maxPeriod, Integer: true}
+}
+
+// fixedLookback returns a lookback that ignores parameters
+func fixedLookback(candles int) func(params []float64) int {
+	return func(params []float64) int { return candles }
+}
+
+// sessionWarmup needs the candles since the start of the regular session the
+// first candle's VWAP comes from
+func sessionWarmup(params []float64, first time.Time) time.Time {
+	return SessionVWAPStart(first)
+}
+
+// vwapBandsIndicator reports upper, middle and lower VWAP bands
+func vwapBandsIndicator(updater interface{ Update(*models.OHLCV) }, vwap *StreamingVWAP, multiplier float64) Indicator {
+	return &indicatorFunc{
+		update: updater.Update,
+		ready:  vwap.Ready,
+		values: func() []float64 {
+			middle := vwap.Value()
+			width := vwap.StdDev() * multiplier
+			return []float64{middle + width, middle, middle - width}
+		},
+	}
+}
+
+// builtinDefinitions returns the indicators of the default registry
+func builtinDefinitions() []*Definition {
+	return []*Definition{
+		{
+			Name:        "sma",
+			Description: "Simple moving average of close",
+			Params:      []ParamDef{periodParam(20)},
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				sma := NewStreamingSMA(int(params[0]))
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) { sma.Update(candle.Close) },
+					ready:  sma.Ready,
+					values: func() []float64 { return []float64{sma.Value()} },
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) },
+		},
+		{
+			Name:        "ema",
+			Description: "Exponential moving average of close",
+			Params:      []ParamDef{periodParam(20)},
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				ema := NewStreamingEMA(int(params[0]))
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) { ema.Update(candle.Close) },
+					ready:  ema.Ready,
+					values: func() []float64 { return []float64{ema.Value()} },
+				}
+			},
+			// Seeded averages need several periods to converge
+			Lookback: func(params []float64) int { return int(params[0]) * 4 },
+		},
+		{
+			Name:        "rsi",
+			Description: "Relative strength index",
+			Params:      []ParamDef{periodParam(14)},
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				rsi := NewStreamingRSI(int(params[0]))
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) { rsi.Update(candle.Close) },
+					ready:  rsi.Ready,
+					values: func() []float64 { return []float64{rsi.Value()} },
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) + 1 },
+		},
+		{
+			Name:        "macd",
+			Description: "Moving average convergence divergence",
+			Params: []ParamDef{
+				{Name: "fast", Default: 12, Min: 1, Max: maxPeriod, Integer: true},
+				{Name: "slow", Default: 26, Min: 1, Max: maxPeriod, Integer: true},
+				{Name: "signal", Default: 9, Min: 1, Max: maxPeriod, Integer: true},
+			},
+			Outputs: []string{"line", "signal", "histogram"},
+			New: func(params []float64) Indicator {
+				fast := NewStreamingEMA(int(params[0]))
+				slow := NewStreamingEMA(int(params[1]))
+				signal := NewStreamingEMA(int(params[2]))
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) {
+						fast.Update(candle.Close)
+						slow.Update(candle.Close)
+						if fast.Ready() && slow.Ready() {
+							signal.Update(fast.Value() - slow.Value())
+						}
+					},
+					ready: signal.Ready,
+					values: func() []float64 {
+						line := fast.Value() - slow.Value()
+						return []float64{line, signal.Value(), line - signal.Value()}
+					},
+				}
+			},
+			Lookback: func(params []float64) int {
+				return int(math.Max(params[0], params[1]))*4 + int(params[2])
+			},
+		},
+		{
+			Name:        "stoch",
+			Description: "Stochastic oscillator %K and %D",
+			Params: []ParamDef{
+				{Name: "k", Default: 14, Min: 1, Max: maxPeriod, Integer: true},
+				{Name: "d", Default: 3, Min: 1, Max: maxPeriod, Integer: true},
+			},
+			Outputs: []string{"k", "d"},
+			New: func(params []float64) Indicator {
+				highs := NewRollingMax(int(params[0]))
+				lows := NewRollingMin(int(params[0]))
+				ks := NewRollingWindow(int(params[1]))
+				var k float64
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) {
+						highs.Update(candle.High)
+						lows.Update(candle.Low)
+						if !highs.Ready() {
+							return
+						}
+						k = 50
+						if highs.Value() != lows.Value() {
+							k = ((candle.Close - lows.Value()) / (highs.Value() - lows.Value())) * 100
+						}
+						ks.Push(k)
+					},
+					ready: ks.Full,
+					values: func() []float64 {
+						return []float64{k, ks.Sum() / float64(ks.Len())}
+					},
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) + int(params[1]) - 1 },
+		},
+		{
+			Name:        "willr",
+			Description: "Williams %R",
+			Params:      []ParamDef{periodParam(14)},
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				highs := NewRollingMax(int(params[0]))
+				lows := NewRollingMin(int(params[0]))
+				var lastClose float64
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) {
+						highs.Update(candle.High)
+						lows.Update(candle.Low)
+						lastClose = candle.Close
+					},
+					ready: highs.Ready,
+					values: func() []float64 {
+						if highs.Value() == lows.Value() {
+							return []float64{-50}
+						}
+						return []float64{((highs.Value() - lastClose) / (highs.Value() - lows.Value())) * -100}
+					},
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) },
+		},
+		{
+			Name:        "roc",
+			Description: "Rate of change of close in percent",
+			Params:      []ParamDef{periodParam(10)},
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				closes := NewRollingWindow(int(params[0]) + 1)
+				var lastClose float64
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) {
+						closes.Push(candle.Close)
+						lastClose = candle.Close
+					},
+					ready: closes.Full,
+					values: func() []float64 {
+						past := closes.Oldest()
+						if past == 0 {
+							return []float64{0}
+						}
+						return []float64{((lastClose - past) / past) * 100}
+					},
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) + 1 },
+		},
+		{
+			Name:        "bbands",
+			Description: "Bollinger Bands",
+			Params: []ParamDef{
+				periodParam(20),
+				{Name: "stddev", Default: 2, Min: 0},
+			},
+			Outputs: []string{"upper", "middle", "lower"},
+			New: func(params []float64) Indicator {
+				sma := NewStreamingSMA(int(params[0]))
+				stdDev := NewStreamingStdDev(int(params[0]))
+				multiplier := params[1]
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) {
+						sma.Update(candle.Close)
+						stdDev.Update(candle.Close)
+					},
+					ready: sma.Ready,
+					values: func() []float64 {
+						middle := sma.Value()
+						width := stdDev.Value() * multiplier
+						return []float64{middle + width, middle, middle - width}
+					},
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) },
+		},
+		{
+			Name:        "stddev",
+			Description: "Population standard deviation of close",
+			Params:      []ParamDef{periodParam(20)},
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				stdDev := NewStreamingStdDev(int(params[0]))
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) { stdDev.Update(candle.Close) },
+					ready:  stdDev.Ready,
+					values: func() []float64 { return []float64{stdDev.Value()} },
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) },
+		},
+		{
+			Name:        "atr",
+			Description: "Average true range",
+			Params:      []ParamDef{periodParam(14)},
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				atr := NewStreamingATR(int(params[0]))
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) { atr.Update(candle) },
+					ready:  atr.Ready,
+					values: func() []float64 { return []float64{atr.Value()} },
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) + 1 },
+		},
+		{
+			Name:        "vma",
+			Description: "Simple moving average of volume",
+			Params:      []ParamDef{periodParam(20)},
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				volumes := NewStreamingSMA(int(params[0]))
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) { volumes.Update(float64(candle.Volume)) },
+					ready:  volumes.Ready,
+					values: func() []float64 { return []float64{volumes.Value()} },
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) },
+		},
+		{
+			Name:        "vwap",
+			Description: "Volume weighted average price since the session open",
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				var vwap SessionVWAP
+				return &indicatorFunc{
+					update: vwap.Update,
+					ready:  vwap.Ready,
+					values: func() []float64 { return []float64{vwap.Value()} },
+				}
+			},
+			Lookback:    fixedLookback(0),
+			WarmupSince: sessionWarmup,
+		},
+		{
+			Name:        "vwapbands",
+			Description: "Session VWAP with standard deviation bands",
+			Params:      []ParamDef{{Name: "stddev", Default: 2, Min: 0}},
+			Outputs:     []string{"upper", "middle", "lower"},
+			New: func(params []float64) Indicator {
+				var vwap SessionVWAP
+				return vwapBandsIndicator(&vwap, &vwap.StreamingVWAP, params[0])
+			},
+			Lookback:    fixedLookback(0),
+			WarmupSince: sessionWarmup,
+		},
+		{
+			Name:        "avwap",
+			Description: "VWAP anchored at a Unix timestamp in seconds, with standard deviation bands",
+			Params: []ParamDef{
+				{Name: "anchor", Min: 1, Integer: true},
+				{Name: "stddev", Default: 2, Min: 0},
+			},
+			Outputs: []string{"upper", "middle", "lower"},
+			New: func(params []float64) Indicator {
+				vwap := NewAnchoredVWAP(time.Unix(int64(params[0]), 0))
+				return vwapBandsIndicator(vwap, &vwap.StreamingVWAP, params[1])
+			},
+			Lookback: fixedLookback(0),
+			WarmupSince: func(params []float64, first time.Time) time.Time {
+				return time.Unix(int64(params[0]), 0)
+			},
+		},
+		{
+			Name:        "obv",
+			Description: "On-balance volume",
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				var obv, previousClose float64
+				count := 0
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) {
+						if count == 0 {
+							obv = float64(candle.Volume)
+						} else if candle.Close > previousClose {
+							obv += float64(candle.Volume)
+						} else if candle.Close < previousClose {
+							obv -= float64(candle.Volume)
+						}
+						previousClose = candle.Close
+						count++
+					},
+					ready:  func() bool { return count >= 2 },
+					values: func() []float64 { return []float64{obv} },
+				}
+			},
+			Lookback: fixedLookback(2),
+		},
+		{
+			Name:        "ad",
+			Description: "Accumulation/distribution line",
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				var adLine float64
+				count := 0
+				return &indicatorFunc{
+					update: func(candle *models.OHLCV) {
+						if candle.High != candle.Low {
+							mfm := ((candle.Close - candle.Low) - (candle.High - candle.Close)) / (candle.High - candle.Low)
+							adLine += mfm * float64(candle.Volume)
+						}
+						count++
+					},
+					ready:  func() bool { return count > 0 },
+					values: func() []float64 { return []float64{adLine} },
+				}
+			},
+			Lookback: fixedLookback(1),
+		},
+		// REQ-256: Trend strength, channel and money flow indicators
+		{
+			Name:        "adx",
+			Description: "Average directional index with +DI and -DI",
+			Params:      []ParamDef{periodParam(14)},
+			Outputs:     []string{"adx", "plus_di", "minus_di"},
+			New: func(params []float64) Indicator {
+				adx := NewStreamingADX(int(params[0]))
+				return &indicatorFunc{
+					update: adx.Update,
+					ready:  adx.Ready,
+					values: func() []float64 {
+						value, plusDI, minusDI := adx.Values()
+						return []float64{value, plusDI, minusDI}
+					},
+				}
+			},
+			// Wilder smoothing needs several periods to converge
+			Lookback: func(params []float64) int { return int(params[0]) * 4 },
+		},
+		{
+			Name:        "psar",
+			Description: "Parabolic SAR; trend is 1 when bullish and -1 when bearish",
+			Params: []ParamDef{
+				{Name: "step", Default: 0.02, Min: 0.001, Max: 1},
+				{Name: "max", Default: 0.2, Min: 0.001, Max: 1},
+			},
+			Outputs: []string{"value", "trend"},
+			New: func(params []float64) Indicator {
+				psar := NewStreamingPSAR(params[0], params[1])
+				return &indicatorFunc{
+					update: psar.Update,
+					ready:  psar.Ready,
+					values: func() []float64 {
+						sar, bullish := psar.Value()
+						return []float64{sar, trendSign(bullish)}
+					},
+				}
+			},
+			// The SAR is path dependent; settle it over a fixed number of candles
+			Lookback: fixedLookback(100),
+		},
+		{
+			Name:        "supertrend",
+			Description: "SuperTrend line; trend is 1 when bullish and -1 when bearish",
+			Params: []ParamDef{
+				periodParam(10),
+				{Name: "multiplier", Default: 3, Min: 0},
+			},
+			Outputs: []string{"value", "trend"},
+			New: func(params []float64) Indicator {
+				superTrend := NewStreamingSuperTrend(int(params[0]), params[1])
+				return &indicatorFunc{
+					update: superTrend.Update,
+					ready:  superTrend.Ready,
+					values: func() []float64 {
+						value, bullish := superTrend.Value()
+						return []float64{value, trendSign(bullish)}
+					},
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) * 4 },
+		},
+		{
+			Name:        "ichimoku",
+			Description: "Ichimoku cloud; ready once the projected cloud is complete",
+			Params: []ParamDef{
+				{Name: "conversion", Default: 9, Min: 1, Max: maxPeriod, Integer: true},
+				{Name: "base", Default: 26, Min: 1, Max: maxPeriod, Integer: true},
+				{Name: "span_b", Default: 52, Min: 1, Max: maxPeriod, Integer: true},
+			},
+			Outputs: []string{"tenkan", "kijun", "senkou_a", "senkou_b", "cloud_a", "cloud_b"},
+			New: func(params []float64) Indicator {
+				ichimoku := NewStreamingIchimoku(int(params[0]), int(params[1]), int(params[2]))
+				return &indicatorFunc{
+					update: ichimoku.Update,
+					ready:  ichimoku.Ready,
+					values: func() []float64 {
+						cloud := ichimoku.Value()
+						return []float64{cloud.Tenkan, cloud.Kijun, cloud.SenkouA, cloud.SenkouB, cloud.CloudA, cloud.CloudB}
+					},
+				}
+			},
+			Lookback: func(params []float64) int {
+				return int(params[1]) + int(math.Max(params[0], params[2]))
+			},
+		},
+		{
+			Name:        "keltner",
+			Description: "Keltner Channels: EMA of close with ATR bands",
+			Params: []ParamDef{
+				{Name: "period", Default: 20, Min: 1, Max: maxPeriod, Integer: true},
+				{Name: "atr", Default: 10, Min: 1, Max: maxPeriod, Integer: true},
+				{Name: "multiplier", Default: 2, Min: 0},
+			},
+			Outputs: []string{"upper", "middle", "lower"},
+			New: func(params []float64) Indicator {
+				keltner := NewStreamingKeltner(int(params[0]), int(params[1]), params[2])
+				return &indicatorFunc{
+					update: keltner.Update,
+					ready:  keltner.Ready,
+					values: func() []float64 {
+						upper, middle, lower := keltner.Values()
+						return []float64{upper, middle, lower}
+					},
+				}
+			},
+			Lookback: func(params []float64) int {
+				return int(math.Max(params[0]*4, params[1]+1))
+			},
+		},
+		{
+			Name:        "donchian",
+			Description: "Donchian Channels of the highest high and lowest low",
+			Params:      []ParamDef{periodParam(20)},
+			Outputs:     []string{"upper", "middle", "lower"},
+			New: func(params []float64) Indicator {
+				donchian := NewStreamingDonchian(int(params[0]))
+				return &indicatorFunc{
+					update: donchian.Update,
+					ready:  donchian.Ready,
+					values: func() []float64 {
+						upper, middle, lower := donchian.Values()
+						return []float64{upper, middle, lower}
+					},
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) },
+		},
+		{
+			Name:        "cci",
+			Description: "Commodity channel index",
+			Params:      []ParamDef{periodParam(20)},
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				cci := NewStreamingCCI(int(params[0]))
+				return &indicatorFunc{
+					update: cci.Update,
+					ready:  cci.Ready,
+					values: func() []float64 { return []float64{cci.Value()} },
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) },
+		},
+		{
+			Name:        "mfi",
+			Description: "Money flow index",
+			Params:      []ParamDef{periodParam(14)},
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				mfi := NewStreamingMFI(int(params[0]))
+				return &indicatorFunc{
+					update: mfi.Update,
+					ready:  mfi.Ready,
+					values: func() []float64 { return []float64{mfi.Value()} },
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) + 1 },
+		},
+		{
+			Name:        "cmf",
+			Description: "Chaikin money flow",
+			Params:      []ParamDef{periodParam(20)},
+			Outputs:     []string{"value"},
+			New: func(params []float64) Indicator {
+				cmf := NewStreamingCMF(int(params[0]))
+				return &indicatorFunc{
+					update: cmf.Update,
+					ready:  cmf.Ready,
+					values: func() []float64 { return []float64{cmf.Value()} },
+				}
+			},
+			Lookback: func(params []float64) int { return int(params[0]) },
+		},
+	}
+}
+
+// trendSign encodes a trend direction as 1 (bullish) or -1 (bearish)
+func trendSign(bullish bool) float64 {
+	if bullish {
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,156 @@
+package indicators
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveCanonicalSpecs(t *testing.T) {
+	registry := DefaultRegistry()
+
+	cases := map[string]string{
+		"sma(200)":          "sma(200)",
+		"EMA( 9 )":          "ema(9)",
+		"rsi":               "rsi(14)",
+		"bbands(20, 2.50)":  "bbands(20,2.5)",
+		"macd(8,21)":        "macd(8,21,9)",
+		"stoch()":           "stoch(14,3)",
+		"  vwap  ":          "vwap()",
+		"bbands( 20 ,2.5 )": "bbands(20,2.5)",
+		"ichimoku":          "ichimoku(9,26,52)",
+		"supertrend(7)":     "supertrend(7,3)",
+	}
+
+	for text, want := range cases {
+		spec, _, err := registry.Resolve(text)
+		if err != nil {
+			t.Fatalf("Resolve(%q) failed: %v", text, err)
+		}
+		if got := spec.String(); got != want {
+			t.Errorf("Resolve(%q) = %s, want %s", text, got, want)
+		}
+	}
+
+	invalid := map[string]string{
+		"foo(3)":        "unknown indicator",
+		"sma(0)":        "at least 1",
+		"sma(2.5)":      "must be an integer",
+		"sma(20,3)":     "at most 1 parameters",
+		"sma(20":        "missing closing parenthesis",
+		"sma(x)":        "invalid parameter",
+		"sma(100000)":   "at most 5000",
+		"":              "empty indicator spec",
+		"1sma(3)":       "invalid name",
+		"bbands(20,-1)": "at least 0",
+	}
+
+	for text, want := range invalid {
+		_, _, err := registry.Resolve(text)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Resolve(%q) error = %v, want %q", text, err, want)
+		}
+	}
+}
+
+func TestSplitSpecs(t *testing.T) {
+	got := SplitSpecs("sma(200), bbands(20,2.5),,rsi")
+	want := []string{"sma(200)", "bbands(20,2.5)", "rsi"}
+
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("SplitSpecs = %v, want %v", got, want)
+	}
+}
+
+func TestComputeSpecsMatchesBatch(t *testing.T) {
+	candles := generateRandomWalk(400)
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close
+	}
+
+	values, err := ComputeSpecs(nil, []string{"sma(200)", "ema(9)", "bbands(20,2.5)", "rsi(7)", "atr(21)", "adx(14)", "keltner(20,10,1.5)", "mfi(10)", "sma(500)"}, candles)
+	if err != nil {
+		t.Fatalf("ComputeSpecs failed: %v", err)
+	}
+
+	upper, middle, lower := BollingerBands(closes, 20, 2.5)
+	adx, plusDI, minusDI := ADX(candles, 14)
+	keltnerUpper, _, keltnerLower := KeltnerChannels(candles, 20, 10, 1.5)
+	expected := map[string]float64{
+		"sma(200)":                 SMA(closes, 200),
+		"ema(9)":                   EMA(closes, 9),
+		"bbands(20,2.5).upper":     upper,
+		"bbands(20,2.5).middle":    middle,
+		"bbands(20,2.5).lower":     lower,
+		"rsi(7)":                   RSI(closes, 7),
+		"atr(21)":                  ATR(candles, 21),
+		"adx(14).adx":              adx,
+		"adx(14).plus_di":          plusDI,
+		"adx(14).minus_di":         minusDI,
+		"keltner(20,10,1.5).upper": keltnerUpper,
+		"keltner(20,10,1.5).lower": keltnerLower,
+		"mfi(10)":                  MFI(candles, 10),
+	}
+
+	for key, want := range expected {
+		got, exists := values[key]
+		if !exists {
+			t.Fatalf("missing value for %s", key)
+		}
+		assertClose(t, len(candles)-1, key, got, want)
+	}
+
+	if _, exists := values["sma(500)"]; exists {
+		t.Error("expected sma(500) to be omitted while warming up")
+	}
+}
+
+func TestComputeSeriesMatchesLatestValues(t *testing.T) {
+	candles := generateRandomWalk(300)
+	specs := []string{"rsi(14)", "macd(12,26,9)", "sma(120)", "RSI(14)"}
+	skip := 100
+
+	series, err := ComputeSeries(nil, specs, candles, skip)
+	if err != nil {
+		t.Fatalf("ComputeSeries failed: %v", err)
+	}
+	if len(series) != 5 {
+		t.Fatalf("expected 5 series (duplicate spec reported once), got %d", len(series))
+	}
+
+	for i := skip; i < len(candles); i++ {
+		latest, err := ComputeSpecs(nil, specs, candles[:i+1])
+		if err != nil {
+			t.Fatalf("ComputeSpecs failed: %v", err)
+		}
+
+		for _, s := range series {
+			if len(s.Values) != len(candles)-skip {
+				t.Fatalf("%s has %d values, want %d", s.Key, len(s.Values), len(candles)-skip)
+			}
+
+			want, ready := latest[s.Key]
+			got := s.Values[i-skip]
+			if !ready {
+				if got != nil {
+					t.Fatalf("candle %d: %s = %v while warming up, want nil", i, s.Key, *got)
+				}
+				continue
+			}
+			if got == nil {
+				t.Fatalf("candle %d: %s missing", i, s.Key)
+			}
+			assertClose(t, i, s.Key, *got, want)
+		}
+	}
+}
+
+func TestRegistryLookback(t *testing.T) {
+	lookback, err := DefaultRegistry().Lookback([]string{"rsi(14)", "sma(200)", "macd(12,26,9)"})
+	if err != nil {
+		t.Fatalf("Lookback failed: %v", err)
+	}
+	if lookback != 200 {
+		t.Errorf("Lookback = %d, want 200", lookback)
+	}
+}
@@ -0,0 +1,142 @@
+package indicators
+
+import (
+	"math"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-263: Relative strength, beta and correlation against a benchmark
+
+// RelativeIndicators compares an asset with a benchmark over a window
+type RelativeIndicators struct {
+	RelativeStrength float64 `json:"relative_strength"` // 100 = same performance
+	ExcessReturn     float64 `json:"excess_return"`     // percent
+	Beta             float64 `json:"beta"`
+	Correlation      float64 `json:"correlation"`
+	Observations     int     `json:"observations"` // aligned returns used
+}
+
+// AlignedCloses returns the closes of both candle series at the timestamps
+// they share, in chronological order
+func AlignedCloses(asset, benchmark []*models.OHLCV) ([]float64, []float64) {
+	closes := make(map[time.Time]float64, len(benchmark))
+	for _, candle := range benchmark {
+		closes[candle.Timestamp.UTC()] = candle.Close
+	}
+
+	var assetCloses, benchmarkCloses []float64
+	for _, candle := range asset {
+		if close, exists := closes[candle.Timestamp.UTC()]; exists {
+			assetCloses = append(assetCloses, candle.Close)
+			benchmarkCloses = append(benchmarkCloses, close)
+		}
+	}
+	return assetCloses, benchmarkCloses
+}
+
+// Returns calculates simple period returns of prices
+func Returns(prices []float64) []float64 {
+	if len(prices) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, 0, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		if prices[i-1] == 0 {
+			returns = append(returns, 0)
+			continue
+		}
+		returns = append(returns, prices[i]/prices[i-1]-1)
+	}
+	return returns
+}
+
+// Correlation calculates the Pearson correlation of two equally long series;
+// it is 0 when either series is constant
+func Correlation(x, y []float64) float64 {
+	n := len(x)
+	if n < 2 || n != len(y) {
+		return 0
+	}
+
+	meanX, meanY := mean(x), mean(y)
+	covariance, varianceX, varianceY := 0.0, 0.0, 0.0
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+
+	if varianceX == 0 || varianceY == 0 {
+		return 0
+	}
+	return covariance / math.Sqrt(varianceX*varianceY)
+}
+
+// Beta calculates the sensitivity of asset returns to benchmark returns
+func Beta(assetReturns, benchmarkReturns []float64) float64 {
+	n := len(assetReturns)
+	if n < 2 || n != len(benchmarkReturns) {
+		return 0
+	}
+
+	meanAsset, meanBenchmark := mean(assetReturns), mean(benchmarkReturns)
+	covariance, variance := 0.0, 0.0
+	for i := range assetReturns {
+		db := benchmarkReturns[i] - meanBenchmark
+		covariance += (assetReturns[i] - meanAsset) * db
+		variance += db * db
+	}
+
+	if variance == 0 {
+		return 0
+	}
+	return covariance / variance
+}
+
+// Relative compares the last period+1 aligned closes of an asset with the
+// benchmark's. It returns nil with fewer than minObservations aligned returns.
+func Relative(assetCloses, benchmarkCloses []float64, period, minObservations int) *RelativeIndicators {
+	if len(assetCloses) != len(benchmarkCloses) {
+		return nil
+	}
+	if len(assetCloses) > period+1 {
+		assetCloses = assetCloses[len(assetCloses)-period-1:]
+		benchmarkCloses = benchmarkCloses[len(benchmarkCloses)-period-1:]
+	}
+
+	assetReturns, benchmarkReturns := Returns(assetCloses), Returns(benchmarkCloses)
+	if len(assetReturns) < minObservations || len(assetReturns) < 2 {
+		return nil
+	}
+
+	first, last := 0, len(assetCloses)-1
+	if assetCloses[first] == 0 || benchmarkCloses[first] == 0 {
+		return nil
+	}
+	assetReturn := assetCloses[last]/assetCloses[first] - 1
+	benchmarkReturn := benchmarkCloses[last]/benchmarkCloses[first] - 1
+
+	relative := &RelativeIndicators{
+		ExcessReturn: (assetReturn - benchmarkReturn) * 100,
+		Beta:         Beta(assetReturns, benchmarkReturns),
+		Correlation:  Correlation(assetReturns, benchmarkReturns),
+		Observations: len(assetReturns),
+	}
+	if benchmarkReturn > -1 {
+		relative.RelativeStrength = (1 + assetReturn) / (1 + benchmarkReturn) * 100
+	}
+
+	return relative
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
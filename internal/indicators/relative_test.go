@@ -0,0 +1,56 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+func TestRelative(t *testing.T) {
+	// The asset moves twice as much as the benchmark every period
+	benchmark := []float64{100}
+	asset := []float64{50}
+	moves := []float64{0.01, -0.02, 0.015, 0.005, -0.01, 0.02, -0.005, 0.01}
+	for _, move := range moves {
+		benchmark = append(benchmark, benchmark[len(benchmark)-1]*(1+move))
+		asset = append(asset, asset[len(asset)-1]*(1+2*move))
+	}
+
+	relative := Relative(asset, benchmark, 20, 5)
+	if relative == nil {
+		t.Fatal("Expected relative indicators")
+	}
+	if math.Abs(relative.Beta-2) > 1e-9 || math.Abs(relative.Correlation-1) > 1e-9 {
+		t.Errorf("beta = %v, correlation = %v, want 2 and 1", relative.Beta, relative.Correlation)
+	}
+
+	assetReturn := asset[len(asset)-1]/asset[0] - 1
+	benchmarkReturn := benchmark[len(benchmark)-1]/benchmark[0] - 1
+	if want := (1 + assetReturn) / (1 + benchmarkReturn) * 100; math.Abs(relative.RelativeStrength-want) > 1e-9 {
+		t.Errorf("relative strength = %v, want %v", relative.RelativeStrength, want)
+	}
+	if relative.Observations != len(moves) {
+		t.Errorf("observations = %d, want %d", relative.Observations, len(moves))
+	}
+
+	if Relative(asset[:4], benchmark[:4], 20, 5) != nil {
+		t.Error("Expected nil with too few observations")
+	}
+}
+
+func TestAlignedClosesSkipsMissingTimestamps(t *testing.T) {
+	base := time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC)
+	candle := func(minute int, close float64) *models.OHLCV {
+		return &models.OHLCV{Timestamp: base.Add(time.Duration(minute) * time.Minute), Close: close}
+	}
+
+	asset := []*models.OHLCV{candle(0, 1), candle(1, 2), candle(2, 3), candle(3, 4)}
+	benchmark := []*models.OHLCV{candle(0, 10), candle(2, 30), candle(3, 40), candle(4, 50)}
+
+	a, b := AlignedCloses(asset, benchmark)
+	if len(a) != 3 || a[1] != 3 || b[1] != 30 {
+		t.Errorf("AlignedCloses = %v, %v", a, b)
+	}
+}
@@ -0,0 +1,93 @@
+package indicators
+
+import (
+	"fmt"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-257: Aligned indicator time series
+
+// Lookback returns the number of candles the specs need before the first
+// value of a range to match live computation
+func (r *Registry) Lookback(specs []string) (int, error) {
+	lookback := 0
+	for _, text := range specs {
+		spec, definition, err := r.Resolve(text)
+		if err != nil {
+			return 0, err
+		}
+		if candles := definition.Lookback(spec.Params); candles > lookback {
+			lookback = candles
+		}
+	}
+	return lookback, nil
+}
+
+// ComputeSeries evaluates specs over a chronological candle history and
+// returns the series of the candles from index skip on. The candles before
+// skip only warm up the indicators. Duplicate specs are reported once.
+func ComputeSeries(registry *Registry, specs []string, candles []*models.OHLCV, skip int) ([]models.IndicatorSeries, error) {
+	if registry == nil {
+		registry = DefaultRegistry()
+	}
+	if skip < 0 || skip > len(candles) {
+		return nil, fmt.Errorf("skip %d out of range for %d candles", skip, len(candles))
+	}
+
+	type seriesEntry struct {
+		indicator Indicator
+		first     int // index of the entry's first series
+		outputs   int
+	}
+
+	var entries []seriesEntry
+	var series []models.IndicatorSeries
+	seen := make(map[string]bool)
+
+	for _, text := range specs {
+		spec, definition, err := registry.Resolve(text)
+		if err != nil {
+			return nil, err
+		}
+
+		canonical := spec.String()
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+
+		keys := OutputKeys(spec, definition)
+		entries = append(entries, seriesEntry{
+			indicator: definition.New(spec.Params),
+			first:     len(series),
+			outputs:   len(keys),
+		})
+		for _, key := range keys {
+			series = append(series, models.IndicatorSeries{Key: key, Values: make([]*float64, 0, len(candles)-skip)})
+		}
+	}
+
+	for i, candle := range candles {
+		for _, entry := range entries {
+			entry.indicator.Update(candle)
+			if i < skip {
+				continue
+			}
+
+			if !entry.indicator.Ready() {
+				for j := entry.first; j < entry.first+entry.outputs; j++ {
+					series[j].Values = append(series[j].Values, nil)
+				}
+				continue
+			}
+
+			for j, value := range entry.indicator.Values() {
+				value := value
+				series[entry.first+j].Values = append(series[entry.first+j].Values, &value)
+			}
+		}
+	}
+
+	return series, nil
+}
@@ -0,0 +1,703 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-254: Incremental streaming indicators with O(1) updates
+//
+// Each streaming indicator mirrors its batch counterpart: after feeding a
+// sequence of candles through Update, Value matches the batch function applied
+// to the same sequence. Windowed sums are maintained as running totals and
+// re-summed once per window to keep floating-point drift bounded.
+
+// RollingWindow is a fixed-size ring buffer with a running sum
+type RollingWindow struct {
+	values  []float64
+	head    int // index of the oldest value
+	count   int
+	sum     float64
+	updates int
+}
+
+// NewRollingWindow creates a rolling window of the given size
+func NewRollingWindow(size int) *RollingWindow {
+	if size < 1 {
+		size = 1
+	}
+	return &RollingWindow{values: make([]float64, size)}
+}
+
+// Push adds a value, evicting the oldest one when the window is full
+func (w *RollingWindow) Push(value float64) {
+	size := len(w.values)
+
+	if w.count < size {
+		w.values[(w.head+w.count)%size] = value
+		w.count++
+		w.sum += value
+	} else {
+		w.sum += value - w.values[w.head]
+		w.values[w.head] = value
+		w.head = (w.head + 1) % size
+	}
+
+	w.updates++
+	if w.updates%size == 0 {
+		w.resum()
+	}
+}
+
+// resum recomputes the running sum in oldest-to-newest order
+func (w *RollingWindow) resum() {
+	sum := 0.0
+	for i := 0; i < w.count; i++ {
+		sum += w.values[(w.head+i)%len(w.values)]
+	}
+	w.sum = sum
+}
+
+// Sum returns the sum of the values in the window
+func (w *RollingWindow) Sum() float64 { return w.sum }
+
+// Len returns the number of values in the window
+func (w *RollingWindow) Len() int { return w.count }
+
+// Full reports whether the window holds size values
+func (w *RollingWindow) Full() bool { return w.count == len(w.values) }
+
+// Oldest returns the oldest value in the window
+func (w *RollingWindow) Oldest() float64 { return w.values[w.head] }
+
+// At returns the i-th value, oldest first
+func (w *RollingWindow) At(i int) float64 { return w.values[(w.head+i)%len(w.values)] }
+
+// StreamingSMA is the incremental form of SMA
+type StreamingSMA struct {
+	window *RollingWindow
+}
+
+// NewStreamingSMA creates an incremental simple moving average
+func NewStreamingSMA(period int) *StreamingSMA {
+	return &StreamingSMA{window: NewRollingWindow(period)}
+}
+
+// Update adds a price and returns the current value
+func (s *StreamingSMA) Update(price float64) float64 {
+	s.window.Push(price)
+	return s.Value()
+}
+
+// Value returns the SMA, or 0 until the window is full
+func (s *StreamingSMA) Value() float64 {
+	if !s.window.Full() {
+		return 0
+	}
+	return s.window.Sum() / float64(s.window.Len())
+}
+
+// Ready reports whether the window is full
+func (s *StreamingSMA) Ready() bool { return s.window.Full() }
+
+// StreamingEMA is the incremental form of EMA, seeded with the SMA of the
+// first period prices
+type StreamingEMA struct {
+	period     int
+	multiplier float64
+	sum        float64
+	value      float64
+	count      int
+}
+
+// NewStreamingEMA creates an incremental exponential moving average
+func NewStreamingEMA(period int) *StreamingEMA {
+	return &StreamingEMA{period: period, multiplier: 2.0 / (float64(period) + 1.0)}
+}
+
+// Update adds a price and returns the current value
+func (e *StreamingEMA) Update(price float64) float64 {
+	e.count++
+	if e.count < e.period {
+		e.sum += price
+	} else if e.count == e.period {
+		e.value = (e.sum + price) / float64(e.period)
+	} else {
+		e.value = (price * e.multiplier) + (e.value * (1 - e.multiplier))
+	}
+	return e.value
+}
+
+// Value returns the current EMA, or 0 until period prices were seen
+func (e *StreamingEMA) Value() float64 { return e.value }
+
+// Count returns the number of prices seen
+func (e *StreamingEMA) Count() int { return e.count }
+
+// Ready reports whether at least period prices were seen
+func (e *StreamingEMA) Ready() bool { return e.count >= e.period }
+
+// WilderAverage is Wilder's running average: the first value is the simple
+// average of period samples, later values are (previous*(period-1)+x)/period
+type WilderAverage struct {
+	period int
+	count  int
+	sum    float64
+	value  float64
+}
+
+// NewWilderAverage creates a Wilder running average
+func NewWilderAverage(period int) *WilderAverage {
+	return &WilderAverage{period: period}
+}
+
+// Update adds a sample and returns the current value
+func (w *WilderAverage) Update(x float64) float64 {
+	w.count++
+	if w.count <= w.period {
+		w.sum += x
+		if w.count == w.period {
+			w.value = w.sum / float64(w.period)
+		}
+	} else {
+		w.value = (w.value*float64(w.period-1) + x) / float64(w.period)
+	}
+	return w.value
+}
+
+// Value returns the average, or 0 until period samples were seen
+func (w *WilderAverage) Value() float64 { return w.value }
+
+// Ready reports whether period samples were seen
+func (w *WilderAverage) Ready() bool { return w.count >= w.period }
+
+// StreamingRSI is the incremental form of RSI
+type StreamingRSI struct {
+	avgGain   *WilderAverage
+	avgLoss   *WilderAverage
+	lastPrice float64
+	count     int
+}
+
+// NewStreamingRSI creates an incremental relative strength index
+func NewStreamingRSI(period int) *StreamingRSI {
+	return &StreamingRSI{
+		avgGain: NewWilderAverage(period),
+		avgLoss: NewWilderAverage(period),
+	}
+}
+
+// Update adds a price and returns the current value
+func (r *StreamingRSI) Update(price float64) float64 {
+	if r.count > 0 {
+		gain, loss := priceChange(price - r.lastPrice)
+		r.avgGain.Update(gain)
+		r.avgLoss.Update(loss)
+	}
+	r.lastPrice = price
+	r.count++
+	return r.Value()
+}
+
+// Value returns the RSI, or 50 until enough prices were seen
+func (r *StreamingRSI) Value() float64 {
+	if !r.Ready() {
+		return 50 // Neutral RSI
+	}
+	return rsiOf(r.avgGain.Value(), r.avgLoss.Value())
+}
+
+// Ready reports whether period changes were seen
+func (r *StreamingRSI) Ready() bool { return r.avgGain.Ready() }
+
+// RollingExtreme tracks the maximum or minimum of a sliding window using a
+// monotonic deque, giving amortized O(1) updates
+type RollingExtreme struct {
+	period  int
+	max     bool
+	indices []int
+	values  []float64
+	seen    int
+}
+
+// NewRollingMax creates a rolling maximum over period values
+func NewRollingMax(period int) *RollingExtreme {
+	return &RollingExtreme{period: period, max: true}
+}
+
+// NewRollingMin creates a rolling minimum over period values
+func NewRollingMin(period int) *RollingExtreme {
+	return &RollingExtreme{period: period}
+}
+
+// Update adds a value and returns the current extreme
+func (r *RollingExtreme) Update(value float64) float64 {
+	for n := len(r.values); n > 0; n = len(r.values) {
+		last := r.values[n-1]
+		if (r.max && last > value) || (!r.max && last < value) {
+			break
+		}
+		r.values = r.values[:n-1]
+		r.indices = r.indices[:n-1]
+	}
+
+	r.values = append(r.values, value)
+	r.indices = append(r.indices, r.seen)
+	r.seen++
+
+	if r.indices[0] <= r.seen-1-r.period {
+		r.values = r.values[1:]
+		r.indices = r.indices[1:]
+	}
+
+	return r.values[0]
+}
+
+// Value returns the current extreme
+func (r *RollingExtreme) Value() float64 {
+	if len(r.values) == 0 {
+		return 0
+	}
+	return r.values[0]
+}
+
+// Ready reports whether a full window was seen
+func (r *RollingExtreme) Ready() bool { return r.seen >= r.period }
+
+// StreamingStdDev is the incremental form of StandardDeviation (population)
+type StreamingStdDev struct {
+	window  *RollingWindow
+	mean    float64
+	m2      float64
+	updates int
+}
+
+// NewStreamingStdDev creates an incremental standard deviation
+func NewStreamingStdDev(period int) *StreamingStdDev {
+	return &StreamingStdDev{window: NewRollingWindow(period)}
+}
+
+// Update adds a price and returns the current value
+func (s *StreamingStdDev) Update(price float64) float64 {
+	if s.window.Full() {
+		// Sliding Welford update: remove the evicted value, then add the new one
+		old := s.window.Oldest()
+		n := float64(s.window.Len())
+		if n > 1 {
+			delta := old - s.mean
+			s.mean -= delta / (n - 1)
+			s.m2 -= delta * (old - s.mean)
+		} else {
+			s.mean, s.m2 = 0, 0
+		}
+	}
+
+	s.window.Push(price)
+
+	n := float64(s.window.Len())
+	delta := price - s.mean
+	s.mean += delta / n
+	s.m2 += delta * (price - s.mean)
+
+	s.updates++
+	if s.updates%len(s.window.values) == 0 {
+		s.recompute()
+	}
+
+	return s.Value()
+}
+
+// recompute rebuilds mean and M2 from the window to bound drift
+func (s *StreamingStdDev) recompute() {
+	n := s.window.Len()
+	mean := 0.0
+	for i := 0; i < n; i++ {
+		mean += s.window.At(i)
+	}
+	mean /= float64(n)
+
+	m2 := 0.0
+	for i := 0; i < n; i++ {
+		m2 += math.Pow(s.window.At(i)-mean, 2)
+	}
+
+	s.mean = mean
+	s.m2 = m2
+}
+
+// Value returns the standard deviation, or 0 until the window is full
+func (s *StreamingStdDev) Value() float64 {
+	if !s.window.Full() {
+		return 0
+	}
+	variance := s.m2 / float64(s.window.Len())
+	if variance < 0 {
+		variance = 0
+	}
+	return math.Sqrt(variance)
+}
+
+// Mean returns the mean of the window
+func (s *StreamingStdDev) Mean() float64 { return s.mean }
+
+// Ready reports whether the window is full
+func (s *StreamingStdDev) Ready() bool { return s.window.Full() }
+
+// StreamingATR is the incremental form of ATR
+type StreamingATR struct {
+	average  *WilderAverage
+	previous *models.OHLCV
+}
+
+// NewStreamingATR creates an incremental average true range
+func NewStreamingATR(period int) *StreamingATR {
+	return &StreamingATR{average: NewWilderAverage(period)}
+}
+
+// Update adds a candle and returns the current value
+func (a *StreamingATR) Update(candle *models.OHLCV) float64 {
+	if a.previous != nil {
+		a.average.Update(TrueRange(candle, a.previous))
+	}
+	a.previous = candle
+	return a.Value()
+}
+
+// Value returns the ATR, or 0 until period+1 candles were seen
+func (a *StreamingATR) Value() float64 { return a.average.Value() }
+
+// Ready reports whether period+1 candles were seen
+func (a *StreamingATR) Ready() bool { return a.average.Ready() }
+
+// IndicatorState maintains the standard indicator set of one symbol and
+// timeframe incrementally. Snapshots equal the Calculate*Indicators results
+// for the full sequence of candles passed to Update.
+type IndicatorState struct {
+	count      int
+	previous   *models.OHLCV
+	priorClose float64 // close of the candle before previous
+
+	// Trend
+	sma20      *StreamingSMA
+	sma50      *StreamingSMA
+	ema12      *StreamingEMA
+	ema26      *StreamingEMA
+	macdSignal *StreamingEMA
+
+	// Momentum
+	rsi     *StreamingRSI
+	high14  *RollingExtreme
+	low14   *RollingExtreme
+	rocBase *RollingWindow // last 11 closes
+	stochK  *RollingWindow // last 3 %K values
+
+	// Volatility
+	atr    *StreamingATR
+	stdDev *StreamingStdDev
+
+	// Volume
+	volumes     []int64 // ring of the last 20 volumes
+	volumeSum   int64
+	vwap        SessionVWAP
+	obv         float64
+	accDist     float64
+	firstVolume int64
+
+	// REQ-256: Trend strength, channels and money flow
+	adx        *StreamingADX
+	psar       *StreamingPSAR
+	superTrend *StreamingSuperTrend
+	ichimoku   *StreamingIchimoku
+	keltner    *StreamingKeltner
+	donchian   *StreamingDonchian
+	cci        *StreamingCCI
+	mfi        *StreamingMFI
+	cmf        *StreamingCMF
+}
+
+// NewIndicatorState creates an empty indicator state
+func NewIndicatorState() *IndicatorState {
+	return &IndicatorState{
+		sma20:      NewStreamingSMA(20),
+		sma50:      NewStreamingSMA(50),
+		ema12:      NewStreamingEMA(12),
+		ema26:      NewStreamingEMA(26),
+		macdSignal: NewStreamingEMA(9),
+		rsi:        NewStreamingRSI(14),
+		high14:     NewRollingMax(14),
+		low14:      NewRollingMin(14),
+		rocBase:    NewRollingWindow(11),
+		stochK:     NewRollingWindow(3),
+		atr:        NewStreamingATR(14),
+		stdDev:     NewStreamingStdDev(20),
+		volumes:    make([]int64, 0, 20),
+
+		adx:        NewStreamingADX(14),
+		psar:       NewStreamingPSAR(0.02, 0.2),
+		superTrend: NewStreamingSuperTrend(10, 3),
+		ichimoku:   NewStreamingIchimoku(9, 26, 52),
+		keltner:    NewStreamingKeltner(20, 10, 2.0),
+		donchian:   NewStreamingDonchian(20),
+		cci:        NewStreamingCCI(20),
+		mfi:        NewStreamingMFI(14),
+		cmf:        NewStreamingCMF(20),
+	}
+}
+
+// NewIndicatorStateFromHistory warm-starts a state from stored candles in
+// chronological order
+func NewIndicatorStateFromHistory(candles []*models.OHLCV) *IndicatorState {
+	state := NewIndicatorState()
+	for _, candle := range candles {
+		state.Update(candle)
+	}
+	return state
+}
+
+// Update adds the next candle in O(1)
+func (s *IndicatorState) Update(candle *models.OHLCV) {
+	price := candle.Close
+
+	s.sma20.Update(price)
+	s.sma50.Update(price)
+	s.ema12.Update(price)
+	s.ema26.Update(price)
+	if s.ema26.Ready() {
+		s.macdSignal.Update(s.ema12.Value() - s.ema26.Value())
+	}
+
+	s.rsi.Update(price)
+	s.high14.Update(candle.High)
+	s.low14.Update(candle.Low)
+	s.rocBase.Push(price)
+	if s.high14.Ready() {
+		s.stochK.Push(s.stochasticK(price))
+	}
+
+	s.atr.Update(candle)
+	s.stdDev.Update(price)
+
+	// Volume moving average (exact integer sum)
+	if len(s.volumes) < cap(s.volumes) {
+		s.volumes = append(s.volumes, candle.Volume)
+	} else {
+		slot := s.count % cap(s.volumes)
+		s.volumeSum -= s.volumes[slot]
+		s.volumes[slot] = candle.Volume
+	}
+	s.volumeSum += candle.Volume
+
+	// REQ-265: VWAP since the session open
+	s.vwap.Update(candle)
+
+	// OBV starts from the first candle's volume
+	if s.count == 0 {
+		s.firstVolume = candle.Volume
+		s.obv = float64(candle.Volume)
+	} else if candle.Close > s.previous.Close {
+		s.obv += float64(candle.Volume)
+	} else if candle.Close < s.previous.Close {
+		s.obv -= float64(candle.Volume)
+	}
+
+	// Accumulation/Distribution
+	if candle.High != candle.Low {
+		mfm := ((candle.Close - candle.Low) - (candle.High - candle.Close)) / (candle.High - candle.Low)
+		s.accDist += mfm * float64(candle.Volume)
+	}
+
+	s.adx.Update(candle)
+	s.psar.Update(candle)
+	s.superTrend.Update(candle)
+	s.ichimoku.Update(candle)
+	s.keltner.Update(candle)
+	s.donchian.Update(candle)
+	s.cci.Update(candle)
+	s.mfi.Update(candle)
+	s.cmf.Update(candle)
+
+	if s.previous != nil {
+		s.priorClose = s.previous.Close
+	}
+	s.previous = candle
+	s.count++
+}
+
+// Count returns the number of candles seen
+func (s *IndicatorState) Count() int { return s.count }
+
+// Last returns the most recent candle
+func (s *IndicatorState) Last() *models.OHLCV { return s.previous }
+
+// Trend returns the trend indicators, equal to CalculateTrendIndicators
+func (s *IndicatorState) Trend() *TrendIndicators {
+	if s.count == 0 {
+		return &TrendIndicators{}
+	}
+
+	trend := &TrendIndicators{
+		SMA20: s.sma20.Value(),
+		SMA50: s.sma50.Value(),
+		EMA12: s.ema12.Value(),
+		EMA26: s.ema26.Value(),
+	}
+
+	if s.count >= 26 {
+		trend.MACD = trend.EMA12 - trend.EMA26
+	}
+
+	if s.macdSignal.Ready() {
+		trend.MACDSignal = s.macdSignal.Value()
+		trend.MACDHist = trend.MACD - trend.MACDSignal
+	}
+
+	return trend
+}
+
+// Momentum returns the momentum indicators, equal to CalculateMomentumIndicators
+func (s *IndicatorState) Momentum() *MomentumIndicators {
+	if s.count == 0 {
+		return &MomentumIndicators{}
+	}
+
+	momentum := &MomentumIndicators{
+		RSI:         s.rsi.Value(),
+		StochasticK: 50,
+		StochasticD: 50,
+		WilliamsR:   -50,
+	}
+
+	if s.high14.Ready() {
+		highestHigh := s.high14.Value()
+		lowestLow := s.low14.Value()
+		currentClose := s.previous.Close
+
+		momentum.StochasticK = s.stochasticK(currentClose)
+		if highestHigh != lowestLow {
+			momentum.WilliamsR = ((highestHigh - currentClose) / (highestHigh - lowestLow)) * -100
+		}
+		if s.stochK.Full() {
+			momentum.StochasticD = s.stochK.Sum() / float64(s.stochK.Len())
+		}
+	}
+
+	if s.rocBase.Full() {
+		pastPrice := s.rocBase.Oldest()
+		if pastPrice != 0 {
+			momentum.ROC = ((s.previous.Close - pastPrice) / pastPrice) * 100
+		}
+	}
+
+	return momentum
+}
+
+// stochasticK returns %K of the 14-candle range for a close
+func (s *IndicatorState) stochasticK(currentClose float64) float64 {
+	highestHigh := s.high14.Value()
+	lowestLow := s.low14.Value()
+
+	if highestHigh == lowestLow {
+		return 50
+	}
+	return ((currentClose - lowestLow) / (highestHigh - lowestLow)) * 100
+}
+
+// Volatility returns the volatility indicators, equal to CalculateVolatilityIndicators
+func (s *IndicatorState) Volatility() *VolatilityIndicators {
+	if s.count == 0 {
+		return &VolatilityIndicators{}
+	}
+
+	volatility := &VolatilityIndicators{
+		ATR:    s.atr.Value(),
+		StdDev: s.stdDev.Value(),
+	}
+
+	if s.sma20.window.Full() {
+		middle := s.sma20.Value()
+		volatility.BollingerMiddle = middle
+		volatility.BollingerUpper = middle + (volatility.StdDev * 2.0)
+		volatility.BollingerLower = middle - (volatility.StdDev * 2.0)
+	}
+
+	if s.count >= 2 && volatility.ATR > 0 {
+		volatility.VolatilityRatio = math.Abs(s.previous.Close-s.priorClose) / volatility.ATR
+	}
+
+	return volatility
+}
+
+// Volume returns the volume indicators, equal to CalculateVolumeIndicators
+func (s *IndicatorState) Volume() *VolumeIndicators {
+	if s.count == 0 {
+		return &VolumeIndicators{}
+	}
+
+	volume := &VolumeIndicators{
+		AccDist: s.accDist,
+	}
+
+	if len(s.volumes) == cap(s.volumes) {
+		volume.VolumeMA = float64(s.volumeSum) / float64(cap(s.volumes))
+	}
+
+	if volume.VWAPBands = s.vwap.Bands(); volume.VWAPBands != nil {
+		volume.VWAP = volume.VWAPBands.VWAP
+	}
+
+	if s.count >= 2 {
+		volume.OBV = s.obv
+	}
+
+	if volume.VolumeMA > 0 {
+		volume.VolumeRatio = float64(s.previous.Volume) / volume.VolumeMA
+	}
+
+	return volume
+}
+
+// TrendStrength returns the trend strength indicators, equal to CalculateTrendStrengthIndicators
+func (s *IndicatorState) TrendStrength() *TrendStrengthIndicators {
+	if s.count == 0 {
+		return &TrendStrengthIndicators{}
+	}
+
+	trendStrength := &TrendStrengthIndicators{
+		Ichimoku: s.ichimoku.Value(),
+	}
+
+	trendStrength.ADX, trendStrength.PlusDI, trendStrength.MinusDI = s.adx.Values()
+	trendStrength.PSAR, trendStrength.PSARBullish = s.psar.Value()
+	trendStrength.SuperTrend, trendStrength.SuperTrendBullish = s.superTrend.Value()
+
+	return trendStrength
+}
+
+// Channels returns the channel indicators, equal to CalculateChannelIndicators
+func (s *IndicatorState) Channels() *ChannelIndicators {
+	if s.count == 0 {
+		return &ChannelIndicators{}
+	}
+
+	channels := &ChannelIndicators{
+		CCI: s.cci.Value(),
+	}
+
+	channels.KeltnerUpper, channels.KeltnerMiddle, channels.KeltnerLower = s.keltner.Values()
+	channels.DonchianUpper, channels.DonchianMiddle, channels.DonchianLower = s.donchian.Values()
+
+	return channels
+}
+
+// MoneyFlow returns the money flow indicators, equal to CalculateMoneyFlowIndicators
+func (s *IndicatorState) MoneyFlow() *MoneyFlowIndicators {
+	if s.count == 0 {
+		return &MoneyFlowIndicators{}
+	}
+
+	return &MoneyFlowIndicators{
+		MFI: s.mfi.Value(),
+		CMF: s.cmf.Value(),
+	}
+}
@@ -0,0 +1,133 @@
+package indicators
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+func generateRandomWalk(n int) []*models.OHLCV {
+	rng := rand.New(rand.NewSource(42))
+	candles := make([]*models.OHLCV, n)
+	price := 100.0
+	start := time.Date(2025, 1, 2, 14, 30, 0, 0, time.UTC)
+
+	for i := 0; i < n; i++ {
+		open := price
+		price += (rng.Float64() - 0.5) * 2
+		high := math.Max(open, price) + rng.Float64()
+		low := math.Min(open, price) - rng.Float64()
+		candles[i] = &models.OHLCV{
+			Symbol:    "TEST",
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     price,
+			Volume:    int64(1000 + rng.Intn(9000)),
+		}
+	}
+
+	return candles
+}
+
+func assertClose(t *testing.T, step int, name string, streaming, batch float64) {
+	t.Helper()
+	tolerance := 1e-9 * math.Max(1, math.Abs(batch))
+	if math.Abs(streaming-batch) > tolerance {
+		t.Fatalf("candle %d: %s streaming=%v batch=%v", step, name, streaming, batch)
+	}
+}
+
+func TestIndicatorStateMatchesBatch(t *testing.T) {
+	candles := generateRandomWalk(600)
+	state := NewIndicatorState()
+
+	for i, candle := range candles {
+		state.Update(candle)
+		window := candles[:i+1]
+
+		trend, batchTrend := state.Trend(), CalculateTrendIndicators(window)
+		assertClose(t, i, "SMA20", trend.SMA20, batchTrend.SMA20)
+		assertClose(t, i, "SMA50", trend.SMA50, batchTrend.SMA50)
+		assertClose(t, i, "EMA12", trend.EMA12, batchTrend.EMA12)
+		assertClose(t, i, "EMA26", trend.EMA26, batchTrend.EMA26)
+		assertClose(t, i, "MACD", trend.MACD, batchTrend.MACD)
+		assertClose(t, i, "MACDHist", trend.MACDHist, batchTrend.MACDHist)
+
+		momentum, batchMomentum := state.Momentum(), CalculateMomentumIndicators(window)
+		assertClose(t, i, "RSI", momentum.RSI, batchMomentum.RSI)
+		assertClose(t, i, "StochasticK", momentum.StochasticK, batchMomentum.StochasticK)
+		assertClose(t, i, "StochasticD", momentum.StochasticD, batchMomentum.StochasticD)
+		assertClose(t, i, "WilliamsR", momentum.WilliamsR, batchMomentum.WilliamsR)
+		assertClose(t, i, "ROC", momentum.ROC, batchMomentum.ROC)
+
+		volatility, batchVolatility := state.Volatility(), CalculateVolatilityIndicators(window)
+		assertClose(t, i, "ATR", volatility.ATR, batchVolatility.ATR)
+		assertClose(t, i, "StdDev", volatility.StdDev, batchVolatility.StdDev)
+		assertClose(t, i, "BollingerUpper", volatility.BollingerUpper, batchVolatility.BollingerUpper)
+		assertClose(t, i, "BollingerLower", volatility.BollingerLower, batchVolatility.BollingerLower)
+		assertClose(t, i, "VolatilityRatio", volatility.VolatilityRatio, batchVolatility.VolatilityRatio)
+
+		volume, batchVolume := state.Volume(), CalculateVolumeIndicators(window)
+		assertClose(t, i, "VolumeMA", volume.VolumeMA, batchVolume.VolumeMA)
+		assertClose(t, i, "VWAP", volume.VWAP, batchVolume.VWAP)
+		assertClose(t, i, "OBV", volume.OBV, batchVolume.OBV)
+		assertClose(t, i, "VolumeRatio", volume.VolumeRatio, batchVolume.VolumeRatio)
+		assertClose(t, i, "AccDist", volume.AccDist, batchVolume.AccDist)
+
+		strength, batchStrength := state.TrendStrength(), CalculateTrendStrengthIndicators(window)
+		assertClose(t, i, "ADX", strength.ADX, batchStrength.ADX)
+		assertClose(t, i, "PlusDI", strength.PlusDI, batchStrength.PlusDI)
+		assertClose(t, i, "PSAR", strength.PSAR, batchStrength.PSAR)
+		assertClose(t, i, "SuperTrend", strength.SuperTrend, batchStrength.SuperTrend)
+		assertClose(t, i, "Tenkan", strength.Ichimoku.Tenkan, batchStrength.Ichimoku.Tenkan)
+		assertClose(t, i, "Kijun", strength.Ichimoku.Kijun, batchStrength.Ichimoku.Kijun)
+		assertClose(t, i, "SenkouB", strength.Ichimoku.SenkouB, batchStrength.Ichimoku.SenkouB)
+		assertClose(t, i, "CloudA", strength.Ichimoku.CloudA, batchStrength.Ichimoku.CloudA)
+		assertClose(t, i, "CloudB", strength.Ichimoku.CloudB, batchStrength.Ichimoku.CloudB)
+
+		channels, batchChannels := state.Channels(), CalculateChannelIndicators(window)
+		assertClose(t, i, "KeltnerUpper", channels.KeltnerUpper, batchChannels.KeltnerUpper)
+		assertClose(t, i, "KeltnerLower", channels.KeltnerLower, batchChannels.KeltnerLower)
+		assertClose(t, i, "DonchianUpper", channels.DonchianUpper, batchChannels.DonchianUpper)
+		assertClose(t, i, "DonchianLower", channels.DonchianLower, batchChannels.DonchianLower)
+		assertClose(t, i, "CCI", channels.CCI, batchChannels.CCI)
+
+		moneyFlow, batchMoneyFlow := state.MoneyFlow(), CalculateMoneyFlowIndicators(window)
+		assertClose(t, i, "MFI", moneyFlow.MFI, batchMoneyFlow.MFI)
+		assertClose(t, i, "CMF", moneyFlow.CMF, batchMoneyFlow.CMF)
+	}
+}
+
+func TestStreamingSMA200MatchesBatch(t *testing.T) {
+	candles := generateRandomWalk(1000)
+	closes := make([]float64, len(candles))
+	sma := NewStreamingSMA(200)
+
+	for i, candle := range candles {
+		closes[i] = candle.Close
+		assertClose(t, i, "SMA200", sma.Update(candle.Close), SMA(closes[:i+1], 200))
+	}
+}
+
+func TestWarmStartContinuesLikeLiveState(t *testing.T) {
+	candles := generateRandomWalk(300)
+
+	live := NewIndicatorState()
+	for _, candle := range candles {
+		live.Update(candle)
+	}
+
+	warm := NewIndicatorStateFromHistory(candles[:250])
+	for _, candle := range candles[250:] {
+		warm.Update(candle)
+	}
+
+	if *warm.Trend() != *live.Trend() || *warm.Momentum() != *live.Momentum() {
+		t.Fatal("warm-started state diverged from live state")
+	}
+}
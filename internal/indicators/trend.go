@@ -3,7 +3,7 @@ package indicators
 import (
 	"math"
 
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-206: Trend indicators (SMA, EMA, MACD)
@@ -32,40 +32,61 @@ func SMA(prices []float64, period int) float64 {
 	return sum / float64(period)
 }
 
-// EMA calculates Exponential Moving Average
+// EMA calculates Exponential Moving Average, seeded with the SMA of the
+// first period prices
 func EMA(prices []float64, period int) float64 {
-	if len(prices) == 0 {
+	series := emaSeries(prices, period)
+	if len(series) == 0 {
 		return 0
 	}
+	return series[len(series)-1]
+}
 
-	if len(prices) == 1 {
-		return prices[0]
+// emaSeries returns the EMA of every price from index period-1 on
+func emaSeries(prices []float64, period int) []float64 {
+	if period < 1 || len(prices) < period {
+		return nil
 	}
 
-	multiplier := 2.0 / (float64(period) + 1.0)
-	ema := prices[0]
+	series := make([]float64, 0, len(prices)-period+1)
+	ema := SMA(prices[:period], period)
+	series = append(series, ema)
 
-	for i := 1; i < len(prices); i++ {
+	multiplier := 2.0 / (float64(period) + 1.0)
+	for i := period; i < len(prices); i++ {
 		ema = (prices[i] * multiplier) + (ema * (1 - multiplier))
+		series = append(series, ema)
 	}
 
-	return ema
+	return series
 }
 
-// MACD calculates Moving Average Convergence Divergence
+// MACD calculates Moving Average Convergence Divergence. The signal line is
+// the EMA of the MACD line; signal and histogram stay 0 until it is seeded.
 func MACD(prices []float64, fastPeriod, slowPeriod, signalPeriod int) (macd, signal, histogram float64) {
-	if len(prices) < slowPeriod {
+	longest := int(math.Max(float64(fastPeriod), float64(slowPeriod)))
+	if len(prices) < longest {
 		return 0, 0, 0
 	}
 
-	fastEMA := EMA(prices, fastPeriod)
-	slowEMA := EMA(prices, slowPeriod)
+	fastEMA := emaSeries(prices, fastPeriod)
+	slowEMA := emaSeries(prices, slowPeriod)
+
+	// Align both averages on the candles where the longer one is defined
+	fastEMA = fastEMA[len(fastEMA)-(len(prices)-longest+1):]
+	slowEMA = slowEMA[len(slowEMA)-(len(prices)-longest+1):]
 
-	macd = fastEMA - slowEMA
+	line := make([]float64, len(slowEMA))
+	for i := range line {
+		line[i] = fastEMA[i] - slowEMA[i]
+	}
+
+	macd = line[len(line)-1]
+	if len(line) < signalPeriod {
+		return macd, 0, 0
+	}
 
-	// Calculate signal line (EMA of MACD)
-	// For simplicity, using a basic calculation here
-	signal = macd * 0.9 // Simplified signal calculation
+	signal = EMA(line, signalPeriod)
 	histogram = macd - signal
 
 	return macd, signal, histogram
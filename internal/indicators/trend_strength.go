@@ -0,0 +1,449 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-256: Trend strength indicators (ADX/DI, Parabolic SAR, SuperTrend, Ichimoku)
+//
+// ADX, Parabolic SAR and SuperTrend are recursive, so their batch functions
+// replay the streaming form over the history and both always agree.
+
+// TrendStrengthIndicators represents trend strength and trend-following indicators
+type TrendStrengthIndicators struct {
+	ADX               float64       `json:"adx"`
+	PlusDI            float64       `json:"plus_di"`
+	MinusDI           float64       `json:"minus_di"`
+	PSAR              float64       `json:"psar"`
+	PSARBullish       bool          `json:"psar_bullish"`
+	SuperTrend        float64       `json:"supertrend"`
+	SuperTrendBullish bool          `json:"supertrend_bullish"`
+	Ichimoku          IchimokuCloud `json:"ichimoku"`
+}
+
+// IchimokuCloud contains Ichimoku Kinko Hyo lines. SenkouA and SenkouB are the
+// leading spans computed on the latest candle (plotted base periods ahead);
+// CloudA and CloudB are the spans projected onto the latest candle.
+type IchimokuCloud struct {
+	Tenkan  float64 `json:"tenkan"`
+	Kijun   float64 `json:"kijun"`
+	SenkouA float64 `json:"senkou_a"`
+	SenkouB float64 `json:"senkou_b"`
+	CloudA  float64 `json:"cloud_a"`
+	CloudB  float64 `json:"cloud_b"`
+}
+
+// StreamingADX is the incremental Average Directional Index with +DI/-DI
+type StreamingADX struct {
+	period   int
+	previous *models.OHLCV
+	samples  int // directional movement samples seen
+
+	trSum    float64 // Wilder-smoothed sums
+	plusSum  float64
+	minusSum float64
+
+	plusDI  float64
+	minusDI float64
+	adx     *WilderAverage
+}
+
+// NewStreamingADX creates an incremental ADX
+func NewStreamingADX(period int) *StreamingADX {
+	return &StreamingADX{period: period, adx: NewWilderAverage(period)}
+}
+
+// Update adds a candle
+func (a *StreamingADX) Update(candle *models.OHLCV) {
+	previous := a.previous
+	a.previous = candle
+	if previous == nil {
+		return
+	}
+
+	upMove := candle.High - previous.High
+	downMove := previous.Low - candle.Low
+
+	plusDM, minusDM := 0.0, 0.0
+	if upMove > downMove && upMove > 0 {
+		plusDM = upMove
+	}
+	if downMove > upMove && downMove > 0 {
+		minusDM = downMove
+	}
+	tr := TrueRange(candle, previous)
+
+	a.samples++
+	period := float64(a.period)
+	if a.samples <= a.period {
+		a.trSum += tr
+		a.plusSum += plusDM
+		a.minusSum += minusDM
+	} else {
+		a.trSum = a.trSum - a.trSum/period + tr
+		a.plusSum = a.plusSum - a.plusSum/period + plusDM
+		a.minusSum = a.minusSum - a.minusSum/period + minusDM
+	}
+
+	if a.samples < a.period {
+		return
+	}
+
+	a.plusDI, a.minusDI = 0, 0
+	if a.trSum > 0 {
+		a.plusDI = 100 * a.plusSum / a.trSum
+		a.minusDI = 100 * a.minusSum / a.trSum
+	}
+
+	dx := 0.0
+	if total := a.plusDI + a.minusDI; total > 0 {
+		dx = 100 * math.Abs(a.plusDI-a.minusDI) / total
+	}
+	a.adx.Update(dx)
+}
+
+// Values returns ADX, +DI and -DI; ADX is 0 until 2*period candles were seen
+func (a *StreamingADX) Values() (adx, plusDI, minusDI float64) {
+	return a.adx.Value(), a.plusDI, a.minusDI
+}
+
+// DIReady reports whether +DI/-DI are available (period+1 candles)
+func (a *StreamingADX) DIReady() bool { return a.samples >= a.period }
+
+// Ready reports whether ADX is available (2*period candles)
+func (a *StreamingADX) Ready() bool { return a.adx.Ready() }
+
+// ADX calculates the Average Directional Index with +DI and -DI
+func ADX(candles []*models.OHLCV, period int) (adx, plusDI, minusDI float64) {
+	stream := NewStreamingADX(period)
+	for _, candle := range candles {
+		stream.Update(candle)
+	}
+	return stream.Values()
+}
+
+// StreamingPSAR is the incremental Parabolic SAR
+type StreamingPSAR struct {
+	step    float64
+	maxStep float64
+
+	count     int
+	bullish   bool
+	sar       float64
+	extreme   float64
+	factor    float64
+	previous  *models.OHLCV
+	previous2 *models.OHLCV
+}
+
+// NewStreamingPSAR creates an incremental Parabolic SAR
+func NewStreamingPSAR(step, maxStep float64) *StreamingPSAR {
+	return &StreamingPSAR{step: step, maxStep: maxStep}
+}
+
+// Update adds a candle
+func (p *StreamingPSAR) Update(candle *models.OHLCV) {
+	p.count++
+	if p.count == 1 {
+		p.previous = candle
+		return
+	}
+
+	if p.count == 2 {
+		// Initial trend follows the first close-to-close move
+		p.bullish = candle.Close >= p.previous.Close
+		if p.bullish {
+			p.sar, p.extreme = p.previous.Low, p.previous.High
+		} else {
+			p.sar, p.extreme = p.previous.High, p.previous.Low
+		}
+		p.factor = p.step
+	}
+
+	p.sar += p.factor * (p.extreme - p.sar)
+
+	if p.bullish {
+		// SAR may not rise above the prior two lows
+		p.sar = math.Min(p.sar, p.previous.Low)
+		if p.previous2 != nil {
+			p.sar = math.Min(p.sar, p.previous2.Low)
+		}
+
+		if candle.Low < p.sar {
+			p.bullish = false
+			p.sar, p.extreme, p.factor = p.extreme, candle.Low, p.step
+		} else if candle.High > p.extreme {
+			p.extreme = candle.High
+			p.factor = math.Min(p.factor+p.step, p.maxStep)
+		}
+	} else {
+		// SAR may not fall below the prior two highs
+		p.sar = math.Max(p.sar, p.previous.High)
+		if p.previous2 != nil {
+			p.sar = math.Max(p.sar, p.previous2.High)
+		}
+
+		if candle.High > p.sar {
+			p.bullish = true
+			p.sar, p.extreme, p.factor = p.extreme, candle.High, p.step
+		} else if candle.Low < p.extreme {
+			p.extreme = candle.Low
+			p.factor = math.Min(p.factor+p.step, p.maxStep)
+		}
+	}
+
+	p.previous2 = p.previous
+	p.previous = candle
+}
+
+// Value returns the SAR of the latest candle and whether the trend is bullish
+func (p *StreamingPSAR) Value() (sar float64, bullish bool) { return p.sar, p.bullish }
+
+// Ready reports whether two candles were seen
+func (p *StreamingPSAR) Ready() bool { return p.count >= 2 }
+
+// ParabolicSAR calculates the Parabolic SAR of the latest candle
+func ParabolicSAR(candles []*models.OHLCV, step, maxStep float64) (sar float64, bullish bool) {
+	stream := NewStreamingPSAR(step, maxStep)
+	for _, candle := range candles {
+		stream.Update(candle)
+	}
+	return stream.Value()
+}
+
+// StreamingSuperTrend is the incremental SuperTrend
+type StreamingSuperTrend struct {
+	multiplier float64
+	atr        *StreamingATR
+	previous   *models.OHLCV
+
+	started bool
+	bullish bool
+	upper   float64
+	lower   float64
+}
+
+// NewStreamingSuperTrend creates an incremental SuperTrend
+func NewStreamingSuperTrend(period int, multiplier float64) *StreamingSuperTrend {
+	return &StreamingSuperTrend{multiplier: multiplier, atr: NewStreamingATR(period)}
+}
+
+// Update adds a candle
+func (s *StreamingSuperTrend) Update(candle *models.OHLCV) {
+	atr := s.atr.Update(candle)
+	previous := s.previous
+	s.previous = candle
+
+	if !s.atr.Ready() {
+		return
+	}
+
+	median := (candle.High + candle.Low) / 2
+	basicUpper := median + s.multiplier*atr
+	basicLower := median - s.multiplier*atr
+
+	if !s.started {
+		s.started = true
+		s.upper, s.lower = basicUpper, basicLower
+		s.bullish = candle.Close >= median
+		return
+	}
+
+	// Final bands only tighten while price stays inside them
+	if basicUpper < s.upper || previous.Close > s.upper {
+		s.upper = basicUpper
+	}
+	if basicLower > s.lower || previous.Close < s.lower {
+		s.lower = basicLower
+	}
+
+	if s.bullish && candle.Close < s.lower {
+		s.bullish = false
+	} else if !s.bullish && candle.Close > s.upper {
+		s.bullish = true
+	}
+}
+
+// Value returns the SuperTrend line and whether the trend is bullish
+func (s *StreamingSuperTrend) Value() (value float64, bullish bool) {
+	if !s.started {
+		return 0, false
+	}
+	if s.bullish {
+		return s.lower, true
+	}
+	return s.upper, false
+}
+
+// Ready reports whether the ATR period was seen
+func (s *StreamingSuperTrend) Ready() bool { return s.started }
+
+// SuperTrend calculates the SuperTrend line of the latest candle
+func SuperTrend(candles []*models.OHLCV, period int, multiplier float64) (value float64, bullish bool) {
+	stream := NewStreamingSuperTrend(period, multiplier)
+	for _, candle := range candles {
+		stream.Update(candle)
+	}
+	return stream.Value()
+}
+
+// Ichimoku calculates the Ichimoku cloud for the latest candle. The base
+// period doubles as the displacement of the leading spans.
+func Ichimoku(candles []*models.OHLCV, conversionPeriod, basePeriod, spanBPeriod int) IchimokuCloud {
+	var cloud IchimokuCloud
+	n := len(candles)
+
+	cloud.Tenkan = midpoint(candles, n, conversionPeriod)
+	cloud.Kijun = midpoint(candles, n, basePeriod)
+	if n >= basePeriod && n >= conversionPeriod {
+		cloud.SenkouA = (cloud.Tenkan + cloud.Kijun) / 2
+	}
+	cloud.SenkouB = midpoint(candles, n, spanBPeriod)
+
+	// Spans computed basePeriod candles ago form the cloud under the latest candle
+	past := n - basePeriod
+	if past >= basePeriod && past >= conversionPeriod {
+		cloud.CloudA = (midpoint(candles, past, conversionPeriod) + midpoint(candles, past, basePeriod)) / 2
+	}
+	if past >= spanBPeriod {
+		cloud.CloudB = midpoint(candles, past, spanBPeriod)
+	}
+
+	return cloud
+}
+
+// midpoint returns the (highest high + lowest low) / 2 of the period candles
+// ending before index end, or 0 if there are not enough candles
+func midpoint(candles []*models.OHLCV, end, period int) float64 {
+	if end < period || period < 1 {
+		return 0
+	}
+
+	highest := candles[end-period].High
+	lowest := candles[end-period].Low
+	for i := end - period + 1; i < end; i++ {
+		highest = math.Max(highest, candles[i].High)
+		lowest = math.Min(lowest, candles[i].Low)
+	}
+
+	return (highest + lowest) / 2
+}
+
+// StreamingIchimoku is the incremental form of Ichimoku
+type StreamingIchimoku struct {
+	conversionPeriod int
+	basePeriod       int
+	spanBPeriod      int
+	count            int
+
+	conversionHigh, conversionLow *RollingExtreme
+	baseHigh, baseLow             *RollingExtreme
+	spanBHigh, spanBLow           *RollingExtreme
+
+	// Leading spans of the last basePeriod+1 candles
+	spanA *RollingWindow
+	spanB *RollingWindow
+
+	current IchimokuCloud
+}
+
+// NewStreamingIchimoku creates an incremental Ichimoku cloud
+func NewStreamingIchimoku(conversionPeriod, basePeriod, spanBPeriod int) *StreamingIchimoku {
+	return &StreamingIchimoku{
+		conversionPeriod: conversionPeriod,
+		basePeriod:       basePeriod,
+		spanBPeriod:      spanBPeriod,
+		conversionHigh:   NewRollingMax(conversionPeriod),
+		conversionLow:    NewRollingMin(conversionPeriod),
+		baseHigh:         NewRollingMax(basePeriod),
+		baseLow:          NewRollingMin(basePeriod),
+		spanBHigh:        NewRollingMax(spanBPeriod),
+		spanBLow:         NewRollingMin(spanBPeriod),
+		spanA:            NewRollingWindow(basePeriod + 1),
+		spanB:            NewRollingWindow(basePeriod + 1),
+	}
+}
+
+// Update adds a candle
+func (s *StreamingIchimoku) Update(candle *models.OHLCV) {
+	s.count++
+	s.conversionHigh.Update(candle.High)
+	s.conversionLow.Update(candle.Low)
+	s.baseHigh.Update(candle.High)
+	s.baseLow.Update(candle.Low)
+	s.spanBHigh.Update(candle.High)
+	s.spanBLow.Update(candle.Low)
+
+	var cloud IchimokuCloud
+	if s.conversionHigh.Ready() {
+		cloud.Tenkan = (s.conversionHigh.Value() + s.conversionLow.Value()) / 2
+	}
+	if s.baseHigh.Ready() {
+		cloud.Kijun = (s.baseHigh.Value() + s.baseLow.Value()) / 2
+	}
+	if s.baseHigh.Ready() && s.conversionHigh.Ready() {
+		cloud.SenkouA = (cloud.Tenkan + cloud.Kijun) / 2
+	}
+	if s.spanBHigh.Ready() {
+		cloud.SenkouB = (s.spanBHigh.Value() + s.spanBLow.Value()) / 2
+	}
+
+	s.spanA.Push(cloud.SenkouA)
+	s.spanB.Push(cloud.SenkouB)
+	if s.spanA.Full() {
+		cloud.CloudA = s.spanA.Oldest()
+		cloud.CloudB = s.spanB.Oldest()
+	}
+
+	s.current = cloud
+}
+
+// Value returns the current cloud
+func (s *StreamingIchimoku) Value() IchimokuCloud { return s.current }
+
+// Ready reports whether the projected cloud is complete
+func (s *StreamingIchimoku) Ready() bool {
+	return s.count >= s.spanBPeriod+s.basePeriod && s.count >= s.conversionPeriod+s.basePeriod
+}
+
+// CalculateTrendStrengthIndicators computes all trend strength indicators for a candle history
+func CalculateTrendStrengthIndicators(candles []*models.OHLCV) *TrendStrengthIndicators {
+	if len(candles) == 0 {
+		return &TrendStrengthIndicators{}
+	}
+
+	indicators := &TrendStrengthIndicators{
+		Ichimoku: Ichimoku(candles, 9, 26, 52),
+	}
+
+	indicators.ADX, indicators.PlusDI, indicators.MinusDI = ADX(candles, 14)
+	indicators.PSAR, indicators.PSARBullish = ParabolicSAR(candles, 0.02, 0.2)
+	indicators.SuperTrend, indicators.SuperTrendBullish = SuperTrend(candles, 10, 3)
+
+	return indicators
+}
+
+// TrendStrengthLevel classifies ADX into weak, moderate and strong trends
+func (t *TrendStrengthIndicators) TrendStrengthLevel() string {
+	if t.ADX >= 25 {
+		return "strong"
+	} else if t.ADX >= 20 {
+		return "moderate"
+	}
+	return "weak"
+}
+
+// CloudPosition returns the price position relative to the Ichimoku cloud
+func (c IchimokuCloud) CloudPosition(price float64) string {
+	top := math.Max(c.CloudA, c.CloudB)
+	bottom := math.Min(c.CloudA, c.CloudB)
+
+	if price > top {
+		return "above_cloud"
+	} else if price < bottom {
+		return "below_cloud"
+	}
+	return "in_cloud"
+}
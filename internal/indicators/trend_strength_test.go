@@ -0,0 +1,55 @@
+package indicators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+func generateSteadyTrend(n int, step float64) []*models.OHLCV {
+	candles := make([]*models.OHLCV, n)
+	start := time.Date(2025, 1, 2, 14, 30, 0, 0, time.UTC)
+	price := 100.0
+
+	for i := 0; i < n; i++ {
+		open := price
+		price += step
+		high, low := open, price
+		if price > open {
+			high, low = price, open
+		}
+		candles[i] = &models.OHLCV{
+			Symbol:    "TEST",
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Open:      open,
+			High:      high + 0.1,
+			Low:       low - 0.1,
+			Close:     price,
+			Volume:    1000,
+		}
+	}
+
+	return candles
+}
+
+func TestTrendStrengthFollowsSteadyTrend(t *testing.T) {
+	up := CalculateTrendStrengthIndicators(generateSteadyTrend(120, 0.5))
+	if up.TrendStrengthLevel() != "strong" || up.PlusDI <= up.MinusDI {
+		t.Errorf("uptrend: ADX=%v +DI=%v -DI=%v, want strong with +DI leading", up.ADX, up.PlusDI, up.MinusDI)
+	}
+	if !up.PSARBullish || !up.SuperTrendBullish {
+		t.Errorf("uptrend: PSAR bullish=%v SuperTrend bullish=%v, want both bullish", up.PSARBullish, up.SuperTrendBullish)
+	}
+	if position := up.Ichimoku.CloudPosition(160); position != "above_cloud" {
+		t.Errorf("uptrend: cloud position = %s, want above_cloud", position)
+	}
+
+	down := CalculateTrendStrengthIndicators(generateSteadyTrend(120, -0.5))
+	if down.TrendStrengthLevel() != "strong" || down.MinusDI <= down.PlusDI {
+		t.Errorf("downtrend: ADX=%v +DI=%v -DI=%v, want strong with -DI leading", down.ADX, down.PlusDI, down.MinusDI)
+	}
+	if down.PSARBullish || down.SuperTrendBullish {
+		t.Errorf("downtrend: PSAR bullish=%v SuperTrend bullish=%v, want both bearish", down.PSARBullish, down.SuperTrendBullish)
+	}
+}
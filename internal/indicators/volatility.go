@@ -3,7 +3,7 @@ package indicators
 import (
 	"math"
 
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-208: Volatility indicators (Bollinger Bands, ATR)
@@ -69,22 +69,24 @@ func TrueRange(current, previous *models.OHLCV) float64 {
 	return math.Max(tr1, math.Max(tr2, tr3))
 }
 
-// ATR calculates Average True Range
+// ATR calculates Average True Range with Wilder smoothing: the first value
+// is the mean of the first period true ranges, later ones are smoothed
 func ATR(candles []*models.OHLCV, period int) float64 {
-	if len(candles) < period+1 {
+	if period < 1 || len(candles) < period+1 {
 		return 0
 	}
 
-	trSum := 0.0
-	for i := len(candles) - period; i < len(candles); i++ {
-		var previous *models.OHLCV
-		if i > 0 {
-			previous = candles[i-1]
-		}
-		trSum += TrueRange(candles[i], previous)
+	atr := 0.0
+	for i := 1; i <= period; i++ {
+		atr += TrueRange(candles[i], candles[i-1])
+	}
+	atr /= float64(period)
+
+	for i := period + 1; i < len(candles); i++ {
+		atr = (atr*float64(period-1) + TrueRange(candles[i], candles[i-1])) / float64(period)
 	}
 
-	return trSum / float64(period)
+	return atr
 }
 
 // CalculateVolatilityIndicators computes all volatility indicators for a candle history
@@ -1,18 +1,21 @@
 package indicators
 
 import (
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-209: Volume indicators (Volume MA, VWAP, OBV)
+// REQ-265: VWAP resets at each session open of the exchange calendar
 
 // VolumeIndicators represents volume-based technical indicators
 type VolumeIndicators struct {
 	VolumeMA    float64 `json:"volume_ma"`
-	VWAP        float64 `json:"vwap"`
+	VWAP        float64 `json:"vwap"` // session VWAP
 	OBV         float64 `json:"obv"`
 	VolumeRatio float64 `json:"volume_ratio"`
 	AccDist     float64 `json:"accumulation_distribution"`
+
+	VWAPBands *models.VWAPBands `json:"vwap_bands,omitempty"`
 }
 
 // VolumeMA calculates Volume Moving Average
@@ -29,7 +32,8 @@ func VolumeMA(candles []*models.OHLCV, period int) float64 {
 	return float64(sum) / float64(period)
 }
 
-// VWAP calculates Volume Weighted Average Price
+// VWAP calculates Volume Weighted Average Price over all given candles; see
+// SessionVWAPBands for the session VWAP
 func VWAP(candles []*models.OHLCV) float64 {
 	if len(candles) == 0 {
 		return 0
@@ -103,10 +107,13 @@ func CalculateVolumeIndicators(candles []*models.OHLCV) *VolumeIndicators {
 	}
 
 	indicators := &VolumeIndicators{
-		VolumeMA: VolumeMA(candles, 20),
-		VWAP:     VWAP(candles),
-		OBV:      OBV(candles),
-		AccDist:  AccumulationDistribution(candles),
+		VolumeMA:  VolumeMA(candles, 20),
+		OBV:       OBV(candles),
+		AccDist:   AccumulationDistribution(candles),
+		VWAPBands: SessionVWAPBands(candles),
+	}
+	if indicators.VWAPBands != nil {
+		indicators.VWAP = indicators.VWAPBands.VWAP
 	}
 
 	// Calculate volume ratio (current vs average)
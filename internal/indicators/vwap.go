@@ -0,0 +1,197 @@
+package indicators
+
+import (
+	"math"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/calendar"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-265: Session-anchored and user-anchored VWAP with standard deviation bands
+
+// StreamingVWAP accumulates the volume weighted average of typical prices
+// and their volume weighted standard deviation in O(1) per candle
+type StreamingVWAP struct {
+	priceVolume  float64
+	squareVolume float64
+	volume       float64
+	start        time.Time
+	bars         int
+}
+
+// Update adds the next candle
+func (v *StreamingVWAP) Update(candle *models.OHLCV) {
+	if v.bars == 0 {
+		v.start = candle.Timestamp
+	}
+	typicalPrice := (candle.High + candle.Low + candle.Close) / 3.0
+	volume := float64(candle.Volume)
+	v.priceVolume += typicalPrice * volume
+	v.squareVolume += typicalPrice * typicalPrice * volume
+	v.volume += volume
+	v.bars++
+}
+
+// Reset starts a new accumulation
+func (v *StreamingVWAP) Reset() {
+	*v = StreamingVWAP{}
+}
+
+// Ready reports whether any volume was accumulated
+func (v *StreamingVWAP) Ready() bool { return v.volume > 0 }
+
+// Value returns the VWAP, 0 without volume
+func (v *StreamingVWAP) Value() float64 {
+	if v.volume == 0 {
+		return 0
+	}
+	return v.priceVolume / v.volume
+}
+
+// StdDev returns the volume weighted standard deviation of typical prices
+func (v *StreamingVWAP) StdDev() float64 {
+	if v.volume == 0 {
+		return 0
+	}
+	vwap := v.Value()
+	return math.Sqrt(math.Max(0, v.squareVolume/v.volume-vwap*vwap))
+}
+
+// Bands returns the VWAP with 1 and 2 standard deviation bands, nil without volume
+func (v *StreamingVWAP) Bands() *models.VWAPBands {
+	if !v.Ready() {
+		return nil
+	}
+	vwap, stdDev := v.Value(), v.StdDev()
+	return &models.VWAPBands{
+		VWAP:   vwap,
+		StdDev: stdDev,
+		Upper1: vwap + stdDev,
+		Lower1: vwap - stdDev,
+		Upper2: vwap + 2*stdDev,
+		Lower2: vwap - 2*stdDev,
+		Start:  v.start,
+		Bars:   v.bars,
+	}
+}
+
+// barDurations maps timeframes to their bar duration; bars of other
+// timeframes are treated as instants at their timestamp
+var barDurations = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// inSession reports whether a candle counts towards the session VWAP
+func inSession(candle *models.OHLCV) bool {
+	return calendar.InRegularHours(candle.Timestamp, barDurations[candle.Timeframe])
+}
+
+// SessionVWAPStart returns midnight exchange time of the trading day whose
+// regular session the session VWAP reports at t: the previous one before the
+// open. Candles from then on warm the VWAP up.
+func SessionVWAPStart(t time.Time) time.Time {
+	if t.Before(calendar.SessionOpen(t)) {
+		return calendar.PreviousSessionDay(t)
+	}
+	return calendar.SessionDay(t)
+}
+
+// SessionVWAP is a VWAP of the regular session that resets at every regular
+// open of the exchange calendar. Pre-market and after-hours bars are left
+// out, so the last session's VWAP holds until the next open.
+type SessionVWAP struct {
+	StreamingVWAP
+	session time.Time
+}
+
+// Update adds the next candle of the regular session, resetting at a new one
+func (s *SessionVWAP) Update(candle *models.OHLCV) {
+	if !inSession(candle) {
+		return
+	}
+	if session := calendar.SessionDay(candle.Timestamp); !session.Equal(s.session) {
+		s.StreamingVWAP.Reset()
+		s.session = session
+	}
+	s.StreamingVWAP.Update(candle)
+}
+
+// AnchoredVWAP is a VWAP of the candles starting at an anchor time
+type AnchoredVWAP struct {
+	StreamingVWAP
+	Anchor time.Time
+}
+
+// NewAnchoredVWAP creates a VWAP anchored at a timestamp
+func NewAnchoredVWAP(anchor time.Time) *AnchoredVWAP {
+	return &AnchoredVWAP{Anchor: anchor}
+}
+
+// Update adds the next candle if it does not start before the anchor
+func (a *AnchoredVWAP) Update(candle *models.OHLCV) {
+	if candle.Timestamp.Before(a.Anchor) {
+		return
+	}
+	a.StreamingVWAP.Update(candle)
+}
+
+// SessionVWAPBands calculates the VWAP of the last regular session in
+// chronological candles, as SessionVWAP reports it after the last candle
+func SessionVWAPBands(candles []*models.OHLCV) *models.VWAPBands {
+	end := len(candles)
+	for end > 0 && !inSession(candles[end-1]) {
+		end--
+	}
+	if end == 0 {
+		return nil
+	}
+
+	session := calendar.SessionDay(candles[end-1].Timestamp)
+	start := end - 1
+	for start > 0 && calendar.SessionDay(candles[start-1].Timestamp).Equal(session) {
+		start--
+	}
+
+	var vwap StreamingVWAP
+	for _, candle := range candles[start:end] {
+		if inSession(candle) {
+			vwap.Update(candle)
+		}
+	}
+	return vwap.Bands()
+}
+
+// AnchoredVWAPBands calculates the VWAP of the candles starting at anchor
+func AnchoredVWAPBands(candles []*models.OHLCV, anchor time.Time) *models.VWAPBands {
+	vwap := NewAnchoredVWAP(anchor)
+	for _, candle := range candles {
+		vwap.Update(candle)
+	}
+	return vwap.Bands()
+}
+
+// LatestGap finds the latest session whose first bar opened at least
+// minPercent away from the previous session's last close. It returns the
+// regular open of that session and the signed gap in percent.
+func LatestGap(candles []*models.OHLCV, minPercent float64) (time.Time, float64, bool) {
+	for i := len(candles) - 1; i > 0; i-- {
+		previous, current := candles[i-1], candles[i]
+		session := calendar.SessionDay(current.Timestamp)
+		if session.Equal(calendar.SessionDay(previous.Timestamp)) || previous.Close == 0 {
+			continue
+		}
+
+		gap := (current.Open - previous.Close) / previous.Close * 100
+		if math.Abs(gap) >= minPercent {
+			return calendar.SessionOpen(current.Timestamp), gap, true
+		}
+	}
+	return time.Time{}, 0, false
+}
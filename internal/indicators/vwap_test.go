@@ -0,0 +1,136 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/calendar"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+func vwapCandle(timestamp time.Time, price float64, volume int64) *models.OHLCV {
+	return &models.OHLCV{
+		Timestamp: timestamp,
+		Open:      price,
+		High:      price,
+		Low:       price,
+		Close:     price,
+		Volume:    volume,
+	}
+}
+
+func TestSessionVWAPResets(t *testing.T) {
+	first := time.Date(2024, time.March, 28, 10, 0, 0, 0, calendar.Location)
+	second := time.Date(2024, time.April, 1, 10, 0, 0, 0, calendar.Location) // after Good Friday and the weekend
+
+	var vwap SessionVWAP
+	vwap.Update(vwapCandle(first, 100, 1000))
+	vwap.Update(vwapCandle(first.Add(time.Minute), 110, 1000))
+	if got := vwap.Value(); math.Abs(got-105) > 1e-9 {
+		t.Errorf("session VWAP = %v, want 105", got)
+	}
+	if got := vwap.StdDev(); math.Abs(got-5) > 1e-9 {
+		t.Errorf("session stddev = %v, want 5", got)
+	}
+
+	vwap.Update(vwapCandle(second, 120, 500))
+	bands := vwap.Bands()
+	if bands.VWAP != 120 || bands.Bars != 1 || !bands.Start.Equal(second) {
+		t.Errorf("VWAP did not reset at the new session: %+v", bands)
+	}
+}
+
+func TestSessionVWAPRegularHours(t *testing.T) {
+	open := calendar.SessionOpen(time.Date(2024, time.April, 1, 0, 0, 0, 0, calendar.Location))
+	candles := []*models.OHLCV{
+		vwapCandle(time.Date(2024, time.March, 28, 10, 30, 0, 0, calendar.Location), 90, 1000), // previous session
+		vwapCandle(open.Add(-time.Hour), 500, 1000),                                            // pre-market
+		vwapCandle(open, 100, 1000),
+		vwapCandle(open.Add(time.Minute), 110, 1000),
+		vwapCandle(open.Add(7*time.Hour), 500, 1000), // after hours
+	}
+	for _, candle := range candles {
+		candle.Timeframe = "1m"
+	}
+
+	var vwap SessionVWAP
+	for i, candle := range candles {
+		vwap.Update(candle)
+		bands := SessionVWAPBands(candles[:i+1])
+		if bands == nil || bands.VWAP != vwap.Value() || bands.Bars != vwap.Bands().Bars {
+			t.Errorf("candle %d: bands %+v differ from the streaming VWAP %v", i, bands, vwap.Value())
+		}
+		// The pre-market bar keeps the previous session's VWAP
+		if i == 1 && vwap.Value() != 90 {
+			t.Errorf("pre-market VWAP = %v, want the previous session's 90", vwap.Value())
+		}
+	}
+	if bands := vwap.Bands(); bands.VWAP != 105 || bands.Bars != 2 || !bands.Start.Equal(open) {
+		t.Errorf("VWAP should start at the regular open: %+v", bands)
+	}
+
+	// Warm-ups start at the session the VWAP reports
+	for _, tt := range []struct {
+		at   time.Time
+		want time.Time
+	}{
+		{open.Add(-time.Hour), time.Date(2024, time.March, 28, 0, 0, 0, 0, calendar.Location)},
+		{open, calendar.Date(open)},
+		{open.Add(7 * time.Hour), calendar.Date(open)},
+	} {
+		if got := SessionVWAPStart(tt.at); !got.Equal(tt.want) {
+			t.Errorf("SessionVWAPStart(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+
+	// Daily bars overlap the session
+	daily := vwapCandle(time.Date(2024, time.April, 2, 0, 0, 0, 0, calendar.Location), 120, 1000)
+	daily.Timeframe = "1d"
+	if bands := SessionVWAPBands([]*models.OHLCV{daily}); bands == nil || bands.VWAP != 120 {
+		t.Errorf("daily bar VWAP = %+v, want 120", bands)
+	}
+}
+
+func TestAnchoredVWAP(t *testing.T) {
+	start := time.Date(2024, time.March, 28, 10, 0, 0, 0, calendar.Location)
+	candles := []*models.OHLCV{
+		vwapCandle(start, 50, 1000),
+		vwapCandle(start.Add(time.Minute), 100, 1000),
+		vwapCandle(start.Add(2*time.Minute), 106, 3000),
+	}
+
+	bands := AnchoredVWAPBands(candles, start.Add(time.Minute))
+	if bands == nil || bands.Bars != 2 {
+		t.Fatalf("anchored VWAP should skip candles before the anchor: %+v", bands)
+	}
+	if math.Abs(bands.VWAP-104.5) > 1e-9 {
+		t.Errorf("anchored VWAP = %v, want 104.5", bands.VWAP)
+	}
+	if math.Abs(bands.Upper2-bands.VWAP-2*bands.StdDev) > 1e-9 {
+		t.Errorf("upper band should be 2 stddev above the VWAP: %+v", bands)
+	}
+}
+
+func TestLatestGap(t *testing.T) {
+	daily := []*models.OHLCV{
+		{Timestamp: time.Date(2024, time.March, 26, 0, 0, 0, 0, time.UTC), Open: 100, Close: 100},
+		{Timestamp: time.Date(2024, time.March, 27, 0, 0, 0, 0, time.UTC), Open: 105, Close: 104},
+		{Timestamp: time.Date(2024, time.March, 28, 0, 0, 0, 0, time.UTC), Open: 104.5, Close: 104},
+	}
+
+	anchor, gap, found := LatestGap(daily, 2)
+	if !found {
+		t.Fatal("Expected a gap")
+	}
+	if math.Abs(gap-5) > 1e-9 {
+		t.Errorf("gap = %v, want 5", gap)
+	}
+	if want := calendar.SessionOpen(daily[1].Timestamp); !anchor.Equal(want) {
+		t.Errorf("anchor = %v, want %v", anchor, want)
+	}
+
+	if _, _, found := LatestGap(daily, 10); found {
+		t.Error("Expected no gap of 10%")
+	}
+}
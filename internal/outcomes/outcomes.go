@@ -0,0 +1,353 @@
+package outcomes
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/analysis"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-272: Pattern outcome statistics from stored history
+
+// Defaults of a scan
+const (
+	DefaultHorizon      = 20  // candles after detection
+	DefaultLookback     = 100 // candles the chart patterns are detected in
+	DefaultTargetRanges = 2.0
+	DefaultStopRanges   = 1.0
+)
+
+// rangePeriods is the number of candles the average range of a pattern
+// without its own levels is taken over
+const rangePeriods = 14
+
+// pageSize is the number of candles loaded at once
+const pageSize = 10000
+
+// CandleSource loads stored candles in chronological order
+type CandleSource interface {
+	GetHistory(ctx context.Context, symbol, timeframe string, start, end time.Time, limit int) ([]*models.OHLCV, error)
+	GetBefore(ctx context.Context, symbol, timeframe string, before time.Time, limit int) ([]*models.OHLCV, error)
+}
+
+// Store persists pattern statistics
+type Store interface {
+	ReplacePatternStats(ctx context.Context, symbol, timeframe string, stats []*models.PatternOutcomeStats) error
+}
+
+// Job describes a scan over stored candles
+type Job struct {
+	Symbols      []string
+	Timeframe    string // stored timeframe: 1m, 5m, 15m, 1h, 4h, 1d
+	Start        time.Time
+	End          time.Time
+	Horizon      int     // candles measured after each pattern, default 20
+	Lookback     int     // candles chart patterns are detected in, default 100
+	TargetRanges float64 // target of patterns without one, in average ranges, default 2
+	StopRanges   float64 // stop of patterns without one, in average ranges, default 1
+}
+
+// Result reports the scan of a symbol
+type Result struct {
+	Symbol   string                        `json:"symbol"`
+	Candles  int                           `json:"candles"`
+	Outcomes int                           `json:"outcomes"`
+	Stats    []*models.PatternOutcomeStats `json:"stats"`
+	Err      error                         `json:"-"`
+}
+
+// Scanner detects the candlestick and chart patterns of stored candles and
+// measures what followed them
+type Scanner struct {
+	source      CandleSource
+	store       Store
+	candlestick *analysis.CandlestickAnalyzer
+	chart       *analysis.ChartPatternAnalyzer
+	logger      zerolog.Logger
+}
+
+// NewScanner creates a pattern outcome scanner detecting chart patterns on
+// the swings of the enrichment engine's detector. The store may be nil to
+// only compute the statistics.
+func NewScanner(source CandleSource, store Store, swings *analysis.SwingDetector) *Scanner {
+	return &Scanner{
+		source:      source,
+		store:       store,
+		candlestick: analysis.NewCandlestickAnalyzer(),
+		chart:       analysis.NewChartPatternAnalyzer(swings),
+		logger:      logger.NewContextLogger("pattern_outcomes"),
+	}
+}
+
+// Run scans the symbols of the job one after another and replaces their
+// stored statistics
+func (s *Scanner) Run(ctx context.Context, job *Job) []Result {
+	job.applyDefaults()
+
+	results := make([]Result, 0, len(job.Symbols))
+	for _, symbol := range job.Symbols {
+		result := s.runSymbol(ctx, job, symbol)
+		if result.Err != nil {
+			s.logger.Error().Err(result.Err).Str("symbol", symbol).Msg("Pattern outcome scan failed")
+		} else {
+			s.logger.Info().
+				Str("symbol", symbol).
+				Int("candles", result.Candles).
+				Int("outcomes", result.Outcomes).
+				Int("patterns", len(result.Stats)).
+				Msg("Pattern outcome scan completed")
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func (j *Job) applyDefaults() {
+	if j.Horizon < 1 {
+		j.Horizon = DefaultHorizon
+	}
+	if j.Lookback < analysis.CandlestickLookback {
+		j.Lookback = DefaultLookback
+	}
+	if j.TargetRanges <= 0 {
+		j.TargetRanges = DefaultTargetRanges
+	}
+	if j.StopRanges <= 0 {
+		j.StopRanges = DefaultStopRanges
+	}
+}
+
+func (s *Scanner) runSymbol(ctx context.Context, job *Job, symbol string) Result {
+	result := Result{Symbol: symbol}
+
+	// Patterns at the start of the range are detected with earlier candles
+	candles, err := s.source.GetBefore(ctx, symbol, job.Timeframe, job.Start, job.Lookback-1)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to load warm-up candles: %w", err)
+		return result
+	}
+	from := len(candles)
+
+	next := job.Start
+	for {
+		page, err := s.source.GetHistory(ctx, symbol, job.Timeframe, next, job.End, pageSize)
+		if err != nil {
+			result.Err = fmt.Errorf("failed to load candles: %w", err)
+			return result
+		}
+		candles = append(candles, page...)
+		if len(page) < pageSize {
+			break
+		}
+		// Stored timestamps have microsecond precision
+		next = page[len(page)-1].Timestamp.Add(time.Microsecond)
+	}
+	result.Candles = len(candles) - from
+
+	outcomes := s.Measure(candles, from, job)
+	result.Outcomes = len(outcomes)
+	result.Stats = Aggregate(symbol, job.Timeframe, job, outcomes)
+
+	if s.store != nil {
+		if err := s.store.ReplacePatternStats(ctx, symbol, job.Timeframe, result.Stats); err != nil {
+			result.Err = err
+		}
+	}
+
+	return result
+}
+
+// Measure detects the patterns completed by each of candles[from:] and
+// measures the horizon candles after them. Candlestick patterns end on the
+// candle they are detected on and count every time; a chart pattern counts
+// on the candle it first appears on, not again while it persists. Candles
+// without a full horizon after them are not measured.
+func (s *Scanner) Measure(candles []*models.OHLCV, from int, job *Job) []models.PatternOutcome {
+	job.applyDefaults()
+
+	var outcomes []models.PatternOutcome
+	previous := make(map[string]bool)
+
+	for i := max(from, 1); i+job.Horizon < len(candles); i++ {
+		window := candles[max(0, i+1-job.Lookback) : i+1]
+		detected := s.detect(window, job)
+
+		current := make(map[string]bool, len(detected))
+		for _, outcome := range detected {
+			key := outcome.PatternType + "|" + outcome.PatternName + "|" + outcome.Signal
+			if current[key] {
+				continue
+			}
+			current[key] = true
+			if outcome.PatternType == "chart" && previous[key] {
+				continue
+			}
+
+			outcome.Timestamp = candles[i].Timestamp
+			measure(&outcome, candles[i+1:i+1+job.Horizon])
+			outcomes = append(outcomes, outcome)
+		}
+		previous = current
+	}
+
+	return outcomes
+}
+
+// detect returns the directional patterns completed by the last candle of the
+// window with their entry, target and stop
+func (s *Scanner) detect(window []*models.OHLCV, job *Job) []models.PatternOutcome {
+	last := window[len(window)-1]
+
+	var avgRange float64
+	recent := window[max(0, len(window)-rangePeriods):]
+	for _, candle := range recent {
+		avgRange += candle.High - candle.Low
+	}
+	avgRange /= float64(len(recent))
+
+	levels := func(outcome models.PatternOutcome, target, stop float64) models.PatternOutcome {
+		outcome.Entry = last.Close
+		direction := 1.0
+		if outcome.Signal == "bearish" {
+			direction = -1
+		}
+
+		// Patterns without levels on both sides of the entry get levels in
+		// average ranges
+		if direction*(target-outcome.Entry) <= 0 || direction*(outcome.Entry-stop) <= 0 {
+			target = outcome.Entry + direction*job.TargetRanges*avgRange
+			stop = outcome.Entry - direction*job.StopRanges*avgRange
+		}
+		outcome.Target, outcome.StopLoss = target, stop
+		return outcome
+	}
+
+	var detected []models.PatternOutcome
+
+	candlesticks := window[max(0, len(window)-analysis.CandlestickLookback):]
+	for _, pattern := range s.candlestick.DetectPatterns(candlesticks) {
+		if pattern.Type != "bullish" && pattern.Type != "bearish" {
+			continue
+		}
+		detected = append(detected, levels(models.PatternOutcome{
+			PatternType: "candlestick",
+			PatternName: pattern.Name,
+			Signal:      pattern.Type,
+		}, 0, 0))
+	}
+
+	for _, pattern := range s.chart.DetectPatterns(window) {
+		if pattern.Signal != "bullish" && pattern.Signal != "bearish" || pattern.Status == analysis.PatternFailed {
+			continue
+		}
+		detected = append(detected, levels(models.PatternOutcome{
+			PatternType: "chart",
+			PatternName: pattern.Name,
+			Signal:      pattern.Signal,
+		}, pattern.Target, pattern.StopLoss))
+	}
+
+	return detected
+}
+
+// measure walks the candles after a pattern until its target or stop. A
+// candle reaching both counts as the stop.
+func measure(outcome *models.PatternOutcome, horizon []*models.OHLCV) {
+	direction := 1.0
+	if outcome.Signal == "bearish" {
+		direction = -1
+	}
+	percent := func(price float64) float64 {
+		return direction * (price - outcome.Entry) / outcome.Entry * 100
+	}
+
+	outcome.Outcome = models.OutcomeExpired
+	outcome.Bars = len(horizon)
+	exit := horizon[len(horizon)-1].Close
+
+	for i, candle := range horizon {
+		favorable, adverse := candle.High, candle.Low
+		if direction < 0 {
+			favorable, adverse = candle.Low, candle.High
+		}
+		outcome.MaxFavorable = math.Max(outcome.MaxFavorable, percent(favorable))
+		outcome.MaxAdverse = math.Max(outcome.MaxAdverse, -percent(adverse))
+
+		switch {
+		case percent(adverse) <= percent(outcome.StopLoss):
+			outcome.Outcome, exit = models.OutcomeStop, outcome.StopLoss
+		case percent(favorable) >= percent(outcome.Target):
+			outcome.Outcome, exit = models.OutcomeTarget, outcome.Target
+		default:
+			continue
+		}
+		outcome.Bars = i + 1
+		break
+	}
+
+	outcome.Return = percent(exit)
+}
+
+// Aggregate summarizes outcomes per pattern and signal, most frequent first
+func Aggregate(symbol, timeframe string, job *Job, outcomes []models.PatternOutcome) []*models.PatternOutcomeStats {
+	now := time.Now()
+	byKey := make(map[string]*models.PatternOutcomeStats)
+	var stats []*models.PatternOutcomeStats
+
+	for _, outcome := range outcomes {
+		key := models.PatternStatsKey(symbol, timeframe, outcome.PatternType, outcome.PatternName, outcome.Signal)
+		stat := byKey[key]
+		if stat == nil {
+			stat = &models.PatternOutcomeStats{
+				Symbol:      symbol,
+				Timeframe:   timeframe,
+				PatternType: outcome.PatternType,
+				PatternName: outcome.PatternName,
+				Signal:      outcome.Signal,
+				Horizon:     job.Horizon,
+				RangeStart:  job.Start,
+				RangeEnd:    job.End,
+				UpdatedAt:   now,
+			}
+			byKey[key] = stat
+			stats = append(stats, stat)
+		}
+
+		stat.Occurrences++
+		switch outcome.Outcome {
+		case models.OutcomeTarget:
+			stat.TargetHits++
+		case models.OutcomeStop:
+			stat.StopHits++
+		default:
+			stat.Expired++
+		}
+		stat.AvgMaxFavorable += outcome.MaxFavorable
+		stat.AvgMaxAdverse += outcome.MaxAdverse
+		stat.AvgReturn += outcome.Return
+		stat.AvgBars += float64(outcome.Bars)
+	}
+
+	for _, stat := range stats {
+		n := float64(stat.Occurrences)
+		stat.HitRate = float64(stat.TargetHits) / n
+		stat.AvgMaxFavorable /= n
+		stat.AvgMaxAdverse /= n
+		stat.AvgReturn /= n
+		stat.AvgBars /= n
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Occurrences > stats[j].Occurrences
+	})
+
+	return stats
+}
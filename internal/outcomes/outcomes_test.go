@@ -0,0 +1,184 @@
+package outcomes
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/analysis"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// memorySource serves candles of a single symbol from memory
+type memorySource struct {
+	candles []*models.OHLCV
+}
+
+func (s *memorySource) GetHistory(ctx context.Context, symbol, timeframe string, start, end time.Time, limit int) ([]*models.OHLCV, error) {
+	var result []*models.OHLCV
+	for _, candle := range s.candles {
+		if !candle.Timestamp.Before(start) && !candle.Timestamp.After(end) && len(result) < limit {
+			result = append(result, candle)
+		}
+	}
+	return result, nil
+}
+
+func (s *memorySource) GetBefore(ctx context.Context, symbol, timeframe string, before time.Time, limit int) ([]*models.OHLCV, error) {
+	var result []*models.OHLCV
+	for _, candle := range s.candles {
+		if candle.Timestamp.Before(before) {
+			result = append(result, candle)
+		}
+	}
+	if len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result, nil
+}
+
+// memoryStore keeps the replaced statistics
+type memoryStore struct {
+	stats map[string][]*models.PatternOutcomeStats
+}
+
+func (s *memoryStore) ReplacePatternStats(ctx context.Context, symbol, timeframe string, stats []*models.PatternOutcomeStats) error {
+	s.stats[symbol+":"+timeframe] = stats
+	return nil
+}
+
+func candle(open, high, low, close float64) *models.OHLCV {
+	return &models.OHLCV{Open: open, High: high, Low: low, Close: close, Volume: 1000}
+}
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		name    string
+		signal  string
+		target  float64
+		stop    float64
+		horizon []*models.OHLCV
+		outcome string
+		bars    int
+		ret     float64
+		favor   float64
+		adverse float64
+	}{
+		{"bullish target", "bullish", 104, 98,
+			[]*models.OHLCV{candle(100, 102, 99, 101), candle(101, 105, 100.5, 104.5)},
+			models.OutcomeTarget, 2, 4, 5, 1},
+		{"stop wins a candle reaching both", "bullish", 104, 98,
+			[]*models.OHLCV{candle(100, 105, 97, 101)},
+			models.OutcomeStop, 1, -2, 5, 3},
+		{"bearish target", "bearish", 96, 102,
+			[]*models.OHLCV{candle(100, 101, 97, 98), candle(98, 99, 95, 96)},
+			models.OutcomeTarget, 2, 4, 5, 1},
+		{"expired", "bullish", 110, 90,
+			[]*models.OHLCV{candle(100, 102, 99, 101), candle(101, 103, 100, 102)},
+			models.OutcomeExpired, 2, 2, 3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome := models.PatternOutcome{Signal: tt.signal, Entry: 100, Target: tt.target, StopLoss: tt.stop}
+			measure(&outcome, tt.horizon)
+
+			if outcome.Outcome != tt.outcome || outcome.Bars != tt.bars {
+				t.Errorf("Expected %s after %d candles, got %s after %d", tt.outcome, tt.bars, outcome.Outcome, outcome.Bars)
+			}
+			for _, check := range []struct {
+				name      string
+				got, want float64
+			}{{"return", outcome.Return, tt.ret}, {"favorable", outcome.MaxFavorable, tt.favor}, {"adverse", outcome.MaxAdverse, tt.adverse}} {
+				if math.Abs(check.got-check.want) > 1e-9 {
+					t.Errorf("Expected %s %v, got %v", check.name, check.want, check.got)
+				}
+			}
+		})
+	}
+}
+
+func TestScannerRun(t *testing.T) {
+	// Five downtrends ending in a hammer, each followed by a rally
+	var candles []*models.OHLCV
+	for cycle := 0; cycle < 5; cycle++ {
+		for i := 0; i < 10; i++ {
+			close := 110 - float64(i)
+			candles = append(candles, candle(close+0.5, close+0.75, close-0.25, close))
+		}
+		candles = append(candles, candle(100.7, 101.05, 99, 101))
+		for i := 1; i <= 10; i++ {
+			close := 101 + float64(i)
+			candles = append(candles, candle(close-0.5, close+0.25, close-0.75, close))
+		}
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, c := range candles {
+		c.Symbol = "AAPL"
+		c.Timestamp = start.AddDate(0, 0, i)
+	}
+
+	store := &memoryStore{stats: make(map[string][]*models.PatternOutcomeStats)}
+	scanner := NewScanner(&memorySource{candles: candles}, store, analysis.NewSwingDetector(analysis.SwingATR, 3))
+	results := scanner.Run(context.Background(), &Job{
+		Symbols:   []string{"AAPL"},
+		Timeframe: "1d",
+		Start:     start,
+		End:       candles[len(candles)-1].Timestamp,
+		Horizon:   5,
+	})
+
+	if len(results) != 1 || results[0].Err != nil || results[0].Candles != len(candles) {
+		t.Fatalf("Unexpected results %+v", results)
+	}
+
+	stats := store.stats["AAPL:1d"]
+	if len(stats) == 0 {
+		t.Fatal("Expected stored stats")
+	}
+
+	var hammer *models.PatternOutcomeStats
+	for _, stat := range stats {
+		if stat.Occurrences != stat.TargetHits+stat.StopHits+stat.Expired {
+			t.Errorf("Outcomes of %s do not add up: %+v", stat.PatternName, stat)
+		}
+		if stat.Key() == models.PatternStatsKey("AAPL", "1d", "candlestick", "hammer", "bullish") {
+			hammer = stat
+		}
+	}
+
+	// Every hammer reaches its target of two average ranges in the rally
+	if hammer == nil || hammer.Occurrences != 5 || hammer.TargetHits != 5 || hammer.HitRate != 1 {
+		t.Fatalf("Expected five successful hammers, got %+v", hammer)
+	}
+	if hammer.AvgBars != 2 || hammer.AvgReturn <= 0 || hammer.AvgMaxAdverse != 0 || hammer.Horizon != 5 {
+		t.Errorf("Unexpected hammer stats %+v", hammer)
+	}
+}
+
+func TestMeasureCountsRepeatedCandlesticks(t *testing.T) {
+	// Flat candles, then three bullish marubozu in a row and a flat horizon
+	var candles []*models.OHLCV
+	for i := 0; i < 12; i++ {
+		candles = append(candles, candle(100, 100.5, 99.5, 100.1))
+	}
+	for i := 0; i < 3; i++ {
+		open := 100 + 2*float64(i)
+		candles = append(candles, candle(open, open+2, open, open+2))
+	}
+	for i := 0; i < 5; i++ {
+		candles = append(candles, candle(106, 106.5, 105.5, 106))
+	}
+
+	scanner := NewScanner(&memorySource{}, nil, analysis.NewSwingDetector(analysis.SwingATR, 3))
+	count := 0
+	for _, outcome := range scanner.Measure(candles, 0, &Job{Horizon: 5}) {
+		if outcome.PatternName == "bullish_marubozu" {
+			count++
+		}
+	}
+	if count != 3 {
+		t.Errorf("Expected every bullish marubozu counted, got %d", count)
+	}
+}
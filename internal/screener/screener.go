@@ -0,0 +1,164 @@
+package screener
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/enrichment"
+	"github.com/ridopark/jonbu-ohlcv/internal/expression"
+	"github.com/ridopark/jonbu-ohlcv/internal/indicators"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-259: Screening symbols with rule expressions
+
+// HistorySource loads stored candles, newest first
+type HistorySource interface {
+	GetBySymbol(ctx context.Context, symbol, timeframe string, limit int) ([]*models.OHLCV, error)
+}
+
+// Result is the evaluation of an expression on the latest candle of a symbol
+type Result struct {
+	Symbol    string             `json:"symbol"`
+	Matched   bool               `json:"matched"`
+	Timestamp time.Time          `json:"timestamp"`
+	Close     float64            `json:"close"`
+	Values    map[string]float64 `json:"values,omitempty"` // indicator values read by the expression
+	Signal    string             `json:"signal,omitempty"`
+	Err       error              `json:"-"`
+}
+
+// Screener evaluates expressions over the latest enriched candle of symbols
+type Screener struct {
+	source   HistorySource
+	engine   *enrichment.CandleEnrichmentEngine
+	registry *indicators.Registry
+}
+
+// New creates a screener over stored candles
+func New(source HistorySource, engine *enrichment.CandleEnrichmentEngine) *Screener {
+	return &Screener{
+		source:   source,
+		engine:   engine,
+		registry: indicators.DefaultRegistry(),
+	}
+}
+
+// HasSignalProfile reports whether candles can be screened with a signal profile
+func (s *Screener) HasSignalProfile(name string) bool {
+	return s.engine.HasSignalProfile(name)
+}
+
+// Screen evaluates the program for every symbol and returns one result per
+// symbol in input order. Signals are scored by the named signal profile,
+// empty for the default. Symbols that cannot be evaluated carry Err.
+func (s *Screener) Screen(ctx context.Context, program *expression.Program, symbols []string, timeframe, profile string) []Result {
+	results := make([]Result, len(symbols))
+
+	concurrency := s.engine.Config().MaxConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		go func(i int, symbol string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = s.screenSymbol(ctx, program, symbol, timeframe, profile)
+		}(i, symbol)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// screenSymbol enriches the latest stored candle of a symbol and evaluates the program
+func (s *Screener) screenSymbol(ctx context.Context, program *expression.Program, symbol, timeframe, profile string) Result {
+	result := Result{Symbol: symbol}
+
+	limit, err := s.historyLimit(program)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	candles, err := s.source.GetBySymbol(ctx, symbol, timeframe, limit)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to load candles: %w", err)
+		return result
+	}
+	if len(candles) == 0 {
+		result.Err = fmt.Errorf("no %s candles stored", timeframe)
+		return result
+	}
+
+	// Stored candles come newest first; enrichment needs chronological order
+	for i, j := 0, len(candles)-1; i < j; i, j = i+1, j-1 {
+		candles[i], candles[j] = candles[j], candles[i]
+	}
+
+	options := models.DefaultEnrichmentOptions()
+	options.Indicators = program.Specs()
+	options.SignalProfile = profile
+
+	current := candles[len(candles)-1]
+	// Screens are batches, not stream enrichments
+	enriched, err := s.engine.EnrichCandle(enrichment.WithoutMetrics(ctx), current, candles[:len(candles)-1], options)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Matched = program.Match(expression.NewCandleEnv(enriched))
+	result.Timestamp = current.Timestamp
+	result.Close = current.Close
+	if enriched.Indicators != nil {
+		result.Values = programValues(program, enriched.Indicators.Values)
+	}
+	if enriched.Signals != nil {
+		result.Signal = enriched.Signals.OverallSignal
+	}
+
+	return result
+}
+
+// historyLimit returns how many candles cover the program's indicators and
+// the engine's own history needs
+func (s *Screener) historyLimit(program *expression.Program) (int, error) {
+	lookback, err := s.registry.Lookback(program.Specs())
+	if err != nil {
+		return 0, err
+	}
+
+	config := s.engine.Config()
+	limit := config.MaxHistoryPeriods
+	if lookback > limit {
+		limit = lookback
+	}
+	if config.MinHistoryPeriods > limit {
+		limit = config.MinHistoryPeriods
+	}
+
+	return limit + 1, nil
+}
+
+// programValues keeps the indicator values the program reads
+func programValues(program *expression.Program, values map[string]float64) map[string]float64 {
+	selected := make(map[string]float64)
+	for _, spec := range program.Specs() {
+		for key, value := range values {
+			if key == spec || strings.HasPrefix(key, spec+".") {
+				selected[key] = value
+			}
+		}
+	}
+	return selected
+}
@@ -0,0 +1,89 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// REQ-251: Multi-instance candle fan-out
+
+// Bus message types
+const (
+	BusMessageCandle         = "candle"
+	BusMessageEnrichedCandle = "enriched_candle"
+	BusMessageControl        = "control"
+	BusMessageEvent          = "event" // alerts and other stream events
+)
+
+// BusMessage is the envelope exchanged between server instances
+type BusMessage struct {
+	Type        string          `json:"type"`
+	Origin      string          `json:"origin"` // instance ID of the publisher
+	Symbol      string          `json:"symbol,omitempty"`
+	Timeframe   string          `json:"timeframe,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+	PublishedAt time.Time       `json:"published_at"`
+}
+
+// BusHandler receives messages delivered by a Bus
+type BusHandler func(msg *BusMessage)
+
+// Bus distributes messages to every server instance, including the publisher
+type Bus interface {
+	// Publish sends a message to all instances
+	Publish(ctx context.Context, msg *BusMessage) error
+
+	// Subscribe registers a handler for inbound messages
+	Subscribe(handler BusHandler)
+
+	// Start begins receiving messages until the context is cancelled
+	Start(ctx context.Context) error
+
+	// Close releases bus resources
+	Close() error
+}
+
+// LocalBus is an in-process Bus used for single-instance deployments and tests
+type LocalBus struct {
+	handlers []BusHandler
+	mu       sync.RWMutex
+}
+
+// NewLocalBus creates a new in-process bus
+func NewLocalBus() *LocalBus {
+	return &LocalBus{}
+}
+
+// Publish delivers the message synchronously to all registered handlers
+func (b *LocalBus) Publish(ctx context.Context, msg *BusMessage) error {
+	b.mu.RLock()
+	handlers := make([]BusHandler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(msg)
+	}
+
+	return nil
+}
+
+// Subscribe registers a handler for inbound messages
+func (b *LocalBus) Subscribe(handler BusHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Start is a no-op for the in-process bus
+func (b *LocalBus) Start(ctx context.Context) error {
+	return nil
+}
+
+// Close is a no-op for the in-process bus
+func (b *LocalBus) Close() error {
+	return nil
+}
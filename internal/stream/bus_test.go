@@ -0,0 +1,187 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// memorySpillStore keeps spilled payloads in memory
+type memorySpillStore struct {
+	mu       sync.Mutex
+	payloads map[int64][]byte
+}
+
+func (s *memorySpillStore) Store(ctx context.Context, data []byte) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.payloads == nil {
+		s.payloads = make(map[int64][]byte)
+	}
+	id := int64(len(s.payloads) + 1)
+	s.payloads[id] = append([]byte(nil), data...)
+	return id, nil
+}
+
+func (s *memorySpillStore) Load(ctx context.Context, id int64) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, exists := s.payloads[id]
+	if !exists {
+		return nil, errors.New("no rows in result set")
+	}
+	return data, nil
+}
+
+func TestLocalBusDeliversToEveryHandler(t *testing.T) {
+	bus := NewLocalBus()
+
+	var received []string
+	for _, name := range []string{"first", "second"} {
+		name := name
+		bus.Subscribe(func(msg *BusMessage) {
+			received = append(received, name+":"+msg.Symbol)
+		})
+	}
+
+	if err := bus.Publish(context.Background(), &BusMessage{Type: BusMessageCandle, Symbol: "AAPL"}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	if want := []string{"first:AAPL", "second:AAPL"}; strings.Join(received, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, received)
+	}
+}
+
+func TestPostgresBusPayloads(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		spilled bool
+	}{
+		{"small message", 300, false},
+		{"message at the limit", maxNotifyPayload, false},
+		{"oversized message", maxNotifyPayload + 1, true},
+		{"enriched candle sized", 64 * 1024, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spills := &memorySpillStore{}
+			bus := NewPostgresBus(nil, "", "test_fanout", zerolog.Nop())
+			bus.spills = spills
+
+			msg := sizedMessage(t, tt.size)
+			payload, err := bus.encode(context.Background(), msg)
+			if err != nil {
+				t.Fatalf("encode failed: %v", err)
+			}
+			if len(payload) > maxNotifyPayload {
+				t.Fatalf("Notification payload of %d bytes exceeds the limit", len(payload))
+			}
+			if spilled := strings.HasPrefix(payload, spillReferencePrefix); spilled != tt.spilled {
+				t.Fatalf("Expected spilled=%v, got payload %.40q", tt.spilled, payload)
+			}
+
+			var received []*BusMessage
+			bus.Subscribe(func(msg *BusMessage) { received = append(received, msg) })
+			bus.dispatch(context.Background(), payload)
+
+			if len(received) != 1 {
+				t.Fatalf("Expected one delivered message, got %d", len(received))
+			}
+			if string(received[0].Payload) != string(msg.Payload) || received[0].Origin != msg.Origin {
+				t.Error("Delivered message differs from the published one")
+			}
+		})
+	}
+}
+
+func TestPostgresBusDropsUnreadablePayloads(t *testing.T) {
+	bus := NewPostgresBus(nil, "", "test_fanout", zerolog.Nop())
+	bus.spills = &memorySpillStore{}
+
+	delivered := 0
+	bus.Subscribe(func(msg *BusMessage) { delivered++ })
+
+	for _, payload := range []string{"@42", "@not-a-number", "{not json"} {
+		bus.dispatch(context.Background(), payload)
+	}
+	if delivered != 0 {
+		t.Errorf("Expected no deliveries, got %d", delivered)
+	}
+}
+
+// sizedMessage returns a bus message whose JSON encoding is size bytes long
+func sizedMessage(t *testing.T, size int) *BusMessage {
+	t.Helper()
+	msg := &BusMessage{
+		Type:        BusMessageEnrichedCandle,
+		Origin:      "instance-a",
+		Symbol:      "AAPL",
+		Timeframe:   "1min",
+		Payload:     json.RawMessage(`""`),
+		PublishedAt: time.Date(2024, 6, 3, 14, 30, 0, 0, time.UTC),
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil || len(data) > size {
+		t.Fatalf("Cannot build a %d byte message", size)
+	}
+	msg.Payload = json.RawMessage(`"` + strings.Repeat("x", size-len(data)) + `"`)
+	return msg
+}
+
+func TestHubFanout(t *testing.T) {
+	bus := NewLocalBus()
+	origin := NewHub(zerolog.Nop())
+	other := NewHub(zerolog.Nop())
+	origin.AttachBus(bus, "instance-a")
+	other.AttachBus(bus, "instance-b")
+	defer origin.Stop()
+	defer other.Stop()
+
+	// Only the publishing side runs, so the broadcast queues stay observable
+	go origin.publishLoop()
+
+	candle := &models.Candle{Symbol: "AAPL", Interval: "1min", Close: 190.5}
+	origin.BroadcastCandle("AAPL", "1min", candle)
+	origin.BroadcastEvent(EventAlert, "AAPL", "1min", map[string]string{"message": "crossed"})
+
+	select {
+	case broadcast := <-other.broadcast:
+		if broadcast.Symbol != "AAPL" || broadcast.Candle.Close != 190.5 {
+			t.Errorf("Unexpected fanned-out candle %+v", broadcast)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the candle on the other instance")
+	}
+
+	select {
+	case event := <-other.eventBroadcast:
+		if event.Type != EventAlert {
+			t.Errorf("Expected an alert event, got %q", event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the event on the other instance")
+	}
+
+	// The origin queued the candle once when it was broadcast and ignores its
+	// own message coming back over the bus
+	if queued := len(origin.broadcast); queued != 1 {
+		t.Errorf("Expected the candle queued once on the origin, got %d", queued)
+	}
+	if queued := len(other.broadcast); queued != 0 {
+		t.Errorf("Expected a single fanned-out candle, got %d more", queued)
+	}
+}
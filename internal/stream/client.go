@@ -42,7 +42,8 @@ type ClientMessage struct {
 	Type      string `json:"type"`
 	Symbol    string `json:"symbol,omitempty"`
 	Timeframe string `json:"timeframe,omitempty"`
-	Action    string `json:"action,omitempty"` // subscribe, unsubscribe
+	Action    string `json:"action,omitempty"`  // subscribe, unsubscribe
+	Profile   string `json:"profile,omitempty"` // REQ-266: signal profile of enriched candles
 }
 
 // ServerMessage represents messages to clients
@@ -196,6 +197,10 @@ func (c *Client) handleSubscription(msg ClientMessage) {
 		c.sendError(fmt.Sprintf("Invalid subscription: %v", err))
 		return
 	}
+	if msg.Action == "subscribe" && !c.hub.HasSignalProfile(msg.Profile) {
+		c.sendError(fmt.Sprintf("Invalid subscription: unknown signal profile: %s", msg.Profile))
+		return
+	}
 
 	subscriptionKey := fmt.Sprintf("%s:%s", msg.Symbol, msg.Timeframe)
 
@@ -210,10 +215,12 @@ func (c *Client) handleSubscription(msg ClientMessage) {
 			Symbol:    msg.Symbol,
 			Timeframe: msg.Timeframe,
 			Action:    "subscribe",
+			Profile:   msg.Profile,
 		}
 		c.logger.Info().
 			Str("symbol", msg.Symbol).
 			Str("timeframe", msg.Timeframe).
+			Str("profile", msg.Profile).
 			Msg("Client subscribed")
 
 	case "unsubscribe":
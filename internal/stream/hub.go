@@ -2,25 +2,30 @@ package stream
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
 
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 	"github.com/rs/zerolog"
 )
 
 // REQ-017: WebSocket server setup and client management
 // REQ-018: Symbol and timeframe subscription management
 // REQ-020: Backpressure and flow control
+// REQ-251: Multi-instance candle fan-out
 
 // Hub maintains the set of active clients and broadcasts messages to them
 type Hub struct {
 	// Registered clients
 	clients map[*Client]bool
 
-	// Client subscriptions by symbol:timeframe
-	subscriptions map[string]map[*Client]bool
+	// Client subscriptions by symbol:timeframe, with the signal profile of each client
+	subscriptions map[string]map[*Client]string
+
+	// REQ-266: Signal profiles clients may subscribe with, nil for any
+	profiles map[string]bool
 
 	// Inbound messages from clients
 	register   chan *Client
@@ -30,6 +35,12 @@ type Hub struct {
 	// Broadcast channel for OHLCV data
 	broadcast         chan CandleBroadcast
 	enrichedBroadcast chan EnrichedCandleBroadcast
+	eventBroadcast    chan EventBroadcast
+
+	// Optional fan-out bus shared with other instances
+	bus        Bus
+	instanceID string
+	outbound   chan *BusMessage
 
 	// Context for graceful shutdown
 	ctx    context.Context
@@ -48,6 +59,7 @@ type SubscriptionEvent struct {
 	Symbol    string
 	Timeframe string
 	Action    string // subscribe, unsubscribe
+	Profile   string // signal profile, empty for the enriched candle's own signals
 }
 
 // CandleBroadcast represents candle data to broadcast
@@ -64,18 +76,42 @@ type EnrichedCandleBroadcast struct {
 	Candle    *models.EnrichedCandle
 }
 
+// Stream event types delivered through BroadcastEvent
+const (
+	EventAlert        = "alert"
+	EventRegimeChange = "regime_change" // REQ-273
+	EventLevelTest    = "level_test"    // REQ-274
+	EventLevelBreak   = "level_break"   // REQ-274
+)
+
+// EventBroadcast represents a stream event such as an alert to broadcast
+type EventBroadcast struct {
+	Type      string
+	Symbol    string
+	Timeframe string
+	Data      interface{}
+}
+
+// streamEvent is the fan-out payload of an EventBroadcast
+type streamEvent struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
 // NewHub creates a new WebSocket hub
 func NewHub(logger zerolog.Logger) *Hub {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Hub{
 		clients:           make(map[*Client]bool),
-		subscriptions:     make(map[string]map[*Client]bool),
+		subscriptions:     make(map[string]map[*Client]string),
 		register:          make(chan *Client, 100),
 		unregister:        make(chan *Client, 100),
 		subscribe:         make(chan SubscriptionEvent, 1000),
 		broadcast:         make(chan CandleBroadcast, 10000),         // REQ-020: Large buffer for backpressure
 		enrichedBroadcast: make(chan EnrichedCandleBroadcast, 10000), // REQ-020: Large buffer for enriched candles
+		eventBroadcast:    make(chan EventBroadcast, 1000),
+		outbound:          make(chan *BusMessage, 10000),
 		ctx:               ctx,
 		cancel:            cancel,
 		logger: logger.With().
@@ -84,11 +120,27 @@ func NewHub(logger zerolog.Logger) *Hub {
 	}
 }
 
+// AttachBus routes broadcasts through a fan-out bus so that clients of every
+// instance receive them. Must be called before Start.
+func (h *Hub) AttachBus(bus Bus, instanceID string) {
+	h.bus = bus
+	h.instanceID = instanceID
+	bus.Subscribe(h.handleBusMessage)
+
+	h.logger.Info().
+		Str("instance_id", instanceID).
+		Msg("Fan-out bus attached")
+}
+
 // Start begins the hub's main loop
 func (h *Hub) Start() {
 	h.logger.Info().Msg("WebSocket hub started")
 
 	go h.run()
+
+	if h.bus != nil {
+		go h.publishLoop()
+	}
 }
 
 // Stop gracefully shuts down the hub
@@ -130,6 +182,9 @@ func (h *Hub) run() {
 		case enrichedBroadcast := <-h.enrichedBroadcast:
 			h.broadcastEnrichedCandle(enrichedBroadcast)
 
+		case event := <-h.eventBroadcast:
+			h.broadcastEvent(event)
+
 		case <-ticker.C:
 			h.logMetrics()
 		}
@@ -194,9 +249,9 @@ func (h *Hub) handleSubscription(event SubscriptionEvent) {
 	switch event.Action {
 	case "subscribe":
 		if h.subscriptions[subscriptionKey] == nil {
-			h.subscriptions[subscriptionKey] = make(map[*Client]bool)
+			h.subscriptions[subscriptionKey] = make(map[*Client]string)
 		}
-		h.subscriptions[subscriptionKey][event.Client] = true
+		h.subscriptions[subscriptionKey][event.Client] = event.Profile
 
 		h.logger.Debug().
 			Str("client_id", event.Client.ID).
@@ -261,7 +316,8 @@ func (h *Hub) broadcastCandle(broadcast CandleBroadcast) {
 		Msg("Broadcasted candle data")
 }
 
-// broadcastEnrichedCandle sends enriched candle data to subscribed clients
+// broadcastEnrichedCandle sends enriched candle data to subscribed clients,
+// each with the signals of its signal profile
 func (h *Hub) broadcastEnrichedCandle(broadcast EnrichedCandleBroadcast) {
 	// Debug: Log what we're broadcasting
 	h.logger.Info().
@@ -271,51 +327,57 @@ func (h *Hub) broadcastEnrichedCandle(broadcast EnrichedCandleBroadcast) {
 
 	subscriptionKey := fmt.Sprintf("%s:%s", broadcast.Symbol, broadcast.Timeframe)
 
+	// REQ-266: Group the subscribers by signal profile
 	h.mu.RLock()
-	clients, exists := h.subscriptions[subscriptionKey]
-	if !exists || len(clients) == 0 {
-		h.mu.RUnlock()
-		return
+	byProfile := make(map[string][]*Client)
+	for client, profile := range h.subscriptions[subscriptionKey] {
+		byProfile[profile] = append(byProfile[profile], client)
 	}
 	h.mu.RUnlock()
 
-	// Create WebSocket message for enriched candle with interval at top level
-	enrichedData := map[string]interface{}{
-		"ohlcv":      broadcast.Candle.OHLCV,
-		"indicators": broadcast.Candle.Indicators,
-		"analysis":   broadcast.Candle.Analysis,
-		"signals":    broadcast.Candle.Signals,
-		"metadata":   broadcast.Candle.Metadata,
-		"interval":   broadcast.Timeframe, // Add interval at top level for frontend
+	if len(byProfile) == 0 {
+		return
 	}
 
-	message := ServerMessage{
-		Type:      "enriched_candle",
-		Symbol:    broadcast.Symbol,
-		Timeframe: broadcast.Timeframe,
-		Interval:  broadcast.Timeframe, // Add interval at top level
-		Data:      enrichedData,
-		Timestamp: time.Now(),
-	}
+	sentCount := 0
+	for profile, clients := range byProfile {
+		// Create WebSocket message for enriched candle with interval at top level
+		enrichedData := map[string]interface{}{
+			"ohlcv":      broadcast.Candle.OHLCV,
+			"indicators": broadcast.Candle.Indicators,
+			"analysis":   broadcast.Candle.Analysis,
+			"signals":    profileSignals(broadcast.Candle, profile),
+			"metadata":   broadcast.Candle.Metadata,
+			"interval":   broadcast.Timeframe, // Add interval at top level for frontend
+		}
 
-	// Debug: Log the actual message being sent
-	h.logger.Info().
-		Str("message_type", message.Type).
-		Str("message_symbol", message.Symbol).
-		Str("message_timeframe", message.Timeframe).
-		Str("message_interval", message.Interval).
-		Interface("enriched_data_interval", enrichedData["interval"]).
-		Msg("About to send enriched candle message - DEBUG")
+		message := ServerMessage{
+			Type:      "enriched_candle",
+			Symbol:    broadcast.Symbol,
+			Timeframe: broadcast.Timeframe,
+			Interval:  broadcast.Timeframe, // Add interval at top level
+			Data:      enrichedData,
+			Timestamp: time.Now(),
+		}
 
-	// Broadcast to all subscribed clients
-	sentCount := 0
-	for client := range clients {
-		select {
-		case <-h.ctx.Done():
-			return
-		default:
-			client.sendMessage(message)
-			sentCount++
+		// Debug: Log the actual message being sent
+		h.logger.Info().
+			Str("message_type", message.Type).
+			Str("message_symbol", message.Symbol).
+			Str("message_timeframe", message.Timeframe).
+			Str("message_interval", message.Interval).
+			Str("profile", profile).
+			Interface("enriched_data_interval", enrichedData["interval"]).
+			Msg("About to send enriched candle message - DEBUG")
+
+		for _, client := range clients {
+			select {
+			case <-h.ctx.Done():
+				return
+			default:
+				client.sendMessage(message)
+				sentCount++
+			}
 		}
 	}
 
@@ -323,11 +385,63 @@ func (h *Hub) broadcastEnrichedCandle(broadcast EnrichedCandleBroadcast) {
 		Str("symbol", broadcast.Symbol).
 		Str("timeframe", broadcast.Timeframe).
 		Int("clients", sentCount).
+		Int("profiles", len(byProfile)).
 		Msg("Broadcasted enriched candle data")
 }
 
+// profileSignals returns the signals of an enriched candle for a signal
+// profile, falling back to the candle's own signals
+func profileSignals(candle *models.EnrichedCandle, profile string) *models.TradingSignals {
+	if signals := candle.ProfileSignals[profile]; signals != nil {
+		return signals
+	}
+	return candle.Signals
+}
+
+// SetSignalProfiles restricts subscriptions to the named signal profiles.
+// Must be called before Start.
+func (h *Hub) SetSignalProfiles(names []string) {
+	h.profiles = make(map[string]bool, len(names))
+	for _, name := range names {
+		h.profiles[name] = true
+	}
+}
+
+// HasSignalProfile reports whether clients may subscribe with a signal
+// profile; the empty profile is always allowed
+func (h *Hub) HasSignalProfile(name string) bool {
+	return name == "" || h.profiles == nil || h.profiles[name]
+}
+
 // BroadcastCandle queues a candle for broadcasting
 func (h *Hub) BroadcastCandle(symbol, timeframe string, candle *models.Candle) {
+	h.queueCandle(symbol, timeframe, candle)
+	h.publish(BusMessageCandle, symbol, timeframe, candle)
+}
+
+// BroadcastEnrichedCandle queues an enriched candle for broadcasting
+func (h *Hub) BroadcastEnrichedCandle(symbol, timeframe string, candle *models.EnrichedCandle) {
+	h.queueEnrichedCandle(symbol, timeframe, candle)
+	h.publish(BusMessageEnrichedCandle, symbol, timeframe, candle)
+}
+
+// BroadcastEvent queues a stream event for clients subscribed to the symbol and timeframe
+func (h *Hub) BroadcastEvent(eventType, symbol, timeframe string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("type", eventType).
+			Str("symbol", symbol).
+			Msg("Failed to marshal stream event")
+		return
+	}
+
+	h.queueEvent(eventType, symbol, timeframe, json.RawMessage(payload))
+	h.publish(BusMessageEvent, symbol, timeframe, streamEvent{Type: eventType, Data: payload})
+}
+
+// queueCandle queues a candle for delivery to this instance's clients
+func (h *Hub) queueCandle(symbol, timeframe string, candle *models.Candle) {
 	select {
 	case h.broadcast <- CandleBroadcast{
 		Symbol:    symbol,
@@ -343,8 +457,8 @@ func (h *Hub) BroadcastCandle(symbol, timeframe string, candle *models.Candle) {
 	}
 }
 
-// BroadcastEnrichedCandle queues an enriched candle for broadcasting
-func (h *Hub) BroadcastEnrichedCandle(symbol, timeframe string, candle *models.EnrichedCandle) {
+// queueEnrichedCandle queues an enriched candle for delivery to this instance's clients
+func (h *Hub) queueEnrichedCandle(symbol, timeframe string, candle *models.EnrichedCandle) {
 	select {
 	case h.enrichedBroadcast <- EnrichedCandleBroadcast{
 		Symbol:    symbol,
@@ -360,6 +474,152 @@ func (h *Hub) BroadcastEnrichedCandle(symbol, timeframe string, candle *models.E
 	}
 }
 
+// queueEvent queues a stream event for delivery to this instance's clients
+func (h *Hub) queueEvent(eventType, symbol, timeframe string, data interface{}) {
+	select {
+	case h.eventBroadcast <- EventBroadcast{
+		Type:      eventType,
+		Symbol:    symbol,
+		Timeframe: timeframe,
+		Data:      data,
+	}:
+	default:
+		// REQ-020: Drop messages if broadcast buffer is full
+		h.logger.Warn().
+			Str("type", eventType).
+			Str("symbol", symbol).
+			Str("timeframe", timeframe).
+			Msg("Event broadcast buffer full, dropping event")
+	}
+}
+
+// broadcastEvent sends a stream event to subscribed clients
+func (h *Hub) broadcastEvent(event EventBroadcast) {
+	subscriptionKey := event.Symbol + ":" + event.Timeframe
+
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.subscriptions[subscriptionKey]))
+	for client := range h.subscriptions[subscriptionKey] {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	if len(clients) == 0 {
+		return
+	}
+
+	h.messageCount++
+
+	message := ServerMessage{
+		Type:      event.Type,
+		Symbol:    event.Symbol,
+		Timeframe: event.Timeframe,
+		Interval:  event.Timeframe,
+		Data:      event.Data,
+		Timestamp: time.Now(),
+	}
+
+	for _, client := range clients {
+		client.sendMessage(message)
+	}
+
+	h.logger.Debug().
+		Str("type", event.Type).
+		Str("symbol", event.Symbol).
+		Str("timeframe", event.Timeframe).
+		Int("clients", len(clients)).
+		Msg("Broadcasted stream event")
+}
+
+// publish queues a message for the fan-out bus, if one is attached
+func (h *Hub) publish(messageType, symbol, timeframe string, data interface{}) {
+	if h.bus == nil {
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("symbol", symbol).
+			Str("timeframe", timeframe).
+			Msg("Failed to marshal fan-out payload")
+		return
+	}
+
+	msg := &BusMessage{
+		Type:        messageType,
+		Origin:      h.instanceID,
+		Symbol:      symbol,
+		Timeframe:   timeframe,
+		Payload:     payload,
+		PublishedAt: time.Now(),
+	}
+
+	select {
+	case h.outbound <- msg:
+	default:
+		// REQ-020: Drop messages if the outbound buffer is full
+		h.logger.Warn().
+			Str("symbol", symbol).
+			Str("timeframe", timeframe).
+			Msg("Fan-out buffer full, dropping message")
+	}
+}
+
+// publishLoop forwards queued messages to the fan-out bus
+func (h *Hub) publishLoop() {
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case msg := <-h.outbound:
+			ctx, cancel := context.WithTimeout(h.ctx, 5*time.Second)
+			if err := h.bus.Publish(ctx, msg); err != nil {
+				h.logger.Error().Err(err).
+					Str("type", msg.Type).
+					Str("symbol", msg.Symbol).
+					Str("timeframe", msg.Timeframe).
+					Msg("Failed to publish to fan-out bus")
+			}
+			cancel()
+		}
+	}
+}
+
+// handleBusMessage delivers candles published by other instances to local clients
+func (h *Hub) handleBusMessage(msg *BusMessage) {
+	if msg.Origin == h.instanceID {
+		// Already delivered locally when it was broadcast
+		return
+	}
+
+	switch msg.Type {
+	case BusMessageCandle:
+		var candle models.Candle
+		if err := json.Unmarshal(msg.Payload, &candle); err != nil {
+			h.logger.Error().Err(err).Msg("Failed to decode fan-out candle")
+			return
+		}
+		h.queueCandle(msg.Symbol, msg.Timeframe, &candle)
+
+	case BusMessageEnrichedCandle:
+		var candle models.EnrichedCandle
+		if err := json.Unmarshal(msg.Payload, &candle); err != nil {
+			h.logger.Error().Err(err).Msg("Failed to decode fan-out enriched candle")
+			return
+		}
+		h.queueEnrichedCandle(msg.Symbol, msg.Timeframe, &candle)
+
+	case BusMessageEvent:
+		var event streamEvent
+		if err := json.Unmarshal(msg.Payload, &event); err != nil {
+			h.logger.Error().Err(err).Msg("Failed to decode fan-out event")
+			return
+		}
+		h.queueEvent(event.Type, msg.Symbol, msg.Timeframe, event.Data)
+	}
+}
+
 // RegisterClient adds a client to the hub
 func (h *Hub) RegisterClient(client *Client) {
 	h.register <- client
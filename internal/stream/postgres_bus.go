@@ -0,0 +1,266 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+)
+
+// REQ-251: Multi-instance candle fan-out via Postgres LISTEN/NOTIFY
+
+const (
+	// maxNotifyPayload keeps NOTIFY payloads under the 8000 byte server limit
+	maxNotifyPayload = 7900
+
+	// spillReferencePrefix marks notifications that reference a stored payload
+	spillReferencePrefix = "@"
+
+	// spillRetention is how long oversized payloads are kept for listeners
+	spillRetention = 5 * time.Minute
+)
+
+// PostgresBus distributes messages through Postgres LISTEN/NOTIFY.
+// Payloads larger than the NOTIFY limit are written to the stream_fanout
+// table and the notification carries a reference to the stored row.
+type PostgresBus struct {
+	db       *sql.DB
+	connStr  string
+	channel  string
+	listener *pq.Listener
+	spills   spillStore
+
+	handlers []BusHandler
+	mu       sync.RWMutex
+
+	logger zerolog.Logger
+}
+
+// spillStore keeps payloads too large for a notification
+type spillStore interface {
+	Store(ctx context.Context, data []byte) (int64, error)
+	Load(ctx context.Context, id int64) ([]byte, error)
+}
+
+// NewPostgresBus creates a new Postgres-backed bus
+func NewPostgresBus(db *sql.DB, connStr, channel string, logger zerolog.Logger) *PostgresBus {
+	logger = logger.With().
+		Str("component", "postgres_bus").
+		Str("channel", channel).
+		Logger()
+
+	return &PostgresBus{
+		db:      db,
+		connStr: connStr,
+		channel: channel,
+		spills:  &tableSpillStore{db: db, channel: channel, logger: logger},
+		logger:  logger,
+	}
+}
+
+// Publish sends a message to all instances listening on the channel
+func (b *PostgresBus) Publish(ctx context.Context, msg *BusMessage) error {
+	payload, err := b.encode(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := b.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, b.channel, payload); err != nil {
+		return fmt.Errorf("failed to notify channel %s: %w", b.channel, err)
+	}
+
+	return nil
+}
+
+// encode returns the notification payload of a message: the message itself,
+// or a reference to it once spilled when it exceeds the NOTIFY limit
+func (b *PostgresBus) encode(ctx context.Context, msg *BusMessage) (string, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal bus message: %w", err)
+	}
+
+	if len(data) <= maxNotifyPayload {
+		return string(data), nil
+	}
+
+	id, err := b.spills.Store(ctx, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to store oversized bus payload: %w", err)
+	}
+	return spillReferencePrefix + strconv.FormatInt(id, 10), nil
+}
+
+// Subscribe registers a handler for inbound messages
+func (b *PostgresBus) Subscribe(handler BusHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Start opens the LISTEN connection and dispatches notifications
+func (b *PostgresBus) Start(ctx context.Context) error {
+	b.listener = pq.NewListener(b.connStr, time.Second, time.Minute, b.handleListenerEvent)
+
+	if err := b.listener.Listen(b.channel); err != nil {
+		b.listener.Close()
+		return fmt.Errorf("failed to listen on channel %s: %w", b.channel, err)
+	}
+
+	go b.listen(ctx)
+
+	b.logger.Info().Msg("Postgres bus listening")
+	return nil
+}
+
+// Close stops listening and releases the LISTEN connection
+func (b *PostgresBus) Close() error {
+	if b.listener == nil {
+		return nil
+	}
+	return b.listener.Close()
+}
+
+// listen dispatches notifications until the context is cancelled
+func (b *PostgresBus) listen(ctx context.Context) {
+	ticker := time.NewTicker(90 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case notification := <-b.listener.Notify:
+			if notification == nil {
+				// Connection was re-established; notifications sent meanwhile are lost
+				b.logger.Warn().Msg("Postgres bus listener reconnected")
+				continue
+			}
+			b.dispatch(ctx, notification.Extra)
+
+		case <-ticker.C:
+			go func() {
+				if err := b.listener.Ping(); err != nil {
+					b.logger.Warn().Err(err).Msg("Postgres bus listener ping failed")
+				}
+			}()
+		}
+	}
+}
+
+// dispatch decodes a notification payload and hands it to all handlers
+func (b *PostgresBus) dispatch(ctx context.Context, payload string) {
+	msg, err := b.decode(ctx, payload)
+	if err != nil {
+		b.logger.Error().Err(err).Msg("Failed to decode bus message")
+		return
+	}
+
+	b.mu.RLock()
+	handlers := make([]BusHandler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(msg)
+	}
+}
+
+// decode returns the message of a notification payload, loading spilled ones
+func (b *PostgresBus) decode(ctx context.Context, payload string) (*BusMessage, error) {
+	data := []byte(payload)
+
+	if strings.HasPrefix(payload, spillReferencePrefix) {
+		id, err := strconv.ParseInt(strings.TrimPrefix(payload, spillReferencePrefix), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid spill reference %q: %w", payload, err)
+		}
+
+		loadCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		data, err = b.spills.Load(loadCtx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load spilled bus payload %d: %w", id, err)
+		}
+	}
+
+	var msg BusMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// tableSpillStore keeps oversized payloads in the stream_fanout table
+type tableSpillStore struct {
+	db      *sql.DB
+	channel string
+	logger  zerolog.Logger
+
+	lastCleanup time.Time
+	cleanupMu   sync.Mutex
+}
+
+// Store writes a payload and returns the ID listeners load it by
+func (s *tableSpillStore) Store(ctx context.Context, data []byte) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO stream_fanout (channel, payload) VALUES ($1, $2) RETURNING id`,
+		s.channel, data,
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	s.cleanup(ctx)
+	return id, nil
+}
+
+// Load reads a stored payload
+func (s *tableSpillStore) Load(ctx context.Context, id int64) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `SELECT payload FROM stream_fanout WHERE id = $1`, id).Scan(&data)
+	return data, err
+}
+
+// cleanup periodically removes spilled payloads that listeners no longer need
+func (s *tableSpillStore) cleanup(ctx context.Context) {
+	s.cleanupMu.Lock()
+	if time.Since(s.lastCleanup) < spillRetention {
+		s.cleanupMu.Unlock()
+		return
+	}
+	s.lastCleanup = time.Now()
+	s.cleanupMu.Unlock()
+
+	cutoff := time.Now().Add(-spillRetention)
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM stream_fanout WHERE channel = $1 AND created_at < $2`, s.channel, cutoff,
+	); err != nil {
+		s.logger.Warn().Err(err).Msg("Failed to clean up spilled bus payloads")
+	}
+}
+
+// handleListenerEvent logs connection state changes of the LISTEN connection
+func (b *PostgresBus) handleListenerEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventConnected:
+		b.logger.Info().Msg("Postgres bus listener connected")
+	case pq.ListenerEventDisconnected:
+		b.logger.Warn().Err(err).Msg("Postgres bus listener disconnected")
+	case pq.ListenerEventReconnected:
+		b.logger.Info().Msg("Postgres bus listener reconnected")
+	case pq.ListenerEventConnectionAttemptFailed:
+		b.logger.Error().Err(err).Msg("Postgres bus listener connection attempt failed")
+	}
+}
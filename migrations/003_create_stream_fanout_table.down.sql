@@ -0,0 +1,2 @@
+-- Rollback migration for stream_fanout table
+DROP TABLE IF EXISTS stream_fanout;
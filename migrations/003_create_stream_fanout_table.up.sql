@@ -0,0 +1,18 @@
+-- Create stream_fanout table for oversized fan-out payloads
+-- REQ-251: Multi-instance candle fan-out via Postgres LISTEN/NOTIFY
+
+CREATE TABLE IF NOT EXISTS stream_fanout (
+    id BIGSERIAL PRIMARY KEY,
+    channel VARCHAR(63) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create index for retention cleanup
+CREATE INDEX IF NOT EXISTS idx_stream_fanout_channel_created_at
+ON stream_fanout (channel, created_at);
+
+-- Add comment to table
+COMMENT ON TABLE stream_fanout IS 'Fan-out payloads too large for a NOTIFY message';
+COMMENT ON COLUMN stream_fanout.channel IS 'NOTIFY channel the payload was published on';
+COMMENT ON COLUMN stream_fanout.payload IS 'Serialized bus message referenced by the notification';
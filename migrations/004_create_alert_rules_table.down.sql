@@ -0,0 +1,2 @@
+-- Rollback migration for alert_rules table
+DROP TABLE IF EXISTS alert_rules;
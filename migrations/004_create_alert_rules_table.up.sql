@@ -0,0 +1,29 @@
+-- Create alert_rules table for server-side alerting
+-- REQ-253: Server-side alert rules evaluated against enriched candles
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    symbol VARCHAR(10) NOT NULL CHECK (symbol ~ '^[A-Z]+$'),
+    timeframe VARCHAR(10) NOT NULL CHECK (timeframe IN ('1min', '5min', '15min', '30min', '1hour', '1day')),
+    condition VARCHAR(50) NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL DEFAULT 0,
+    pattern VARCHAR(50) NOT NULL DEFAULT '',
+    webhook_url TEXT NOT NULL DEFAULT '',
+    cooldown_seconds INTEGER NOT NULL DEFAULT 300 CHECK (cooldown_seconds >= 0),
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    last_triggered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create index for loading the rules of a stream
+CREATE INDEX IF NOT EXISTS idx_alert_rules_symbol_timeframe
+ON alert_rules (symbol, timeframe) WHERE enabled = true;
+
+-- Add comment to table
+COMMENT ON TABLE alert_rules IS 'User-registered alert rules evaluated on every enriched candle';
+COMMENT ON COLUMN alert_rules.condition IS 'Rule condition (price_cross_above, rsi_above, pattern, resistance_breakout, ...)';
+COMMENT ON COLUMN alert_rules.threshold IS 'Price level or indicator value for threshold conditions';
+COMMENT ON COLUMN alert_rules.cooldown_seconds IS 'Minimum seconds between two firings of the rule';
+COMMENT ON COLUMN alert_rules.last_triggered_at IS 'When the rule last fired, used to enforce the cooldown across restarts';
@@ -0,0 +1,2 @@
+-- Rollback migration for enriched_candles table
+DROP TABLE IF EXISTS enriched_candles;
@@ -0,0 +1,47 @@
+-- Create enriched_candles table for post-hoc review of signals
+-- REQ-260: Persisted enriched candles with query API
+
+CREATE TABLE IF NOT EXISTS enriched_candles (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(10) NOT NULL,
+    timeframe VARCHAR(10) NOT NULL CHECK (timeframe IN ('1m', '5m', '15m', '1h', '4h', '1d')),
+    timestamp TIMESTAMPTZ NOT NULL,
+
+    -- Key columns for filtering
+    close DOUBLE PRECISION NOT NULL,
+    volume BIGINT NOT NULL DEFAULT 0,
+    overall_signal VARCHAR(10) NOT NULL DEFAULT '',
+    signal_strength DOUBLE PRECISION NOT NULL DEFAULT 0,
+    confidence DOUBLE PRECISION NOT NULL DEFAULT 0,
+    trend_direction VARCHAR(10) NOT NULL DEFAULT '',
+    market_regime VARCHAR(20) NOT NULL DEFAULT '',
+    rsi DOUBLE PRECISION,
+
+    -- Full enrichment output
+    ohlcv JSONB NOT NULL,
+    indicators JSONB,
+    analysis JSONB,
+    signals JSONB,
+    metadata JSONB,
+
+    -- Provenance
+    engine_version VARCHAR(20) NOT NULL,
+    config_hash VARCHAR(32) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One enriched candle per candle and producer; re-enrichment with the same
+-- engine version and configuration replaces it, other producers keep their rows
+CREATE UNIQUE INDEX IF NOT EXISTS idx_enriched_candles_symbol_timeframe_timestamp_producer
+ON enriched_candles (symbol, timeframe, timestamp, engine_version, config_hash);
+
+-- Create index for signal review queries
+CREATE INDEX IF NOT EXISTS idx_enriched_candles_signal_confidence
+ON enriched_candles (symbol, timeframe, overall_signal, confidence);
+
+-- Add comment to table
+COMMENT ON TABLE enriched_candles IS 'Enriched candles with indicators, analysis and signals as produced by the enrichment engine';
+COMMENT ON COLUMN enriched_candles.ohlcv IS 'Base OHLCV candle as JSON';
+COMMENT ON COLUMN enriched_candles.indicators IS 'Technical indicators as JSON, including registry values keyed by spec';
+COMMENT ON COLUMN enriched_candles.engine_version IS 'Enrichment engine version that produced the row';
+COMMENT ON COLUMN enriched_candles.config_hash IS 'Hash of engine configuration, options and custom signals that produced the row';
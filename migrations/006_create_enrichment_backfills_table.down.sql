@@ -0,0 +1,2 @@
+-- Rollback migration for enrichment_backfills table
+DROP TABLE IF EXISTS enrichment_backfills;
@@ -0,0 +1,21 @@
+-- Create enrichment_backfills table for resumable backfill jobs
+-- REQ-261: Batch historical enrichment backfill
+
+CREATE TABLE IF NOT EXISTS enrichment_backfills (
+    symbol VARCHAR(10) NOT NULL,
+    timeframe VARCHAR(10) NOT NULL,
+    range_start TIMESTAMPTZ NOT NULL,
+    range_end TIMESTAMPTZ NOT NULL,
+    engine_version VARCHAR(20) NOT NULL,
+    config_hash VARCHAR(32) NOT NULL,
+    last_timestamp TIMESTAMPTZ,
+    processed BIGINT NOT NULL DEFAULT 0,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (symbol, timeframe, range_start, range_end, engine_version, config_hash)
+);
+
+-- Add comment to table
+COMMENT ON TABLE enrichment_backfills IS 'Progress checkpoints of enrichment backfill jobs, one per symbol, timeframe, range and engine configuration';
+COMMENT ON COLUMN enrichment_backfills.last_timestamp IS 'Timestamp of the last enriched candle written by the job';
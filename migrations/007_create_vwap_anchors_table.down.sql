@@ -0,0 +1,2 @@
+-- Rollback migration for vwap_anchors table
+DROP TABLE IF EXISTS vwap_anchors;
@@ -0,0 +1,21 @@
+-- Create vwap_anchors table for anchored VWAPs
+-- REQ-265: User-anchored VWAPs maintained on the live stream
+
+CREATE TABLE IF NOT EXISTS vwap_anchors (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(10) NOT NULL CHECK (symbol ~ '^[A-Z]+$'),
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    event VARCHAR(20) NOT NULL CHECK (event IN ('manual', 'gap')),
+    anchor_at TIMESTAMPTZ NOT NULL,
+    gap_percent DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create index for loading the anchors of a symbol
+CREATE INDEX IF NOT EXISTS idx_vwap_anchors_symbol
+ON vwap_anchors (symbol, anchor_at);
+
+-- Add comment to table
+COMMENT ON TABLE vwap_anchors IS 'Starting points of anchored VWAPs, applied to every timeframe of the symbol';
+COMMENT ON COLUMN vwap_anchors.event IS 'manual for a user-chosen timestamp, gap for the open of the latest session that gapped';
+COMMENT ON COLUMN vwap_anchors.gap_percent IS 'Signed open gap versus the previous close for gap anchors';
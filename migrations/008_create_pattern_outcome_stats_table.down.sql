@@ -0,0 +1,2 @@
+-- Rollback migration for pattern_outcome_stats table
+DROP TABLE IF EXISTS pattern_outcome_stats;
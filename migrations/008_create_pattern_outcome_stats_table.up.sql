@@ -0,0 +1,31 @@
+-- Create pattern_outcome_stats table for pattern hit rates
+-- REQ-272: Pattern outcome statistics from stored history
+
+CREATE TABLE IF NOT EXISTS pattern_outcome_stats (
+    symbol VARCHAR(10) NOT NULL,
+    timeframe VARCHAR(10) NOT NULL,
+    pattern_type VARCHAR(20) NOT NULL CHECK (pattern_type IN ('candlestick', 'chart')),
+    pattern_name VARCHAR(50) NOT NULL,
+    signal VARCHAR(10) NOT NULL CHECK (signal IN ('bullish', 'bearish')),
+    horizon INTEGER NOT NULL CHECK (horizon > 0),
+    occurrences INTEGER NOT NULL,
+    target_hits INTEGER NOT NULL,
+    stop_hits INTEGER NOT NULL,
+    expired INTEGER NOT NULL,
+    hit_rate DOUBLE PRECISION NOT NULL,
+    avg_max_favorable DOUBLE PRECISION NOT NULL,
+    avg_max_adverse DOUBLE PRECISION NOT NULL,
+    avg_return DOUBLE PRECISION NOT NULL,
+    avg_bars DOUBLE PRECISION NOT NULL,
+    range_start TIMESTAMPTZ NOT NULL,
+    range_end TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (symbol, timeframe, pattern_type, pattern_name, signal)
+);
+
+-- Add comment to table
+COMMENT ON TABLE pattern_outcome_stats IS 'Outcomes of the patterns detected in stored candles, replaced per symbol and timeframe by each scan';
+COMMENT ON COLUMN pattern_outcome_stats.hit_rate IS 'Share of occurrences reaching the target before the stop within the horizon';
+COMMENT ON COLUMN pattern_outcome_stats.avg_max_favorable IS 'Mean maximum favorable excursion in percent of the entry';
+COMMENT ON COLUMN pattern_outcome_stats.avg_max_adverse IS 'Mean maximum adverse excursion in percent of the entry';
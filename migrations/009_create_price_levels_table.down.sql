@@ -0,0 +1,2 @@
+-- Rollback migration for price_levels table
+DROP TABLE IF EXISTS price_levels;
@@ -0,0 +1,25 @@
+-- Create price_levels table for tracked support and resistance levels
+-- REQ-274: Support and resistance lifecycle per symbol:timeframe
+
+CREATE TABLE IF NOT EXISTS price_levels (
+    symbol VARCHAR(10) NOT NULL,
+    timeframe VARCHAR(10) NOT NULL,
+    price DOUBLE PRECISION NOT NULL CHECK (price > 0),
+    role VARCHAR(10) NOT NULL CHECK (role IN ('support', 'resistance')),
+    sources JSONB NOT NULL,
+    strength DOUBLE PRECISION NOT NULL,
+    tests INTEGER NOT NULL DEFAULT 0,
+    breaks INTEGER NOT NULL DEFAULT 0,
+    touches JSONB NOT NULL,
+    first_seen TIMESTAMPTZ NOT NULL,
+    last_touch TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (symbol, timeframe, price)
+);
+
+-- Add comment to table
+COMMENT ON TABLE price_levels IS 'Support and resistance levels of the live streams, replaced per symbol and timeframe when they change';
+COMMENT ON COLUMN price_levels.role IS 'Current role; a confirmed break flips support into resistance and back';
+COMMENT ON COLUMN price_levels.sources IS 'detected, round or the higher timeframes the level was found on';
+COMMENT ON COLUMN price_levels.touches IS 'Latest tests and breaks with the role the level had and the close';
@@ -0,0 +1,2 @@
+-- Rollback migration for stream_symbols table
+DROP TABLE IF EXISTS stream_symbols;
@@ -0,0 +1,10 @@
+-- Create stream_symbols table for the symbols requested for streaming
+-- REQ-251: Multi-instance candle fan-out with leader-elected ingestion
+
+CREATE TABLE IF NOT EXISTS stream_symbols (
+    symbol VARCHAR(10) PRIMARY KEY,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Add comment to table
+COMMENT ON TABLE stream_symbols IS 'Symbols requested for streaming on any instance, reloaded by the instance elected to ingest';
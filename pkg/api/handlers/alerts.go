@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/alerts"
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-253: REST management of server-side alert rules
+
+type AlertHandler struct {
+	repo   *database.AlertRepository
+	engine *alerts.Engine
+	logger zerolog.Logger
+}
+
+// NewAlertHandler creates a new alert rule API handler. The engine may be nil
+// when this instance does not evaluate alerts; rules are then picked up by
+// the evaluating instance on its next reload.
+func NewAlertHandler(repo *database.AlertRepository, engine *alerts.Engine) *AlertHandler {
+	return &AlertHandler{
+		repo:   repo,
+		engine: engine,
+		logger: logger.NewContextLogger("alert_handler"),
+	}
+}
+
+// CreateRule handles POST /api/v1/alerts
+func (h *AlertHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	var request types.AlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		reqLogger.Error().Err(err).Msg("Invalid alert rule body")
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rule := &models.AlertRule{
+		Name:            request.Name,
+		Symbol:          strings.ToUpper(request.Symbol),
+		Timeframe:       request.Timeframe,
+		Condition:       request.Condition,
+		Threshold:       request.Threshold,
+		Pattern:         request.Pattern,
+		WebhookURL:      request.WebhookURL,
+		CooldownSeconds: models.DefaultAlertCooldown,
+		Enabled:         true,
+	}
+	if request.CooldownSeconds != nil {
+		rule.CooldownSeconds = *request.CooldownSeconds
+	}
+	if request.Enabled != nil {
+		rule.Enabled = *request.Enabled
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	// REQ-041: Input validation
+	if err := alerts.ValidateRule(ctx, rule); err != nil {
+		reqLogger.Error().Err(err).Msg("Invalid alert rule")
+		http.Error(w, "Invalid alert rule: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.Create(ctx, rule); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to create alert rule")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if h.engine != nil {
+		h.engine.AddRule(rule)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(rule); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		return
+	}
+
+	reqLogger.Info().
+		Int64("rule_id", rule.ID).
+		Str("symbol", rule.Symbol).
+		Str("condition", rule.Condition).
+		Msg("Alert rule created")
+}
+
+// ListRules handles GET /api/v1/alerts
+func (h *AlertHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	symbol := r.URL.Query().Get("symbol")
+	if symbol != "" {
+		if err := validateSymbol(symbol); err != nil {
+			reqLogger.Error().Err(err).Str("symbol", symbol).Msg("Invalid symbol")
+			http.Error(w, "Invalid symbol: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	rules, err := h.repo.List(ctx, symbol)
+	if err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to list alert rules")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if rules == nil {
+		rules = []*models.AlertRule{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if err := json.NewEncoder(w).Encode(&types.AlertRuleListResponse{Count: len(rules), Rules: rules}); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// GetRule handles GET /api/v1/alerts/{id}
+func (h *AlertHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	id, err := parseRuleID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	rule, err := h.repo.Get(ctx, id)
+	if errors.Is(err, database.ErrAlertRuleNotFound) {
+		http.Error(w, "Alert rule not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		reqLogger.Error().Err(err).Int64("rule_id", id).Msg("Failed to get alert rule")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if err := json.NewEncoder(w).Encode(rule); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// DeleteRule handles DELETE /api/v1/alerts/{id}
+func (h *AlertHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	id, err := parseRuleID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	err = h.repo.Delete(ctx, id)
+	if errors.Is(err, database.ErrAlertRuleNotFound) {
+		http.Error(w, "Alert rule not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		reqLogger.Error().Err(err).Int64("rule_id", id).Msg("Failed to delete alert rule")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if h.engine != nil {
+		h.engine.RemoveRule(id)
+	}
+
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusNoContent)
+
+	reqLogger.Info().Int64("rule_id", id).Msg("Alert rule deleted")
+}
+
+// parseRuleID reads the {id} path parameter
+func parseRuleID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id < 1 {
+		return 0, errors.New("invalid alert rule id")
+	}
+	return id, nil
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/correlation"
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-263: Correlation matrix across symbols
+
+// maxCorrelationSymbols bounds the size of a correlation matrix
+const maxCorrelationSymbols = 50
+
+type CorrelationHandler struct {
+	tracker *correlation.Tracker
+	repo    *database.OHLCVRepository
+	symbols func() []string
+	logger  zerolog.Logger
+}
+
+// NewCorrelationHandler creates a new correlation API handler. Series the live
+// tracker does not cover are read from stored candles; symbols returns the
+// tracked symbols used when a request names none.
+func NewCorrelationHandler(tracker *correlation.Tracker, repo *database.OHLCVRepository, symbols func() []string) *CorrelationHandler {
+	return &CorrelationHandler{
+		tracker: tracker,
+		repo:    repo,
+		symbols: symbols,
+		logger:  logger.NewContextLogger("correlation_handler"),
+	}
+}
+
+// GetCorrelation handles GET /api/v1/correlation
+func (h *CorrelationHandler) GetCorrelation(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	// REQ-041: Input validation
+	query := r.URL.Query()
+	timeframe := query.Get("timeframe")
+	if timeframe == "" {
+		timeframe = "1m"
+	}
+
+	if err := validateTimeframe(timeframe); err != nil {
+		reqLogger.Error().Err(err).Str("timeframe", timeframe).Msg("Invalid timeframe")
+		http.Error(w, "Invalid timeframe: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var symbols []string
+	if list := query.Get("symbols"); list != "" {
+		symbols = strings.Split(list, ",")
+	} else {
+		symbols = h.symbols()
+	}
+	seen := make(map[string]bool)
+	unique := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if err := validateSymbol(symbol); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid symbol %q: %v", symbol, err), http.StatusBadRequest)
+			return
+		}
+		if !seen[symbol] {
+			seen[symbol] = true
+			unique = append(unique, symbol)
+		}
+	}
+	symbols = unique
+	if len(symbols) < 2 {
+		http.Error(w, "At least two symbols are required: e.g. symbols=AAPL,MSFT", http.StatusBadRequest)
+		return
+	}
+	if len(symbols) > maxCorrelationSymbols {
+		http.Error(w, fmt.Sprintf("Too many symbols: maximum %d", maxCorrelationSymbols), http.StatusBadRequest)
+		return
+	}
+
+	window := 50 // default returns per pair
+	if windowStr := query.Get("window"); windowStr != "" {
+		var err error
+		window, err = strconv.Atoi(windowStr)
+		if err != nil || window < 5 || window > 1000 {
+			http.Error(w, "Invalid window: must be between 5 and 1000", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	series := make(map[string][]*models.OHLCV, len(symbols))
+	stored := 0
+	for _, symbol := range symbols {
+		candles := h.tracker.Candles(symbol, timeframe, window+1)
+		if len(candles) <= window {
+			// Not streamed on this instance or not long enough yet
+			history, err := h.repo.GetBySymbol(ctx, symbol, timeframe, window+1)
+			if err != nil {
+				reqLogger.Error().Err(err).Str("symbol", symbol).Msg("Failed to fetch OHLCV data")
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			if len(history) > len(candles) {
+				// Stored candles come newest first
+				for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+					history[i], history[j] = history[j], history[i]
+				}
+				candles = history
+				stored++
+			}
+		}
+		series[symbol] = candles
+	}
+
+	matrix := correlation.Compute(symbols, series, window/2)
+	response := &types.CorrelationResponse{
+		Timeframe:         timeframe,
+		Window:            window,
+		StoredSymbols:     stored,
+		CorrelationMatrix: matrix,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	reqLogger.Info().
+		Strs("symbols", symbols).
+		Str("timeframe", timeframe).
+		Int("window", window).
+		Int("stored", stored).
+		Msg("Correlation request completed successfully")
+}
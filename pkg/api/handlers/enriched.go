@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-260: Stored enriched candles over a range with signal filters
+
+type EnrichedHandler struct {
+	repo   *database.EnrichedRepository
+	logger zerolog.Logger
+}
+
+// NewEnrichedHandler creates a new enriched candle history API handler
+func NewEnrichedHandler(repo *database.EnrichedRepository) *EnrichedHandler {
+	return &EnrichedHandler{
+		repo:   repo,
+		logger: logger.NewContextLogger("enriched_handler"),
+	}
+}
+
+// GetEnriched handles GET /api/v1/enriched/{symbol}
+func (h *EnrichedHandler) GetEnriched(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	reqLogger.Info().Msg("Processing enriched candle request")
+
+	// REQ-041: Input validation
+	vars := mux.Vars(r)
+	symbol := vars["symbol"]
+	if err := validateSymbol(symbol); err != nil {
+		reqLogger.Error().Err(err).Str("symbol", symbol).Msg("Invalid symbol")
+		http.Error(w, "Invalid symbol: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	timeframe := query.Get("timeframe")
+	if timeframe == "" {
+		timeframe = "1m" // enriched candles are persisted from the live stream
+	}
+
+	if err := validateTimeframe(timeframe); err != nil {
+		reqLogger.Error().Err(err).Str("timeframe", timeframe).Msg("Invalid timeframe")
+		http.Error(w, "Invalid timeframe: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	start, err := parseTimeParam(query.Get("start"), time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		http.Error(w, "Invalid start: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	end, err := parseTimeParam(query.Get("end"), time.Now())
+	if err != nil {
+		http.Error(w, "Invalid end: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if start.After(end) {
+		reqLogger.Error().Time("start", start).Time("end", end).Msg("Invalid date range")
+		http.Error(w, "Start date must be before end date", http.StatusBadRequest)
+		return
+	}
+
+	limitStr := query.Get("limit")
+	limit := 1000 // default for enriched history
+	if limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 10000 {
+			reqLogger.Error().Str("limit", limitStr).Msg("Invalid limit parameter")
+			http.Error(w, "Invalid limit: must be between 1 and 10000", http.StatusBadRequest)
+			return
+		}
+	}
+
+	filter := &database.EnrichedCandleQuery{
+		Symbol:        symbol,
+		Timeframe:     timeframe,
+		Start:         start,
+		End:           end,
+		Signal:        strings.ToLower(query.Get("signal")),
+		Trend:         strings.ToLower(query.Get("trend")),
+		Regime:        strings.ToLower(query.Get("regime")),
+		EngineVersion: query.Get("engine_version"),
+		ConfigHash:    query.Get("config_hash"),
+		Limit:         limit,
+	}
+
+	if filter.Signal != "" {
+		if err := validateSignal(filter.Signal); err != nil {
+			http.Error(w, "Invalid signal: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	filter.MinConfidence, err = parsePercentParam(query.Get("min_confidence"))
+	if err != nil {
+		http.Error(w, "Invalid min_confidence: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter.MinStrength, err = parsePercentParam(query.Get("min_strength"))
+	if err != nil {
+		http.Error(w, "Invalid min_strength: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	records, err := h.repo.Query(ctx, filter)
+	if err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to fetch enriched candles")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if records == nil {
+		records = []*models.EnrichedCandleRecord{}
+	}
+
+	response := &types.EnrichedCandlesResponse{
+		Symbol:    symbol,
+		Timeframe: timeframe,
+		Start:     start,
+		End:       end,
+		Filters:   enrichedFilters(query),
+		Count:     len(records),
+		Data:      records,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	reqLogger.Info().
+		Str("symbol", symbol).
+		Str("timeframe", timeframe).
+		Int("count", len(records)).
+		Msg("Enriched candle request completed successfully")
+}
+
+// validateSignal checks an overall signal filter
+func validateSignal(signal string) error {
+	switch signal {
+	case "bullish", "bearish", "neutral":
+		return nil
+	default:
+		return fmt.Errorf("must be bullish, bearish or neutral")
+	}
+}
+
+// parsePercentParam parses an optional 0-100 threshold
+func parsePercentParam(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 || parsed > 100 {
+		return 0, fmt.Errorf("must be a number between 0 and 100")
+	}
+	return parsed, nil
+}
+
+// enrichedFilters echoes the filters applied to the response
+func enrichedFilters(query url.Values) map[string]string {
+	filters := make(map[string]string)
+	for _, key := range []string{"signal", "trend", "regime", "min_confidence", "min_strength", "engine_version", "config_hash"} {
+		if value := query.Get(key); value != "" {
+			filters[key] = value
+		}
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+	return filters
+}
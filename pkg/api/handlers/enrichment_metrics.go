@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/enrichment"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+)
+
+// REQ-269: Enrichment latency metrics
+
+// prometheusContentType is the Prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type EnrichmentMetricsHandler struct {
+	engine *enrichment.CandleEnrichmentEngine
+	logger zerolog.Logger
+}
+
+// NewEnrichmentMetricsHandler creates a new enrichment metrics API handler
+func NewEnrichmentMetricsHandler(engine *enrichment.CandleEnrichmentEngine) *EnrichmentMetricsHandler {
+	return &EnrichmentMetricsHandler{
+		engine: engine,
+		logger: logger.NewContextLogger("enrichment_metrics_handler"),
+	}
+}
+
+// GetMetrics handles GET /api/v1/enrichment/metrics. The window parameter
+// selects the rolling window of the latency summaries, format=prometheus
+// returns the cumulative histograms for scraping instead.
+func (h *EnrichmentMetricsHandler) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	query := r.URL.Query()
+
+	switch query.Get("format") {
+	case "", "json":
+	case "prometheus":
+		h.WritePrometheus(w, r)
+		return
+	default:
+		http.Error(w, "Invalid format: must be json or prometheus", http.StatusBadRequest)
+		return
+	}
+
+	window := enrichment.DefaultMetricsWindow
+	if param := query.Get("window"); param != "" {
+		parsed, err := time.ParseDuration(param)
+		if err != nil || parsed < time.Minute || parsed > enrichment.MaxMetricsWindow {
+			http.Error(w, "Invalid window: must be a duration between 1m and "+
+				enrichment.MaxMetricsWindow.String(), http.StatusBadRequest)
+			return
+		}
+		window = parsed
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if err := json.NewEncoder(w).Encode(h.engine.Metrics(window)); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// WritePrometheus handles GET /metrics
+func (h *EnrichmentMetricsHandler) WritePrometheus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", prometheusContentType)
+
+	if err := h.engine.WritePrometheus(w); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to write Prometheus metrics")
+	}
+}
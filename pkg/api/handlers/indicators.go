@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/indicators"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-257: Indicator time series over a range with automatic warm-up
+
+// maxSeriesSpecs bounds the number of specs of a single request
+const maxSeriesSpecs = 20
+
+// maxWarmupCandles bounds the candles read to warm up anchored indicators
+const maxWarmupCandles = 100000
+
+type IndicatorHandler struct {
+	repo     *database.OHLCVRepository
+	registry *indicators.Registry
+	logger   zerolog.Logger
+}
+
+// NewIndicatorHandler creates a new indicator series API handler
+func NewIndicatorHandler(repo *database.OHLCVRepository) *IndicatorHandler {
+	return &IndicatorHandler{
+		repo:     repo,
+		registry: indicators.DefaultRegistry(),
+		logger:   logger.NewContextLogger("indicator_handler"),
+	}
+}
+
+// GetSeries handles GET /api/v1/indicators/{symbol}
+func (h *IndicatorHandler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	reqLogger.Info().Msg("Processing indicator series request")
+
+	// REQ-041: Input validation
+	vars := mux.Vars(r)
+	symbol := vars["symbol"]
+	if err := validateSymbol(symbol); err != nil {
+		reqLogger.Error().Err(err).Str("symbol", symbol).Msg("Invalid symbol")
+		http.Error(w, "Invalid symbol: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	timeframe := query.Get("timeframe")
+	if timeframe == "" {
+		timeframe = "1d"
+	}
+
+	if err := validateTimeframe(timeframe); err != nil {
+		reqLogger.Error().Err(err).Str("timeframe", timeframe).Msg("Invalid timeframe")
+		http.Error(w, "Invalid timeframe: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	specs := indicators.SplitSpecs(query.Get("series"))
+	if len(specs) == 0 {
+		http.Error(w, "Missing series: e.g. series=rsi(14),macd(12,26,9)", http.StatusBadRequest)
+		return
+	}
+	if len(specs) > maxSeriesSpecs {
+		http.Error(w, fmt.Sprintf("Too many series: maximum %d", maxSeriesSpecs), http.StatusBadRequest)
+		return
+	}
+
+	lookback, err := h.registry.Lookback(specs)
+	if err != nil {
+		reqLogger.Error().Err(err).Strs("series", specs).Msg("Invalid series")
+		http.Error(w, "Invalid series: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	start, err := parseTimeParam(query.Get("start"), time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		http.Error(w, "Invalid start: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	end, err := parseTimeParam(query.Get("end"), time.Now())
+	if err != nil {
+		http.Error(w, "Invalid end: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if start.After(end) {
+		reqLogger.Error().Time("start", start).Time("end", end).Msg("Invalid date range")
+		http.Error(w, "Start date must be before end date", http.StatusBadRequest)
+		return
+	}
+
+	limitStr := query.Get("limit")
+	limit := 1000 // default for series
+	if limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 10000 {
+			reqLogger.Error().Str("limit", limitStr).Msg("Invalid limit parameter")
+			http.Error(w, "Invalid limit: must be between 1 and 10000", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	candles, err := h.repo.GetHistory(ctx, symbol, timeframe, start, end, limit)
+	if err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to fetch OHLCV history")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Warm up on the candles right before the range so the first values match
+	// what the live stream reports
+	var warmup []*models.OHLCV
+	if lookback > 0 && len(candles) > 0 {
+		warmup, err = h.repo.GetBefore(ctx, symbol, timeframe, candles[0].Timestamp, lookback)
+		if err != nil {
+			reqLogger.Error().Err(err).Msg("Failed to fetch warm-up candles")
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// REQ-265: Session and anchored VWAPs need every candle since their start
+	if len(candles) > 0 {
+		since, err := h.registry.WarmupSince(specs, candles[0].Timestamp)
+		if err != nil {
+			http.Error(w, "Invalid series: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if !since.IsZero() && since.Before(candles[0].Timestamp) &&
+			(len(warmup) == 0 || since.Before(warmup[0].Timestamp)) {
+			// Candles since then include the lookback warm-up
+			warmup, err = h.repo.GetHistory(ctx, symbol, timeframe, since,
+				candles[0].Timestamp.Add(-time.Microsecond), maxWarmupCandles)
+			if err != nil {
+				reqLogger.Error().Err(err).Msg("Failed to fetch warm-up candles")
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			if len(warmup) == maxWarmupCandles {
+				http.Error(w, fmt.Sprintf("Anchor too far before the range: more than %d warm-up candles", maxWarmupCandles), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	series, err := indicators.ComputeSeries(h.registry, specs, append(warmup, candles...), len(warmup))
+	if err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to compute indicator series")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	timestamps := make([]time.Time, len(candles))
+	for i, candle := range candles {
+		timestamps[i] = candle.Timestamp
+	}
+
+	response := &types.IndicatorSeriesResponse{
+		Symbol:        symbol,
+		Timeframe:     timeframe,
+		Start:         start,
+		End:           end,
+		Lookback:      lookback,
+		WarmupCandles: len(warmup),
+		Count:         len(candles),
+		Timestamps:    timestamps,
+		Series:        series,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	reqLogger.Info().
+		Str("symbol", symbol).
+		Str("timeframe", timeframe).
+		Strs("series", specs).
+		Int("count", len(candles)).
+		Int("warmup", len(warmup)).
+		Msg("Indicator series request completed successfully")
+}
+
+// parseTimeParam accepts RFC3339 timestamps or YYYY-MM-DD dates
+func parseTimeParam(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("use RFC3339 or YYYY-MM-DD")
+	}
+	return parsed, nil
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-274: Tracked support and resistance levels
+
+type LevelHandler struct {
+	repo   *database.LevelRepository
+	logger zerolog.Logger
+}
+
+// NewLevelHandler creates a new level API handler
+func NewLevelHandler(repo *database.LevelRepository) *LevelHandler {
+	return &LevelHandler{
+		repo:   repo,
+		logger: logger.NewContextLogger("level_handler"),
+	}
+}
+
+// GetLevels handles GET /api/v1/levels/{symbol}, optionally of one timeframe
+func (h *LevelHandler) GetLevels(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	// REQ-041: Input validation
+	symbol := strings.ToUpper(mux.Vars(r)["symbol"])
+	if err := validateSymbol(symbol); err != nil {
+		http.Error(w, "Invalid symbol: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timeframe := r.URL.Query().Get("timeframe")
+	if timeframe != "" {
+		if err := validateTimeframe(timeframe); err != nil {
+			http.Error(w, "Invalid timeframe: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	levels, err := h.repo.List(ctx, symbol, timeframe)
+	if err != nil {
+		reqLogger.Error().Err(err).Str("symbol", symbol).Msg("Failed to fetch levels")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if levels == nil {
+		levels = []*models.TrackedLevel{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	response := &types.LevelsResponse{Symbol: symbol, Timeframe: timeframe, Count: len(levels), Levels: levels}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-272: Pattern outcome statistics from stored history
+
+type PatternStatsHandler struct {
+	repo   *database.PatternStatsRepository
+	logger zerolog.Logger
+}
+
+// NewPatternStatsHandler creates a new pattern statistics API handler
+func NewPatternStatsHandler(repo *database.PatternStatsRepository) *PatternStatsHandler {
+	return &PatternStatsHandler{
+		repo:   repo,
+		logger: logger.NewContextLogger("pattern_stats_handler"),
+	}
+}
+
+// GetStats handles GET /api/v1/patterns/stats, optionally filtered by
+// symbol, timeframe and pattern name
+func (h *PatternStatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	// REQ-041: Input validation
+	query := r.URL.Query()
+	symbol := strings.ToUpper(query.Get("symbol"))
+	if symbol != "" {
+		if err := validateSymbol(symbol); err != nil {
+			http.Error(w, "Invalid symbol: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	timeframe := query.Get("timeframe")
+	if timeframe != "" {
+		if err := validateTimeframe(timeframe); err != nil {
+			http.Error(w, "Invalid timeframe: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	pattern := query.Get("pattern")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	stats, err := h.repo.List(ctx, symbol, timeframe, pattern)
+	if err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to fetch pattern stats")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if stats == nil {
+		stats = []*models.PatternOutcomeStats{}
+	}
+
+	filters := make(map[string]string)
+	for key, value := range map[string]string{"symbol": symbol, "timeframe": timeframe, "pattern": pattern} {
+		if value != "" {
+			filters[key] = value
+		}
+	}
+	if len(filters) == 0 {
+		filters = nil
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if err := json.NewEncoder(w).Encode(&types.PatternStatsResponse{Filters: filters, Count: len(stats), Stats: stats}); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
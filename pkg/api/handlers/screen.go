@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/expression"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/internal/screener"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+)
+
+// REQ-259: Screening symbols with rule expressions
+
+// maxScreenSymbols bounds the number of symbols of a single screen
+const maxScreenSymbols = 500
+
+type ScreenHandler struct {
+	screener *screener.Screener
+	symbols  func() []string
+	logger   zerolog.Logger
+}
+
+// NewScreenHandler creates a new screen API handler. symbols returns the
+// tracked symbols screened when a request names none.
+func NewScreenHandler(screener *screener.Screener, symbols func() []string) *ScreenHandler {
+	return &ScreenHandler{
+		screener: screener,
+		symbols:  symbols,
+		logger:   logger.NewContextLogger("screen_handler"),
+	}
+}
+
+// Screen handles POST /api/v1/screen
+func (h *ScreenHandler) Screen(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	var request types.ScreenRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		reqLogger.Error().Err(err).Msg("Invalid screen body")
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// REQ-041: Input validation
+	program, err := expression.Compile(request.Expression)
+	if err != nil {
+		reqLogger.Error().Err(err).Str("expression", request.Expression).Msg("Invalid expression")
+		http.Error(w, "Invalid expression: "+describeExpressionError(err), http.StatusBadRequest)
+		return
+	}
+
+	timeframe := request.Timeframe
+	if timeframe == "" {
+		timeframe = "1d"
+	}
+	if err := validateTimeframe(timeframe); err != nil {
+		reqLogger.Error().Err(err).Str("timeframe", timeframe).Msg("Invalid timeframe")
+		http.Error(w, "Invalid timeframe: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// REQ-266: Signal profile of the screened candles
+	if !h.screener.HasSignalProfile(request.Profile) {
+		http.Error(w, "Invalid profile: unknown signal profile "+request.Profile, http.StatusBadRequest)
+		return
+	}
+
+	symbols := request.Symbols
+	if len(symbols) == 0 {
+		symbols = h.symbols()
+	}
+	for i, symbol := range symbols {
+		symbols[i] = strings.ToUpper(strings.TrimSpace(symbol))
+		if err := validateSymbol(symbols[i]); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid symbol %q: %v", symbol, err), http.StatusBadRequest)
+			return
+		}
+	}
+	if len(symbols) > maxScreenSymbols {
+		http.Error(w, fmt.Sprintf("Too many symbols: maximum %d", maxScreenSymbols), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	response := &types.ScreenResponse{
+		Expression: program.Source(),
+		Timeframe:  timeframe,
+		Profile:    request.Profile,
+		Indicators: program.Specs(),
+		Scanned:    len(symbols),
+		Matches:    []types.ScreenMatch{},
+	}
+
+	for _, result := range h.screener.Screen(ctx, program, symbols, timeframe, request.Profile) {
+		switch {
+		case result.Err != nil:
+			response.Skipped = append(response.Skipped, types.ScreenSkip{
+				Symbol: result.Symbol,
+				Reason: result.Err.Error(),
+			})
+		case result.Matched:
+			response.Matches = append(response.Matches, types.ScreenMatch{
+				Symbol:    result.Symbol,
+				Timestamp: result.Timestamp,
+				Close:     result.Close,
+				Signal:    result.Signal,
+				Values:    result.Values,
+			})
+		}
+	}
+	response.Count = len(response.Matches)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	reqLogger.Info().
+		Str("expression", program.Source()).
+		Str("timeframe", timeframe).
+		Int("scanned", response.Scanned).
+		Int("matches", response.Count).
+		Int("skipped", len(response.Skipped)).
+		Msg("Screen request completed successfully")
+}
+
+// describeExpressionError adds the source line with a caret to expression errors
+func describeExpressionError(err error) string {
+	var exprErr *expression.Error
+	if errors.As(err, &exprErr) {
+		return exprErr.Error() + "\n" + exprErr.Context()
+	}
+	return err.Error()
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-266: Signal profiles selectable per subscription and request
+
+type SignalProfileHandler struct {
+	profiles func() []models.SignalProfile
+	logger   zerolog.Logger
+}
+
+// NewSignalProfileHandler creates a new signal profile API handler. profiles
+// returns the profiles available to the enrichment engine.
+func NewSignalProfileHandler(profiles func() []models.SignalProfile) *SignalProfileHandler {
+	return &SignalProfileHandler{
+		profiles: profiles,
+		logger:   logger.NewContextLogger("signal_profile_handler"),
+	}
+}
+
+// ListProfiles handles GET /api/v1/signal-profiles
+func (h *SignalProfileHandler) ListProfiles(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	profiles := h.profiles()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if err := json.NewEncoder(w).Encode(&types.SignalProfilesResponse{Count: len(profiles), Profiles: profiles}); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/analysis"
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-264: Volume profiles per session or over a range of stored bars
+
+// maxVolumeProfileBars bounds the bars read for one request
+const maxVolumeProfileBars = 50000
+
+type VolumeProfileHandler struct {
+	repo     *database.OHLCVRepository
+	analyzer *analysis.VolumeProfileAnalyzer
+	logger   zerolog.Logger
+}
+
+// NewVolumeProfileHandler creates a new volume profile API handler
+func NewVolumeProfileHandler(repo *database.OHLCVRepository) *VolumeProfileHandler {
+	return &VolumeProfileHandler{
+		repo:     repo,
+		analyzer: analysis.NewVolumeProfileAnalyzer(),
+		logger:   logger.NewContextLogger("volume_profile_handler"),
+	}
+}
+
+// GetVolumeProfile handles GET /api/v1/volume-profile/{symbol}
+func (h *VolumeProfileHandler) GetVolumeProfile(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	// REQ-041: Input validation
+	vars := mux.Vars(r)
+	symbol := vars["symbol"]
+	if err := validateSymbol(symbol); err != nil {
+		reqLogger.Error().Err(err).Str("symbol", symbol).Msg("Invalid symbol")
+		http.Error(w, "Invalid symbol: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	timeframe := query.Get("timeframe")
+	if timeframe == "" {
+		timeframe = "1m"
+	}
+
+	if err := validateTimeframe(timeframe); err != nil {
+		reqLogger.Error().Err(err).Str("timeframe", timeframe).Msg("Invalid timeframe")
+		http.Error(w, "Invalid timeframe: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	start, err := parseTimeParam(query.Get("start"), time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		http.Error(w, "Invalid start: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	end, err := parseTimeParam(query.Get("end"), time.Now())
+	if err != nil {
+		http.Error(w, "Invalid end: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if start.After(end) {
+		reqLogger.Error().Time("start", start).Time("end", end).Msg("Invalid date range")
+		http.Error(w, "Start date must be before end date", http.StatusBadRequest)
+		return
+	}
+
+	bins := 50 // default price bins per profile
+	if binsStr := query.Get("bins"); binsStr != "" {
+		bins, err = strconv.Atoi(binsStr)
+		if err != nil || bins < 5 || bins > 500 {
+			http.Error(w, "Invalid bins: must be between 5 and 500", http.StatusBadRequest)
+			return
+		}
+	}
+
+	group := query.Get("group")
+	if group == "" {
+		group = "session"
+	}
+	if group != "session" && group != "range" {
+		http.Error(w, "Invalid group: must be session or range", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	candles, err := h.repo.GetHistory(ctx, symbol, timeframe, start, end, maxVolumeProfileBars+1)
+	if err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to fetch OHLCV history")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	truncated := len(candles) > maxVolumeProfileBars
+	if truncated {
+		candles = candles[:maxVolumeProfileBars]
+	}
+
+	var profiles []*models.PriceVolumeProfile
+	if group == "session" {
+		profiles = h.analyzer.Sessions(candles, bins)
+	} else if profile := h.analyzer.BuildWithBins(candles, bins); profile != nil {
+		profiles = append(profiles, profile)
+	}
+	if profiles == nil {
+		profiles = []*models.PriceVolumeProfile{}
+	}
+
+	response := &types.VolumeProfileResponse{
+		Symbol:    symbol,
+		Timeframe: timeframe,
+		Start:     start,
+		End:       end,
+		Group:     group,
+		Bars:      len(candles),
+		Truncated: truncated,
+		Profiles:  profiles,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	reqLogger.Info().
+		Str("symbol", symbol).
+		Str("timeframe", timeframe).
+		Str("group", group).
+		Int("bars", len(candles)).
+		Int("profiles", len(profiles)).
+		Msg("Volume profile request completed successfully")
+}
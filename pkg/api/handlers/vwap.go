@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+
+	"github.com/ridopark/jonbu-ohlcv/internal/database"
+	"github.com/ridopark/jonbu-ohlcv/internal/indicators"
+	"github.com/ridopark/jonbu-ohlcv/internal/logger"
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-265: REST management of VWAP anchors
+
+// gapLookbackDays is the number of daily candles searched for a gap anchor
+const gapLookbackDays = 260
+
+type VWAPAnchorHandler struct {
+	repo     *database.VWAPAnchorRepository
+	candles  *database.OHLCVRepository
+	onChange func()
+	logger   zerolog.Logger
+}
+
+// NewVWAPAnchorHandler creates a new VWAP anchor API handler. onChange, when
+// set, is called after anchors were created or deleted so the live stream can
+// pick them up right away.
+func NewVWAPAnchorHandler(repo *database.VWAPAnchorRepository, candles *database.OHLCVRepository, onChange func()) *VWAPAnchorHandler {
+	return &VWAPAnchorHandler{
+		repo:     repo,
+		candles:  candles,
+		onChange: onChange,
+		logger:   logger.NewContextLogger("vwap_anchor_handler"),
+	}
+}
+
+// CreateAnchor handles POST /api/v1/vwap/anchors
+func (h *VWAPAnchorHandler) CreateAnchor(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	var request types.VWAPAnchorRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		reqLogger.Error().Err(err).Msg("Invalid VWAP anchor body")
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	anchor := &models.VWAPAnchor{
+		Symbol: strings.ToUpper(request.Symbol),
+		Name:   request.Name,
+		Event:  strings.ToLower(request.Event),
+	}
+	if anchor.Event == "" {
+		anchor.Event = models.AnchorManual
+	}
+
+	// REQ-041: Input validation
+	if err := validateSymbol(anchor.Symbol); err != nil {
+		http.Error(w, "Invalid symbol: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	switch anchor.Event {
+	case models.AnchorManual:
+		if request.AnchorAt == nil {
+			http.Error(w, "Invalid VWAP anchor: anchor_at is required", http.StatusBadRequest)
+			return
+		}
+		anchor.AnchorAt = *request.AnchorAt
+
+	case models.AnchorGap:
+		minGap := request.MinGapPercent
+		if minGap == 0 {
+			minGap = models.DefaultAnchorGapPercent
+		}
+		if minGap < 0 || minGap > 100 {
+			http.Error(w, "Invalid min_gap_percent: must be between 0 and 100", http.StatusBadRequest)
+			return
+		}
+
+		daily, err := h.candles.GetBySymbol(ctx, anchor.Symbol, "1d", gapLookbackDays)
+		if err != nil {
+			reqLogger.Error().Err(err).Msg("Failed to fetch daily candles")
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		// Stored candles come newest first
+		for i, j := 0, len(daily)-1; i < j; i, j = i+1, j-1 {
+			daily[i], daily[j] = daily[j], daily[i]
+		}
+
+		anchorAt, gap, found := indicators.LatestGap(daily, minGap)
+		if !found {
+			http.Error(w, fmt.Sprintf("No gap of at least %v%% in the last %d daily candles of %s",
+				minGap, gapLookbackDays, anchor.Symbol), http.StatusUnprocessableEntity)
+			return
+		}
+		anchor.AnchorAt = anchorAt
+		anchor.GapPercent = gap
+	}
+
+	// Anchors are addressed by Unix seconds on the indicator endpoint
+	anchor.AnchorAt = anchor.AnchorAt.Truncate(time.Second)
+
+	if err := anchor.Validate(); err != nil {
+		reqLogger.Error().Err(err).Msg("Invalid VWAP anchor")
+		http.Error(w, "Invalid VWAP anchor: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.Create(ctx, anchor); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to create VWAP anchor")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if h.onChange != nil {
+		h.onChange()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(anchor); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		return
+	}
+
+	reqLogger.Info().
+		Int64("anchor_id", anchor.ID).
+		Str("symbol", anchor.Symbol).
+		Str("event", anchor.Event).
+		Time("anchor_at", anchor.AnchorAt).
+		Msg("VWAP anchor created")
+}
+
+// ListAnchors handles GET /api/v1/vwap/anchors
+func (h *VWAPAnchorHandler) ListAnchors(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	symbol := r.URL.Query().Get("symbol")
+	if symbol != "" {
+		if err := validateSymbol(symbol); err != nil {
+			reqLogger.Error().Err(err).Str("symbol", symbol).Msg("Invalid symbol")
+			http.Error(w, "Invalid symbol: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	anchors, err := h.repo.List(ctx, symbol)
+	if err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to list VWAP anchors")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if anchors == nil {
+		anchors = []*models.VWAPAnchor{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if err := json.NewEncoder(w).Encode(&types.VWAPAnchorListResponse{Count: len(anchors), Anchors: anchors}); err != nil {
+		reqLogger.Error().Err(err).Msg("Failed to encode response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// DeleteAnchor handles DELETE /api/v1/vwap/anchors/{id}
+func (h *VWAPAnchorHandler) DeleteAnchor(w http.ResponseWriter, r *http.Request) {
+	correlationID := uuid.New().String()
+	reqLogger := logger.NewRequestLogger(correlationID, r.Method, r.URL.Path)
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id < 1 {
+		http.Error(w, "invalid vwap anchor id", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	err = h.repo.Delete(ctx, id)
+	if errors.Is(err, database.ErrVWAPAnchorNotFound) {
+		http.Error(w, "VWAP anchor not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		reqLogger.Error().Err(err).Int64("anchor_id", id).Msg("Failed to delete VWAP anchor")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if h.onChange != nil {
+		h.onChange()
+	}
+
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusNoContent)
+
+	reqLogger.Info().Int64("anchor_id", id).Msg("VWAP anchor deleted")
+}
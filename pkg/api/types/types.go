@@ -3,7 +3,7 @@ package types
 import (
 	"time"
 
-	"github.com/ridopark/jonbu-ohlcv/internal/models"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
 )
 
 // REQ-016: API request/response types for REST endpoints
@@ -52,3 +52,161 @@ type ErrorResponse struct {
 	CorrelationID string    `json:"correlation_id,omitempty"`
 	Timestamp     time.Time `json:"timestamp"`
 }
+
+// REQ-253: Alert rule request/response types
+
+// AlertRuleRequest represents a request to create an alert rule
+type AlertRuleRequest struct {
+	Name            string  `json:"name"`
+	Symbol          string  `json:"symbol" validate:"required"`
+	Timeframe       string  `json:"timeframe" validate:"required"`
+	Condition       string  `json:"condition" validate:"required"`
+	Threshold       float64 `json:"threshold,omitempty"`
+	Pattern         string  `json:"pattern,omitempty"`
+	WebhookURL      string  `json:"webhook_url,omitempty"`
+	CooldownSeconds *int    `json:"cooldown_seconds,omitempty"`
+	Enabled         *bool   `json:"enabled,omitempty"`
+}
+
+// AlertRuleListResponse represents the response for listing alert rules
+type AlertRuleListResponse struct {
+	Count int                 `json:"count"`
+	Rules []*models.AlertRule `json:"rules"`
+}
+
+// REQ-257: Indicator series response types
+
+// IndicatorSeriesResponse represents aligned indicator series over a range.
+// Each series holds one value per timestamp; null marks warm-up.
+type IndicatorSeriesResponse struct {
+	Symbol        string                   `json:"symbol"`
+	Timeframe     string                   `json:"timeframe"`
+	Start         time.Time                `json:"start"`
+	End           time.Time                `json:"end"`
+	Lookback      int                      `json:"lookback"`
+	WarmupCandles int                      `json:"warmup_candles"`
+	Count         int                      `json:"count"`
+	Timestamps    []time.Time              `json:"timestamps"`
+	Series        []models.IndicatorSeries `json:"series"`
+}
+
+// REQ-259: Screen request/response types
+
+// ScreenRequest represents a request to screen symbols with a rule expression
+type ScreenRequest struct {
+	Expression string   `json:"expression" validate:"required"`
+	Timeframe  string   `json:"timeframe,omitempty"` // defaults to 1d
+	Symbols    []string `json:"symbols,omitempty"`   // defaults to all tracked symbols
+	Profile    string   `json:"profile,omitempty"`   // REQ-266: signal profile, defaults to default
+}
+
+// ScreenMatch is a symbol whose latest candle matched the expression
+type ScreenMatch struct {
+	Symbol    string             `json:"symbol"`
+	Timestamp time.Time          `json:"timestamp"`
+	Close     float64            `json:"close"`
+	Signal    string             `json:"signal,omitempty"`
+	Values    map[string]float64 `json:"values,omitempty"`
+}
+
+// ScreenSkip is a symbol that could not be evaluated
+type ScreenSkip struct {
+	Symbol string `json:"symbol"`
+	Reason string `json:"reason"`
+}
+
+// ScreenResponse represents the result of a screen
+type ScreenResponse struct {
+	Expression string        `json:"expression"`
+	Timeframe  string        `json:"timeframe"`
+	Profile    string        `json:"profile,omitempty"`
+	Indicators []string      `json:"indicators"`
+	Scanned    int           `json:"scanned"`
+	Count      int           `json:"count"`
+	Matches    []ScreenMatch `json:"matches"`
+	Skipped    []ScreenSkip  `json:"skipped,omitempty"`
+}
+
+// REQ-260: Enriched candle history types
+
+// EnrichedCandlesResponse represents stored enriched candles over a range
+type EnrichedCandlesResponse struct {
+	Symbol    string                         `json:"symbol"`
+	Timeframe string                         `json:"timeframe"`
+	Start     time.Time                      `json:"start"`
+	End       time.Time                      `json:"end"`
+	Filters   map[string]string              `json:"filters,omitempty"`
+	Count     int                            `json:"count"`
+	Data      []*models.EnrichedCandleRecord `json:"data"`
+}
+
+// REQ-263: Correlation matrix types
+
+// CorrelationResponse represents pairwise return correlations of symbols
+type CorrelationResponse struct {
+	Timeframe     string `json:"timeframe"`
+	Window        int    `json:"window"`         // returns per pair
+	StoredSymbols int    `json:"stored_symbols"` // series read from storage instead of the live stream
+	*models.CorrelationMatrix
+}
+
+// REQ-264: Volume profile types
+
+// VolumeProfileResponse represents price-volume profiles of stored bars
+type VolumeProfileResponse struct {
+	Symbol    string                       `json:"symbol"`
+	Timeframe string                       `json:"timeframe"`
+	Start     time.Time                    `json:"start"`
+	End       time.Time                    `json:"end"`
+	Group     string                       `json:"group"` // session or range
+	Bars      int                          `json:"bars"`
+	Truncated bool                         `json:"truncated,omitempty"` // the range held more bars than read
+	Profiles  []*models.PriceVolumeProfile `json:"profiles"`
+}
+
+// REQ-265: VWAP anchor request/response types
+
+// VWAPAnchorRequest represents a request to register a VWAP anchor. Without
+// an event the anchor is the given timestamp; the gap event anchors at the
+// regular open of the latest session whose daily open gapped at least
+// MinGapPercent from the previous close.
+type VWAPAnchorRequest struct {
+	Symbol        string     `json:"symbol"`
+	Name          string     `json:"name,omitempty"`
+	Event         string     `json:"event,omitempty"` // manual (default), gap
+	AnchorAt      *time.Time `json:"anchor_at,omitempty"`
+	MinGapPercent float64    `json:"min_gap_percent,omitempty"` // gap event, default 2
+}
+
+// VWAPAnchorListResponse represents the response for listing VWAP anchors
+type VWAPAnchorListResponse struct {
+	Count   int                  `json:"count"`
+	Anchors []*models.VWAPAnchor `json:"anchors"`
+}
+
+// REQ-266: Signal profile types
+
+// SignalProfilesResponse represents the response for listing signal profiles
+type SignalProfilesResponse struct {
+	Count    int                    `json:"count"`
+	Profiles []models.SignalProfile `json:"profiles"`
+}
+
+// REQ-272: Pattern outcome statistics types
+
+// PatternStatsResponse represents stored pattern outcome statistics
+type PatternStatsResponse struct {
+	Filters map[string]string             `json:"filters,omitempty"`
+	Count   int                           `json:"count"`
+	Stats   []*models.PatternOutcomeStats `json:"stats"`
+}
+
+// REQ-274: Tracked support and resistance level types
+
+// LevelsResponse represents the tracked levels of a symbol
+type LevelsResponse struct {
+	Symbol    string                 `json:"symbol"`
+	Timeframe string                 `json:"timeframe,omitempty"`
+	Count     int                    `json:"count"`
+	Levels    []*models.TrackedLevel `json:"levels"`
+}
@@ -0,0 +1,528 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-252: Go client SDK for REST and streaming access
+
+// Config holds client configuration
+type Config struct {
+	// BaseURL is the server root, e.g. http://localhost:8080
+	BaseURL string
+
+	// Timeout bounds every REST request
+	Timeout time.Duration
+
+	// HTTPClient overrides the default HTTP client when set
+	HTTPClient *http.Client
+}
+
+// DefaultConfig returns a client configuration for the given server
+func DefaultConfig(baseURL string) *Config {
+	return &Config{
+		BaseURL: baseURL,
+		Timeout: 30 * time.Second,
+	}
+}
+
+// Client is a typed REST client for the jonbu-ohlcv API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// APIError is returned when the server responds with a non-2xx status
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// NewClient creates a new REST client
+func NewClient(config *Config) *Client {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(config.BaseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// OHLCVQuery filters GET /api/v1/ohlcv/{symbol}
+type OHLCVQuery struct {
+	Timeframe string // 1m, 5m, 15m, 1h, 4h, 1d (server default 1d)
+	Limit     int    // 1-1000 (server default 100)
+}
+
+// HistoryQuery filters GET /api/v1/ohlcv/{symbol}/history
+type HistoryQuery struct {
+	Timeframe string
+	Start     time.Time // date precision, server default 30 days ago
+	End       time.Time // date precision, server default now
+	Limit     int       // 1-10000 (server default 1000)
+}
+
+// IndicatorSeriesQuery filters GET /api/v1/indicators/{symbol}
+type IndicatorSeriesQuery struct {
+	Timeframe string
+	Series    []string  // specs such as rsi(14) or macd(12,26,9)
+	Start     time.Time // server default 30 days ago
+	End       time.Time // server default now
+	Limit     int       // 1-10000 (server default 1000)
+}
+
+// EnrichedQuery filters GET /api/v1/enriched/{symbol}
+type EnrichedQuery struct {
+	Timeframe     string    // server default 1m
+	Start         time.Time // server default one day ago
+	End           time.Time // server default now
+	Signal        string    // bullish, bearish or neutral
+	Trend         string
+	Regime        string
+	MinConfidence float64 // 0-100, inclusive
+	MinStrength   float64 // 0-100, inclusive
+	EngineVersion string  // without it and ConfigHash, the latest enrichment of each candle
+	ConfigHash    string
+	Limit         int // 1-10000 (server default 1000)
+}
+
+// CorrelationQuery filters GET /api/v1/correlation
+type CorrelationQuery struct {
+	Symbols   []string // server default all tracked symbols
+	Timeframe string   // server default 1m
+	Window    int      // returns per pair, 5-1000 (server default 50)
+}
+
+// VolumeProfileQuery filters GET /api/v1/volume-profile/{symbol}
+type VolumeProfileQuery struct {
+	Timeframe string    // server default 1m
+	Start     time.Time // server default one day ago
+	End       time.Time // server default now
+	Bins      int       // price bins per profile, 5-500 (server default 50)
+	Group     string    // session (server default) or range
+}
+
+// HealthStatus is the response of GET /health
+type HealthStatus struct {
+	Status       string                 `json:"status"`
+	Timestamp    time.Time              `json:"timestamp"`
+	Version      string                 `json:"version"`
+	Phase        string                 `json:"phase"`
+	Database     string                 `json:"database"`
+	StreamServer string                 `json:"stream_server"`
+	WorkerPool   string                 `json:"worker_pool"`
+	AlpacaStream map[string]interface{} `json:"alpaca_stream"`
+	Fanout       map[string]interface{} `json:"fanout,omitempty"`
+}
+
+// StreamStatus is the response of GET /api/v1/stream/status
+type StreamStatus struct {
+	WebSocketClients    int                    `json:"websocket_clients"`
+	MessagesSent        int64                  `json:"messages_sent"`
+	ActiveSubscriptions int                    `json:"active_subscriptions"`
+	WorkerPool          map[string]interface{} `json:"worker_pool"`
+	AlpacaConnection    map[string]interface{} `json:"alpaca_connection"`
+	Status              string                 `json:"status"`
+}
+
+// StreamSymbolsResponse is the response of POST /api/v1/stream/symbols
+type StreamSymbolsResponse struct {
+	Status  string   `json:"status"`
+	Symbols []string `json:"symbols"`
+	Message string   `json:"message"`
+}
+
+// GetOHLCV fetches the most recent candles for a symbol
+func (c *Client) GetOHLCV(ctx context.Context, symbol string, query *OHLCVQuery) (*types.OHLCVResponse, error) {
+	params := url.Values{}
+	if query != nil {
+		if query.Timeframe != "" {
+			params.Set("timeframe", query.Timeframe)
+		}
+		if query.Limit > 0 {
+			params.Set("limit", strconv.Itoa(query.Limit))
+		}
+	}
+
+	var response types.OHLCVResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/ohlcv/"+url.PathEscape(symbol), params, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetHistory fetches candles for a symbol over a date range
+func (c *Client) GetHistory(ctx context.Context, symbol string, query *HistoryQuery) (*types.OHLCVHistoryResponse, error) {
+	params := url.Values{}
+	if query != nil {
+		if query.Timeframe != "" {
+			params.Set("timeframe", query.Timeframe)
+		}
+		if !query.Start.IsZero() {
+			params.Set("start", query.Start.Format("2006-01-02"))
+		}
+		if !query.End.IsZero() {
+			params.Set("end", query.End.Format("2006-01-02"))
+		}
+		if query.Limit > 0 {
+			params.Set("limit", strconv.Itoa(query.Limit))
+		}
+	}
+
+	var response types.OHLCVHistoryResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/ohlcv/"+url.PathEscape(symbol)+"/history", params, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetIndicatorSeries fetches indicator series aligned with the candles of a range
+func (c *Client) GetIndicatorSeries(ctx context.Context, symbol string, query *IndicatorSeriesQuery) (*types.IndicatorSeriesResponse, error) {
+	params := url.Values{}
+	if query != nil {
+		if query.Timeframe != "" {
+			params.Set("timeframe", query.Timeframe)
+		}
+		if len(query.Series) > 0 {
+			params.Set("series", strings.Join(query.Series, ","))
+		}
+		if !query.Start.IsZero() {
+			params.Set("start", query.Start.Format(time.RFC3339))
+		}
+		if !query.End.IsZero() {
+			params.Set("end", query.End.Format(time.RFC3339))
+		}
+		if query.Limit > 0 {
+			params.Set("limit", strconv.Itoa(query.Limit))
+		}
+	}
+
+	var response types.IndicatorSeriesResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/indicators/"+url.PathEscape(symbol), params, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetEnrichedCandles fetches stored enriched candles matching the query
+func (c *Client) GetEnrichedCandles(ctx context.Context, symbol string, query *EnrichedQuery) (*types.EnrichedCandlesResponse, error) {
+	params := url.Values{}
+	if query != nil {
+		for key, value := range map[string]string{
+			"timeframe":      query.Timeframe,
+			"signal":         query.Signal,
+			"trend":          query.Trend,
+			"regime":         query.Regime,
+			"engine_version": query.EngineVersion,
+			"config_hash":    query.ConfigHash,
+		} {
+			if value != "" {
+				params.Set(key, value)
+			}
+		}
+		if !query.Start.IsZero() {
+			params.Set("start", query.Start.Format(time.RFC3339))
+		}
+		if !query.End.IsZero() {
+			params.Set("end", query.End.Format(time.RFC3339))
+		}
+		if query.MinConfidence > 0 {
+			params.Set("min_confidence", strconv.FormatFloat(query.MinConfidence, 'f', -1, 64))
+		}
+		if query.MinStrength > 0 {
+			params.Set("min_strength", strconv.FormatFloat(query.MinStrength, 'f', -1, 64))
+		}
+		if query.Limit > 0 {
+			params.Set("limit", strconv.Itoa(query.Limit))
+		}
+	}
+
+	var response types.EnrichedCandlesResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/enriched/"+url.PathEscape(symbol), params, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetCorrelation fetches the return correlation matrix of symbols
+func (c *Client) GetCorrelation(ctx context.Context, query *CorrelationQuery) (*types.CorrelationResponse, error) {
+	params := url.Values{}
+	if query != nil {
+		if len(query.Symbols) > 0 {
+			params.Set("symbols", strings.Join(query.Symbols, ","))
+		}
+		if query.Timeframe != "" {
+			params.Set("timeframe", query.Timeframe)
+		}
+		if query.Window > 0 {
+			params.Set("window", strconv.Itoa(query.Window))
+		}
+	}
+
+	var response types.CorrelationResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/correlation", params, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetVolumeProfile fetches price-volume profiles of a symbol's stored bars
+func (c *Client) GetVolumeProfile(ctx context.Context, symbol string, query *VolumeProfileQuery) (*types.VolumeProfileResponse, error) {
+	params := url.Values{}
+	if query != nil {
+		if query.Timeframe != "" {
+			params.Set("timeframe", query.Timeframe)
+		}
+		if !query.Start.IsZero() {
+			params.Set("start", query.Start.Format(time.RFC3339))
+		}
+		if !query.End.IsZero() {
+			params.Set("end", query.End.Format(time.RFC3339))
+		}
+		if query.Bins > 0 {
+			params.Set("bins", strconv.Itoa(query.Bins))
+		}
+		if query.Group != "" {
+			params.Set("group", query.Group)
+		}
+	}
+
+	var response types.VolumeProfileResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/volume-profile/"+url.PathEscape(symbol), params, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Screen evaluates a rule expression on the latest candle of the given
+// symbols, or of all tracked symbols when none are given
+func (c *Client) Screen(ctx context.Context, request *types.ScreenRequest) (*types.ScreenResponse, error) {
+	var response types.ScreenResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/screen", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AddStreamSymbols subscribes the server's live stream to the given symbols
+func (c *Client) AddStreamSymbols(ctx context.Context, symbols []string) (*StreamSymbolsResponse, error) {
+	request := &types.SymbolRequest{Symbols: symbols}
+
+	var response StreamSymbolsResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/stream/symbols", nil, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// RemoveStreamSymbol unsubscribes the server's live stream from a symbol
+func (c *Client) RemoveStreamSymbol(ctx context.Context, symbol string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/stream/symbols/"+url.PathEscape(symbol), nil, nil, nil)
+}
+
+// GetStreamStatus returns streaming and worker pool status
+func (c *Client) GetStreamStatus(ctx context.Context) (*StreamStatus, error) {
+	var response StreamStatus
+	if err := c.do(ctx, http.MethodGet, "/api/v1/stream/status", nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Health returns the server health status. An unhealthy server is reported
+// through the returned status, not as an error.
+func (c *Client) Health(ctx context.Context) (*HealthStatus, error) {
+	var response HealthStatus
+	err := c.do(ctx, http.MethodGet, "/health", nil, nil, &response)
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusServiceUnavailable && response.Status != "" {
+		return &response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreateAlertRule registers a server-side alert rule
+func (c *Client) CreateAlertRule(ctx context.Context, rule *types.AlertRuleRequest) (*models.AlertRule, error) {
+	var response models.AlertRule
+	if err := c.do(ctx, http.MethodPost, "/api/v1/alerts", nil, rule, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListAlertRules returns all alert rules, optionally filtered by symbol
+func (c *Client) ListAlertRules(ctx context.Context, symbol string) (*types.AlertRuleListResponse, error) {
+	params := url.Values{}
+	if symbol != "" {
+		params.Set("symbol", symbol)
+	}
+
+	var response types.AlertRuleListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/alerts", params, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteAlertRule removes an alert rule
+func (c *Client) DeleteAlertRule(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/alerts/"+strconv.FormatInt(id, 10), nil, nil, nil)
+}
+
+// CreateVWAPAnchor registers the starting point of an anchored VWAP
+func (c *Client) CreateVWAPAnchor(ctx context.Context, anchor *types.VWAPAnchorRequest) (*models.VWAPAnchor, error) {
+	var response models.VWAPAnchor
+	if err := c.do(ctx, http.MethodPost, "/api/v1/vwap/anchors", nil, anchor, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListVWAPAnchors returns all VWAP anchors, optionally filtered by symbol
+func (c *Client) ListVWAPAnchors(ctx context.Context, symbol string) (*types.VWAPAnchorListResponse, error) {
+	params := url.Values{}
+	if symbol != "" {
+		params.Set("symbol", symbol)
+	}
+
+	var response types.VWAPAnchorListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/vwap/anchors", params, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteVWAPAnchor removes a VWAP anchor
+func (c *Client) DeleteVWAPAnchor(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/vwap/anchors/"+strconv.FormatInt(id, 10), nil, nil, nil)
+}
+
+// ListSignalProfiles returns the signal profiles clients can select
+func (c *Client) ListSignalProfiles(ctx context.Context) (*types.SignalProfilesResponse, error) {
+	var response types.SignalProfilesResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/signal-profiles", nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetEnrichmentMetrics returns the enrichment counters with the latencies of
+// the rolling window, the server default when zero
+func (c *Client) GetEnrichmentMetrics(ctx context.Context, window time.Duration) (*models.EnrichmentMetrics, error) {
+	params := url.Values{}
+	if window > 0 {
+		params.Set("window", window.String())
+	}
+
+	var response models.EnrichmentMetrics
+	if err := c.do(ctx, http.MethodGet, "/api/v1/enrichment/metrics", params, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetPatternStats returns the stored pattern outcome statistics, optionally
+// filtered by symbol, timeframe and pattern name
+func (c *Client) GetPatternStats(ctx context.Context, symbol, timeframe, pattern string) (*types.PatternStatsResponse, error) {
+	params := url.Values{}
+	for key, value := range map[string]string{"symbol": symbol, "timeframe": timeframe, "pattern": pattern} {
+		if value != "" {
+			params.Set(key, value)
+		}
+	}
+
+	var response types.PatternStatsResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/patterns/stats", params, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetLevels returns the tracked support and resistance levels of a symbol,
+// of every live timeframe when timeframe is empty
+func (c *Client) GetLevels(ctx context.Context, symbol, timeframe string) (*types.LevelsResponse, error) {
+	params := url.Values{}
+	if timeframe != "" {
+		params.Set("timeframe", timeframe)
+	}
+
+	var response types.LevelsResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/levels/"+url.PathEscape(symbol), params, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// do performs a request and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, params url.Values, body, out interface{}) error {
+	endpoint := c.baseURL + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var apiErr error
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr = &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(data)),
+		}
+	}
+
+	if out != nil && len(data) > 0 && json.Valid(data) {
+		if err := json.Unmarshal(data, out); err != nil && apiErr == nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return apiErr
+}
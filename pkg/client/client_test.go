@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/api/types"
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+func TestGetOHLCV(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/ohlcv/AAPL" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("timeframe") != "5m" || r.URL.Query().Get("limit") != "2" {
+			http.Error(w, "Bad query", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(types.OHLCVResponse{
+			Symbol:    "AAPL",
+			Timeframe: "5m",
+			Count:     1,
+			Data:      []*models.OHLCV{{Symbol: "AAPL", Close: 190.5, Timeframe: "5m"}},
+		})
+	}))
+	defer server.Close()
+
+	c := NewClient(DefaultConfig(server.URL))
+
+	resp, err := c.GetOHLCV(context.Background(), "AAPL", &OHLCVQuery{Timeframe: "5m", Limit: 2})
+	if err != nil {
+		t.Fatalf("GetOHLCV failed: %v", err)
+	}
+	if resp.Count != 1 || resp.Data[0].Close != 190.5 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	_, err = c.GetOHLCV(context.Background(), "MSFT", nil)
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 APIError, got %v", err)
+	}
+}
+
+func TestStreamClientResubscribesAfterReconnect(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+
+	var mu sync.Mutex
+	connections := 0
+	subscribed := make(chan clientMessage, 4)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		mu.Lock()
+		connections++
+		attempt := connections
+		mu.Unlock()
+
+		var msg clientMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		subscribed <- msg
+
+		if attempt == 1 {
+			// Drop the first connection to force a reconnect
+			return
+		}
+
+		candle := models.Candle{Symbol: msg.Symbol, Close: 101, Interval: msg.Timeframe}
+		data, _ := json.Marshal(candle)
+		frame, _ := json.Marshal(serverMessage{Type: "candle", Symbol: msg.Symbol, Timeframe: msg.Timeframe, Data: data})
+		conn.WriteMessage(websocket.TextMessage, frame)
+
+		conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	config := DefaultStreamConfig(server.URL)
+	config.ReconnectMinDelay = 10 * time.Millisecond
+
+	sc := NewStreamClient(config)
+	if err := sc.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer sc.Close()
+
+	if err := sc.Subscribe("aapl", "1min", WithProfile("swing")); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-subscribed:
+			if msg.Symbol != "AAPL" || msg.Timeframe != "1min" || msg.Action != "subscribe" || msg.Profile != "swing" {
+				t.Errorf("unexpected subscription message: %+v", msg)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for subscription %d", i+1)
+		}
+	}
+
+	select {
+	case candle := <-sc.Candles():
+		if candle.Symbol != "AAPL" || candle.Close != 101 {
+			t.Errorf("unexpected candle: %+v", candle)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for candle after reconnect")
+	}
+}
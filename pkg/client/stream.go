@@ -0,0 +1,476 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ridopark/jonbu-ohlcv/pkg/models"
+)
+
+// REQ-252: Streaming client with auto-reconnect and resubscription
+
+// ErrStreamClosed is returned when using a stream client after Close
+var ErrStreamClosed = errors.New("stream client closed")
+
+// StreamConfig holds WebSocket streaming configuration
+type StreamConfig struct {
+	// URL is the WebSocket endpoint, e.g. ws://localhost:8080/ws/ohlcv
+	URL string
+
+	// ReconnectMinDelay is the first backoff delay after a dropped connection
+	ReconnectMinDelay time.Duration
+
+	// ReconnectMaxDelay caps the exponential backoff
+	ReconnectMaxDelay time.Duration
+
+	// PingInterval is how often application-level pings are sent
+	PingInterval time.Duration
+
+	// BufferSize is the capacity of each typed event channel
+	BufferSize int
+}
+
+// DefaultStreamConfig returns a streaming configuration for the given server.
+// The base URL may use http(s) or ws(s); the /ws/ohlcv path is appended.
+func DefaultStreamConfig(baseURL string) *StreamConfig {
+	endpoint := strings.TrimRight(baseURL, "/")
+	switch {
+	case strings.HasPrefix(endpoint, "https://"):
+		endpoint = "wss://" + strings.TrimPrefix(endpoint, "https://")
+	case strings.HasPrefix(endpoint, "http://"):
+		endpoint = "ws://" + strings.TrimPrefix(endpoint, "http://")
+	}
+
+	return &StreamConfig{
+		URL:               endpoint + "/ws/ohlcv",
+		ReconnectMinDelay: 500 * time.Millisecond,
+		ReconnectMaxDelay: 30 * time.Second,
+		PingInterval:      30 * time.Second,
+		BufferSize:        256,
+	}
+}
+
+// clientMessage mirrors the server's inbound WebSocket message
+type clientMessage struct {
+	Type      string `json:"type"`
+	Symbol    string `json:"symbol,omitempty"`
+	Timeframe string `json:"timeframe,omitempty"`
+	Action    string `json:"action,omitempty"`
+	Profile   string `json:"profile,omitempty"`
+}
+
+// serverMessage mirrors the server's outbound WebSocket message
+type serverMessage struct {
+	Type      string          `json:"type"`
+	Symbol    string          `json:"symbol,omitempty"`
+	Timeframe string          `json:"timeframe,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// StreamClient streams live candles over WebSocket. Subscriptions survive
+// reconnects: after a dropped connection the client reconnects with
+// exponential backoff and replays every active subscription.
+//
+// Events are delivered in order, so a typed channel that is not drained
+// eventually blocks delivery on the others.
+type StreamClient struct {
+	config *StreamConfig
+	dialer *websocket.Dialer
+
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	subscriptions map[string]string // symbol:timeframe key -> signal profile
+	mu            sync.RWMutex
+
+	candles  chan models.Candle
+	enriched chan models.EnrichedCandle
+	alerts   chan models.Alert
+	errs     chan error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	closed bool
+}
+
+// NewStreamClient creates a new streaming client
+func NewStreamClient(config *StreamConfig) *StreamClient {
+	return &StreamClient{
+		config:        config,
+		dialer:        websocket.DefaultDialer,
+		subscriptions: make(map[string]string),
+		candles:       make(chan models.Candle, config.BufferSize),
+		enriched:      make(chan models.EnrichedCandle, config.BufferSize),
+		alerts:        make(chan models.Alert, config.BufferSize),
+		errs:          make(chan error, 16),
+		done:          make(chan struct{}),
+	}
+}
+
+// Candles returns the channel of raw candles. It is closed after Close.
+func (sc *StreamClient) Candles() <-chan models.Candle {
+	return sc.candles
+}
+
+// EnrichedCandles returns the channel of enriched candles. It is closed after Close.
+func (sc *StreamClient) EnrichedCandles() <-chan models.EnrichedCandle {
+	return sc.enriched
+}
+
+// Alerts returns alerts fired by server-side rules for subscribed streams.
+// It is closed after Close.
+func (sc *StreamClient) Alerts() <-chan models.Alert {
+	return sc.alerts
+}
+
+// Errors returns server-reported and connection errors. Errors are dropped
+// when the channel is full, so reading it is optional.
+func (sc *StreamClient) Errors() <-chan error {
+	return sc.errs
+}
+
+// Connect dials the server and starts the receive loop. The initial dial must
+// succeed; later disconnects are retried until the context is cancelled or
+// Close is called.
+func (sc *StreamClient) Connect(ctx context.Context) error {
+	if _, err := url.Parse(sc.config.URL); err != nil {
+		return fmt.Errorf("invalid stream URL: %w", err)
+	}
+
+	conn, _, err := sc.dialer.DialContext(ctx, sc.config.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", sc.config.URL, err)
+	}
+
+	sc.ctx, sc.cancel = context.WithCancel(ctx)
+	sc.setConn(conn)
+
+	go sc.run(conn)
+	go func() {
+		// Unblock the pending read when the caller's context is cancelled
+		<-sc.ctx.Done()
+		if conn := sc.getConn(); conn != nil {
+			conn.Close()
+		}
+	}()
+	return nil
+}
+
+// SubscribeOption customizes a subscription
+type SubscribeOption func(*clientMessage)
+
+// WithProfile selects the signal profile of the subscription's enriched
+// candles (see Client.ListSignalProfiles). The server default applies when unset.
+func WithProfile(profile string) SubscribeOption {
+	return func(msg *clientMessage) {
+		msg.Profile = profile
+	}
+}
+
+// Subscribe starts streaming a symbol and timeframe (1min, 5min, 15min, 30min, 1hour, 1day).
+// Subscribing again to the same stream replaces its options.
+func (sc *StreamClient) Subscribe(symbol, timeframe string, opts ...SubscribeOption) error {
+	msg := clientMessage{Type: "subscription", Action: "subscribe", Symbol: strings.ToUpper(symbol), Timeframe: timeframe}
+	for _, opt := range opts {
+		opt(&msg)
+	}
+	key := subscriptionKey(msg.Symbol, timeframe)
+
+	sc.mu.Lock()
+	if sc.closed {
+		sc.mu.Unlock()
+		return ErrStreamClosed
+	}
+	sc.subscriptions[key] = msg.Profile
+	sc.mu.Unlock()
+
+	return sc.send(msg)
+}
+
+// Unsubscribe stops streaming a symbol and timeframe
+func (sc *StreamClient) Unsubscribe(symbol, timeframe string) error {
+	symbol = strings.ToUpper(symbol)
+	key := subscriptionKey(symbol, timeframe)
+
+	sc.mu.Lock()
+	if sc.closed {
+		sc.mu.Unlock()
+		return ErrStreamClosed
+	}
+	delete(sc.subscriptions, key)
+	sc.mu.Unlock()
+
+	return sc.send(clientMessage{Type: "subscription", Action: "unsubscribe", Symbol: symbol, Timeframe: timeframe})
+}
+
+// Subscriptions returns the active symbol:timeframe subscriptions
+func (sc *StreamClient) Subscriptions() []string {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	keys := make([]string, 0, len(sc.subscriptions))
+	for key := range sc.subscriptions {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Close disconnects and closes the event channels
+func (sc *StreamClient) Close() error {
+	sc.mu.Lock()
+	if sc.closed {
+		sc.mu.Unlock()
+		return nil
+	}
+	sc.closed = true
+	sc.mu.Unlock()
+
+	if sc.cancel == nil {
+		// Never connected
+		close(sc.candles)
+		close(sc.enriched)
+		close(sc.alerts)
+		close(sc.errs)
+		return nil
+	}
+
+	sc.cancel()
+	if conn := sc.getConn(); conn != nil {
+		sc.writeMu.Lock()
+		conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		sc.writeMu.Unlock()
+		conn.Close()
+	}
+
+	<-sc.done
+	return nil
+}
+
+// run reads from the connection and reconnects until cancelled
+func (sc *StreamClient) run(conn *websocket.Conn) {
+	defer func() {
+		close(sc.candles)
+		close(sc.enriched)
+		close(sc.alerts)
+		close(sc.errs)
+		close(sc.done)
+	}()
+
+	for {
+		sc.readLoop(conn)
+
+		if sc.ctx.Err() != nil {
+			return
+		}
+
+		conn = sc.reconnect()
+		if conn == nil {
+			return
+		}
+	}
+}
+
+// readLoop dispatches messages until the connection fails
+func (sc *StreamClient) readLoop(conn *websocket.Conn) {
+	pingCtx, stopPing := context.WithCancel(sc.ctx)
+	defer stopPing()
+	go sc.pingLoop(pingCtx)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if sc.ctx.Err() == nil {
+				sc.reportError(fmt.Errorf("stream connection lost: %w", err))
+			}
+			sc.setConn(nil)
+			conn.Close()
+			return
+		}
+
+		// The server may batch several messages in one frame separated by newlines
+		for _, line := range bytes.Split(data, []byte{'\n'}) {
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			sc.dispatch(line)
+		}
+	}
+}
+
+// dispatch decodes one server message onto the typed channels
+func (sc *StreamClient) dispatch(data []byte) {
+	var msg serverMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		sc.reportError(fmt.Errorf("failed to decode stream message: %w", err))
+		return
+	}
+
+	switch msg.Type {
+	case "candle":
+		var candle models.Candle
+		if err := json.Unmarshal(msg.Data, &candle); err != nil {
+			sc.reportError(fmt.Errorf("failed to decode candle: %w", err))
+			return
+		}
+		select {
+		case sc.candles <- candle:
+		case <-sc.ctx.Done():
+		}
+
+	case "enriched_candle":
+		var candle models.EnrichedCandle
+		if err := json.Unmarshal(msg.Data, &candle); err != nil {
+			sc.reportError(fmt.Errorf("failed to decode enriched candle: %w", err))
+			return
+		}
+		select {
+		case sc.enriched <- candle:
+		case <-sc.ctx.Done():
+		}
+
+	case "alert":
+		var alert models.Alert
+		if err := json.Unmarshal(msg.Data, &alert); err != nil {
+			sc.reportError(fmt.Errorf("failed to decode alert: %w", err))
+			return
+		}
+		select {
+		case sc.alerts <- alert:
+		case <-sc.ctx.Done():
+		}
+
+	case "error":
+		sc.reportError(fmt.Errorf("server error: %s", msg.Error))
+	}
+}
+
+// reconnect dials with exponential backoff and replays subscriptions
+func (sc *StreamClient) reconnect() *websocket.Conn {
+	delay := sc.config.ReconnectMinDelay
+
+	for {
+		select {
+		case <-sc.ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+
+		conn, _, err := sc.dialer.DialContext(sc.ctx, sc.config.URL, nil)
+		if err != nil {
+			sc.reportError(fmt.Errorf("reconnect failed: %w", err))
+
+			delay *= 2
+			if delay > sc.config.ReconnectMaxDelay {
+				delay = sc.config.ReconnectMaxDelay
+			}
+			continue
+		}
+
+		sc.setConn(conn)
+
+		if err := sc.resubscribe(); err != nil {
+			sc.reportError(err)
+			conn.Close()
+			continue
+		}
+
+		return conn
+	}
+}
+
+// resubscribe replays all active subscriptions on a fresh connection
+func (sc *StreamClient) resubscribe() error {
+	sc.mu.RLock()
+	subscriptions := make(map[string]string, len(sc.subscriptions))
+	for key, profile := range sc.subscriptions {
+		subscriptions[key] = profile
+	}
+	sc.mu.RUnlock()
+
+	for key, profile := range subscriptions {
+		symbol, timeframe, _ := strings.Cut(key, ":")
+		msg := clientMessage{Type: "subscription", Action: "subscribe", Symbol: symbol, Timeframe: timeframe, Profile: profile}
+		if err := sc.send(msg); err != nil {
+			return fmt.Errorf("failed to resubscribe %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// pingLoop keeps the connection alive through idle proxies
+func (sc *StreamClient) pingLoop(ctx context.Context) {
+	if sc.config.PingInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(sc.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sc.send(clientMessage{Type: "ping"})
+		}
+	}
+}
+
+// send writes a message on the current connection. While disconnected the
+// message is dropped; subscriptions are replayed after reconnecting.
+func (sc *StreamClient) send(msg clientMessage) error {
+	conn := sc.getConn()
+	if conn == nil {
+		return nil
+	}
+
+	sc.writeMu.Lock()
+	defer sc.writeMu.Unlock()
+
+	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := conn.WriteJSON(msg); err != nil {
+		return fmt.Errorf("failed to send %s message: %w", msg.Type, err)
+	}
+	return nil
+}
+
+// reportError forwards an error without blocking the receive loop
+func (sc *StreamClient) reportError(err error) {
+	select {
+	case sc.errs <- err:
+	default:
+	}
+}
+
+func (sc *StreamClient) setConn(conn *websocket.Conn) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.conn = conn
+}
+
+func (sc *StreamClient) getConn() *websocket.Conn {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	return sc.conn
+}
+
+// subscriptionKey matches the server's symbol:timeframe subscription key
+func subscriptionKey(symbol, timeframe string) string {
+	return symbol + ":" + timeframe
+}
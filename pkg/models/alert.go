@@ -0,0 +1,104 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// REQ-253: Server-side alert rules evaluated against enriched candles
+
+// Alert rule conditions
+const (
+	AlertPriceCrossAbove    = "price_cross_above"
+	AlertPriceCrossBelow    = "price_cross_below"
+	AlertRSIAbove           = "rsi_above"
+	AlertRSIBelow           = "rsi_below"
+	AlertPattern            = "pattern"
+	AlertResistanceBreakout = "resistance_breakout"
+	AlertSupportBreakdown   = "support_breakdown"
+)
+
+// DefaultAlertCooldown is applied when a rule does not specify a cooldown
+const DefaultAlertCooldown = 300 // seconds
+
+// AlertRule is a user-registered condition evaluated on every enriched candle
+type AlertRule struct {
+	ID              int64      `json:"id" db:"id"`
+	Name            string     `json:"name" db:"name"`
+	Symbol          string     `json:"symbol" db:"symbol"`
+	Timeframe       string     `json:"timeframe" db:"timeframe"` // stream format: 1min, 5min, 15min
+	Condition       string     `json:"condition" db:"condition"`
+	Threshold       float64    `json:"threshold,omitempty" db:"threshold"` // price level or RSI value
+	Pattern         string     `json:"pattern,omitempty" db:"pattern"`     // candlestick pattern, e.g. bearish_engulfing
+	WebhookURL      string     `json:"webhook_url,omitempty" db:"webhook_url"`
+	CooldownSeconds int        `json:"cooldown_seconds" db:"cooldown_seconds"`
+	Enabled         bool       `json:"enabled" db:"enabled"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty" db:"last_triggered_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Alert is emitted when a rule fires
+type Alert struct {
+	RuleID      int64     `json:"rule_id"`
+	RuleName    string    `json:"rule_name,omitempty"`
+	Symbol      string    `json:"symbol"`
+	Timeframe   string    `json:"timeframe"`
+	Condition   string    `json:"condition"`
+	Message     string    `json:"message"`
+	Value       float64   `json:"value"`           // observed value that triggered the rule
+	Level       float64   `json:"level,omitempty"` // level that was crossed, if any
+	Price       float64   `json:"price"`           // close of the triggering candle
+	CandleTime  time.Time `json:"candle_time"`     // timestamp of the triggering candle
+	TriggeredAt time.Time `json:"triggered_at"`
+}
+
+// Validate checks that the rule is complete for its condition
+func (r *AlertRule) Validate() error {
+	if r.Symbol == "" {
+		return ErrInvalidSymbol
+	}
+	for _, char := range r.Symbol {
+		if char < 'A' || char > 'Z' {
+			return ErrInvalidSymbol
+		}
+	}
+
+	switch r.Timeframe {
+	case "1min", "5min", "15min", "30min", "1hour", "1day":
+	default:
+		return fmt.Errorf("invalid timeframe: must be one of 1min, 5min, 15min, 30min, 1hour, 1day")
+	}
+
+	switch r.Condition {
+	case AlertPriceCrossAbove, AlertPriceCrossBelow:
+		if r.Threshold <= 0 {
+			return fmt.Errorf("condition %s requires a positive threshold", r.Condition)
+		}
+	case AlertRSIAbove, AlertRSIBelow:
+		if r.Threshold <= 0 || r.Threshold >= 100 {
+			return fmt.Errorf("condition %s requires a threshold between 0 and 100", r.Condition)
+		}
+	case AlertPattern:
+		if r.Pattern == "" {
+			return fmt.Errorf("condition %s requires a pattern", r.Condition)
+		}
+	case AlertResistanceBreakout, AlertSupportBreakdown:
+	default:
+		return fmt.Errorf("unknown condition: %s", r.Condition)
+	}
+
+	if r.WebhookURL != "" {
+		parsed, err := url.Parse(r.WebhookURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("webhook_url must be an absolute http(s) URL")
+		}
+	}
+
+	if r.CooldownSeconds < 0 {
+		return fmt.Errorf("cooldown_seconds cannot be negative")
+	}
+
+	return nil
+}
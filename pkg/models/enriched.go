@@ -0,0 +1,655 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// REQ-200 to REQ-205: Enriched candle with AI insights
+
+// EnrichedCandle extends OHLCV with technical indicators and market context
+type EnrichedCandle struct {
+	// Base OHLCV data
+	OHLCV *OHLCV `json:"ohlcv"`
+
+	// Technical indicators
+	Indicators *TechnicalIndicators `json:"indicators"`
+
+	// Market analysis
+	Analysis *MarketAnalysis `json:"analysis"`
+
+	// Signal strength and confidence
+	Signals *TradingSignals `json:"signals"`
+
+	// REQ-266: Signals of the additionally requested profiles, by profile name
+	ProfileSignals map[string]*TradingSignals `json:"profile_signals,omitempty"`
+
+	// Performance metadata
+	Metadata *CandleMetadata `json:"metadata"`
+}
+
+// TechnicalIndicators contains all calculated indicators
+type TechnicalIndicators struct {
+	// Trend indicators
+	SMA20 float64   `json:"sma_20"`
+	SMA50 float64   `json:"sma_50"`
+	EMA12 float64   `json:"ema_12"`
+	EMA26 float64   `json:"ema_26"`
+	MACD  *MACDData `json:"macd"`
+
+	// REQ-256: Trend strength indicators
+	ADX          *ADXData          `json:"adx"`
+	ParabolicSAR *ParabolicSARData `json:"parabolic_sar"`
+	SuperTrend   *SuperTrendData   `json:"supertrend"`
+	Ichimoku     *IchimokuData     `json:"ichimoku"`
+
+	// Momentum indicators
+	RSI        float64         `json:"rsi"`
+	Stochastic *StochasticData `json:"stochastic"`
+	WilliamsR  float64         `json:"williams_r"`
+	CCI        float64         `json:"cci"`
+
+	// Volatility indicators
+	BollingerBands *BollingerBandsData `json:"bollinger_bands"`
+	ATR            float64             `json:"atr"`
+	Keltner        *ChannelData        `json:"keltner"`
+	Donchian       *ChannelData        `json:"donchian"`
+
+	// Volume indicators
+	VWAP      float64 `json:"vwap"` // REQ-265: since the session open
+	OBV       float64 `json:"obv"`
+	VolumeMA  float64 `json:"volume_ma"`
+	AccumDist float64 `json:"accum_dist"`
+	MFI       float64 `json:"mfi"`
+	CMF       float64 `json:"cmf"`
+
+	// REQ-265: Session VWAP bands and VWAPs of the symbol's registered anchors
+	VWAPBands     *VWAPBands     `json:"vwap_bands,omitempty"`
+	AnchoredVWAPs []AnchoredVWAP `json:"anchored_vwaps,omitempty"`
+
+	// Trend analysis
+	TrendDirection string  `json:"trend_direction"` // bullish, bearish, sideways
+	TrendStrength  float64 `json:"trend_strength"`  // 0-100
+
+	// Momentum analysis
+	MomentumDirection string  `json:"momentum_direction"` // bullish, bearish, neutral
+	MomentumStrength  float64 `json:"momentum_strength"`  // 0-100
+
+	// Volatility analysis
+	VolatilityLevel   string  `json:"volatility_level"`   // low, normal, high
+	VolatilityPercent float64 `json:"volatility_percent"` // 0-100
+
+	// Volume analysis
+	VolumeConfirmation string  `json:"volume_confirmation"` // confirmed, weak, divergent
+	RelativeVolume     float64 `json:"relative_volume"`     // vs average
+
+	// REQ-255: Registry indicator values keyed by canonical spec, e.g.
+	// "sma(200)" or "bbands(20,2.5).upper"
+	Values map[string]float64 `json:"values,omitempty"`
+
+	// REQ-263: Comparison with the benchmark, nil without benchmark history
+	Relative *RelativeData `json:"relative,omitempty"`
+}
+
+// RelativeData compares a symbol with a benchmark over a rolling window
+type RelativeData struct {
+	Benchmark        string  `json:"benchmark"` // e.g. SPY or a sector ETF
+	Periods          int     `json:"periods"`
+	RelativeStrength float64 `json:"relative_strength"` // 100 = same performance, >100 outperforming
+	ExcessReturn     float64 `json:"excess_return"`     // percent over the window
+	Beta             float64 `json:"beta"`
+	Correlation      float64 `json:"correlation"` // of period returns, -1 to 1
+	Observations     int     `json:"observations"`
+}
+
+// MarketAnalysis contains pattern and regime analysis
+type MarketAnalysis struct {
+	// Candlestick patterns
+	CandlestickPatterns []string `json:"candlestick_patterns"`
+
+	// REQ-270: Scores of the candlestick patterns, in the same order
+	CandlestickDetails []CandlestickPatternResult `json:"candlestick_details,omitempty"`
+
+	// Chart patterns
+	ChartPatterns []ChartPatternResult `json:"chart_patterns"`
+
+	// Market regime
+	MarketRegime string `json:"market_regime"` // accumulation, markup, distribution, markdown
+
+	// REQ-273: Volatility regime of a hidden Markov model fit on the returns
+	VolatilityRegime *VolatilityRegime `json:"volatility_regime,omitempty"`
+
+	// Support and resistance
+	SupportResistance *SupportResistanceLevels `json:"support_resistance"`
+
+	// REQ-275: Fibonacci levels of the latest significant swing
+	Fibonacci *FibonacciLevels `json:"fibonacci,omitempty"`
+
+	// Market context
+	MarketPhase   string `json:"market_phase"` // opening, midday, closing
+	SessionType   string `json:"session_type"` // regular, extended
+	DayOfWeek     string `json:"day_of_week"`
+	MarketHours   bool   `json:"market_hours"`
+	VolumeProfile string `json:"volume_profile"` // low, normal, high, spike
+
+	// REQ-264: Price-volume profile of the current session's bars
+	SessionProfile *PriceVolumeProfile `json:"session_profile,omitempty"`
+}
+
+// VolatilityRegime is the volatility state of the latest return under a
+// Gaussian hidden Markov model of the symbol's returns
+type VolatilityRegime struct {
+	Regime           string             `json:"regime"`            // low_volatility, normal_volatility, high_volatility
+	Probability      float64            `json:"probability"`       // 0-1
+	Probabilities    map[string]float64 `json:"probabilities"`     // every regime of the model, 0-1
+	ExpectedDuration float64            `json:"expected_duration"` // candles a spell of the regime lasts on average
+	Duration         int                `json:"duration"`          // candles the regime has held so far
+	Volatility       float64            `json:"volatility"`        // standard deviation of the regime's returns in percent
+	Model            string             `json:"model"`             // stored: fit on stored returns, window: fit on the candles at hand
+	Samples          int                `json:"samples"`           // returns the model was fit on
+}
+
+// FibonacciLevels are the retracements and extensions of the leg between
+// the two latest confirmed swing points
+type FibonacciLevels struct {
+	Direction    string           `json:"direction"` // up: from a swing low to a swing high, down
+	StartPrice   float64          `json:"start_price"`
+	StartTime    time.Time        `json:"start_time"`
+	EndPrice     float64          `json:"end_price"`
+	EndTime      time.Time        `json:"end_time"`
+	Retracements []FibonacciLevel `json:"retracements"` // back from the end toward the start
+	Extensions   []FibonacciLevel `json:"extensions"`   // beyond the end, measured from the start
+}
+
+// FibonacciLevel is the price at a ratio of a swing leg
+type FibonacciLevel struct {
+	Ratio float64 `json:"ratio"`
+	Price float64 `json:"price"`
+}
+
+// RegimeChange reports a stream moving from one volatility regime to another
+type RegimeChange struct {
+	Symbol    string            `json:"symbol"`
+	Timeframe string            `json:"timeframe"`
+	Timestamp time.Time         `json:"timestamp"`
+	From      string            `json:"from"`
+	To        string            `json:"to"`
+	Regime    *VolatilityRegime `json:"regime"`
+}
+
+// TradingSignals provides consolidated signal information
+type TradingSignals struct {
+	// REQ-266: Signal profile that produced the signals
+	Profile string `json:"profile"`
+
+	// Overall signal
+	OverallSignal  string  `json:"overall_signal"`  // bullish, bearish, neutral
+	SignalStrength float64 `json:"signal_strength"` // 0-100
+	Confidence     float64 `json:"confidence"`      // 0-100
+
+	// Component signals
+	TrendSignal    string `json:"trend_signal"`    // bullish, bearish, neutral
+	MomentumSignal string `json:"momentum_signal"` // bullish, bearish, neutral
+	VolumeSignal   string `json:"volume_signal"`   // bullish, bearish, neutral
+
+	// Entry/exit suggestions
+	EntryLevel float64   `json:"entry_level,omitempty"`
+	StopLoss   float64   `json:"stop_loss,omitempty"`
+	TakeProfit []float64 `json:"take_profit,omitempty"`
+
+	// Risk assessment
+	RiskLevel  string  `json:"risk_level"` // low, medium, high
+	Volatility float64 `json:"volatility"` // expected volatility
+
+	// Pattern-based signals
+	PatternSignals []PatternSignal `json:"pattern_signals,omitempty"`
+
+	// REQ-259: Configured expression signals that matched this candle
+	CustomSignals []CustomSignal `json:"custom_signals,omitempty"`
+
+	// REQ-262: Agreement with the higher timeframes of the symbol
+	Confluence *TimeframeConfluence `json:"confluence,omitempty"`
+
+	// REQ-267: Why the signal was given, score factors first, each group
+	// ordered by the size of its contribution
+	Factors []SignalFactor `json:"factors,omitempty"`
+}
+
+// Signal factor kinds
+const (
+	FactorIndicator  = "indicator"  // component signal of the indicators
+	FactorPattern    = "pattern"    // candlestick pattern
+	FactorRule       = "rule"       // rule expression of the signal profile
+	FactorConfluence = "confluence" // higher timeframe agreement
+	FactorProfile    = "profile"    // base value and cap of the signal profile
+)
+
+// What a signal factor contributes to
+const (
+	FactorScore      = "score"      // points of the normalized signal score (-100 to 100)
+	FactorConfidence = "confidence" // points of confidence
+)
+
+// SignalFactor is one contribution to a trading signal. Score contributions
+// add up to the signed signal score; confidence contributions add up to the
+// confidence.
+type SignalFactor struct {
+	Kind         string  `json:"kind"`         // indicator, pattern, rule, confluence, profile
+	Name         string  `json:"name"`         // e.g. trend, hammer, above_fast_ema
+	Value        string  `json:"value"`        // observed value, e.g. "bullish (strength 72.4)"
+	Rule         string  `json:"rule"`         // how the value contributes
+	Weight       float64 `json:"weight"`       // weight of the profile
+	Contribution float64 `json:"contribution"` // signed points
+	Affects      string  `json:"affects"`      // score, confidence
+}
+
+// TimeframeState is the latest closed candle state of a higher timeframe
+type TimeframeState struct {
+	Timeframe string    `json:"timeframe"` // 15m, 1h, 1d
+	Timestamp time.Time `json:"timestamp"` // latest closed candle
+	Trend     string    `json:"trend"`     // bullish, bearish, neutral
+	RSI       float64   `json:"rsi"`
+	Regime    string    `json:"regime,omitempty"`
+}
+
+// TimeframeConfluence compares a candle's signal with its higher timeframes
+type TimeframeConfluence struct {
+	Timeframes  []TimeframeState `json:"timeframes"`
+	Score       float64          `json:"score"`     // -100 (all bearish) to 100 (all bullish)
+	Alignment   float64          `json:"alignment"` // 0-100 weighted share agreeing with the overall signal
+	Aligned     bool             `json:"aligned"`   // directional signal and no higher timeframe against it
+	Conflicting bool             `json:"conflicting"`
+	Conflicts   []string         `json:"conflicts,omitempty"` // e.g. "bullish 1m signal against 1d bearish trend"
+}
+
+// CustomSignal is a matched custom signal
+type CustomSignal struct {
+	Name       string  `json:"name"`
+	Signal     string  `json:"signal"`     // bullish, bearish, neutral
+	Confidence float64 `json:"confidence"` // 0-100
+}
+
+// CustomSignalDefinition defines a signal by a rule expression, e.g.
+// when: "rsi(14) < 30 and close > sma(200)"
+type CustomSignalDefinition struct {
+	Name       string  `json:"name" mapstructure:"name"`
+	When       string  `json:"when" mapstructure:"when"`
+	Signal     string  `json:"signal" mapstructure:"signal"`         // bullish, bearish, neutral
+	Confidence float64 `json:"confidence" mapstructure:"confidence"` // 0-100
+}
+
+// Validate checks the definition fields other than the expression
+func (d *CustomSignalDefinition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("custom signal name is required")
+	}
+	if d.When == "" {
+		return fmt.Errorf("custom signal %s: when expression is required", d.Name)
+	}
+	switch d.Signal {
+	case "bullish", "bearish", "neutral":
+	default:
+		return fmt.Errorf("custom signal %s: signal must be bullish, bearish or neutral", d.Name)
+	}
+	if d.Confidence < 0 || d.Confidence > 100 {
+		return fmt.Errorf("custom signal %s: confidence must be between 0 and 100", d.Name)
+	}
+	return nil
+}
+
+// PatternSignal represents a signal from a specific pattern
+type PatternSignal struct {
+	PatternType string  `json:"pattern_type"`
+	PatternName string  `json:"pattern_name"`
+	Signal      string  `json:"signal"`     // bullish, bearish, neutral
+	Confidence  float64 `json:"confidence"` // 0-100
+	Target      float64 `json:"target,omitempty"`
+	StopLoss    float64 `json:"stop_loss,omitempty"`
+	TimeHorizon string  `json:"time_horizon"` // short, medium, long
+
+	// REQ-272: Stored outcomes the confidence is calibrated with
+	HitRate float64 `json:"hit_rate,omitempty"` // target hits per occurrence, 0-1
+	Samples int     `json:"samples,omitempty"`
+}
+
+// CandleMetadata contains performance and generation information
+type CandleMetadata struct {
+	// Generation timing
+	GeneratedAt      time.Time `json:"generated_at"`
+	ProcessingTimeMs float64   `json:"processing_time_ms"`
+
+	// Data quality
+	DataQuality       string  `json:"data_quality"`       // high, medium, low
+	IndicatorCoverage float64 `json:"indicator_coverage"` // percentage of indicators calculated
+
+	// Cache information
+	CacheHits       int     `json:"cache_hits"`
+	CacheMisses     int     `json:"cache_misses"`
+	CacheEfficiency float64 `json:"cache_efficiency"`
+
+	// Version information
+	EngineVersion string `json:"engine_version"`
+	ModelVersion  string `json:"model_version,omitempty"`
+
+	// Debug information
+	WarningsCount int      `json:"warnings_count"`
+	Warnings      []string `json:"warnings,omitempty"`
+
+	// REQ-268: Components cut off by the enrichment deadline or failed
+	Partial           bool     `json:"partial,omitempty"`
+	SkippedComponents []string `json:"skipped_components,omitempty"`
+	FailedComponents  []string `json:"failed_components,omitempty"`
+}
+
+// PriceVolumeProfile is a price-volume histogram over a range of bars
+type PriceVolumeProfile struct {
+	Session     string    `json:"session,omitempty"` // trading day, YYYY-MM-DD
+	Start       time.Time `json:"start"`             // first bar
+	End         time.Time `json:"end"`               // last bar
+	Bars        int       `json:"bars"`
+	TotalVolume int64     `json:"total_volume"`
+	Low         float64   `json:"low"`
+	High        float64   `json:"high"`
+	BinSize     float64   `json:"bin_size"`
+
+	PointOfControl  float64   `json:"point_of_control"` // price with the most volume
+	ValueAreaHigh   float64   `json:"value_area_high"`  // 70% of the volume between low and high
+	ValueAreaLow    float64   `json:"value_area_low"`
+	HighVolumeNodes []float64 `json:"high_volume_nodes,omitempty"`
+	LowVolumeNodes  []float64 `json:"low_volume_nodes,omitempty"`
+
+	Bins []VolumeProfileBin `json:"bins,omitempty"`
+}
+
+// VolumeProfileBin is the volume traded within a price bin
+type VolumeProfileBin struct {
+	Low    float64 `json:"low"`
+	High   float64 `json:"high"`
+	Volume float64 `json:"volume"`
+}
+
+// Price returns the middle of the bin
+func (b VolumeProfileBin) Price() float64 {
+	return (b.Low + b.High) / 2
+}
+
+// Supporting data structures
+
+// MACDData contains MACD indicator values
+type MACDData struct {
+	Line      float64 `json:"line"`
+	Signal    float64 `json:"signal"`
+	Histogram float64 `json:"histogram"`
+}
+
+// StochasticData contains Stochastic oscillator values
+type StochasticData struct {
+	K         float64 `json:"k"`
+	D         float64 `json:"d"`
+	Condition string  `json:"condition"` // oversold, neutral, overbought
+}
+
+// BollingerBandsData contains Bollinger Bands values
+type BollingerBandsData struct {
+	Upper     float64 `json:"upper"`
+	Middle    float64 `json:"middle"`
+	Lower     float64 `json:"lower"`
+	Bandwidth float64 `json:"bandwidth"`
+	Position  string  `json:"position"` // below_lower, middle, above_upper
+}
+
+// ADXData contains Average Directional Index values
+type ADXData struct {
+	ADX       float64 `json:"adx"`
+	PlusDI    float64 `json:"plus_di"`
+	MinusDI   float64 `json:"minus_di"`
+	Strength  string  `json:"strength"`  // weak, moderate, strong
+	Direction string  `json:"direction"` // bullish, bearish
+}
+
+// ParabolicSARData contains Parabolic SAR values
+type ParabolicSARData struct {
+	Value float64 `json:"value"`
+	Trend string  `json:"trend"` // bullish, bearish
+}
+
+// SuperTrendData contains SuperTrend values
+type SuperTrendData struct {
+	Value float64 `json:"value"`
+	Trend string  `json:"trend"` // bullish, bearish
+}
+
+// IchimokuData contains Ichimoku cloud values
+type IchimokuData struct {
+	Tenkan   float64 `json:"tenkan"`
+	Kijun    float64 `json:"kijun"`
+	SenkouA  float64 `json:"senkou_a"`
+	SenkouB  float64 `json:"senkou_b"`
+	CloudA   float64 `json:"cloud_a"`
+	CloudB   float64 `json:"cloud_b"`
+	Position string  `json:"position"` // above_cloud, in_cloud, below_cloud
+}
+
+// ChannelData contains price channel values
+type ChannelData struct {
+	Upper    float64 `json:"upper"`
+	Middle   float64 `json:"middle"`
+	Lower    float64 `json:"lower"`
+	Position string  `json:"position"` // below_lower, middle, above_upper
+}
+
+// CandlestickPatternResult is a scored candlestick pattern
+type CandlestickPatternResult struct {
+	Name        string  `json:"name"`     // e.g. hammer, bullish_engulfing
+	Signal      string  `json:"signal"`   // bullish, bearish, neutral
+	Strength    float64 `json:"strength"` // 0-100
+	Candles     int     `json:"candles"`  // candles forming the pattern
+	Description string  `json:"description"`
+}
+
+// ChartPatternResult contains chart pattern detection results
+type ChartPatternResult struct {
+	Type       string  `json:"type"`       // triangle, breakout, head_shoulders, etc.
+	Name       string  `json:"name"`       // descriptive name
+	Confidence float64 `json:"confidence"` // 0-100
+	Signal     string  `json:"signal"`     // bullish, bearish, neutral
+	Target     float64 `json:"target,omitempty"`
+	StopLoss   float64 `json:"stop_loss,omitempty"`
+	Timeframe  string  `json:"timeframe"` // short, medium, long
+	Status     string  `json:"status"`    // forming, confirmed, failed
+
+	// REQ-271: Geometry for drawing the pattern; lines end at the latest candle
+	Neckline     *PatternLine   `json:"neckline,omitempty"`
+	Boundaries   []PatternLine  `json:"boundaries,omitempty"` // upper and lower trendlines
+	Points       []PatternPoint `json:"points,omitempty"`     // swing points forming the pattern
+	Invalidation float64        `json:"invalidation,omitempty"`
+}
+
+// PatternPoint is a price at a candle of a chart pattern
+type PatternPoint struct {
+	Time  time.Time `json:"time"`
+	Price float64   `json:"price"`
+	Label string    `json:"label,omitempty"` // head, left_shoulder, peak, trough, etc.
+}
+
+// PatternLine is a chart pattern line between two points
+type PatternLine struct {
+	Label string       `json:"label,omitempty"` // neckline, upper, lower
+	Start PatternPoint `json:"start"`
+	End   PatternPoint `json:"end"`
+}
+
+// SupportResistanceLevels is imported from analysis package but redefined here for models
+type SupportResistanceLevels struct {
+	Support    []*SupportResistanceLevel `json:"support"`
+	Resistance []*SupportResistanceLevel `json:"resistance"`
+	Current    *CurrentLevelInfo         `json:"current"`
+}
+
+// SupportResistanceLevel represents a support or resistance level
+type SupportResistanceLevel struct {
+	Price      float64 `json:"price"`
+	Type       string  `json:"type"`       // support, resistance
+	Strength   float64 `json:"strength"`   // 0-100
+	Touches    int     `json:"touches"`    // number of times price touched this level
+	LastTouch  int     `json:"last_touch"` // periods ago
+	Confidence float64 `json:"confidence"` // 0-100
+}
+
+// CurrentLevelInfo provides context about current price position
+type CurrentLevelInfo struct {
+	Price                float64                 `json:"price"`
+	NearestSupport       *SupportResistanceLevel `json:"nearest_support"`
+	NearestResistance    *SupportResistanceLevel `json:"nearest_resistance"`
+	DistanceToSupport    float64                 `json:"distance_to_support"`    // percentage
+	DistanceToResistance float64                 `json:"distance_to_resistance"` // percentage
+	Position             string                  `json:"position"`               // near_support, near_resistance, middle
+}
+
+// EnrichmentOptions controls which enrichments to apply
+type EnrichmentOptions struct {
+	// Indicator categories
+	TrendIndicators      bool `json:"trend_indicators"`
+	MomentumIndicators   bool `json:"momentum_indicators"`
+	VolatilityIndicators bool `json:"volatility_indicators"`
+	VolumeIndicators     bool `json:"volume_indicators"`
+
+	// REQ-255: Registry indicator specs, e.g. "sma(200)", "ema(9)", "bbands(20,2.5)"
+	Indicators []string `json:"indicators,omitempty"`
+
+	// REQ-262: Compare signals with the higher timeframes of the symbol
+	MultiTimeframe bool `json:"multi_timeframe,omitempty"`
+
+	// REQ-263: Benchmark symbol for relative strength, beta and correlation
+	Benchmark string `json:"benchmark,omitempty"`
+
+	// Analysis categories
+	CandlestickPatterns bool `json:"candlestick_patterns"`
+	ChartPatterns       bool `json:"chart_patterns"`
+	MarketRegime        bool `json:"market_regime"`
+	SupportResistance   bool `json:"support_resistance"`
+
+	// REQ-264: Price-volume profile of the current session
+	VolumeProfile bool `json:"volume_profile,omitempty"`
+
+	// Signal generation
+	TradingSignals bool `json:"trading_signals"`
+	RiskAssessment bool `json:"risk_assessment"`
+
+	// REQ-266: Profile of the trading signals, empty for the default, and
+	// further profiles whose signals are added as profile signals
+	SignalProfile  string   `json:"signal_profile,omitempty"`
+	SignalProfiles []string `json:"signal_profiles,omitempty"`
+
+	// Performance options
+	UseCache        bool `json:"use_cache"`
+	IncludeMetadata bool `json:"include_metadata"`
+	IncludeWarnings bool `json:"include_warnings"`
+}
+
+// DefaultEnrichmentOptions returns standard enrichment settings
+func DefaultEnrichmentOptions() *EnrichmentOptions {
+	return &EnrichmentOptions{
+		TrendIndicators:      true,
+		MomentumIndicators:   true,
+		VolatilityIndicators: true,
+		VolumeIndicators:     true,
+		CandlestickPatterns:  true,
+		ChartPatterns:        true,
+		MarketRegime:         true,
+		SupportResistance:    true,
+		VolumeProfile:        true,
+		TradingSignals:       true,
+		RiskAssessment:       true,
+		UseCache:             true,
+		IncludeMetadata:      true,
+		IncludeWarnings:      false,
+	}
+}
+
+// FastEnrichmentOptions returns performance-optimized settings
+func FastEnrichmentOptions() *EnrichmentOptions {
+	return &EnrichmentOptions{
+		TrendIndicators:      true,
+		MomentumIndicators:   true,
+		VolatilityIndicators: false,
+		VolumeIndicators:     true,
+		CandlestickPatterns:  true,
+		ChartPatterns:        false,
+		MarketRegime:         false,
+		SupportResistance:    false,
+		TradingSignals:       true,
+		RiskAssessment:       false,
+		UseCache:             true,
+		IncludeMetadata:      false,
+		IncludeWarnings:      false,
+	}
+}
+
+// StreamEnrichmentOptions returns the settings of live streamed candles: fast
+// enrichment plus trend, volatility, support/resistance and the session volume
+// profile for charting.
+// Backfills use them too so stored history matches the live stream.
+func StreamEnrichmentOptions() *EnrichmentOptions {
+	options := FastEnrichmentOptions()
+	options.TrendIndicators = true
+	options.VolatilityIndicators = true
+	options.SupportResistance = true
+	options.VolumeProfile = true
+	// REQ-273: Volatility regimes and their change events
+	options.MarketRegime = true
+	return options
+}
+
+// ComprehensiveEnrichmentOptions returns all available enrichments
+func ComprehensiveEnrichmentOptions() *EnrichmentOptions {
+	return &EnrichmentOptions{
+		TrendIndicators:      true,
+		MomentumIndicators:   true,
+		VolatilityIndicators: true,
+		VolumeIndicators:     true,
+		CandlestickPatterns:  true,
+		ChartPatterns:        true,
+		MarketRegime:         true,
+		SupportResistance:    true,
+		VolumeProfile:        true,
+		TradingSignals:       true,
+		RiskAssessment:       true,
+		UseCache:             true,
+		IncludeMetadata:      true,
+		IncludeWarnings:      true,
+	}
+}
+
+// REQ-260: Persisted enriched candles
+
+// EnrichedCandleRecord is a stored enriched candle with the engine version and
+// configuration hash that produced it
+type EnrichedCandleRecord struct {
+	ID            int64           `json:"id"`
+	Symbol        string          `json:"symbol"`
+	Timeframe     string          `json:"timeframe"`
+	Timestamp     time.Time       `json:"timestamp"`
+	EngineVersion string          `json:"engine_version"`
+	ConfigHash    string          `json:"config_hash"`
+	Candle        *EnrichedCandle `json:"candle"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// REQ-261: Resumable enrichment backfills
+
+// BackfillCheckpoint records the progress of a backfill job. A job is one
+// symbol, timeframe and range enriched by one engine version and config hash,
+// so a changed engine starts a new job instead of resuming an old one.
+type BackfillCheckpoint struct {
+	Symbol        string    `json:"symbol"`
+	Timeframe     string    `json:"timeframe"`
+	RangeStart    time.Time `json:"range_start"`
+	RangeEnd      time.Time `json:"range_end"`
+	EngineVersion string    `json:"engine_version"`
+	ConfigHash    string    `json:"config_hash"`
+	LastTimestamp time.Time `json:"last_timestamp"` // last enriched candle
+	Processed     int64     `json:"processed"`
+	Completed     bool      `json:"completed"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
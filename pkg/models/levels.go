@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// REQ-274: Support and resistance levels tracked across candles
+
+// Level roles
+const (
+	LevelSupport    = "support"
+	LevelResistance = "resistance"
+)
+
+// Level sources besides higher timeframes, which use the timeframe, e.g. 1h
+const (
+	LevelSourceDetected = "detected" // pivots of the stream's own candles
+	LevelSourceRound    = "round"    // round number near the price
+)
+
+// Level touches and events
+const (
+	LevelTest  = "test"  // price reached the level and held it
+	LevelBreak = "break" // price closed beyond the level long enough to flip its role
+)
+
+// TrackedLevel is a support or resistance level of a symbol:timeframe with
+// its touch history. A confirmed break flips its role.
+type TrackedLevel struct {
+	Symbol    string       `json:"symbol"`
+	Timeframe string       `json:"timeframe"`
+	Price     float64      `json:"price"`
+	Role      string       `json:"role"`     // support, resistance
+	Sources   []string     `json:"sources"`  // detected, round, higher timeframes such as 1h
+	Strength  float64      `json:"strength"` // 0-100
+	Tests     int          `json:"tests"`
+	Breaks    int          `json:"breaks"`
+	Touches   []LevelTouch `json:"touches"` // latest last, bounded
+	FirstSeen time.Time    `json:"first_seen"`
+	LastTouch *time.Time   `json:"last_touch,omitempty"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// LevelTouch is a test or break of a level
+type LevelTouch struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"` // test, break
+	Role      string    `json:"role"` // role of the level before the touch
+	Close     float64   `json:"close"`
+}
+
+// LevelEvent reports a level tested or broken by a candle
+type LevelEvent struct {
+	Type      string        `json:"type"` // test, break
+	Symbol    string        `json:"symbol"`
+	Timeframe string        `json:"timeframe"`
+	Timestamp time.Time     `json:"timestamp"`
+	Direction string        `json:"direction"` // down onto or through support, up onto or through resistance
+	Close     float64       `json:"close"`
+	Level     *TrackedLevel `json:"level"` // after the event: a broken support is resistance
+}
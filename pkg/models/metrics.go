@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// REQ-269: Enrichment metrics served at /api/v1/enrichment/metrics
+
+// EnrichmentMetrics tracks performance statistics
+type EnrichmentMetrics struct {
+	// Timing metrics
+	TotalEnrichments int64   `json:"total_enrichments"`
+	AverageLatencyMs float64 `json:"average_latency_ms"`
+	MaxLatencyMs     float64 `json:"max_latency_ms"`
+	LatencyP95Ms     float64 `json:"latency_p95_ms"`
+
+	// Quality metrics
+	CacheHitRate float64 `json:"cache_hit_rate"`
+	CacheHits    int64   `json:"cache_hits"`
+	CacheMisses  int64   `json:"cache_misses"`
+	SuccessRate  float64 `json:"success_rate"`
+	ErrorRate    float64 `json:"error_rate"`
+
+	// REQ-268: Enrichments cut off by the deadline
+	PartialEnrichments int64 `json:"partial_enrichments"`
+
+	// Component metrics
+	IndicatorLatencyMs float64 `json:"indicator_latency_ms"`
+	AnalysisLatencyMs  float64 `json:"analysis_latency_ms"`
+	SignalLatencyMs    float64 `json:"signal_latency_ms"`
+
+	// Resource metrics
+	MemoryUsageMB  float64 `json:"memory_usage_mb"`
+	GoroutineCount int     `json:"goroutine_count"`
+
+	// Error tracking
+	LastError  string `json:"last_error,omitempty"`
+	ErrorCount int64  `json:"error_count"`
+
+	// Updated timestamp
+	LastUpdated time.Time `json:"last_updated"`
+
+	// REQ-269: Latencies of the rolling window; the timing and component
+	// metrics above cover the same window
+	WindowMinutes     int                       `json:"window_minutes"`
+	Latency           LatencySummary            `json:"latency"`
+	ComponentLatency  map[string]LatencySummary `json:"component_latency"`
+	StreamLatency     map[string]LatencySummary `json:"stream_latency"` // by symbol:timeframe
+	SkippedComponents map[string]int64          `json:"skipped_components"`
+	FailedComponents  map[string]int64          `json:"failed_components"`
+}
+
+// LatencySummary summarizes the latencies observed within a window
+type LatencySummary struct {
+	Count  int64   `json:"count"`
+	MeanMs float64 `json:"mean_ms"`
+	P50Ms  float64 `json:"p50_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// REQ-272: Outcomes of detected patterns in stored history
+
+// Pattern outcomes
+const (
+	OutcomeTarget  = "target"  // the target was reached first
+	OutcomeStop    = "stop"    // the stop was reached first
+	OutcomeExpired = "expired" // neither within the horizon
+)
+
+// PatternOutcome is what followed one detected pattern within the horizon.
+// Excursions and the return are percentages of the entry, positive in the
+// pattern's direction.
+type PatternOutcome struct {
+	PatternType  string    `json:"pattern_type"` // candlestick, chart
+	PatternName  string    `json:"pattern_name"`
+	Signal       string    `json:"signal"`    // bullish, bearish
+	Timestamp    time.Time `json:"timestamp"` // candle the pattern was detected on
+	Entry        float64   `json:"entry"`     // close of that candle
+	Target       float64   `json:"target"`
+	StopLoss     float64   `json:"stop_loss"`
+	Outcome      string    `json:"outcome"` // target, stop, expired
+	Bars         int       `json:"bars"`    // candles until the outcome
+	MaxFavorable float64   `json:"max_favorable"`
+	MaxAdverse   float64   `json:"max_adverse"`
+	Return       float64   `json:"return"` // at the target, the stop or the end of the horizon
+}
+
+// PatternOutcomeStats aggregates the outcomes of a pattern and signal for one
+// symbol and timeframe
+type PatternOutcomeStats struct {
+	Symbol          string    `json:"symbol" db:"symbol"`
+	Timeframe       string    `json:"timeframe" db:"timeframe"`
+	PatternType     string    `json:"pattern_type" db:"pattern_type"`
+	PatternName     string    `json:"pattern_name" db:"pattern_name"`
+	Signal          string    `json:"signal" db:"signal"`
+	Horizon         int       `json:"horizon" db:"horizon"` // candles after detection
+	Occurrences     int       `json:"occurrences" db:"occurrences"`
+	TargetHits      int       `json:"target_hits" db:"target_hits"`
+	StopHits        int       `json:"stop_hits" db:"stop_hits"`
+	Expired         int       `json:"expired" db:"expired"`
+	HitRate         float64   `json:"hit_rate" db:"hit_rate"` // target hits per occurrence, 0-1
+	AvgMaxFavorable float64   `json:"avg_max_favorable" db:"avg_max_favorable"`
+	AvgMaxAdverse   float64   `json:"avg_max_adverse" db:"avg_max_adverse"`
+	AvgReturn       float64   `json:"avg_return" db:"avg_return"`
+	AvgBars         float64   `json:"avg_bars" db:"avg_bars"` // candles until the outcome
+	RangeStart      time.Time `json:"range_start" db:"range_start"`
+	RangeEnd        time.Time `json:"range_end" db:"range_end"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PatternStatsKey identifies the statistics of a pattern signal
+func PatternStatsKey(symbol, timeframe, patternType, patternName, signal string) string {
+	return strings.Join([]string{symbol, timeframe, patternType, patternName, signal}, "|")
+}
+
+// Key returns the PatternStatsKey of the statistics
+func (s *PatternOutcomeStats) Key() string {
+	return PatternStatsKey(s.Symbol, s.Timeframe, s.PatternType, s.PatternName, s.Signal)
+}
@@ -0,0 +1,22 @@
+package models
+
+// REQ-257: Aligned indicator time series
+
+// IndicatorSeries is one indicator output aligned with a candle range. Values
+// holds one entry per candle; nil marks candles where the indicator is still
+// warming up.
+type IndicatorSeries struct {
+	Key    string     `json:"key"`
+	Values []*float64 `json:"values"`
+}
+
+// REQ-263: Live correlation matrix across the tracked universe
+
+// CorrelationMatrix holds pairwise return correlations in the order of
+// Symbols. Entries are nil when a pair shares fewer than the minimum number of
+// returns.
+type CorrelationMatrix struct {
+	Symbols      []string     `json:"symbols"`
+	Correlations [][]*float64 `json:"correlations"`
+	Observations [][]int      `json:"observations"` // aligned returns per pair
+}
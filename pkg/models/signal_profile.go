@@ -0,0 +1,135 @@
+package models
+
+import (
+	"fmt"
+)
+
+// REQ-266: Named signal strategy profiles
+
+// DefaultSignalProfile is the name of the profile used when none is selected
+const DefaultSignalProfile = "default"
+
+// SignalProfile defines how trading signals are scored: the weight of each
+// component, the thresholds of the overall signal, how confidence is built and
+// which patterns and rules contribute
+type SignalProfile struct {
+	Name        string `json:"name" mapstructure:"name"`
+	Description string `json:"description,omitempty" mapstructure:"description"`
+
+	Weights    SignalWeights     `json:"weights" mapstructure:"weights"`
+	Thresholds SignalThresholds  `json:"thresholds" mapstructure:"thresholds"`
+	Confidence ConfidenceWeights `json:"confidence" mapstructure:"confidence"`
+
+	// Patterns lists the candlestick and chart patterns that contribute to
+	// the signal; empty for all
+	Patterns []string `json:"patterns,omitempty" mapstructure:"patterns"`
+
+	// Rules are additional score components read from indicators by
+	// rule expressions, e.g. when: "close > ema(9)"
+	Rules []SignalRule `json:"rules,omitempty" mapstructure:"rules"`
+}
+
+// SignalWeights are the score contributions of the component signals. A zero
+// weight leaves the component out.
+type SignalWeights struct {
+	Trend            float64 `json:"trend" mapstructure:"trend"`
+	Momentum         float64 `json:"momentum" mapstructure:"momentum"`
+	Volume           float64 `json:"volume" mapstructure:"volume"`                       // added when volume confirms
+	VolumeDivergence float64 `json:"volume_divergence" mapstructure:"volume_divergence"` // subtracted when volume diverges
+}
+
+// SignalThresholds bound the normalized score (-100 to 100) of the overall signal
+type SignalThresholds struct {
+	Bullish float64 `json:"bullish" mapstructure:"bullish"` // score above it is bullish
+	Bearish float64 `json:"bearish" mapstructure:"bearish"` // score below minus it is bearish
+}
+
+// ConfidenceWeights build the signal confidence from a base value
+type ConfidenceWeights struct {
+	Base               float64 `json:"base" mapstructure:"base"`
+	TrendStrength      float64 `json:"trend_strength" mapstructure:"trend_strength"`       // per point of trend strength
+	MomentumStrength   float64 `json:"momentum_strength" mapstructure:"momentum_strength"` // per point of momentum strength
+	VolumeConfirmation float64 `json:"volume_confirmation" mapstructure:"volume_confirmation"`
+	Pattern            float64 `json:"pattern" mapstructure:"pattern"` // when a contributing candlestick pattern formed
+	Max                float64 `json:"max" mapstructure:"max"`         // cap, 0 for 100
+}
+
+// SignalRule adds its weight in the direction of its signal when its
+// expression matches
+type SignalRule struct {
+	Name   string  `json:"name" mapstructure:"name"`
+	When   string  `json:"when" mapstructure:"when"`
+	Signal string  `json:"signal" mapstructure:"signal"` // bullish, bearish
+	Weight float64 `json:"weight" mapstructure:"weight"`
+}
+
+// BuiltinSignalProfile returns the default profile: 40% trend, 35%
+// momentum and 25% volume with a neutral band of +/-60
+func BuiltinSignalProfile() SignalProfile {
+	return SignalProfile{
+		Name:        DefaultSignalProfile,
+		Description: "Balanced trend, momentum and volume scoring",
+		Weights: SignalWeights{
+			Trend:            40,
+			Momentum:         35,
+			Volume:           25,
+			VolumeDivergence: 15,
+		},
+		Thresholds: SignalThresholds{
+			Bullish: 60,
+			Bearish: 60,
+		},
+		Confidence: ConfidenceWeights{
+			Base:               50,
+			TrendStrength:      0.3,
+			MomentumStrength:   0.2,
+			VolumeConfirmation: 15,
+			Pattern:            10,
+			Max:                95,
+		},
+	}
+}
+
+// Validate checks the profile fields other than the rule expressions
+func (p *SignalProfile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("signal profile name is required")
+	}
+
+	weights := p.Weights
+	if weights.Trend < 0 || weights.Momentum < 0 || weights.Volume < 0 || weights.VolumeDivergence < 0 {
+		return fmt.Errorf("signal profile %s: weights must not be negative", p.Name)
+	}
+
+	total := weights.Trend + weights.Momentum + weights.Volume
+	for _, rule := range p.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("signal profile %s: rule name is required", p.Name)
+		}
+		if rule.When == "" {
+			return fmt.Errorf("signal profile %s: rule %s: when expression is required", p.Name, rule.Name)
+		}
+		if rule.Signal != "bullish" && rule.Signal != "bearish" {
+			return fmt.Errorf("signal profile %s: rule %s: signal must be bullish or bearish", p.Name, rule.Name)
+		}
+		if rule.Weight <= 0 {
+			return fmt.Errorf("signal profile %s: rule %s: weight must be positive", p.Name, rule.Name)
+		}
+		total += rule.Weight
+	}
+	if total <= 0 {
+		return fmt.Errorf("signal profile %s: at least one component needs a weight", p.Name)
+	}
+
+	for _, threshold := range []float64{p.Thresholds.Bullish, p.Thresholds.Bearish} {
+		if threshold < 0 || threshold > 100 {
+			return fmt.Errorf("signal profile %s: thresholds must be between 0 and 100", p.Name)
+		}
+	}
+
+	if p.Confidence.Max < 0 || p.Confidence.Max > 100 {
+		return fmt.Errorf("signal profile %s: confidence max must be between 0 and 100", p.Name)
+	}
+
+	return nil
+}
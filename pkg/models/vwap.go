@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// REQ-265: Session-anchored and user-anchored VWAP
+
+// VWAP anchor events
+const (
+	AnchorManual = "manual" // user-chosen timestamp
+	AnchorGap    = "gap"    // regular open of the latest session that gapped
+)
+
+// DefaultAnchorGapPercent is the minimum open gap of a gap anchor
+const DefaultAnchorGapPercent = 2.0
+
+// VWAPAnchor is a user-registered starting point of an anchored VWAP. It
+// applies to every timeframe of the symbol.
+type VWAPAnchor struct {
+	ID         int64     `json:"id" db:"id"`
+	Symbol     string    `json:"symbol" db:"symbol"`
+	Name       string    `json:"name" db:"name"`   // e.g. "Q2 earnings"
+	Event      string    `json:"event" db:"event"` // manual, gap
+	AnchorAt   time.Time `json:"anchor_at" db:"anchor_at"`
+	GapPercent float64   `json:"gap_percent,omitempty" db:"gap_percent"` // gap of a gap anchor
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+
+	// Spec addresses the anchor on the indicator endpoint, e.g. "avwap(1717421400)"
+	Spec string `json:"spec" db:"-"`
+}
+
+// AnchorSpec returns the registry spec of a VWAP anchored at a time
+func AnchorSpec(anchorAt time.Time) string {
+	return fmt.Sprintf("avwap(%d)", anchorAt.Unix())
+}
+
+// Validate checks that the anchor is complete
+func (a *VWAPAnchor) Validate() error {
+	if a.Symbol == "" {
+		return ErrInvalidSymbol
+	}
+	switch a.Event {
+	case AnchorManual, AnchorGap:
+	default:
+		return fmt.Errorf("event must be %s or %s", AnchorManual, AnchorGap)
+	}
+	if a.AnchorAt.IsZero() {
+		return fmt.Errorf("anchor_at is required")
+	}
+	if a.AnchorAt.After(time.Now()) {
+		return fmt.Errorf("anchor_at must not be in the future")
+	}
+	return nil
+}
+
+// VWAPBands is a volume weighted average price with standard deviation bands
+type VWAPBands struct {
+	VWAP   float64   `json:"vwap"`
+	StdDev float64   `json:"stddev"` // volume weighted deviation of typical prices
+	Upper1 float64   `json:"upper_1"`
+	Lower1 float64   `json:"lower_1"`
+	Upper2 float64   `json:"upper_2"`
+	Lower2 float64   `json:"lower_2"`
+	Start  time.Time `json:"start"` // first bar included
+	Bars   int       `json:"bars"`
+}
+
+// AnchoredVWAP is the VWAP of a registered anchor
+type AnchoredVWAP struct {
+	AnchorID int64     `json:"anchor_id"`
+	Name     string    `json:"name,omitempty"`
+	Event    string    `json:"event"`
+	AnchorAt time.Time `json:"anchor_at"`
+	*VWAPBands
+}
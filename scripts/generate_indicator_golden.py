@@ -0,0 +1,332 @@
+#!/usr/bin/env python3
+"""Generate the golden indicator corpus used by internal/indicators tests.
+
+REQ-258: Reference values are computed here, independently of the Go code,
+with the textbook definitions:
+
+  * EMA is seeded with the SMA of the first `period` values, alpha = 2/(n+1)
+  * RSI, ATR and ADX/DI use Wilder smoothing; the first average is the simple
+    mean of the first `period` samples
+  * MACD signal is the EMA(9) of the MACD line, Stochastic %D the SMA(3) of %K
+  * Standard deviation is the population deviation
+  * OBV starts at the first candle's volume; VWAP and A/D are cumulative
+
+A cell is left empty while an indicator is still warming up.
+
+Usage: python3 scripts/generate_indicator_golden.py
+"""
+
+import csv
+import math
+import os
+import random
+from datetime import datetime, timedelta, timezone
+
+OUT_DIR = os.path.join(os.path.dirname(__file__), "..", "internal", "indicators", "testdata")
+CANDLES = 160
+
+
+def generate_candles():
+    rng = random.Random(7)
+    start = datetime(2025, 1, 2, 14, 30, tzinfo=timezone.utc)
+    candles = []
+    price = 100.0
+    for i in range(CANDLES):
+        open_ = price
+        if 60 <= i < 63:
+            # A flat stretch exercises zero-range and zero-change edge cases
+            candles.append(dict(ts=start + timedelta(minutes=i), open=price, high=price,
+                                low=price, close=price, volume=1000 + i))
+            continue
+        drift = 0.15 if (i // 40) % 2 == 0 else -0.15
+        price = round(price + drift + rng.uniform(-1, 1), 2)
+        high = round(max(open_, price) + rng.uniform(0, 0.8), 2)
+        low = round(min(open_, price) - rng.uniform(0, 0.8), 2)
+        candles.append(dict(ts=start + timedelta(minutes=i), open=open_, high=high,
+                            low=low, close=price, volume=rng.randint(1000, 9999)))
+    return candles
+
+
+def sma(values, n, i):
+    if i + 1 < n:
+        return None
+    return sum(values[i + 1 - n:i + 1]) / n
+
+
+def ema_series(values, n):
+    out = [None] * len(values)
+    alpha = 2.0 / (n + 1)
+    for i in range(len(values)):
+        if i + 1 < n:
+            continue
+        if i + 1 == n:
+            out[i] = sum(values[:n]) / n
+        else:
+            out[i] = values[i] * alpha + out[i - 1] * (1 - alpha)
+    return out
+
+
+def wilder_series(samples, n):
+    """Wilder average of samples (None samples are skipped at the start)"""
+    out = [None] * len(samples)
+    seen, total, value = 0, 0.0, None
+    for i, x in enumerate(samples):
+        if x is None:
+            continue
+        seen += 1
+        if seen <= n:
+            total += x
+            if seen == n:
+                value = total / n
+        else:
+            value = (value * (n - 1) + x) / n
+        out[i] = value if seen >= n else None
+    return out
+
+
+def true_range(c, p):
+    return max(c["high"] - c["low"], abs(c["high"] - p["close"]), abs(c["low"] - p["close"]))
+
+
+def highest(cs, key, n, i):
+    return max(c[key] for c in cs[i + 1 - n:i + 1])
+
+
+def lowest(cs, key, n, i):
+    return min(c[key] for c in cs[i + 1 - n:i + 1])
+
+
+def compute(cs):
+    n = len(cs)
+    closes = [c["close"] for c in cs]
+    typical = [(c["high"] + c["low"] + c["close"]) / 3 for c in cs]
+    cols = {}
+
+    def col(name):
+        cols[name] = [None] * n
+        return cols[name]
+
+    # Trend
+    sma20, sma50 = col("sma20"), col("sma50")
+    for i in range(n):
+        sma20[i], sma50[i] = sma(closes, 20, i), sma(closes, 50, i)
+    cols["ema12"], cols["ema26"] = ema_series(closes, 12), ema_series(closes, 26)
+    macd = col("macd")
+    for i in range(n):
+        if cols["ema26"][i] is not None:
+            macd[i] = cols["ema12"][i] - cols["ema26"][i]
+    line = [m for m in macd if m is not None]
+    signal_tail = ema_series(line, 9)
+    signal, hist = col("macd_signal"), col("macd_hist")
+    offset = n - len(line)
+    for j, s in enumerate(signal_tail):
+        if s is not None:
+            signal[offset + j] = s
+            hist[offset + j] = line[j] - s
+
+    # Momentum
+    gains = [None] + [max(closes[i] - closes[i - 1], 0.0) for i in range(1, n)]
+    losses = [None] + [max(closes[i - 1] - closes[i], 0.0) for i in range(1, n)]
+    avg_gain, avg_loss = wilder_series(gains, 14), wilder_series(losses, 14)
+    rsi = col("rsi14")
+    for i in range(n):
+        if avg_gain[i] is None:
+            continue
+        if avg_loss[i] == 0:
+            rsi[i] = 50.0 if avg_gain[i] == 0 else 100.0
+        else:
+            rsi[i] = 100 - 100 / (1 + avg_gain[i] / avg_loss[i])
+
+    stoch_k, stoch_d, willr, roc = col("stoch_k"), col("stoch_d"), col("willr14"), col("roc10")
+    for i in range(n):
+        if i + 1 >= 14:
+            hh, ll = highest(cs, "high", 14, i), lowest(cs, "low", 14, i)
+            if hh == ll:
+                stoch_k[i], willr[i] = 50.0, -50.0
+            else:
+                stoch_k[i] = (closes[i] - ll) / (hh - ll) * 100
+                willr[i] = (hh - closes[i]) / (hh - ll) * -100
+        if i + 1 >= 16:
+            stoch_d[i] = sum(stoch_k[i - 2:i + 1]) / 3
+        if i >= 10 and closes[i - 10] != 0:
+            roc[i] = (closes[i] - closes[i - 10]) / closes[i - 10] * 100
+
+    # Volatility
+    stddev, bb_u, bb_m, bb_l = col("stddev20"), col("bb_upper"), col("bb_middle"), col("bb_lower")
+    for i in range(19, n):
+        window = closes[i - 19:i + 1]
+        mean = sum(window) / 20
+        sd = math.sqrt(sum((x - mean) ** 2 for x in window) / 20)
+        stddev[i], bb_m[i], bb_u[i], bb_l[i] = sd, mean, mean + 2 * sd, mean - 2 * sd
+    trs = [None] + [true_range(cs[i], cs[i - 1]) for i in range(1, n)]
+    cols["atr14"] = wilder_series(trs, 14)
+
+    # Volume; all candles are in one session, so the session VWAP is cumulative
+    vwap, obv, ad = col("vwap"), col("obv"), col("ad")
+    pv, vol, running_obv, running_ad = 0.0, 0, 0.0, 0.0
+    for i, c in enumerate(cs):
+        pv += typical[i] * c["volume"]
+        vol += c["volume"]
+        vwap[i] = pv / vol
+        if i == 0:
+            running_obv = float(c["volume"])
+        elif closes[i] > closes[i - 1]:
+            running_obv += c["volume"]
+        elif closes[i] < closes[i - 1]:
+            running_obv -= c["volume"]
+        if i >= 1:
+            obv[i] = running_obv
+        if c["high"] != c["low"]:
+            running_ad += ((c["close"] - c["low"]) - (c["high"] - c["close"])) / (c["high"] - c["low"]) * c["volume"]
+        ad[i] = running_ad
+
+    # ADX / DI (Wilder sums as in the original definition)
+    plus_di, minus_di, adx = col("plus_di"), col("minus_di"), col("adx14")
+    tr_sum = plus_sum = minus_sum = 0.0
+    dxs = [None] * n
+    for i in range(1, n):
+        up = cs[i]["high"] - cs[i - 1]["high"]
+        down = cs[i - 1]["low"] - cs[i]["low"]
+        pdm = up if up > down and up > 0 else 0.0
+        mdm = down if down > up and down > 0 else 0.0
+        if i <= 14:
+            tr_sum, plus_sum, minus_sum = tr_sum + trs[i], plus_sum + pdm, minus_sum + mdm
+        else:
+            tr_sum = tr_sum - tr_sum / 14 + trs[i]
+            plus_sum = plus_sum - plus_sum / 14 + pdm
+            minus_sum = minus_sum - minus_sum / 14 + mdm
+        if i < 14:
+            continue
+        p = 100 * plus_sum / tr_sum if tr_sum > 0 else 0.0
+        m = 100 * minus_sum / tr_sum if tr_sum > 0 else 0.0
+        plus_di[i], minus_di[i] = p, m
+        dxs[i] = 100 * abs(p - m) / (p + m) if p + m > 0 else 0.0
+    cols["adx14"] = wilder_series(dxs, 14)
+
+    # Parabolic SAR (0.02, 0.2); the first trend follows the first close move
+    psar, psar_trend = col("psar"), col("psar_trend")
+    bull = closes[1] >= closes[0]
+    sar = cs[0]["low"] if bull else cs[0]["high"]
+    ep = cs[0]["high"] if bull else cs[0]["low"]
+    af = 0.02
+    for i in range(1, n):
+        sar = sar + af * (ep - sar)
+        c = cs[i]
+        if bull:
+            sar = min([sar, cs[i - 1]["low"]] + ([cs[i - 2]["low"]] if i >= 2 else []))
+            if c["low"] < sar:
+                bull, sar, ep, af = False, ep, c["low"], 0.02
+            elif c["high"] > ep:
+                ep, af = c["high"], min(af + 0.02, 0.2)
+        else:
+            sar = max([sar, cs[i - 1]["high"]] + ([cs[i - 2]["high"]] if i >= 2 else []))
+            if c["high"] > sar:
+                bull, sar, ep, af = True, ep, c["high"], 0.02
+            elif c["low"] < ep:
+                ep, af = c["low"], min(af + 0.02, 0.2)
+        psar[i], psar_trend[i] = sar, 1.0 if bull else -1.0
+
+    # SuperTrend (10, 3) on Wilder ATR(10)
+    atr10 = wilder_series(trs, 10)
+    st, st_trend = col("supertrend"), col("supertrend_trend")
+    upper = lower = None
+    bull = False
+    for i in range(n):
+        if atr10[i] is None:
+            continue
+        median = (cs[i]["high"] + cs[i]["low"]) / 2
+        bu, bl = median + 3 * atr10[i], median - 3 * atr10[i]
+        if upper is None:
+            upper, lower, bull = bu, bl, closes[i] >= median
+        else:
+            if bu < upper or closes[i - 1] > upper:
+                upper = bu
+            if bl > lower or closes[i - 1] < lower:
+                lower = bl
+            if bull and closes[i] < lower:
+                bull = False
+            elif not bull and closes[i] > upper:
+                bull = True
+        st[i], st_trend[i] = (lower, 1.0) if bull else (upper, -1.0)
+
+    # Ichimoku (9, 26, 52)
+    def mid(end, period):
+        if end < period:
+            return None
+        return (max(c["high"] for c in cs[end - period:end]) + min(c["low"] for c in cs[end - period:end])) / 2
+
+    tenkan, kijun, span_a, span_b = col("tenkan"), col("kijun"), col("senkou_a"), col("senkou_b")
+    cloud_a, cloud_b = col("cloud_a"), col("cloud_b")
+    for i in range(n):
+        end = i + 1
+        tenkan[i], kijun[i], span_b[i] = mid(end, 9), mid(end, 26), mid(end, 52)
+        if tenkan[i] is not None and kijun[i] is not None:
+            span_a[i] = (tenkan[i] + kijun[i]) / 2
+        past = end - 26
+        if past >= 26:
+            cloud_a[i] = (mid(past, 9) + mid(past, 26)) / 2
+        if past >= 52:
+            cloud_b[i] = mid(past, 52)
+
+    # Keltner (EMA20, ATR10, 2) and Donchian (20)
+    ema20 = ema_series(closes, 20)
+    ku, km, kl = col("keltner_upper"), col("keltner_middle"), col("keltner_lower")
+    du, dm, dl = col("donchian_upper"), col("donchian_middle"), col("donchian_lower")
+    for i in range(n):
+        if ema20[i] is not None and atr10[i] is not None:
+            km[i], ku[i], kl[i] = ema20[i], ema20[i] + 2 * atr10[i], ema20[i] - 2 * atr10[i]
+        if i + 1 >= 20:
+            hh, ll = highest(cs, "high", 20, i), lowest(cs, "low", 20, i)
+            du[i], dl[i], dm[i] = hh, ll, (hh + ll) / 2
+
+    # CCI (20), MFI (14), CMF (20)
+    cci, mfi, cmf = col("cci20"), col("mfi14"), col("cmf20")
+    for i in range(n):
+        if i + 1 >= 20:
+            window = typical[i - 19:i + 1]
+            mean = sum(window) / 20
+            md = sum(abs(x - mean) for x in window) / 20
+            cci[i] = 0.0 if md == 0 else (typical[i] - mean) / (0.015 * md)
+            flow = sum(((c["close"] - c["low"]) - (c["high"] - c["close"])) / (c["high"] - c["low"]) * c["volume"]
+                       for c in cs[i - 19:i + 1] if c["high"] != c["low"])
+            volume = sum(c["volume"] for c in cs[i - 19:i + 1])
+            cmf[i] = flow / volume if volume else 0.0
+        if i >= 14:
+            pos = neg = 0.0
+            for j in range(i - 13, i + 1):
+                raw = typical[j] * cs[j]["volume"]
+                if typical[j] > typical[j - 1]:
+                    pos += raw
+                elif typical[j] < typical[j - 1]:
+                    neg += raw
+            if neg == 0:
+                mfi[i] = 50.0 if pos == 0 else 100.0
+            else:
+                mfi[i] = 100 - 100 / (1 + pos / neg)
+
+    # Keep a stable column order
+    return cols
+
+
+def main():
+    os.makedirs(OUT_DIR, exist_ok=True)
+    cs = generate_candles()
+
+    with open(os.path.join(OUT_DIR, "golden_ohlcv.csv"), "w", newline="") as f:
+        writer = csv.writer(f)
+        writer.writerow(["timestamp", "open", "high", "low", "close", "volume"])
+        for c in cs:
+            writer.writerow([c["ts"].strftime("%Y-%m-%dT%H:%M:%SZ"), "%.2f" % c["open"], "%.2f" % c["high"],
+                             "%.2f" % c["low"], "%.2f" % c["close"], c["volume"]])
+
+    cols = compute(cs)
+    names = list(cols)
+    with open(os.path.join(OUT_DIR, "golden_expected.csv"), "w", newline="") as f:
+        writer = csv.writer(f)
+        writer.writerow(["index"] + names)
+        for i in range(len(cs)):
+            writer.writerow([i] + ["" if cols[name][i] is None else repr(cols[name][i]) for name in names])
+
+
+if __name__ == "__main__":
+    main()